@@ -2,18 +2,85 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
 	"github.com/Srinath-exe/simplebank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
+var errAccountNotGranted = errors.New("account is not covered by the granted consent")
+
+// accountResponse is an account with its balance as a decimal amount
+type accountResponse struct {
+	ID         int64       `json:"id"`
+	Owner      string      `json:"owner"`
+	Balance    money.Money `json:"balance"`
+	Currency   string      `json:"currency"`
+	Status     string      `json:"status"`
+	FreezeMode string      `json:"freeze_mode"`
+	Product    string      `json:"product"`
+	Nickname   string      `json:"nickname"`
+	ClosedAt   *time.Time  `json:"closed_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{
+		ID:         account.ID,
+		Owner:      account.Owner,
+		Balance:    money.New(account.Balance, account.Currency),
+		Currency:   account.Currency,
+		Status:     account.Status,
+		FreezeMode: account.FreezeMode,
+		Product:    account.Product,
+		Nickname:   account.Nickname,
+		ClosedAt:   nullTimePtr(account.ClosedAt),
+		CreatedAt:  account.CreatedAt,
+	}
+}
+
+func newAccountsResponse(accounts []db.Account) []accountResponse {
+	rsp := make([]accountResponse, len(accounts))
+
+	for i, account := range accounts {
+		rsp[i] = newAccountResponse(account)
+	}
+
+	return rsp
+}
+
+// closeAccountResponse is the closed account and the transfer that swept its
+// balance, if there was one
+type closeAccountResponse struct {
+	Account accountResponse     `json:"account"`
+	Sweep   *transferTxResponse `json:"sweep,omitempty"`
+}
+
+func newCloseAccountResponse(result db.CloseAccountTxResult) closeAccountResponse {
+	rsp := closeAccountResponse{Account: newAccountResponse(result.Account)}
+
+	if result.Sweep != nil {
+		sweep := newTransferTxResponse(*result.Sweep)
+		rsp.Sweep = &sweep
+	}
+
+	return rsp
+}
+
 // createAccountRequest defines the request body for createAccount handler.
+// Accounts are checking accounts unless another product is asked for.
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	Product  string `json:"product"`
+	Nickname string `json:"nickname" binding:"max=50"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
@@ -30,11 +97,61 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		Owner:    authPayload.Username,
 		Currency: req.Currency,
 		Balance:  0,
+		Product:  req.Product,
+		Nickname: strings.TrimSpace(req.Nickname),
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	if arg.Product == "" {
+		arg.Product = db.ProductChecking
+	}
+
+	var account db.Account
+
+	audit := newAuditParams(ctx, auditAccountCreate, auditTargetAccount, "")
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		// serializes account creation for the user, so the count below
+		// can't miss an account being opened at the same time
+		if err := q.LockUserAccounts(ctx, arg.Owner); err != nil {
+			return err
+		}
+
+		product, err := q.GetAccountProduct(ctx, arg.Product)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errUnknownProduct
+			}
+			return err
+		}
+
+		count, err := q.CountOpenAccounts(ctx, db.CountOpenAccountsParams{
+			Owner:    arg.Owner,
+			Currency: arg.Currency,
+			Product:  arg.Product,
+		})
+		if err != nil {
+			return err
+		}
+
+		if count >= int64(product.MaxAccounts) {
+			return errProductAccountLimit
+		}
+
+		account, err = q.CreateAccount(ctx, arg)
+		audit.TargetID = strconv.FormatInt(account.ID, 10)
+		audit.After = account
+		return err
+	})
 
 	if err != nil {
+		switch {
+		case errors.Is(err, errUnknownProduct):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		case errors.Is(err, errProductAccountLimit):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
 
 		if pqerr, ok := err.(*pq.Error); ok {
 			switch pqerr.Code.Name() {
@@ -48,7 +165,7 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 
 }
 
@@ -84,12 +201,43 @@ func (server *Server) getAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	if !authPayload.CanAccessAccount(account.ID) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotGranted))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+// getAccountLimits shows how much of the transfer limits of an account has
+// been used in the current day and month
+func (server *Server) getAccountLimits(ctx *gin.Context) {
+	var req getAccountRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.ownedAccount(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	usage, err := db.GetTransferLimitUsage(ctx, server.store, account, server.transferLimits(account.Currency), time.Now())
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newLimitsResponse(usage))
 }
 
 type getAccountsListRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+	Product  string `form:"product"`
 }
 
 func (server *Server) getAccountsList(ctx *gin.Context) {
@@ -103,18 +251,28 @@ func (server *Server) getAccountsList(ctx *gin.Context) {
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
 	arg := db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		Owner:   authPayload.Username,
+		Product: sql.NullString{String: req.Product, Valid: req.Product != ""},
+		Limit:   req.PageSize,
+		Offset:  (req.PageID - 1) * req.PageSize,
 	}
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	granted := accounts[:0]
+	for _, account := range accounts {
+		if authPayload.CanAccessAccount(account.ID) {
+			granted = append(granted, account)
+		}
+	}
+	accounts = granted
+
+	ctx.JSON(http.StatusOK, newAccountsResponse(accounts))
 
 }
 
@@ -142,12 +300,15 @@ func (server *Server) searchAccounts(ctx *gin.Context) {
 		offset = *searchRequest.Offset
 	}
 
-	//  := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
+	// only the caller's accounts are searched, and only the accounts of their
+	// consent when the token was issued for one
 	params := db.SearchAccountsParams{
 		Column1: sql.NullString{String: searchRequest.SeachOwnerQuery, Valid: true},
 		Limit:   limit,
 		Offset:  offset,
+		Owner:   authPayload.Username,
 	}
 
 	res, err := server.store.SearchAccounts(ctx, params)
@@ -162,9 +323,18 @@ func (server *Server) searchAccounts(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, res)
+	granted := res[:0]
+	for _, account := range res {
+		if authPayload.CanAccessAccount(account.ID) {
+			granted = append(granted, account)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, newAccountsResponse(granted))
 }
 
+// deleteAccount closes an empty account. Accounts are never removed, so their
+// entries and transfers stay in the history.
 func (server *Server) deleteAccount(ctx *gin.Context) {
 	var req getAccountRequest
 
@@ -173,16 +343,92 @@ func (server *Server) deleteAccount(ctx *gin.Context) {
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
+	_, ok := server.closeOwnedAccount(ctx, req.ID, 0)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "account closed successfully")
+}
+
+type closeAccountRequest struct {
+	ID             int64 `json:"id" binding:"required,min=1"`
+	SweepAccountID int64 `json:"sweep_account_id" binding:"omitempty,min=1"`
+}
+
+// closeAccount closes an account, moving any remaining balance to another of
+// the user's accounts in the same currency
+func (server *Server) closeAccount(ctx *gin.Context) {
+	var req closeAccountRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, ok := server.closeOwnedAccount(ctx, req.ID, req.SweepAccountID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCloseAccountResponse(result))
+}
+
+func (server *Server) closeOwnedAccount(ctx *gin.Context, accountID int64, sweepAccountID int64) (db.CloseAccountTxResult, bool) {
+	var result db.CloseAccountTxResult
+
+	account, ok := server.ownedAccount(ctx, accountID)
+	if !ok {
+		return result, false
+	}
+
+	if sweepAccountID != 0 {
+		if _, ok := server.ownedAccount(ctx, sweepAccountID); !ok {
+			return result, false
+		}
+
+		// sweeping moves money, which a disabled currency doesn't allow
+		if !server.currencies.enabled(ctx, account.Currency) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errCurrencyDisabled))
+			return result, false
+		}
+	}
+
+	arg := db.CloseAccountTxParams{
+		AccountID:      accountID,
+		SweepAccountID: sweepAccountID,
+		Audit:          newAuditParams(ctx, auditAccountClose, auditTargetAccount, strconv.FormatInt(accountID, 10)),
+	}
+
+	result, err := server.store.CloseAccountTx(ctx, arg)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrAccountClosed), errors.Is(err, db.ErrAccountFrozen),
+			errors.Is(err, db.ErrAccountNotEmpty), errors.Is(err, db.ErrInvalidSweepAccount):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return result, false
+	}
+
+	return result, true
+}
+
+// ownedAccount loads an account the caller owns and is allowed to access,
+// writing the error response if they can't
+func (server *Server) ownedAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
+			return account, false
 		}
 
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return account, false
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
@@ -190,22 +436,23 @@ func (server *Server) deleteAccount(ctx *gin.Context) {
 	if account.Owner != authPayload.Username {
 		err := errors.New("account doesn't belong to the authenticated user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
+		return account, false
 	}
 
-	err = server.store.DeleteAccount(ctx, req.ID)
-
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+	if !authPayload.CanAccessAccount(account.ID) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotGranted))
+		return account, false
 	}
 
-	ctx.JSON(http.StatusOK, "account deleted successfully")
+	return account, true
 }
 
+// updateAccountRequest adjusts the balance of an account by a decimal amount
+// in its currency, negative to debit it. Adjustments create money, so only
+// bankers make them, on any customer's account.
 type updateAccountRequest struct {
-	ID     int64 `json:"id" binding:"required,min=1"`
-	Amount int64 `json:"amount" binding:"required"`
+	ID     int64       `json:"id" binding:"required,min=1"`
+	Amount json.Number `json:"amount" binding:"required"`
 }
 
 func (server *Server) updateAccount(ctx *gin.Context) {
@@ -229,25 +476,121 @@ func (server *Server) updateAccount(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.currencies.enabled(ctx, account.Currency) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errCurrencyDisabled))
+		return
+	}
 
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	amount, err := money.Parse(req.Amount.String(), account.Currency)
+	if err == nil && amount.IsZero() {
+		err = errAmountZero
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
 	arg := db.AddAccountBalanceParams{
 		ID:     req.ID,
-		Amount: req.Amount,
+		Amount: amount.Amount(),
 	}
 
-	account, err = server.store.AddAccountBalance(ctx, arg)
+	audit := newAuditParams(ctx, auditAccountUpdate, auditTargetAccount, strconv.FormatInt(account.ID, 10))
+	audit.Before = account
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		account, err = q.AddAccountBalance(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if account.Status == db.AccountStatusClosed {
+			return db.ErrAccountClosed
+		}
+
+		if (arg.Amount < 0 && !account.CanDebit()) || (arg.Amount > 0 && !account.CanCredit()) {
+			return db.ErrAccountFrozen
+		}
+
+		// the adjustment is posted so the balance still equals the sum of the entries
+		_, err = q.CreateEntry(ctx, db.CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    arg.Amount,
+			EntryType: db.EntryTypeAdjustment,
+		})
+		if err != nil {
+			return err
+		}
+
+		audit.After = account
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrAccountClosed) || errors.Is(err, db.ErrAccountFrozen) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type renameAccountRequest struct {
+	ID       int64  `json:"id" binding:"required,min=1"`
+	Nickname string `json:"nickname" binding:"max=50"`
+}
+
+// renameAccount sets the nickname of an account, or clears it when it's
+// empty. Open accounts of the same product and currency need different
+// nicknames.
+func (server *Server) renameAccount(ctx *gin.Context) {
+	var req renameAccountRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.ownedAccount(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	if account.Status == db.AccountStatusClosed {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(db.ErrAccountClosed))
+		return
+	}
+
+	arg := db.UpdateAccountNicknameParams{
+		ID:       account.ID,
+		Nickname: strings.TrimSpace(req.Nickname),
+	}
+
+	audit := newAuditParams(ctx, auditAccountRename, auditTargetAccount, strconv.FormatInt(account.ID, 10))
+	audit.Before = account
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		account, err = q.UpdateAccountNickname(ctx, arg)
+		audit.After = account
+		return err
+	})
 
 	if err != nil {
+		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"github.com/Srinath-exe/simplebank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -133,6 +135,7 @@ func TestGetAccountApi(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -156,18 +159,39 @@ func randomAccount(owner string) db.Account {
 		Owner:    owner,
 		Currency: util.RandomCurrency(),
 		Balance:  util.RandomMoney(),
+		Status:   db.AccountStatusActive,
+		Product:  db.ProductChecking,
 	}
 }
 
+var (
+	checkingProduct = db.AccountProduct{Code: db.ProductChecking, Name: "Checking", MaxAccounts: 1}
+	savingsProduct  = db.AccountProduct{Code: db.ProductSavings, Name: "Savings", MaxAccounts: 5, MaxMonthlyWithdrawals: 6}
+)
+
+// expectOpenAccounts stubs the product checks made before an account is
+// created, with count open accounts of the product already there
+func expectOpenAccounts(store *mockdb.MockStore, owner string, currency string, product db.AccountProduct, count int64) {
+	store.EXPECT().LockUserAccounts(gomock.Any(), gomock.Eq(owner)).Times(1).Return(nil)
+	store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Eq(product.Code)).Times(1).Return(product, nil)
+	store.EXPECT().
+		CountOpenAccounts(gomock.Any(), gomock.Eq(db.CountOpenAccountsParams{
+			Owner:    owner,
+			Currency: currency,
+			Product:  product.Code,
+		})).
+		Times(1).
+		Return(count, nil)
+}
+
 func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Account) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotAccount db.Account
-	err = json.Unmarshal(data, &gotAccount)
+	want, err := json.Marshal(newAccountResponse(account))
 	require.NoError(t, err)
 
-	require.Equal(t, account, gotAccount)
+	require.JSONEq(t, string(want), string(data))
 }
 
 func TestCreateAccountApi(t *testing.T) {
@@ -190,10 +214,13 @@ func TestCreateAccountApi(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				expectOpenAccounts(store, user.Username, account.Currency, checkingProduct, 0)
+
 				arg := db.CreateAccountParams{
 					Owner:    user.Username,
 					Currency: account.Currency,
 					Balance:  0,
+					Product:  db.ProductChecking,
 				}
 				store.EXPECT().
 					CreateAccount(gomock.Any(), gomock.Eq(arg)).
@@ -205,6 +232,85 @@ func TestCreateAccountApi(t *testing.T) {
 				requireBodyMatchAccount(t, recorder.Body, account)
 			},
 		},
+		{
+			name: "SecondSavingsAccount",
+			body: gin.H{
+				"currency": account.Currency,
+				"product":  db.ProductSavings,
+				"nickname": " Holiday ",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectOpenAccounts(store, user.Username, account.Currency, savingsProduct, 1)
+
+				arg := db.CreateAccountParams{
+					Owner:    user.Username,
+					Currency: account.Currency,
+					Balance:  0,
+					Product:  db.ProductSavings,
+					Nickname: "Holiday",
+				}
+				store.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ProductAccountLimit",
+			body: gin.H{
+				"currency": account.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectOpenAccounts(store, user.Username, account.Currency, checkingProduct, 1)
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownProduct",
+			body: gin.H{
+				"currency": account.Currency,
+				"product":  "brokerage",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LockUserAccounts(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Eq("brokerage")).Times(1).Return(db.AccountProduct{}, sql.ErrNoRows)
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NicknameTooLong",
+			body: gin.H{
+				"currency": account.Currency,
+				"nickname": util.RandomString(51),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "Bad Request",
 			body: gin.H{
@@ -242,6 +348,7 @@ func TestCreateAccountApi(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				expectOpenAccounts(store, user.Username, account.Currency, checkingProduct, 0)
 				store.EXPECT().
 					CreateAccount(gomock.Any(), gomock.Any()).
 					Times(1).
@@ -262,6 +369,7 @@ func TestCreateAccountApi(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -290,6 +398,7 @@ func TestListAccountsApi(t *testing.T) {
 	type Query struct {
 		PageID   int
 		PageSize int
+		Product  string
 	}
 	testCases := []struct {
 		name          string
@@ -321,6 +430,30 @@ func TestListAccountsApi(t *testing.T) {
 				requireBodyMatchAccounts(t, recorder.Body, accounts)
 			},
 		},
+		{
+			name: "ProductFilter",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			query: Query{
+				PageID:   1,
+				PageSize: n,
+				Product:  db.ProductSavings,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsParams{
+					Owner:   user.Username,
+					Product: sql.NullString{String: db.ProductSavings, Valid: true},
+					Limit:   5,
+					Offset:  0,
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Account{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, accounts []db.Account) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccounts(t, recorder.Body, []db.Account{})
+			},
+		},
 		{
 			name: "No Authorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
@@ -396,6 +529,7 @@ func TestListAccountsApi(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -408,6 +542,9 @@ func TestListAccountsApi(t *testing.T) {
 			q := request.URL.Query()
 			q.Add("page_id", fmt.Sprintf("%d", tc.query.PageID))
 			q.Add("page_size", fmt.Sprintf("%d", tc.query.PageSize))
+			if tc.query.Product != "" {
+				q.Add("product", tc.query.Product)
+			}
 			request.URL.RawQuery = q.Encode()
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -423,11 +560,10 @@ func requireBodyMatchAccounts(t *testing.T, buffer *bytes.Buffer, accounts []db.
 	data, err := io.ReadAll(buffer)
 	require.NoError(t, err)
 
-	var gotAccounts []db.Account
-	err = json.Unmarshal(data, &gotAccounts)
+	want, err := json.Marshal(newAccountsResponse(accounts))
 	require.NoError(t, err)
 
-	require.Equal(t, accounts, gotAccounts)
+	require.JSONEq(t, string(want), string(data))
 }
 
 func TestSearchAccountsApi(t *testing.T) {
@@ -463,6 +599,7 @@ func TestSearchAccountsApi(t *testing.T) {
 					Column1: sql.NullString{String: users[0].Username, Valid: true},
 					Limit:   5,
 					Offset:  0,
+					Owner:   users[0].Username,
 				}
 				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts[:5], nil)
 			},
@@ -526,6 +663,7 @@ func TestSearchAccountsApi(t *testing.T) {
 					Column1: sql.NullString{String: "unknown_user", Valid: true},
 					Limit:   5,
 					Offset:  0,
+					Owner:   users[0].Username,
 				}
 				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Account{}, sql.ErrNoRows)
 			},
@@ -535,6 +673,30 @@ func TestSearchAccountsApi(t *testing.T) {
 			},
 		},
 
+		{
+			name: "DelegatedTokenSeesConsentedAccountsOnly",
+			request: gin.H{
+				"owner": users[1].Username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addConsentAuthorization(t, request, tokenMaker, users[0].Username, scopeAccountsRead, accounts[1].ID)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthConsent(gomock.Any(), gomock.Any()).AnyTimes().Return(db.OauthConsent{}, nil)
+				store.EXPECT().
+					SearchAccounts(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SearchAccountsParams) ([]db.Account, error) {
+						// other owners' accounts are never searched
+						require.Equal(t, users[0].Username, arg.Owner)
+						return append([]db.Account{}, accounts[:5]...), nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, accounts []db.Account) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccounts(t, recorder.Body, accounts[1:2])
+			},
+		},
 		{
 			name: "dynamic limit and offset",
 			request: gin.H{
@@ -550,6 +712,7 @@ func TestSearchAccountsApi(t *testing.T) {
 					Column1: sql.NullString{String: users[0].Username, Valid: true},
 					Limit:   10,
 					Offset:  1,
+					Owner:   users[0].Username,
 				}
 				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts[5:15], nil)
 			},
@@ -569,6 +732,7 @@ func TestSearchAccountsApi(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -588,3 +752,357 @@ func TestSearchAccountsApi(t *testing.T) {
 		})
 	}
 }
+
+func TestCloseAccountApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	sweepAccount := randomAccount(user.Username)
+	sweepAccount.ID = account.ID + 1
+	sweepAccount.Currency = account.Currency
+	otherAccount := randomAccount(util.RandomOwner())
+	otherAccount.ID = account.ID + 2
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"id": account.ID, "sweep_account_id": sweepAccount.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				closed := account
+				closed.Balance = 0
+				closed.Status = db.AccountStatusClosed
+
+				arg := db.CloseAccountTxParams{
+					AccountID:      account.ID,
+					SweepAccountID: sweepAccount.ID,
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(sweepAccount.ID)).Times(1).Return(sweepAccount, nil)
+				store.EXPECT().
+					CloseAccountTx(gomock.Any(), EqAuditedParams(arg, auditAccountClose)).
+					Times(1).
+					Return(db.CloseAccountTxResult{Account: closed}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					Account struct {
+						Status  string `json:"status"`
+						Balance string `json:"balance"`
+					} `json:"account"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.AccountStatusClosed, res.Account.Status)
+				require.Equal(t, "0.00", res.Account.Balance)
+			},
+		},
+		{
+			name: "NotEmpty",
+			body: gin.H{"id": account.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CloseAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CloseAccountTxResult{}, db.ErrAccountNotEmpty)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "SweepToOtherUser",
+			body: gin.H{"id": account.ID, "sweep_account_id": otherAccount.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().CloseAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{"id": account.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CloseAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"id": account.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().CloseAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			body: gin.H{"id": 0},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts/close", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteAccountApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		CloseAccountTx(gomock.Any(), EqAuditedParams(db.CloseAccountTxParams{AccountID: account.ID}, auditAccountClose)).
+		Times(1).
+		Return(db.CloseAccountTxResult{Account: account}, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/accounts/delete/%d", account.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRenameAccountApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	account.Product = db.ProductSavings
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"id": account.ID, "nickname": "Rainy day "},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				renamed := account
+				renamed.Nickname = "Rainy day"
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					UpdateAccountNickname(gomock.Any(), gomock.Eq(db.UpdateAccountNicknameParams{ID: account.ID, Nickname: "Rainy day"})).
+					Times(1).
+					Return(renamed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				renamed := account
+				renamed.Nickname = "Rainy day"
+				requireBodyMatchAccount(t, recorder.Body, renamed)
+			},
+		},
+		{
+			name: "NicknameTaken",
+			body: gin.H{"id": account.ID, "nickname": "Holiday"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					UpdateAccountNickname(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "ClosedAccount",
+			body: gin.H{"id": account.ID, "nickname": "Old"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				closed := account
+				closed.Status = db.AccountStatusClosed
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(closed, nil)
+				store.EXPECT().UpdateAccountNickname(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{"id": account.ID, "nickname": "Mine"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpdateAccountNickname(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts/nickname", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateAccountRequiresBankerApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name      string
+		setupAuth func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		updates   int
+		status    int
+	}{
+		{
+			name: "Owner",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "DelegatedCredential",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addConsentAuthorization(t, request, tokenMaker, user.Username, scopeAccountsWrite, account.ID)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "Banker",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, util.RandomOwner(), util.BankerRole)
+			},
+			updates: 1,
+			status:  http.StatusOK,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetOAuthConsent(gomock.Any(), gomock.Any()).AnyTimes().Return(db.OauthConsent{}, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(tc.updates).Return(account, nil)
+			store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Any()).Times(tc.updates).Return(account, nil)
+			store.EXPECT().CreateEntry(gomock.Any(), gomock.Any()).Times(tc.updates)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"id": account.ID, "amount": "1000.00"})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts/update", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+)
+
+// API keys look like sbk_<prefix>_<secret>. The prefix is stored in clear
+// to find the key, only a hash of the secret is stored.
+const apiKeyTag = "sbk"
+
+func parseAPIKey(apiKey string) (prefix string, secret string, ok bool) {
+	parts := strings.Split(apiKey, "_")
+	if len(parts) != 3 || parts[0] != apiKeyTag || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+type apiKeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func newAPIKeyResponse(key db.ApiKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  nullTimePtr(key.ExpiresAt),
+		LastUsedAt: nullTimePtr(key.LastUsedAt),
+		RevokedAt:  nullTimePtr(key.RevokedAt),
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,scope"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type createAPIKeyResponse struct {
+	// Key is only returned once, when the key is created
+	Key    string         `json:"key"`
+	APIKey apiKeyResponse `json:"api_key"`
+}
+
+func (server *Server) createAPIKey(ctx *gin.Context) {
+	var req createAPIKeyRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		err := errors.New("expires_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	prefix, err := util.RandomSecret(4)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	secret, err := util.RandomSecret(24)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateAPIKeyParams{
+		Owner:     authPayload.Username,
+		Name:      req.Name,
+		Prefix:    prefix,
+		HashedKey: util.HashSecret(secret),
+		Scopes:    req.Scopes,
+	}
+
+	if req.ExpiresAt != nil {
+		arg.ExpiresAt = sql.NullTime{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	var key db.ApiKey
+
+	audit := newAuditParams(ctx, auditAPIKeyCreate, auditTargetAPIKey, "")
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		key, err = q.CreateAPIKey(ctx, arg)
+		audit.TargetID = strconv.FormatInt(key.ID, 10)
+		audit.After = newAPIKeyResponse(key)
+		return err
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := createAPIKeyResponse{
+		Key:    apiKeyTag + "_" + prefix + "_" + secret,
+		APIKey: newAPIKeyResponse(key),
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) listAPIKeys(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	keys, err := server.store.ListAPIKeys(ctx, authPayload.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]apiKeyResponse, len(keys))
+
+	for i, key := range keys {
+		rsp[i] = newAPIKeyResponse(key)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type revokeAPIKeyRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) revokeAPIKey(ctx *gin.Context) {
+	var req revokeAPIKeyRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.RevokeAPIKeyParams{
+		ID:    req.ID,
+		Owner: authPayload.Username,
+	}
+
+	var key db.ApiKey
+
+	audit := newAuditParams(ctx, auditAPIKeyRevoke, auditTargetAPIKey, strconv.FormatInt(req.ID, 10))
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		key, err = q.RevokeAPIKey(ctx, arg)
+		audit.After = newAPIKeyResponse(key)
+		return err
+	})
+
+	if err != nil {
+
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAPIKeyResponse(key))
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// Audited actions
+const (
+	auditUserCreate            = "user.create"
+	auditUserDeactivate        = "user.deactivate"
+	auditUserPasswordUpdate    = "user.password_update"
+	auditUserPasswordForgot    = "user.password_forgot"
+	auditUserPasswordReset     = "user.password_reset"
+	auditAccountCreate         = "account.create"
+	auditAccountUpdate         = "account.update"
+	auditAccountClose          = "account.close"
+	auditAccountRename         = "account.rename"
+	auditAccountFreeze         = "account.freeze"
+	auditAccountUnfreeze       = "account.unfreeze"
+	auditTransferCreate        = "transfer.create"
+	auditTransferReviewCreate  = "transfer_review.create"
+	auditTransferBatchCreate   = "transfer_batch.create"
+	auditTransferReviewApprove = "transfer_review.approve"
+	auditTransferReviewReject  = "transfer_review.reject"
+	auditPayeeCreate           = "payee.create"
+	auditPayeeUpdate           = "payee.update"
+	auditPayeeDelete           = "payee.delete"
+	auditPaymentRequestCreate  = "payment_request.create"
+	auditPaymentRequestDecline = "payment_request.decline"
+	auditPaymentRequestPay     = "payment_request.pay"
+	auditAPIKeyCreate          = "api_key.create"
+	auditAPIKeyRevoke          = "api_key.revoke"
+	auditOAuthClientCreate     = "oauth_client.create"
+	auditOAuthConsentCreate    = "oauth_consent.create"
+	auditOAuthConsentRevoke    = "oauth_consent.revoke"
+	auditCurrencyEnable        = "currency.enable"
+	auditCurrencyDisable       = "currency.disable"
+	auditInterestRateCreate    = "interest_rate.create"
+	auditFeeScheduleUpdate     = "fee_schedule.update"
+	auditStatementImportCreate = "statement_import.create"
+	auditBeneficiaryCreate     = "beneficiary.create"
+	auditBeneficiaryDelete     = "beneficiary.delete"
+	auditOutboundPaymentSettle = "outbound_payment.settle"
+	auditOutboundPaymentReturn = "outbound_payment.return"
+	auditPaymentExportCreate   = "payment_export.create"
+	auditPaymentReportApply    = "payment_export.status_report"
+)
+
+// Types of audited targets
+const (
+	auditTargetUser           = "user"
+	auditTargetAccount        = "account"
+	auditTargetTransfer       = "transfer"
+	auditTargetTransferReview = "transfer_review"
+	auditTargetTransferBatch  = "transfer_batch"
+	auditTargetPayee          = "payee"
+	auditTargetPaymentRequest = "payment_request"
+	auditTargetAPIKey         = "api_key"
+	auditTargetOAuthClient    = "oauth_client"
+	auditTargetOAuthConsent   = "oauth_consent"
+	auditTargetCurrency       = "currency"
+	auditTargetInterestRate   = "interest_rate"
+	auditTargetFeeSchedule    = "fee_schedule"
+	auditTargetStatement      = "statement_import"
+	auditTargetBeneficiary    = "beneficiary"
+	auditTargetOutbound       = "outbound_payment"
+	auditTargetPaymentExport  = "payment_export"
+)
+
+// newAuditParams describes a change made by the current request. The actor is
+// the authenticated user; unauthenticated routes set it themselves.
+func newAuditParams(ctx *gin.Context, action string, targetType string, targetID string) db.AuditParams {
+	audit := db.AuditParams{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		RequestID:  ctx.GetString(requestIDKey),
+		IP:         ctx.ClientIP(),
+	}
+
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		audit.Actor = payload.(*token.Payload).Username
+	}
+
+	return audit
+}
+
+type listAuditEventsRequest struct {
+	Actor      string    `form:"actor"`
+	Action     string    `form:"action"`
+	TargetType string    `form:"target_type"`
+	TargetID   string    `form:"target_id"`
+	From       time.Time `form:"from"`
+	To         time.Time `form:"to"`
+	PageID     int32     `form:"page_id" binding:"required,min=1"`
+	PageSize   int32     `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// listAuditEvents lets bankers search the audit log, newest first
+func (server *Server) listAuditEvents(ctx *gin.Context) {
+	var req listAuditEventsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListAuditEventsParams{
+		Actor:       nullString(req.Actor),
+		Action:      nullString(req.Action),
+		TargetType:  nullString(req.TargetType),
+		TargetID:    nullString(req.TargetID),
+		CreatedFrom: nullTime(req.From),
+		CreatedTo:   nullTime(req.To),
+		Limit:       req.PageSize,
+		Offset:      (req.PageID - 1) * req.PageSize,
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// allowAuditTx lets handlers run AuditTx, calling the callback with the mock
+// store itself so the queries inside it can be stubbed as usual
+func allowAuditTx(store *mockdb.MockStore) {
+	store.EXPECT().
+		AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, audit db.AuditParams, fn func(db.Querier, *db.AuditParams) error) error {
+			return fn(store, &audit)
+		})
+}
+
+type eqAuditedParamsMatcher struct {
+	arg    interface{}
+	action string
+}
+
+func (e eqAuditedParamsMatcher) Matches(x interface{}) bool {
+	if reflect.TypeOf(x) != reflect.TypeOf(e.arg) {
+		return false
+	}
+
+	arg := reflect.New(reflect.TypeOf(x)).Elem()
+	arg.Set(reflect.ValueOf(x))
+
+	audit := arg.FieldByName("Audit")
+	if audit.Interface().(db.AuditParams).Action != e.action {
+		return false
+	}
+	audit.Set(reflect.Zero(audit.Type()))
+
+	return reflect.DeepEqual(e.arg, arg.Interface())
+}
+
+func (e eqAuditedParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg: %v audited as %s", e.arg, e.action)
+}
+
+// EqAuditedParams matches tx params whose Audit records action, comparing the
+// other fields with arg
+func EqAuditedParams(arg interface{}, action string) gomock.Matcher {
+	return eqAuditedParamsMatcher{arg, action}
+}
+
+func randomAuditEvent() db.AuditEvent {
+	return db.AuditEvent{
+		ID:         util.RandomInt(1, 1000),
+		Actor:      util.RandomOwner(),
+		Action:     auditAccountUpdate,
+		TargetType: auditTargetAccount,
+		TargetID:   fmt.Sprint(util.RandomInt(1, 1000)),
+		RequestID:  util.RandomString(16),
+		Ip:         "192.0.2.1",
+		Before:     json.RawMessage(`{"balance":10}`),
+		After:      json.RawMessage(`{"balance":20}`),
+		PrevHash:   util.RandomString(64),
+		Hash:       util.RandomString(64),
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestListAuditEventsApi(t *testing.T) {
+	banker := util.RandomOwner()
+	event := randomAuditEvent()
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: url.Values{
+				"actor":     {event.Actor},
+				"from":      {"2024-01-02T00:00:00Z"},
+				"page_id":   {"2"},
+				"page_size": {"5"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAuditEventsParams{
+					Actor:       sql.NullString{String: event.Actor, Valid: true},
+					CreatedFrom: sql.NullTime{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+					Limit:       5,
+					Offset:      5,
+				}
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, got db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+						require.Equal(t, arg.Actor, got.Actor)
+						require.False(t, got.Action.Valid)
+						require.True(t, arg.CreatedFrom.Time.Equal(got.CreatedFrom.Time))
+						require.False(t, got.CreatedTo.Valid)
+						require.Equal(t, arg.Limit, got.Limit)
+						require.Equal(t, arg.Offset, got.Offset)
+						return []db.AuditEvent{event}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var events []db.AuditEvent
+				err := json.Unmarshal(recorder.Body.Bytes(), &events)
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, event.Hash, events[0].Hash)
+				require.JSONEq(t, string(event.After), string(events[0].After))
+			},
+		},
+		{
+			name:  "NotBanker",
+			query: url.Values{"page_id": {"1"}, "page_size": {"5"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: url.Values{"page_id": {"1"}, "page_size": {"1000"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/audit-events?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateAccountAudit(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+	updated := account
+	updated.Balance += 10
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, audit db.AuditParams, fn func(db.Querier, *db.AuditParams) error) error {
+			require.Equal(t, banker, audit.Actor)
+			require.Equal(t, auditAccountUpdate, audit.Action)
+			require.Equal(t, auditTargetAccount, audit.TargetType)
+			require.Equal(t, fmt.Sprint(account.ID), audit.TargetID)
+			require.Equal(t, "request-1", audit.RequestID)
+			require.Equal(t, account, audit.Before)
+
+			err := fn(store, &audit)
+			require.Equal(t, updated, audit.After)
+			return err
+		})
+	store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Any()).Times(1).Return(updated, nil)
+	store.EXPECT().
+		CreateEntry(gomock.Any(), gomock.Eq(db.CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    1000,
+			EntryType: db.EntryTypeAdjustment,
+		})).
+		Times(1)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(map[string]int64{"id": account.ID, "amount": 10})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/accounts/update", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeaderKey, "request-1")
+
+	addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "request-1", recorder.Header().Get(requestIDHeaderKey))
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var errBeneficiaryAccount = errors.New("either iban, or account_number with bic or aba_routing, is required")
+
+// beneficiaryResponse is an account at another bank the caller pays out to
+type beneficiaryResponse struct {
+	ID            int64     `json:"id"`
+	Nickname      string    `json:"nickname"`
+	Name          string    `json:"name"`
+	IBAN          string    `json:"iban,omitempty"`
+	AccountNumber string    `json:"account_number,omitempty"`
+	BIC           string    `json:"bic,omitempty"`
+	ABARouting    string    `json:"aba_routing,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newBeneficiaryResponse(beneficiary db.ExternalBeneficiary) beneficiaryResponse {
+	return beneficiaryResponse{
+		ID:            beneficiary.ID,
+		Nickname:      beneficiary.Nickname,
+		Name:          beneficiary.Name,
+		IBAN:          beneficiary.Iban,
+		AccountNumber: beneficiary.AccountNumber,
+		BIC:           beneficiary.Bic,
+		ABARouting:    beneficiary.AbaRouting,
+		CreatedAt:     beneficiary.CreatedAt,
+	}
+}
+
+// createBeneficiaryRequest adds an account at another bank, given by its
+// IBAN or, where IBANs aren't used, by its account number and the BIC or US
+// ABA routing number of the bank. IBANs may be written with spaces, and IBANs
+// and BICs in lower case.
+type createBeneficiaryRequest struct {
+	Nickname      string `json:"nickname" binding:"required,max=50"`
+	Name          string `json:"name" binding:"required,max=70"`
+	IBAN          string `json:"iban" binding:"omitempty,iban"`
+	AccountNumber string `json:"account_number" binding:"omitempty,alphanum,max=34"`
+	BIC           string `json:"bic" binding:"omitempty,bic"`
+	ABARouting    string `json:"aba_routing" binding:"omitempty,aba_routing"`
+}
+
+func (server *Server) createBeneficiary(ctx *gin.Context) {
+	var req createBeneficiaryRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	byIBAN := req.IBAN != "" && req.AccountNumber == ""
+	byAccountNumber := req.IBAN == "" && req.AccountNumber != "" && (req.BIC != "" || req.ABARouting != "")
+
+	if !byIBAN && !byAccountNumber {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errBeneficiaryAccount))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateExternalBeneficiaryParams{
+		Owner:         authPayload.Username,
+		Nickname:      req.Nickname,
+		Name:          req.Name,
+		Iban:          util.NormalizeIBAN(req.IBAN),
+		AccountNumber: req.AccountNumber,
+		Bic:           util.NormalizeBIC(req.BIC),
+		AbaRouting:    req.ABARouting,
+	}
+
+	var beneficiary db.ExternalBeneficiary
+
+	audit := newAuditParams(ctx, auditBeneficiaryCreate, auditTargetBeneficiary, "")
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		beneficiary, err = q.CreateExternalBeneficiary(ctx, arg)
+		audit.TargetID = strconv.FormatInt(beneficiary.ID, 10)
+		audit.After = beneficiary
+		return err
+	})
+
+	if err != nil {
+		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBeneficiaryResponse(beneficiary))
+}
+
+type getBeneficiaryRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getBeneficiary(ctx *gin.Context) {
+	var req getBeneficiaryRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	beneficiary, err := server.store.GetExternalBeneficiary(ctx, db.GetExternalBeneficiaryParams{
+		ID:    req.ID,
+		Owner: authPayload.Username,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBeneficiaryResponse(beneficiary))
+}
+
+type listBeneficiariesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+func (server *Server) listBeneficiaries(ctx *gin.Context) {
+	var req listBeneficiariesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	beneficiaries, err := server.store.ListExternalBeneficiaries(ctx, db.ListExternalBeneficiariesParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]beneficiaryResponse, len(beneficiaries))
+
+	for i, beneficiary := range beneficiaries {
+		rsp[i] = newBeneficiaryResponse(beneficiary)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// deleteBeneficiary removes a beneficiary. Payments already made to it keep
+// the details they were sent with.
+func (server *Server) deleteBeneficiary(ctx *gin.Context) {
+	var req getBeneficiaryRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	audit := newAuditParams(ctx, auditBeneficiaryDelete, auditTargetBeneficiary, strconv.FormatInt(req.ID, 10))
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		beneficiary, err := q.DeleteExternalBeneficiary(ctx, db.DeleteExternalBeneficiaryParams{
+			ID:    req.ID,
+			Owner: authPayload.Username,
+		})
+		audit.Before = beneficiary
+		return err
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "beneficiary deleted"})
+}
+
+// transferBeneficiary looks up the caller's beneficiary a transfer is paid
+// out to and returns the settlement account that receives the transfer in
+// currency. It writes the response and returns false when the transfer can't
+// be made.
+func (server *Server) transferBeneficiary(ctx *gin.Context, beneficiaryID int64, owner string, currency string) (int64, bool) {
+	_, err := server.store.GetExternalBeneficiary(ctx, db.GetExternalBeneficiaryParams{
+		ID:    beneficiaryID,
+		Owner: owner,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return 0, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return 0, false
+	}
+
+	settlement, err := server.store.GetSystemAccount(ctx, db.GetSystemAccountParams{
+		Purpose:  db.SystemAccountExternalSettlement,
+		Currency: currency,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(db.ErrNoExternalSettlement))
+			return 0, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return 0, false
+	}
+
+	return settlement.AccountID, true
+}
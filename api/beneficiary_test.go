@@ -0,0 +1,389 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBeneficiary(owner string) db.ExternalBeneficiary {
+	return db.ExternalBeneficiary{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     owner,
+		Nickname:  util.RandomOwner(),
+		Name:      util.RandomOwner(),
+		Iban:      "GB82WEST12345698765432",
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestCreateBeneficiaryApi(t *testing.T) {
+	user := util.RandomOwner()
+	beneficiary := randomBeneficiary(user)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "IBAN",
+			body: gin.H{
+				"nickname": beneficiary.Nickname,
+				"name":     beneficiary.Name,
+				"iban":     "gb82 west 1234 5698 7654 32",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateExternalBeneficiary(gomock.Any(), gomock.Eq(db.CreateExternalBeneficiaryParams{
+						Owner:    user,
+						Nickname: beneficiary.Nickname,
+						Name:     beneficiary.Name,
+						Iban:     beneficiary.Iban,
+					})).
+					Times(1).
+					Return(beneficiary, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res beneficiaryResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, beneficiary.ID, res.ID)
+				require.Equal(t, beneficiary.Iban, res.IBAN)
+			},
+		},
+		{
+			name: "ABARouting",
+			body: gin.H{
+				"nickname":       beneficiary.Nickname,
+				"name":           beneficiary.Name,
+				"account_number": "000123456789",
+				"aba_routing":    "021000021",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateExternalBeneficiary(gomock.Any(), gomock.Eq(db.CreateExternalBeneficiaryParams{
+						Owner:         user,
+						Nickname:      beneficiary.Nickname,
+						Name:          beneficiary.Name,
+						AccountNumber: "000123456789",
+						AbaRouting:    "021000021",
+					})).
+					Times(1).
+					Return(beneficiary, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "LowerCaseBIC",
+			body: gin.H{
+				"nickname":       beneficiary.Nickname,
+				"name":           beneficiary.Name,
+				"account_number": "000123456789",
+				"bic":            "deutdeff500",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateExternalBeneficiary(gomock.Any(), gomock.Eq(db.CreateExternalBeneficiaryParams{
+						Owner:         user,
+						Nickname:      beneficiary.Nickname,
+						Name:          beneficiary.Name,
+						AccountNumber: "000123456789",
+						Bic:           "DEUTDEFF500",
+					})).
+					Times(1).
+					Return(beneficiary, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidIBAN",
+			body: gin.H{
+				"nickname": beneficiary.Nickname,
+				"name":     beneficiary.Name,
+				"iban":     "GB82WEST12345698765431",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExternalBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidABARouting",
+			body: gin.H{
+				"nickname":       beneficiary.Nickname,
+				"name":           beneficiary.Name,
+				"account_number": "000123456789",
+				"aba_routing":    "021000022",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExternalBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidBIC",
+			body: gin.H{
+				"nickname":       beneficiary.Nickname,
+				"name":           beneficiary.Name,
+				"account_number": "000123456789",
+				"bic":            "DEUT1",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExternalBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNumberWithoutBank",
+			body: gin.H{
+				"nickname":       beneficiary.Nickname,
+				"name":           beneficiary.Name,
+				"account_number": "000123456789",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExternalBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "IBANAndAccountNumber",
+			body: gin.H{
+				"nickname":       beneficiary.Nickname,
+				"name":           beneficiary.Name,
+				"iban":           beneficiary.Iban,
+				"account_number": "000123456789",
+				"bic":            "DEUTDEFF",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExternalBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Duplicate",
+			body: gin.H{
+				"nickname": beneficiary.Nickname,
+				"name":     beneficiary.Name,
+				"iban":     beneficiary.Iban,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateExternalBeneficiary(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExternalBeneficiary{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/beneficiaries", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTransferToBeneficiaryApi(t *testing.T) {
+	user, _ := randomUser(t)
+
+	account := randomAccount(user.Username)
+	account.Currency = util.USD
+
+	beneficiary := randomBeneficiary(user.Username)
+	settlement := db.SystemAccount{
+		Purpose:   db.SystemAccountExternalSettlement,
+		Currency:  util.USD,
+		AccountID: util.RandomInt(1001, 2000),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"from_account_id": account.ID,
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          "0.10",
+				"currency":        util.USD,
+				"memo":            "Invoice 1",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetExternalBeneficiary(gomock.Any(), gomock.Eq(db.GetExternalBeneficiaryParams{ID: beneficiary.ID, Owner: user.Username})).
+					Times(1).
+					Return(beneficiary, nil)
+				store.EXPECT().
+					GetSystemAccount(gomock.Any(), gomock.Eq(db.GetSystemAccountParams{Purpose: db.SystemAccountExternalSettlement, Currency: util.USD})).
+					Times(1).
+					Return(settlement, nil)
+
+				arg := db.TransferTxParams{
+					FromAccID:     account.ID,
+					ToAccID:       settlement.AccountID,
+					Amount:        10,
+					Memo:          "Invoice 1",
+					BeneficiaryID: beneficiary.ID,
+				}
+				payment := db.OutboundPayment{
+					ID:         util.RandomInt(1, 1000),
+					AccountID:  account.ID,
+					Amount:     10,
+					Currency:   util.USD,
+					EndToEndID: db.OutboundEndToEndID(1),
+					Status:     db.OutboundPaymentInitiated,
+				}
+				store.EXPECT().
+					TransferTx(gomock.Any(), EqAuditedParams(arg, auditTransferCreate)).
+					Times(1).
+					Return(db.TransferTxResult{FromAccount: account, OutboundPayment: &payment}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+
+				payment, ok := res["outbound_payment"].(map[string]any)
+				require.True(t, ok)
+				require.Equal(t, db.OutboundPaymentInitiated, payment["status"])
+				require.Equal(t, "0.10", payment["amount"])
+			},
+		},
+		{
+			name: "BeneficiaryNotFound",
+			body: gin.H{
+				"from_account_id": account.ID,
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          "0.10",
+				"currency":        util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetExternalBeneficiary(gomock.Any(), gomock.Any()).Times(1).Return(db.ExternalBeneficiary{}, sql.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoSettlementAccount",
+			body: gin.H{
+				"from_account_id": account.ID,
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          "0.10",
+				"currency":        util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetExternalBeneficiary(gomock.Any(), gomock.Any()).Times(1).Return(beneficiary, nil)
+				store.EXPECT().GetSystemAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.SystemAccount{}, sql.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "PayeeAndBeneficiary",
+			body: gin.H{
+				"from_account_id": account.ID,
+				"payee_id":        util.RandomInt(1, 1000),
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          "0.10",
+				"currency":        util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
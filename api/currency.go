@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/gin-gonic/gin"
+)
+
+var errCurrencyDisabled = errors.New("currency is disabled, its accounts are read-only")
+
+// currencyRegistry caches the currencies table so validating a request
+// doesn't hit the database. The cache is reloaded once it is older than ttl;
+// a ttl of 0 keeps it until it is invalidated.
+type currencyRegistry struct {
+	store      db.Store
+	ttl        time.Duration
+	mu         sync.Mutex
+	currencies map[string]db.Currency
+	loadedAt   time.Time
+}
+
+func newCurrencyRegistry(store db.Store, ttl time.Duration) *currencyRegistry {
+	return &currencyRegistry{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// get returns a currency by its code. When the table can't be reloaded the
+// currencies loaded before are used.
+func (registry *currencyRegistry) get(ctx context.Context, code string) (db.Currency, bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.stale() {
+		currencies, err := registry.store.ListCurrencies(ctx)
+		if err != nil {
+			log.Printf("cannot load currencies: %v", err)
+		} else {
+			registry.set(currencies)
+		}
+	}
+
+	currency, ok := registry.currencies[code]
+	return currency, ok
+}
+
+// enabled reports whether accounts can be opened and used in a currency
+func (registry *currencyRegistry) enabled(ctx context.Context, code string) bool {
+	currency, ok := registry.get(ctx, code)
+	return ok && currency.Enabled
+}
+
+func (registry *currencyRegistry) stale() bool {
+	if registry.loadedAt.IsZero() {
+		return true
+	}
+	return registry.ttl > 0 && time.Since(registry.loadedAt) > registry.ttl
+}
+
+// set replaces the cached currencies and registers their minor units for
+// parsing and formatting amounts, the caller must hold the lock
+func (registry *currencyRegistry) set(currencies []db.Currency) {
+	registry.currencies = make(map[string]db.Currency, len(currencies))
+	for _, currency := range currencies {
+		registry.currencies[currency.Code] = currency
+		money.Register(currency.Code, int(currency.MinorUnits))
+	}
+	registry.loadedAt = time.Now()
+}
+
+// invalidate makes the next lookup reload the table
+func (registry *currencyRegistry) invalidate() {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.loadedAt = time.Time{}
+}
+
+// listCurrencies shows every currency the bank knows and whether it is enabled
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	currencies, err := server.store.ListCurrencies(ctx)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, currencies)
+}
+
+type currencyRequest struct {
+	Code string `uri:"code" binding:"required,len=3"`
+}
+
+// enableCurrency lets a banker open accounts in a currency
+func (server *Server) enableCurrency(ctx *gin.Context) {
+	server.setCurrencyEnabled(ctx, true)
+}
+
+// disableCurrency stops new accounts from being opened in a currency and
+// makes the existing ones read-only
+func (server *Server) disableCurrency(ctx *gin.Context) {
+	server.setCurrencyEnabled(ctx, false)
+}
+
+func (server *Server) setCurrencyEnabled(ctx *gin.Context, enabled bool) {
+	var req currencyRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	code := strings.ToUpper(req.Code)
+
+	action := auditCurrencyDisable
+	if enabled {
+		action = auditCurrencyEnable
+	}
+
+	var currency db.Currency
+	audit := newAuditParams(ctx, action, auditTargetCurrency, code)
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		before, err := q.GetCurrency(ctx, code)
+		if err != nil {
+			return err
+		}
+
+		currency, err = q.UpdateCurrencyEnabled(ctx, db.UpdateCurrencyEnabledParams{
+			Code:    code,
+			Enabled: enabled,
+		})
+		audit.Before = before
+		audit.After = currency
+		return err
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.currencies.invalidate()
+
+	ctx.JSON(http.StatusOK, currency)
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyRegistry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	usd := db.Currency{Code: util.USD, MinorUnits: 2, Enabled: true}
+	jpy := db.Currency{Code: "JPY", MinorUnits: 0, Enabled: false}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{usd, jpy}, nil)
+
+	registry := newCurrencyRegistry(store, time.Hour)
+	require.True(t, registry.enabled(ctx, util.USD))
+	require.False(t, registry.enabled(ctx, "JPY"))
+	require.False(t, registry.enabled(ctx, "XYZ"))
+
+	// the cache is used until it expires
+	currency, ok := registry.get(ctx, "JPY")
+	require.True(t, ok)
+	require.Equal(t, jpy, currency)
+
+	// after an invalidation the table is reloaded, and the old currencies are
+	// kept when that fails
+	registry.invalidate()
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return(nil, errors.New("db down"))
+	require.True(t, registry.enabled(ctx, util.USD))
+
+	jpy.Enabled = true
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{usd, jpy}, nil)
+	require.True(t, registry.enabled(ctx, "JPY"))
+}
+
+func TestCurrencyRegistryExpires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCurrencies(gomock.Any()).Times(2).Return([]db.Currency{{Code: util.USD, Enabled: true}}, nil)
+
+	registry := newCurrencyRegistry(store, time.Minute)
+	require.True(t, registry.enabled(ctx, util.USD))
+
+	registry.loadedAt = time.Now().Add(-2 * time.Minute)
+	require.True(t, registry.enabled(ctx, util.USD))
+	require.True(t, registry.enabled(ctx, util.USD))
+}
+
+func TestSetCurrencyEnabledApi(t *testing.T) {
+	banker := util.RandomOwner()
+	jpy := db.Currency{Code: "JPY", Name: "Yen", MinorUnits: 0, Enabled: false}
+
+	enabled := jpy
+	enabled.Enabled = true
+
+	testCases := []struct {
+		name          string
+		url           string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Enable",
+			url:  "/currencies/jpy/enable",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq("JPY")).Times(1).Return(jpy, nil)
+				store.EXPECT().
+					UpdateCurrencyEnabled(gomock.Any(), gomock.Eq(db.UpdateCurrencyEnabledParams{Code: "JPY", Enabled: true})).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{enabled}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res db.Currency
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.True(t, res.Enabled)
+
+				// the registry picks up the change
+				require.True(t, server.currencies.enabled(context.Background(), "JPY"))
+			},
+		},
+		{
+			name: "Disable",
+			url:  "/currencies/USD/disable",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				usd := db.Currency{Code: util.USD, MinorUnits: 2, Enabled: true}
+				disabled := usd
+				disabled.Enabled = false
+
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq(util.USD)).Times(1).Return(usd, nil)
+				store.EXPECT().
+					UpdateCurrencyEnabled(gomock.Any(), gomock.Eq(db.UpdateCurrencyEnabledParams{Code: util.USD, Enabled: false})).
+					Times(1).
+					Return(disabled, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EnableNewCurrency",
+			url:  "/currencies/XTS/enable",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// a currency added to the table with no ISO 4217 default
+				xts := db.Currency{Code: "XTS", Name: "Test", MinorUnits: 3}
+				enabled := xts
+				enabled.Enabled = true
+
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq("XTS")).Times(1).Return(xts, nil)
+				store.EXPECT().
+					UpdateCurrencyEnabled(gomock.Any(), gomock.Eq(db.UpdateCurrencyEnabledParams{Code: "XTS", Enabled: true})).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{enabled}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.True(t, server.currencies.enabled(context.Background(), "XTS"))
+
+				// amounts use the minor units of the table
+				amount, err := money.Parse("1.234", "XTS")
+				require.NoError(t, err)
+				require.Equal(t, int64(1234), amount.Amount())
+				require.Equal(t, "1.234", amount.String())
+			},
+		},
+		{
+			name: "NotFound",
+			url:  "/currencies/GBP/disable",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Eq("GBP")).Times(1).Return(db.Currency{}, sql.ErrNoRows)
+				store.EXPECT().UpdateCurrencyEnabled(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotBanker",
+			url:  "/currencies/JPY/enable",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCurrency(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateCurrencyEnabled(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, tc.url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestDisabledCurrencyApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = "JPY"
+
+	t.Run("CreateAccount", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+
+		server := NewTestServer(t, store)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(gin.H{"currency": "JPY"})
+		require.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("UpdateAccount", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+		store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Any()).Times(0)
+
+		server := NewTestServer(t, store)
+		recorder := httptest.NewRecorder()
+
+		data, err := json.Marshal(gin.H{"id": account.ID, "amount": 100})
+		require.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/accounts/update", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		addAuthorizationWithRole(t, request, server.tokenMaker, util.RandomOwner(), util.BankerRole)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+
+	t.Run("GetAccount", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+		server := NewTestServer(t, store)
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", account.ID), nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
@@ -2,24 +2,115 @@ package api
 
 import (
 	"database/sql"
-	"log"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/token"
 	"github.com/gin-gonic/gin"
 )
 
+var errSearchAmountCurrency = errors.New("min_amount and max_amount need a currency")
+
+// entryResponse is an entry with the transfer that created it and the
+// account on the other side of that transfer. Fee entries aren't part of a
+// transfer; they carry the type of the fee and the transfer it was charged on
+// instead.
+type entryResponse struct {
+	ID                    int64       `json:"id"`
+	AccountID             int64       `json:"account_id"`
+	Amount                money.Money `json:"amount"`
+	Currency              string      `json:"currency"`
+	EntryType             string      `json:"entry_type"`
+	TransferID            *int64      `json:"transfer_id"`
+	Memo                  string      `json:"memo"`
+	Reference             string      `json:"reference"`
+	CounterpartyAccountID *int64      `json:"counterparty_account_id"`
+	CounterpartyOwner     string      `json:"counterparty_owner"`
+	FeeType               string      `json:"fee_type,omitempty"`
+	FeeTransferID         *int64      `json:"fee_transfer_id,omitempty"`
+	CreatedAt             time.Time   `json:"created_at"`
+}
+
+func nullInt64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
+func newEntryResponse(entry db.GetEntryRow) entryResponse {
+	return entryResponse{
+		ID:                    entry.ID,
+		AccountID:             entry.AccountID,
+		Amount:                money.New(entry.Amount, entry.Currency),
+		Currency:              entry.Currency,
+		EntryType:             entry.EntryType,
+		TransferID:            nullInt64Ptr(entry.TransferID),
+		Memo:                  entry.Memo.String,
+		Reference:             entry.Reference.String,
+		CounterpartyAccountID: nullInt64Ptr(entry.CounterpartyAccountID),
+		CounterpartyOwner:     entry.CounterpartyOwner.String,
+		FeeType:               entry.FeeType.String,
+		FeeTransferID:         nullInt64Ptr(entry.FeeTransferID),
+		CreatedAt:             entry.CreatedAt,
+	}
+}
+
+// newTransferEntryResponse describes an entry just made by transfer, with
+// counterparty the account on the other side
+func newTransferEntryResponse(entry db.Entry, transfer db.Transfer, counterparty db.Account) entryResponse {
+	return entryResponse{
+		ID:                    entry.ID,
+		AccountID:             entry.AccountID,
+		Amount:                money.New(entry.Amount, counterparty.Currency),
+		Currency:              counterparty.Currency,
+		EntryType:             entry.EntryType,
+		TransferID:            nullInt64Ptr(entry.TransferID),
+		Memo:                  transfer.Memo,
+		Reference:             transfer.Reference,
+		CounterpartyAccountID: &counterparty.ID,
+		CounterpartyOwner:     counterparty.Owner,
+		CreatedAt:             entry.CreatedAt,
+	}
+}
+
+// searchEntriesRequest searches the caller's entries. The amounts are
+// decimals in currency, which they need, and only entries in that currency
+// are searched when it is given.
 type searchEntriesRequest struct {
-	SearchQuery string `json:"search_query" binding:"required"`
-	Limit       int32  `json:"limit,omitempty"`
-	Offset      int32  `json:"offset,omitempty"`
-	OrderBy     string `json:"order_by,omitempty"`
-	Column1     string `json:"column_1,omitempty"`
-	MaxAmount   int64  `json:"max_amount,omitempty"`
-	MinAmount   int64  `json:"min_amount,omitempty"`
-	MaxDate     string `json:"max_date,omitempty"`
-	MinDate     string `json:"min_date,omitempty"`
+	SearchQuery string      `json:"search_query" binding:"required"`
+	Limit       int32       `json:"limit,omitempty"`
+	Offset      int32       `json:"offset,omitempty"`
+	OrderBy     string      `json:"order_by,omitempty"`
+	Column1     string      `json:"column_1,omitempty"`
+	Currency    string      `json:"currency,omitempty" binding:"omitempty,currency"`
+	MaxAmount   json.Number `json:"max_amount,omitempty"`
+	MinAmount   json.Number `json:"min_amount,omitempty"`
+	MaxDate     string      `json:"max_date,omitempty"`
+	MinDate     string      `json:"min_date,omitempty"`
+}
+
+// searchAmount is a bound of an entries search in minor units of currency,
+// or def when it isn't given
+func searchAmount(amount json.Number, currency string, def int64) (int64, error) {
+	if amount == "" {
+		return def, nil
+	}
+
+	if currency == "" {
+		return 0, errSearchAmountCurrency
+	}
+
+	m, err := money.Parse(amount.String(), currency)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.Amount(), nil
 }
 
 func (server *Server) searchEntries(ctx *gin.Context) {
@@ -30,8 +121,6 @@ func (server *Server) searchEntries(ctx *gin.Context) {
 		return
 	}
 
-	log.Println(req)
-
 	// validiate the search query
 	Column1 := "id"
 	Limit := int32(10)
@@ -59,13 +148,16 @@ func (server *Server) searchEntries(ctx *gin.Context) {
 		Column1 = req.Column1
 	}
 
-	if req.MaxAmount != 0 {
-		maxAmount = req.MaxAmount
+	if maxAmount, err = searchAmount(req.MaxAmount, req.Currency, maxAmount); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
 
-	if req.MinAmount != 0 {
-		minAmount = req.MinAmount
+	if minAmount, err = searchAmount(req.MinAmount, req.Currency, minAmount); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
+
 	layout := "2006-01-02T15:04:05Z"
 
 	if req.MaxDate != "" {
@@ -84,11 +176,18 @@ func (server *Server) searchEntries(ctx *gin.Context) {
 		}
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	// only the caller's entries are searched, and only the accounts of their
+	// consent when the token was issued for one
 	arg := db.SeachEntriesByAccountOwnerParams{
 		Field:       Column1,
 		Limit:       Limit,
 		Offset:      Offset,
 		SearchQuery: sql.NullString{String: req.SearchQuery, Valid: true},
+		Owner:       authPayload.Username,
+		AccountIds:  authPayload.AccountIDs,
+		Currency:    sql.NullString{String: req.Currency, Valid: req.Currency != ""},
 		MinAmount:   minAmount,
 		MaxAmount:   maxAmount,
 		StartDate:   startDate,
@@ -108,7 +207,13 @@ func (server *Server) searchEntries(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entries)
+	rsp := make([]entryResponse, len(entries))
+
+	for i, entry := range entries {
+		rsp[i] = newEntryResponse(db.GetEntryRow(entry))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 type ListEntryFromAccountIdRequest struct {
@@ -126,6 +231,10 @@ func (server *Server) listEntriesFromAccountId(ctx *gin.Context) {
 		return
 	}
 
+	if _, ok := server.ownedAccount(ctx, req.ID); !ok {
+		return
+	}
+
 	limit := int32(2)  // Set default limit
 	offset := int32(0) // Set default offset
 
@@ -155,7 +264,13 @@ func (server *Server) listEntriesFromAccountId(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entries)
+	rsp := make([]entryResponse, len(entries))
+
+	for i, entry := range entries {
+		rsp[i] = newEntryResponse(db.GetEntryRow(entry))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 
 }
 
@@ -184,5 +299,11 @@ func (server *Server) getEntry(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entry)
+	// the entry names the counterparty and carries the memo of its transfer,
+	// so only the holder of the account may see it
+	if _, ok := server.ownedAccount(ctx, entry.AccountID); !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newEntryResponse(entry))
 }
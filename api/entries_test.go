@@ -0,0 +1,350 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEntryApi(t *testing.T) {
+	user := util.RandomOwner()
+	account := randomAccount(user)
+	counterparty := randomAccount(util.RandomOwner())
+
+	entry := db.GetEntryRow{
+		ID:                    util.RandomInt(1, 1000),
+		AccountID:             account.ID,
+		Amount:                -10,
+		CreatedAt:             time.Now(),
+		TransferID:            sql.NullInt64{Int64: util.RandomInt(1, 1000), Valid: true},
+		EntryType:             db.EntryTypeTransferDebit,
+		Currency:              account.Currency,
+		Memo:                  sql.NullString{String: "lunch", Valid: true},
+		Reference:             sql.NullString{String: "", Valid: true},
+		CounterpartyAccountID: sql.NullInt64{Int64: counterparty.ID, Valid: true},
+		CounterpartyOwner:     sql.NullString{String: counterparty.Owner, Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Eq(entry.ID)).Times(1).Return(entry, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					entryResponse
+					Amount string `json:"amount"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "-0.10", res.Amount)
+				require.Equal(t, db.EntryTypeTransferDebit, res.EntryType)
+				require.Equal(t, account.Currency, res.Currency)
+				require.Equal(t, entry.TransferID.Int64, *res.TransferID)
+				require.Equal(t, counterparty.ID, *res.CounterpartyAccountID)
+				require.Equal(t, counterparty.Owner, res.CounterpartyOwner)
+				require.Equal(t, "lunch", res.Memo)
+			},
+		},
+		{
+			name: "Adjustment",
+			buildStubs: func(store *mockdb.MockStore) {
+				adjustment := db.GetEntryRow{
+					ID:        entry.ID,
+					AccountID: account.ID,
+					Amount:    25,
+					EntryType: db.EntryTypeAdjustment,
+					Currency:  account.Currency,
+				}
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Eq(entry.ID)).Times(1).Return(adjustment, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					entryResponse
+					Amount string `json:"amount"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "0.25", res.Amount)
+				require.Equal(t, db.EntryTypeAdjustment, res.EntryType)
+				require.Nil(t, res.TransferID)
+				require.Nil(t, res.CounterpartyAccountID)
+			},
+		},
+		{
+			name: "OtherUsersEntry",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Eq(entry.ID)).Times(1).Return(entry, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{ID: account.ID, Owner: util.RandomOwner()}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), counterparty.Owner)
+				require.NotContains(t, recorder.Body.String(), "lunch")
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEntry(gomock.Any(), gomock.Eq(entry.ID)).Times(1).Return(db.GetEntryRow{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/entries/%d", entry.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestEntryReadsAccountGrantApi(t *testing.T) {
+	user := util.RandomOwner()
+	account := randomAccount(user)
+	granted := randomAccount(user)
+	granted.ID = account.ID + 1
+	other := randomAccount(util.RandomOwner())
+	other.ID = account.ID + 2
+
+	testCases := []struct {
+		name          string
+		url           string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "ListOtherUsersAccount",
+			url:  "/entries",
+			body: gin.H{"id": other.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(other.ID)).Times(1).Return(other, nil)
+				store.EXPECT().ListEntryFromAccountId(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ListAccountNotGranted",
+			url:  "/entries",
+			body: gin.H{"id": account.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addConsentAuthorization(t, request, tokenMaker, user, scopeEntriesRead, granted.ID)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntryFromAccountId(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errAccountNotGranted.Error())
+			},
+		},
+		{
+			name: "ListGrantedAccount",
+			url:  "/entries",
+			body: gin.H{"id": granted.ID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addConsentAuthorization(t, request, tokenMaker, user, scopeEntriesRead, granted.ID)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(granted.ID)).Times(1).Return(granted, nil)
+				store.EXPECT().
+					ListEntryFromAccountId(gomock.Any(), gomock.Eq(db.ListEntryFromAccountIdParams{AccountID: granted.ID, Limit: 2})).
+					Times(1).
+					Return([]db.ListEntryFromAccountIdRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "SearchGrantedAccountsOnly",
+			url:  "/entries/search",
+			body: gin.H{"search_query": user},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addConsentAuthorization(t, request, tokenMaker, user, scopeEntriesRead, granted.ID)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SeachEntriesByAccountOwner(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SeachEntriesByAccountOwnerParams) ([]db.SeachEntriesByAccountOwnerRow, error) {
+						require.Equal(t, user, arg.Owner)
+						require.Equal(t, []int64{granted.ID}, arg.AccountIds)
+						return []db.SeachEntriesByAccountOwnerRow{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetOAuthConsent(gomock.Any(), gomock.Any()).AnyTimes().Return(db.OauthConsent{}, nil)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, tc.url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSearchEntriesAmountsApi(t *testing.T) {
+	user := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DecimalAmounts",
+			body: gin.H{"search_query": user, "currency": util.USD, "min_amount": "1.50", "max_amount": 20},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SeachEntriesByAccountOwner(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SeachEntriesByAccountOwnerParams) ([]db.SeachEntriesByAccountOwnerRow, error) {
+						require.Equal(t, int64(150), arg.MinAmount)
+						require.Equal(t, int64(2000), arg.MaxAmount)
+						require.Equal(t, sql.NullString{String: util.USD, Valid: true}, arg.Currency)
+						return []db.SeachEntriesByAccountOwnerRow{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoAmounts",
+			body: gin.H{"search_query": user},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SeachEntriesByAccountOwner(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SeachEntriesByAccountOwnerParams) ([]db.SeachEntriesByAccountOwnerRow, error) {
+						require.False(t, arg.Currency.Valid)
+						return []db.SeachEntriesByAccountOwnerRow{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AmountWithoutCurrency",
+			body: gin.H{"search_query": user, "min_amount": "1.50"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SeachEntriesByAccountOwner(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyDecimals",
+			body: gin.H{"search_query": user, "currency": util.USD, "max_amount": "1.505"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SeachEntriesByAccountOwner(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/entries/search", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errFeeAmountNegative = errors.New("fee amounts must not be negative")
+	errFeeMinAboveMax    = errors.New("min_amount must not be above max_amount")
+	errFeeTiersMethod    = errors.New("tiers are only used by the tiered method")
+)
+
+// feeResponse is a fee charged to an account
+type feeResponse struct {
+	ID         int64       `json:"id"`
+	AccountID  int64       `json:"account_id"`
+	FeeType    string      `json:"fee_type"`
+	Amount     money.Money `json:"amount"`
+	Currency   string      `json:"currency"`
+	TransferID *int64      `json:"transfer_id"`
+	EntryID    int64       `json:"entry_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+func newFeeResponses(fees []db.Fee, currency string) []feeResponse {
+	rsp := make([]feeResponse, len(fees))
+	for i, fee := range fees {
+		rsp[i] = feeResponse{
+			ID:         fee.ID,
+			AccountID:  fee.AccountID,
+			FeeType:    fee.FeeType,
+			Amount:     money.New(fee.Amount, currency),
+			Currency:   currency,
+			TransferID: nullInt64Ptr(fee.TransferID),
+			EntryID:    fee.EntryID,
+			CreatedAt:  fee.CreatedAt,
+		}
+	}
+	return rsp
+}
+
+// transferQuoteResponse is what a transfer would cost before it is made. The
+// fee is charged on top of the amount, from the same account.
+type transferQuoteResponse struct {
+	Amount   money.Money `json:"amount"`
+	Fee      money.Money `json:"fee"`
+	Total    money.Money `json:"total"`
+	Currency string      `json:"currency"`
+}
+
+func newTransferQuoteResponse(amount money.Money, quote db.FeeQuote) (transferQuoteResponse, error) {
+	fee := money.New(quote.Amount, amount.Currency())
+
+	total, err := amount.Add(fee)
+	if err != nil {
+		return transferQuoteResponse{}, err
+	}
+
+	return transferQuoteResponse{
+		Amount:   amount,
+		Fee:      fee,
+		Total:    total,
+		Currency: amount.Currency(),
+	}, nil
+}
+
+type feeTierResponse struct {
+	UpTo       *money.Money `json:"up_to"`
+	FlatAmount money.Money  `json:"flat_amount"`
+	RateBps    int32        `json:"rate_bps"`
+}
+
+// feeScheduleResponse is the fee of a type charged on accounts of a product
+// in a currency, with its amounts as decimals in that currency. Min and max
+// amounts of 0 don't apply.
+type feeScheduleResponse struct {
+	ID         int64             `json:"id"`
+	FeeType    string            `json:"fee_type"`
+	Product    string            `json:"product"`
+	Currency   string            `json:"currency"`
+	Method     string            `json:"method"`
+	FlatAmount money.Money       `json:"flat_amount"`
+	RateBps    int32             `json:"rate_bps"`
+	MinAmount  money.Money       `json:"min_amount"`
+	MaxAmount  money.Money       `json:"max_amount"`
+	Tiers      []feeTierResponse `json:"tiers"`
+	UpdatedBy  string            `json:"updated_by"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+func newFeeScheduleResponse(schedule db.FeeSchedule) feeScheduleResponse {
+	rsp := feeScheduleResponse{
+		ID:         schedule.ID,
+		FeeType:    schedule.FeeType,
+		Product:    schedule.Product,
+		Currency:   schedule.Currency,
+		Method:     schedule.Method,
+		FlatAmount: money.New(schedule.FlatAmount, schedule.Currency),
+		RateBps:    schedule.RateBps,
+		MinAmount:  money.New(schedule.MinAmount, schedule.Currency),
+		MaxAmount:  money.New(schedule.MaxAmount, schedule.Currency),
+		Tiers:      []feeTierResponse{},
+		UpdatedBy:  schedule.UpdatedBy,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+
+	// schedules are validated when they are saved, so only tiered ones have tiers
+	var tiers []db.FeeTier
+	_ = json.Unmarshal(schedule.Tiers, &tiers)
+
+	for _, tier := range tiers {
+		t := feeTierResponse{
+			FlatAmount: money.New(tier.FlatAmount, schedule.Currency),
+			RateBps:    tier.RateBps,
+		}
+		if tier.UpTo != 0 {
+			upTo := money.New(tier.UpTo, schedule.Currency)
+			t.UpTo = &upTo
+		}
+		rsp.Tiers = append(rsp.Tiers, t)
+	}
+
+	return rsp
+}
+
+// listFeeSchedules lists the fee schedules of every product and currency
+func (server *Server) listFeeSchedules(ctx *gin.Context) {
+	schedules, err := server.store.ListFeeSchedules(ctx)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]feeScheduleResponse, len(schedules))
+	for i, schedule := range schedules {
+		rsp[i] = newFeeScheduleResponse(schedule)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// feeTierRequest is a tier of a tiered schedule. Amounts are decimals in the
+// currency of the schedule, and the last tier has no up_to.
+type feeTierRequest struct {
+	UpTo       json.Number `json:"up_to"`
+	FlatAmount json.Number `json:"flat_amount"`
+	RateBps    int32       `json:"rate_bps" binding:"min=0,max=10000"`
+}
+
+// upsertFeeScheduleRequest sets the fee of a type for a product and
+// currency. Amounts are decimals in the currency.
+type upsertFeeScheduleRequest struct {
+	FeeType    string           `json:"fee_type" binding:"required,oneof=transfer maintenance"`
+	Product    string           `json:"product" binding:"required"`
+	Currency   string           `json:"currency" binding:"required,currency"`
+	Method     string           `json:"method" binding:"required,oneof=flat percentage tiered"`
+	FlatAmount json.Number      `json:"flat_amount"`
+	RateBps    int32            `json:"rate_bps" binding:"min=0,max=10000"`
+	MinAmount  json.Number      `json:"min_amount"`
+	MaxAmount  json.Number      `json:"max_amount"`
+	Tiers      []feeTierRequest `json:"tiers" binding:"max=20,dive"`
+}
+
+// feeAmount parses an optional fee amount in currency, 0 when it is left out
+func feeAmount(amount json.Number, currency string) (int64, error) {
+	if amount == "" {
+		return 0, nil
+	}
+
+	m, err := money.Parse(amount.String(), currency)
+	if err != nil {
+		return 0, err
+	}
+
+	if m.IsNegative() {
+		return 0, errFeeAmountNegative
+	}
+
+	return m.Amount(), nil
+}
+
+// feeScheduleParams converts a request to the parameters of the upsert,
+// checking the amounts and tiers fit the method
+func feeScheduleParams(req upsertFeeScheduleRequest, updatedBy string) (db.UpsertFeeScheduleParams, error) {
+	arg := db.UpsertFeeScheduleParams{
+		FeeType:   req.FeeType,
+		Product:   req.Product,
+		Currency:  req.Currency,
+		Method:    req.Method,
+		RateBps:   req.RateBps,
+		Tiers:     json.RawMessage("[]"),
+		UpdatedBy: updatedBy,
+	}
+
+	var err error
+	if arg.FlatAmount, err = feeAmount(req.FlatAmount, req.Currency); err != nil {
+		return arg, err
+	}
+	if arg.MinAmount, err = feeAmount(req.MinAmount, req.Currency); err != nil {
+		return arg, err
+	}
+	if arg.MaxAmount, err = feeAmount(req.MaxAmount, req.Currency); err != nil {
+		return arg, err
+	}
+
+	if arg.MaxAmount > 0 && arg.MinAmount > arg.MaxAmount {
+		return arg, errFeeMinAboveMax
+	}
+
+	if req.Method != db.FeeMethodTiered {
+		if len(req.Tiers) > 0 {
+			return arg, errFeeTiersMethod
+		}
+		return arg, nil
+	}
+
+	tiers := make([]db.FeeTier, len(req.Tiers))
+	for i, tier := range req.Tiers {
+		tiers[i].RateBps = tier.RateBps
+		if tiers[i].UpTo, err = feeAmount(tier.UpTo, req.Currency); err != nil {
+			return arg, err
+		}
+		if tiers[i].FlatAmount, err = feeAmount(tier.FlatAmount, req.Currency); err != nil {
+			return arg, err
+		}
+	}
+
+	if arg.Tiers, err = json.Marshal(tiers); err != nil {
+		return arg, err
+	}
+
+	_, err = db.ParseFeeTiers(arg.Tiers)
+	return arg, err
+}
+
+// upsertFeeSchedule creates or replaces the fee of a type for a product and
+// currency. The new schedule applies to transfers made and maintenance fees
+// charged from then on.
+func (server *Server) upsertFeeSchedule(ctx *gin.Context) {
+	var req upsertFeeScheduleRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg, err := feeScheduleParams(req, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var schedule db.FeeSchedule
+	audit := newAuditParams(ctx, auditFeeScheduleUpdate, auditTargetFeeSchedule, "")
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		if _, err := q.GetAccountProduct(ctx, arg.Product); err != nil {
+			if err == sql.ErrNoRows {
+				return errUnknownProduct
+			}
+			return err
+		}
+
+		before, err := q.GetFeeSchedule(ctx, db.GetFeeScheduleParams{
+			FeeType:  arg.FeeType,
+			Product:  arg.Product,
+			Currency: arg.Currency,
+		})
+		switch {
+		case err == nil:
+			audit.Before = before
+		case err != sql.ErrNoRows:
+			return err
+		}
+
+		schedule, err = q.UpsertFeeSchedule(ctx, arg)
+		audit.TargetID = strconv.FormatInt(schedule.ID, 10)
+		audit.After = schedule
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, errUnknownProduct) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newFeeScheduleResponse(schedule))
+}
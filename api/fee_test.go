@@ -0,0 +1,299 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFeeSchedulesApi(t *testing.T) {
+	user, _ := randomUser(t)
+	schedule := db.FeeSchedule{
+		ID:       1,
+		FeeType:  db.FeeTypeTransfer,
+		Product:  db.ProductChecking,
+		Currency: util.USD,
+		Method:   db.FeeMethodTiered,
+		Tiers:    json.RawMessage(`[{"up_to": 10000, "flat_amount": 25, "rate_bps": 0}, {"flat_amount": 0, "rate_bps": 50}]`),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListFeeSchedules(gomock.Any()).Times(1).Return([]db.FeeSchedule{schedule}, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/fee-schedules", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res []struct {
+		Method string `json:"method"`
+		Tiers  []struct {
+			UpTo       *string `json:"up_to"`
+			FlatAmount string  `json:"flat_amount"`
+			RateBps    int32   `json:"rate_bps"`
+		} `json:"tiers"`
+	}
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Len(t, res[0].Tiers, 2)
+	require.Equal(t, "100.00", *res[0].Tiers[0].UpTo)
+	require.Equal(t, "0.25", res[0].Tiers[0].FlatAmount)
+	require.Nil(t, res[0].Tiers[1].UpTo)
+	require.Equal(t, int32(50), res[0].Tiers[1].RateBps)
+}
+
+func TestUpsertFeeScheduleApi(t *testing.T) {
+	banker := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Percentage",
+			body: gin.H{
+				"fee_type":    db.FeeTypeTransfer,
+				"product":     db.ProductChecking,
+				"currency":    util.USD,
+				"method":      db.FeeMethodPercentage,
+				"flat_amount": "0.25",
+				"rate_bps":    100,
+				"max_amount":  "10",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertFeeScheduleParams{
+					FeeType:    db.FeeTypeTransfer,
+					Product:    db.ProductChecking,
+					Currency:   util.USD,
+					Method:     db.FeeMethodPercentage,
+					FlatAmount: 25,
+					RateBps:    100,
+					MaxAmount:  1000,
+					Tiers:      json.RawMessage("[]"),
+					UpdatedBy:  banker,
+				}
+
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Eq(db.ProductChecking)).Times(1).Return(checkingProduct, nil)
+				store.EXPECT().GetFeeSchedule(gomock.Any(), gomock.Any()).Times(1).Return(db.FeeSchedule{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpsertFeeSchedule(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.FeeSchedule{ID: 1, Currency: util.USD, Method: arg.Method, FlatAmount: 25, RateBps: 100, MaxAmount: 1000, Tiers: arg.Tiers}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "0.25", res["flat_amount"])
+				require.Equal(t, "10.00", res["max_amount"])
+			},
+		},
+		{
+			name: "Tiered",
+			body: gin.H{
+				"fee_type": db.FeeTypeMaintenance,
+				"product":  db.ProductChecking,
+				"currency": util.USD,
+				"method":   db.FeeMethodTiered,
+				"tiers": []gin.H{
+					{"up_to": "1000", "flat_amount": "5"},
+					{"flat_amount": "0"},
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Any()).Times(1).Return(checkingProduct, nil)
+				store.EXPECT().GetFeeSchedule(gomock.Any(), gomock.Any()).Times(1).Return(db.FeeSchedule{ID: 1}, nil)
+				store.EXPECT().
+					UpsertFeeSchedule(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpsertFeeScheduleParams) (db.FeeSchedule, error) {
+						require.JSONEq(t, `[{"up_to": 100000, "flat_amount": 500, "rate_bps": 0}, {"flat_amount": 0, "rate_bps": 0}]`, string(arg.Tiers))
+						return db.FeeSchedule{ID: 1, Currency: util.USD, Method: arg.Method, Tiers: arg.Tiers}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "TiersOutOfOrder",
+			body: gin.H{
+				"fee_type": db.FeeTypeTransfer,
+				"product":  db.ProductChecking,
+				"currency": util.USD,
+				"method":   db.FeeMethodTiered,
+				"tiers": []gin.H{
+					{"up_to": "1000", "flat_amount": "5"},
+					{"up_to": "500", "flat_amount": "2"},
+					{"flat_amount": "1"},
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TiersOnFlatMethod",
+			body: gin.H{
+				"fee_type":    db.FeeTypeTransfer,
+				"product":     db.ProductChecking,
+				"currency":    util.USD,
+				"method":      db.FeeMethodFlat,
+				"flat_amount": "1",
+				"tiers":       []gin.H{{"flat_amount": "1"}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MinAboveMax",
+			body: gin.H{
+				"fee_type":   db.FeeTypeTransfer,
+				"product":    db.ProductChecking,
+				"currency":   util.USD,
+				"method":     db.FeeMethodPercentage,
+				"rate_bps":   100,
+				"min_amount": "5",
+				"max_amount": "1",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeAmount",
+			body: gin.H{
+				"fee_type":    db.FeeTypeTransfer,
+				"product":     db.ProductChecking,
+				"currency":    util.USD,
+				"method":      db.FeeMethodFlat,
+				"flat_amount": "-1",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownProduct",
+			body: gin.H{
+				"fee_type":    db.FeeTypeTransfer,
+				"product":     "brokerage",
+				"currency":    util.USD,
+				"method":      db.FeeMethodFlat,
+				"flat_amount": "1",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Eq("brokerage")).Times(1).Return(db.AccountProduct{}, sql.ErrNoRows)
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{
+				"fee_type":    db.FeeTypeTransfer,
+				"product":     db.ProductChecking,
+				"currency":    util.USD,
+				"method":      db.FeeMethodFlat,
+				"flat_amount": "1",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/fee-schedules", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
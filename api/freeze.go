@@ -0,0 +1,160 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// Reasons a banker can give for freezing an account
+const (
+	freezeReasonFraud           = "fraud_investigation"
+	freezeReasonAML             = "aml_review"
+	freezeReasonLegalOrder      = "legal_order"
+	freezeReasonCustomerRequest = "customer_request"
+	freezeReasonDeceased        = "deceased"
+	freezeReasonOther           = "other"
+)
+
+var freezeReasons = []string{
+	freezeReasonFraud,
+	freezeReasonAML,
+	freezeReasonLegalOrder,
+	freezeReasonCustomerRequest,
+	freezeReasonDeceased,
+	freezeReasonOther,
+}
+
+func isFreezeReason(reason string) bool {
+	for _, r := range freezeReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// freezeResponse is the frozen or unfrozen account and the freeze itself
+type freezeResponse struct {
+	Account accountResponse  `json:"account"`
+	Freeze  db.AccountFreeze `json:"freeze"`
+}
+
+func newFreezeResponse(result db.FreezeAccountTxResult) freezeResponse {
+	return freezeResponse{
+		Account: newAccountResponse(result.Account),
+		Freeze:  result.Freeze,
+	}
+}
+
+type freezeAccountRequest struct {
+	ID         int64  `json:"id" binding:"required,min=1"`
+	Mode       string `json:"mode" binding:"required,oneof=debit full"`
+	ReasonCode string `json:"reason_code" binding:"required,freeze_reason"`
+	Note       string `json:"note" binding:"max=500"`
+}
+
+// freezeAccount lets a banker block an account under investigation, either
+// only for outgoing money or in both directions
+func (server *Server) freezeAccount(ctx *gin.Context) {
+	var req freezeAccountRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.ReasonCode == freezeReasonOther && req.Note == "" {
+		err := errors.New("a note is required when the reason is other")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.FreezeAccountTxParams{
+		AccountID:  req.ID,
+		Mode:       req.Mode,
+		ReasonCode: req.ReasonCode,
+		Note:       req.Note,
+		FrozenBy:   authPayload.Username,
+		Audit:      newAuditParams(ctx, auditAccountFreeze, auditTargetAccount, strconv.FormatInt(req.ID, 10)),
+	}
+
+	result, err := server.store.FreezeAccountTx(ctx, arg)
+
+	if err != nil {
+		writeFreezeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newFreezeResponse(result))
+}
+
+type unfreezeAccountRequest struct {
+	ID int64 `json:"id" binding:"required,min=1"`
+}
+
+// unfreezeAccount lifts the freeze in force on an account
+func (server *Server) unfreezeAccount(ctx *gin.Context) {
+	var req unfreezeAccountRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.UnfreezeAccountTxParams{
+		AccountID: req.ID,
+		LiftedBy:  authPayload.Username,
+		Audit:     newAuditParams(ctx, auditAccountUnfreeze, auditTargetAccount, strconv.FormatInt(req.ID, 10)),
+	}
+
+	result, err := server.store.UnfreezeAccountTx(ctx, arg)
+
+	if err != nil {
+		writeFreezeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newFreezeResponse(result))
+}
+
+func writeFreezeError(ctx *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, db.ErrAccountAlreadyFrozen), errors.Is(err, db.ErrAccountNotFrozen):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrAccountClosed):
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// listAccountFreezes shows bankers every freeze put on an account, newest first
+func (server *Server) listAccountFreezes(ctx *gin.Context) {
+	var req getAccountRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	freezes, err := server.store.ListAccountFreezes(ctx, req.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, freezes)
+}
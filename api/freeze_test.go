@@ -0,0 +1,366 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomAccountFreeze(account db.Account, banker string) db.AccountFreeze {
+	return db.AccountFreeze{
+		ID:         util.RandomInt(1, 1000),
+		AccountID:  account.ID,
+		Mode:       db.FreezeModeDebit,
+		ReasonCode: freezeReasonFraud,
+		Note:       util.RandomString(20),
+		FrozenBy:   banker,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestFreezeAccountApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+	freeze := randomAccountFreeze(account, banker)
+
+	frozen := account
+	frozen.Status = db.AccountStatusFrozen
+	frozen.FreezeMode = freeze.Mode
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"id":          account.ID,
+				"mode":        freeze.Mode,
+				"reason_code": freeze.ReasonCode,
+				"note":        freeze.Note,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.FreezeAccountTxParams{
+					AccountID:  account.ID,
+					Mode:       freeze.Mode,
+					ReasonCode: freeze.ReasonCode,
+					Note:       freeze.Note,
+					FrozenBy:   banker,
+				}
+				store.EXPECT().
+					FreezeAccountTx(gomock.Any(), EqAuditedParams(arg, auditAccountFreeze)).
+					Times(1).
+					Return(db.FreezeAccountTxResult{Account: frozen, Freeze: freeze}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					Account struct {
+						Status     string `json:"status"`
+						FreezeMode string `json:"freeze_mode"`
+					} `json:"account"`
+					Freeze db.AccountFreeze `json:"freeze"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.AccountStatusFrozen, res.Account.Status)
+				require.Equal(t, db.FreezeModeDebit, res.Account.FreezeMode)
+				require.Equal(t, banker, res.Freeze.FrozenBy)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{
+				"id":          account.ID,
+				"mode":        freeze.Mode,
+				"reason_code": freeze.ReasonCode,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidReason",
+			body: gin.H{
+				"id":          account.ID,
+				"mode":        freeze.Mode,
+				"reason_code": "bored",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidMode",
+			body: gin.H{
+				"id":          account.ID,
+				"mode":        "credit",
+				"reason_code": freeze.ReasonCode,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OtherWithoutNote",
+			body: gin.H{
+				"id":          account.ID,
+				"mode":        freeze.Mode,
+				"reason_code": freezeReasonOther,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyFrozen",
+			body: gin.H{
+				"id":          account.ID,
+				"mode":        freeze.Mode,
+				"reason_code": freeze.ReasonCode,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.FreezeAccountTxResult{}, db.ErrAccountAlreadyFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{
+				"id":          account.ID,
+				"mode":        freeze.Mode,
+				"reason_code": freeze.ReasonCode,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.FreezeAccountTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts/freeze", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUnfreezeAccountApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UnfreezeAccountTxParams{
+					AccountID: account.ID,
+					LiftedBy:  banker,
+				}
+				store.EXPECT().
+					UnfreezeAccountTx(gomock.Any(), EqAuditedParams(arg, auditAccountUnfreeze)).
+					Times(1).
+					Return(db.FreezeAccountTxResult{Account: account}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFrozen",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UnfreezeAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.FreezeAccountTxResult{}, db.ErrAccountNotFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UnfreezeAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.FreezeAccountTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"id": account.ID})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts/unfreeze", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAccountFreezesApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+	freeze := randomAccountFreeze(account, banker)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAccountFreezes(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]db.AccountFreeze{freeze}, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d/freezes", account.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var freezes []db.AccountFreeze
+	err = json.Unmarshal(recorder.Body.Bytes(), &freezes)
+	require.NoError(t, err)
+	require.Len(t, freezes, 1)
+	require.Equal(t, freeze.ReasonCode, freezes[0].ReasonCode)
+}
+
+func TestUpdateFrozenAccountApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+	account.Status = db.AccountStatusFrozen
+	account.FreezeMode = db.FreezeModeDebit
+
+	testCases := []struct {
+		name    string
+		amount  int64
+		entries int
+		status  int
+	}{
+		{name: "DebitBlocked", amount: -10, entries: 0, status: http.StatusUnprocessableEntity},
+		{name: "CreditAllowed", amount: 10, entries: 1, status: http.StatusOK},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			updated := account
+			updated.Balance += tc.amount
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Any()).Times(1).Return(updated, nil)
+			store.EXPECT().CreateEntry(gomock.Any(), gomock.Any()).Times(tc.entries)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"id": account.ID, "amount": tc.amount})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts/update", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var errRateInPast = errors.New("interest rates can't take effect before today, interest may already have accrued")
+
+// interestRateResponse is a rate of an interest schedule, effective from a
+// UTC day
+type interestRateResponse struct {
+	ID            int64     `json:"id"`
+	Product       string    `json:"product"`
+	Currency      string    `json:"currency"`
+	RateBps       int32     `json:"rate_bps"`
+	EffectiveFrom string    `json:"effective_from"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newInterestRateResponse(rate db.InterestRate) interestRateResponse {
+	return interestRateResponse{
+		ID:            rate.ID,
+		Product:       rate.Product,
+		Currency:      rate.Currency,
+		RateBps:       rate.RateBps,
+		EffectiveFrom: rate.EffectiveFrom.Format(time.DateOnly),
+		CreatedBy:     rate.CreatedBy,
+		CreatedAt:     rate.CreatedAt,
+	}
+}
+
+type listInterestRatesRequest struct {
+	Product string `form:"product" binding:"required"`
+}
+
+// listInterestRates lists the interest schedule of a product in every
+// currency, past rates included
+func (server *Server) listInterestRates(ctx *gin.Context) {
+	var req listInterestRatesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rates, err := server.store.ListInterestRates(ctx, req.Product)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]interestRateResponse, len(rates))
+	for i, rate := range rates {
+		rsp[i] = newInterestRateResponse(rate)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type createInterestRateRequest struct {
+	Product       string `json:"product" binding:"required"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	RateBps       *int32 `json:"rate_bps" binding:"required,min=0,max=10000"`
+	EffectiveFrom string `json:"effective_from" binding:"required"`
+}
+
+// createInterestRate adds a rate to the schedule of a product. Rates can
+// only be set from today on, so interest already accrued is never wrong.
+func (server *Server) createInterestRate(ctx *gin.Context) {
+	var req createInterestRateRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	effectiveFrom, err := time.Parse(time.DateOnly, req.EffectiveFrom)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if effectiveFrom.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errRateInPast))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateInterestRateParams{
+		Product:       req.Product,
+		Currency:      req.Currency,
+		RateBps:       *req.RateBps,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     authPayload.Username,
+	}
+
+	var rate db.InterestRate
+	audit := newAuditParams(ctx, auditInterestRateCreate, auditTargetInterestRate, "")
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		if _, err := q.GetAccountProduct(ctx, arg.Product); err != nil {
+			if err == sql.ErrNoRows {
+				return errUnknownProduct
+			}
+			return err
+		}
+
+		var err error
+		rate, err = q.CreateInterestRate(ctx, arg)
+		audit.TargetID = strconv.FormatInt(rate.ID, 10)
+		audit.After = rate
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, errUnknownProduct) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newInterestRateResponse(rate))
+}
+
+// interestPostingResponse is interest paid into an account for the days
+// before period_end
+type interestPostingResponse struct {
+	PeriodEnd  string      `json:"period_end"`
+	Amount     money.Money `json:"amount"`
+	TransferID *int64      `json:"transfer_id"`
+	PostedAt   time.Time   `json:"posted_at"`
+}
+
+// accountInterestResponse is the interest an account has accrued since it
+// was last paid. Accrued is rounded down to whole minor units; the fraction
+// is paid once it adds up.
+type accountInterestResponse struct {
+	AccountID   int64                    `json:"account_id"`
+	Currency    string                   `json:"currency"`
+	RateBps     int32                    `json:"rate_bps"`
+	Accrued     money.Money              `json:"accrued"`
+	AccruedDays int64                    `json:"accrued_days"`
+	LastPosting *interestPostingResponse `json:"last_posting"`
+}
+
+// getAccountInterest shows the interest accrued on an account to date, not
+// yet posted, and the current rate of its product
+func (server *Server) getAccountInterest(ctx *gin.Context) {
+	var req getAccountRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.ownedAccount(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	rsp := accountInterestResponse{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+	}
+
+	rate, err := server.store.GetInterestRate(ctx, db.GetInterestRateParams{
+		Product:       account.Product,
+		Currency:      account.Currency,
+		EffectiveFrom: today,
+	})
+	switch {
+	case err == nil:
+		rsp.RateBps = rate.RateBps
+	case err != sql.ErrNoRows:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	unposted, err := server.store.GetUnpostedInterest(ctx, db.GetUnpostedInterestParams{
+		AccountID:   account.ID,
+		AccrualDate: today.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accrued := unposted.AccruedMicros
+	rsp.AccruedDays = unposted.Days
+
+	last, err := server.store.GetLastInterestPosting(ctx, account.ID)
+	switch {
+	case err == nil:
+		accrued += last.AccruedMicros - last.Amount*db.MicrosPerMinorUnit
+		rsp.LastPosting = &interestPostingResponse{
+			PeriodEnd:  last.PeriodEnd.Format(time.DateOnly),
+			Amount:     money.New(last.Amount, account.Currency),
+			TransferID: nullInt64Ptr(last.TransferID),
+			PostedAt:   last.CreatedAt,
+		}
+	case err != sql.ErrNoRows:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Accrued = money.New(accrued/db.MicrosPerMinorUnit, account.Currency)
+
+	ctx.JSON(http.StatusOK, rsp)
+}
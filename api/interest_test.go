@@ -0,0 +1,291 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountInterestApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	account.Currency = util.USD
+	account.Product = db.ProductSavings
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetInterestRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.InterestRate{Product: db.ProductSavings, Currency: util.USD, RateBps: 150}, nil)
+				store.EXPECT().
+					GetUnpostedInterest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetUnpostedInterestRow{AccruedMicros: 12_600_000, Days: 3}, nil)
+				// 0.7 of a cent was carried over from the last posting
+				store.EXPECT().
+					GetLastInterestPosting(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.InterestPosting{
+						AccountID:     account.ID,
+						PeriodEnd:     time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+						AccruedMicros: 250_700_000,
+						Amount:        250,
+						TransferID:    sql.NullInt64{Int64: 7, Valid: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					RateBps     int32  `json:"rate_bps"`
+					Accrued     string `json:"accrued"`
+					AccruedDays int64  `json:"accrued_days"`
+					LastPosting struct {
+						PeriodEnd string `json:"period_end"`
+						Amount    string `json:"amount"`
+					} `json:"last_posting"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, int32(150), res.RateBps)
+				require.Equal(t, "0.13", res.Accrued)
+				require.Equal(t, int64(3), res.AccruedDays)
+				require.Equal(t, "2026-10-01", res.LastPosting.PeriodEnd)
+				require.Equal(t, "2.50", res.LastPosting.Amount)
+			},
+		},
+		{
+			name: "NoInterest",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetInterestRate(gomock.Any(), gomock.Any()).Times(1).Return(db.InterestRate{}, sql.ErrNoRows)
+				store.EXPECT().GetUnpostedInterest(gomock.Any(), gomock.Any()).Times(1).Return(db.GetUnpostedInterestRow{}, nil)
+				store.EXPECT().GetLastInterestPosting(gomock.Any(), gomock.Any()).Times(1).Return(db.InterestPosting{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res map[string]interface{}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "0.00", res["accrued"])
+				require.Nil(t, res["last_posting"])
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUnpostedInterest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d/interest", account.ID), nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateInterestRateApi(t *testing.T) {
+	banker := util.RandomOwner()
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"product": db.ProductSavings, "currency": util.USD, "rate_bps": 175, "effective_from": tomorrow.Format(time.DateOnly)},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateInterestRateParams{
+					Product:       db.ProductSavings,
+					Currency:      util.USD,
+					RateBps:       175,
+					EffectiveFrom: tomorrow,
+					CreatedBy:     banker,
+				}
+
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Eq(db.ProductSavings)).Times(1).Return(savingsProduct, nil)
+				store.EXPECT().
+					CreateInterestRate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.InterestRate{ID: 1, Product: arg.Product, Currency: arg.Currency, RateBps: arg.RateBps, EffectiveFrom: tomorrow}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res interestRateResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, tomorrow.Format(time.DateOnly), res.EffectiveFrom)
+			},
+		},
+		{
+			name: "ZeroRate",
+			body: gin.H{"product": db.ProductSavings, "currency": util.USD, "rate_bps": 0, "effective_from": tomorrow.Format(time.DateOnly)},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Any()).Times(1).Return(savingsProduct, nil)
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(1).Return(db.InterestRate{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InThePast",
+			body: gin.H{"product": db.ProductSavings, "currency": util.USD, "rate_bps": 175, "effective_from": "2020-06-01"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDate",
+			body: gin.H{"product": db.ProductSavings, "currency": util.USD, "rate_bps": 175, "effective_from": "next week"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownProduct",
+			body: gin.H{"product": "brokerage", "currency": util.USD, "rate_bps": 175, "effective_from": tomorrow.Format(time.DateOnly)},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Eq("brokerage")).Times(1).Return(db.AccountProduct{}, sql.ErrNoRows)
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyScheduled",
+			body: gin.H{"product": db.ProductSavings, "currency": util.USD, "rate_bps": 175, "effective_from": tomorrow.Format(time.DateOnly)},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountProduct(gomock.Any(), gomock.Any()).Times(1).Return(savingsProduct, nil)
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(1).Return(db.InterestRate{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotBanker",
+			body: gin.H{"product": db.ProductSavings, "currency": util.USD, "rate_bps": 175, "effective_from": tomorrow.Format(time.DateOnly)},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/interest-rates", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// getJWKS publishes the public keys used to sign access tokens so that other
+// services can verify them. Symmetric makers have no public keys to share.
+func (server *Server) getJWKS(ctx *gin.Context) {
+	provider, ok := server.tokenMaker.(token.KeySetProvider)
+	if !ok {
+		ctx.JSON(http.StatusOK, token.JWKS{Keys: []token.JWK{}})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, provider.KeySet().JWKS())
+}
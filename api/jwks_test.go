@@ -0,0 +1,27 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJWKS(t *testing.T) {
+	server := NewTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var jwks token.JWKS
+	err = json.Unmarshal(recorder.Body.Bytes(), &jwks)
+	require.NoError(t, err)
+	require.Empty(t, jwks.Keys)
+}
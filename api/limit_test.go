@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferLimitExceeded(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			require.Equal(t, db.TransferLimits{
+				MaxPerTransfer: 500,
+				AccountDaily:   1000,
+				UserMonthly:    5000,
+			}, arg.Limits)
+			return db.TransferTxResult{}, &db.LimitExceededError{Limit: db.LimitAccountDaily, Max: 1000, Remaining: 30}
+		})
+
+	server := NewTestServer(t, store)
+	server.config.TransferMaxAmounts = util.CurrencyAmounts{util.USD: 500}
+	server.config.AccountDailyLimits = util.CurrencyAmounts{util.USD: 1000, util.EUR: 10}
+	server.config.UserMonthlyLimits = util.CurrencyAmounts{util.USD: 5000}
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          "0.40",
+		"currency":        util.USD,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+	var res struct {
+		Error     string `json:"error"`
+		Limit     string `json:"limit"`
+		Remaining string `json:"remaining"`
+	}
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Equal(t, db.LimitAccountDaily, res.Limit)
+	require.Equal(t, "0.30", res.Remaining)
+	require.Contains(t, res.Error, "0.30 remaining")
+}
+
+func TestGetAccountLimitsApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetAccountTransferTotals(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.GetAccountTransferTotalsParams) (db.GetAccountTransferTotalsRow, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, 1, arg.MonthStart.Day())
+						require.False(t, arg.DayStart.Before(arg.MonthStart))
+						return db.GetAccountTransferTotalsRow{Daily: 300, Monthly: 900}, nil
+					})
+				store.EXPECT().
+					GetUserTransferTotals(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetUserTransferTotalsRow{Daily: 400, Monthly: 1200}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				type limitUsage struct {
+					Limit     string `json:"limit"`
+					Used      string `json:"used"`
+					Remaining string `json:"remaining"`
+				}
+				var usage struct {
+					Currency       string     `json:"currency"`
+					AccountDaily   limitUsage `json:"account_daily"`
+					AccountMonthly limitUsage `json:"account_monthly"`
+					UserDaily      limitUsage `json:"user_daily"`
+					UserMonthly    limitUsage `json:"user_monthly"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &usage)
+				require.NoError(t, err)
+				require.Equal(t, account.Currency, usage.Currency)
+				require.Equal(t, limitUsage{Limit: "10.00", Used: "3.00", Remaining: "7.00"}, usage.AccountDaily)
+				require.Equal(t, limitUsage{Limit: "0.00", Used: "9.00", Remaining: "0.00"}, usage.AccountMonthly)
+				require.Equal(t, limitUsage{Limit: "0.00", Used: "4.00", Remaining: "0.00"}, usage.UserDaily)
+				require.Equal(t, limitUsage{Limit: "10.00", Used: "12.00", Remaining: "0.00"}, usage.UserMonthly)
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			username: "unauthorized_user",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountTransferTotals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			server.config.AccountDailyLimits = util.CurrencyAmounts{account.Currency: 1000}
+			server.config.UserMonthlyLimits = util.CurrencyAmounts{account.Currency: 1000}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d/limits", account.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
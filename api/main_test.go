@@ -15,13 +15,41 @@ import (
 
 func NewTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:        util.RandomString(32),
+		AccessTokenDuration:      time.Minute,
+		ElevatedTokenDuration:    time.Minute,
+		OAuthAccessTokenDuration: time.Minute,
+		OAuthCodeDuration:        time.Minute,
+		PasswordMinLength:        6,
+		PasswordHistorySize:      3,
+		PasswordResetDuration:    time.Minute,
+		PasswordResetURL:         "https://simplebank.example.com/reset-password",
+		StepUpThresholds: util.CurrencyAmounts{
+			util.USD: 1000,
+			util.EUR: 1000,
+			util.CAD: 1000,
+		},
+		StepUpMaxAuthAge:        5 * time.Minute,
+		TransferBatchMaxItems:   3,
+		PaymentRequestDuration:  time.Hour,
+		PaymentRequestMaxExpiry: 24 * time.Hour,
+		StatementMatchWindow:    72 * time.Hour,
+		PaymentExportMaxItems:   100,
+		NostroAccountName:       "Simple Bank",
+		NostroIBAN:              "DE89370400440532013000",
+		NostroBIC:               "COBADEFFXXX",
 	}
 
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
 
+	server.currencies.set([]db.Currency{
+		{Code: util.USD, Name: "US Dollar", MinorUnits: 2, Enabled: true},
+		{Code: util.EUR, Name: "Euro", MinorUnits: 2, Enabled: true},
+		{Code: util.CAD, Name: "Canadian Dollar", MinorUnits: 2, Enabled: true},
+		{Code: "JPY", Name: "Yen", MinorUnits: 0, Enabled: false},
+	})
+
 	return server
 }
 
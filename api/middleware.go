@@ -1,23 +1,62 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
 	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
+	apiKeyHeaderKey         = "x-api-key"
+	requestIDHeaderKey      = "x-request-id"
+	requestIDKey            = "request_id"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+// requestIDMiddleware tags every request with an id, reusing the one sent by a
+// proxy in X-Request-ID, and echoes it in the response so audit events can be
+// matched to requests.
+func requestIDMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeaderKey)
+
+		if len(requestID) == 0 || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Set(requestIDKey, requestID)
+		ctx.Header(requestIDHeaderKey, requestID)
+	}
+}
+
+// authMiddleware authenticates the request with either a bearer token or an
+// API key sent in the X-API-Key header.
+func authMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if apiKey := ctx.GetHeader(apiKeyHeaderKey); len(apiKey) != 0 {
+			payload, status, err := authenticateAPIKey(ctx, store, apiKey)
+			if err != nil {
+				ctx.AbortWithStatusJSON(status, errorResponse(err))
+				return
+			}
+
+			ctx.Set(authorizationPayloadKey, payload)
+			return
+		}
+
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+
 		if len(authorizationHeader) == 0 {
 			err := errors.New("authorization header is not provided")
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
@@ -25,6 +64,7 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		}
 
 		fields := strings.Fields(authorizationHeader)
+
 		if len(fields) != 2 {
 			err := errors.New("authorization header is not valid")
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
@@ -48,7 +88,109 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
+		// tokens issued to OAuth clients stop working once the user revokes the consent
+		if payload.ConsentID != 0 {
+			consent, err := store.GetOAuthConsent(ctx, payload.ConsentID)
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+
+			if consent.RevokedAt.Valid {
+				err := errors.New("consent has been revoked")
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+		}
+
 		ctx.Set(authorizationPayloadKey, payload)
+	}
+}
+
+// authenticateAPIKey looks the key up by its prefix, checks the secret and
+// returns a payload restricted to the key's scopes.
+func authenticateAPIKey(ctx *gin.Context, store db.Store, apiKey string) (*token.Payload, int, error) {
+	prefix, secret, ok := parseAPIKey(apiKey)
+	if !ok {
+		return nil, http.StatusUnauthorized, errors.New("api key is not valid")
+	}
+
+	key, err := store.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, http.StatusUnauthorized, errors.New("api key is not valid")
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if !util.CheckSecretHash(secret, key.HashedKey) {
+		return nil, http.StatusUnauthorized, errors.New("api key is not valid")
+	}
+
+	if key.RevokedAt.Valid {
+		return nil, http.StatusUnauthorized, errors.New("api key has been revoked")
+	}
+
+	if key.ExpiresAt.Valid && time.Now().After(key.ExpiresAt.Time) {
+		return nil, http.StatusUnauthorized, errors.New("api key has expired")
+	}
+
+	err = store.UpdateAPIKeyLastUsed(ctx, key.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	payload := &token.Payload{
+		Username:  key.Owner,
+		IssuedAt:  key.CreatedAt,
+		ExpiredAt: key.ExpiresAt.Time,
+		Scopes:    key.Scopes,
+		APIKeyID:  key.ID,
+	}
+
+	return payload, http.StatusOK, nil
+}
+
+// requireScope rejects requests whose credentials don't grant scope
+func requireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		if !payload.HasScope(scope) {
+			err := fmt.Errorf("missing required scope %s", scope)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+	}
+}
+
+// requireSession rejects delegated credentials such as API keys from routes
+// that manage the user itself
+func requireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		if payload.IsDelegated() {
+			err := errors.New("this route requires a user session")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+	}
+}
+
+// requireRole rejects requests from users without one of the given roles.
+// Delegated credentials never carry a role.
+func requireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		for _, role := range roles {
+			if payload.Role == role {
+				return
+			}
+		}
 
+		err := errors.New("this route requires a privileged role")
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
 	}
 }
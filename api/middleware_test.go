@@ -1,13 +1,18 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
 	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,6 +24,27 @@ func addAuthorization(t *testing.T, request *http.Request, tokenMaker token.Make
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+func addAuthorizationWithRole(t *testing.T, request *http.Request, tokenMaker token.Maker, username string, role string) {
+	accessToken, err := tokenMaker.CreateToken(username, time.Minute, token.WithRole(role))
+	require.NoError(t, err)
+
+	request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+}
+
+// addConsentAuthorization adds a token an OAuth client got from a consent
+// covering accountIDs. The auth middleware looks the consent up, so the
+// store must allow GetOAuthConsent.
+func addConsentAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, username string, scope string, accountIDs ...int64) {
+	accessToken, err := tokenMaker.CreateToken(username, time.Minute,
+		token.WithScopes(scope),
+		token.WithClientID("client"),
+		token.WithConsent(1, accountIDs...),
+	)
+	require.NoError(t, err)
+
+	request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -85,7 +111,7 @@ func TestAuthMiddleware(t *testing.T) {
 
 			authPath := "/auth"
 
-			server.router.GET(authPath, authMiddleware(server.tokenMaker), func(ctx *gin.Context) {
+			server.router.GET(authPath, authMiddleware(server.tokenMaker, server.store), func(ctx *gin.Context) {
 				ctx.JSON(http.StatusOK, gin.H{})
 			})
 
@@ -101,3 +127,144 @@ func TestAuthMiddleware(t *testing.T) {
 
 	}
 }
+
+func randomAPIKey(t *testing.T, owner string, scopes ...string) (key db.ApiKey, apiKey string) {
+	prefix, err := util.RandomSecret(4)
+	require.NoError(t, err)
+
+	secret, err := util.RandomSecret(24)
+	require.NoError(t, err)
+
+	key = db.ApiKey{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     owner,
+		Name:      util.RandomString(6),
+		Prefix:    prefix,
+		HashedKey: util.HashSecret(secret),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+
+	return key, apiKeyTag + "_" + prefix + "_" + secret
+}
+
+func TestAPIKeyAuthMiddleware(t *testing.T) {
+	key, apiKey := randomAPIKey(t, "user", scopeAccountsRead)
+
+	testCases := []struct {
+		name          string
+		apiKey        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			apiKey: apiKey,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).Times(1).Return(key, nil)
+				store.EXPECT().UpdateAPIKeyLastUsed(gomock.Any(), gomock.Eq(key.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "MalformedKey",
+			apiKey: "not-a-key",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "WrongSecret",
+			apiKey: apiKeyTag + "_" + key.Prefix + "_" + util.RandomString(48),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).Times(1).Return(key, nil)
+				store.EXPECT().UpdateAPIKeyLastUsed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "UnknownKey",
+			apiKey: apiKey,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).Times(1).Return(db.ApiKey{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "RevokedKey",
+			apiKey: apiKey,
+			buildStubs: func(store *mockdb.MockStore) {
+				revoked := key
+				revoked.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).Times(1).Return(revoked, nil)
+				store.EXPECT().UpdateAPIKeyLastUsed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "ExpiredKey",
+			apiKey: apiKey,
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := key
+				expired.ExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).Times(1).Return(expired, nil)
+				store.EXPECT().UpdateAPIKeyLastUsed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingScope",
+			apiKey: apiKey,
+			buildStubs: func(store *mockdb.MockStore) {
+				writeOnly := key
+				writeOnly.Scopes = []string{scopeTransfersWrite}
+				store.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).Times(1).Return(writeOnly, nil)
+				store.EXPECT().UpdateAPIKeyLastUsed(gomock.Any(), gomock.Eq(key.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+
+			authPath := "/auth"
+
+			server.router.GET(authPath, authMiddleware(server.tokenMaker, server.store), requireScope(scopeAccountsRead), func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			request.Header.Set(apiKeyHeaderKey, tc.apiKey)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
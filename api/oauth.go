@@ -0,0 +1,537 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+)
+
+// OAuth error codes from RFC 6749 section 5.2
+const (
+	oauthErrorInvalidRequest = "invalid_request"
+	oauthErrorInvalidClient  = "invalid_client"
+	oauthErrorInvalidGrant   = "invalid_grant"
+	oauthErrorInvalidScope   = "invalid_scope"
+	oauthErrorServerError    = "server_error"
+)
+
+func oauthErrorResponse(code string, err error) gin.H {
+	return gin.H{"error": code, "error_description": err.Error()}
+}
+
+type registerOAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	RedirectURIs []string `json:"redirect_uris" binding:"required,min=1,dive,url"`
+	Scopes       []string `json:"scopes" binding:"required,min=1,dive,scope"`
+	Confidential bool     `json:"confidential"`
+}
+
+type oauthClientResponse struct {
+	ClientID     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	Confidential bool      `json:"confidential"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type registerOAuthClientResponse struct {
+	// ClientSecret is only returned once, for confidential clients
+	ClientSecret string              `json:"client_secret,omitempty"`
+	Client       oauthClientResponse `json:"client"`
+}
+
+func newOAuthClientResponse(client db.OauthClient) oauthClientResponse {
+	return oauthClientResponse{
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectUris,
+		Scopes:       client.Scopes,
+		Confidential: client.HashedSecret != "",
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+func (server *Server) registerOAuthClient(ctx *gin.Context) {
+	var req registerOAuthClientRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	clientID, err := util.RandomSecret(16)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var secret, hashedSecret string
+	if req.Confidential {
+		secret, err = util.RandomSecret(32)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		hashedSecret = util.HashSecret(secret)
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateOAuthClientParams{
+		ClientID:     clientID,
+		HashedSecret: hashedSecret,
+		Name:         req.Name,
+		Owner:        authPayload.Username,
+		RedirectUris: req.RedirectURIs,
+		Scopes:       req.Scopes,
+	}
+
+	var client db.OauthClient
+
+	audit := newAuditParams(ctx, auditOAuthClientCreate, auditTargetOAuthClient, clientID)
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		client, err = q.CreateOAuthClient(ctx, arg)
+		audit.After = newOAuthClientResponse(client)
+		return err
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := registerOAuthClientResponse{
+		ClientSecret: secret,
+		Client:       newOAuthClientResponse(client),
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type authorizeOAuthClientRequest struct {
+	ResponseType        string  `json:"response_type" binding:"required,oneof=code"`
+	ClientID            string  `json:"client_id" binding:"required"`
+	RedirectURI         string  `json:"redirect_uri" binding:"required,url"`
+	Scope               string  `json:"scope" binding:"required"`
+	AccountIDs          []int64 `json:"account_ids" binding:"required,min=1,dive,min=1"`
+	State               string  `json:"state"`
+	CodeChallenge       string  `json:"code_challenge" binding:"required,min=43,max=128"`
+	CodeChallengeMethod string  `json:"code_challenge_method" binding:"required,oneof=S256"`
+}
+
+type authorizeOAuthClientResponse struct {
+	RedirectURI string `json:"redirect_uri"`
+	Code        string `json:"code"`
+	State       string `json:"state,omitempty"`
+}
+
+// authorizeOAuthClient is called once the logged in user has consented to
+// give a client access to some of their accounts. It records the consent
+// and returns the redirect carrying the authorization code.
+func (server *Server) authorizeOAuthClient(ctx *gin.Context) {
+	var req authorizeOAuthClientRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidRequest, err))
+		return
+	}
+
+	client, err := server.store.GetOAuthClient(ctx, req.ClientID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidClient, err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrorServerError, err))
+		return
+	}
+
+	if !containsString(client.RedirectUris, req.RedirectURI) {
+		err := errors.New("redirect_uri is not registered for this client")
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidRequest, err))
+		return
+	}
+
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		err := errors.New("scope is required")
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidScope, err))
+		return
+	}
+
+	for _, scope := range scopes {
+		if !containsString(client.Scopes, scope) {
+			err := fmt.Errorf("scope %s is not allowed for this client", scope)
+			ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidScope, err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	for _, accountID := range req.AccountIDs {
+		account, err := server.store.GetAccount(ctx, accountID)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if account.Owner != authPayload.Username {
+			err := errors.New("account doesn't belong to the authenticated user")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+	}
+
+	code, err := util.RandomSecret(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrorServerError, err))
+		return
+	}
+
+	arg := db.OAuthAuthorizeTxParams{
+		Username:      authPayload.Username,
+		ClientID:      client.ClientID,
+		Scopes:        scopes,
+		AccountIDs:    req.AccountIDs,
+		HashedCode:    util.HashSecret(code),
+		RedirectUri:   req.RedirectURI,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(server.config.OAuthCodeDuration),
+		Audit:         newAuditParams(ctx, auditOAuthConsentCreate, auditTargetOAuthConsent, ""),
+	}
+
+	_, err = server.store.OAuthAuthorizeTx(ctx, arg)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrorServerError, err))
+		return
+	}
+
+	redirect, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidRequest, err))
+		return
+	}
+
+	query := redirect.Query()
+	query.Set("code", code)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirect.RawQuery = query.Encode()
+
+	rsp := authorizeOAuthClientResponse{
+		RedirectURI: redirect.String(),
+		Code:        code,
+		State:       req.State,
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type oauthTokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required,oneof=authorization_code"`
+	Code         string `form:"code" binding:"required"`
+	RedirectURI  string `form:"redirect_uri" binding:"required"`
+	ClientID     string `form:"client_id" binding:"required"`
+	ClientSecret string `form:"client_secret"`
+	CodeVerifier string `form:"code_verifier" binding:"required,min=43,max=128"`
+}
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// oauthToken exchanges an authorization code for an access token limited to
+// the scopes and accounts the user consented to.
+func (server *Server) oauthToken(ctx *gin.Context) {
+	var req oauthTokenRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidRequest, err))
+		return
+	}
+
+	client, ok := server.authenticateOAuthClient(ctx, req.ClientID, req.ClientSecret)
+	if !ok {
+		return
+	}
+
+	code, err := server.store.ConsumeOAuthAuthorizationCode(ctx, util.HashSecret(req.Code))
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := errors.New("authorization code is invalid, expired or already used")
+			ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidGrant, err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrorServerError, err))
+		return
+	}
+
+	consent, err := server.store.GetOAuthConsent(ctx, code.ConsentID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrorServerError, err))
+		return
+	}
+
+	if consent.ClientID != client.ClientID || consent.RevokedAt.Valid {
+		err := errors.New("authorization code was not issued to this client")
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidGrant, err))
+		return
+	}
+
+	if code.RedirectUri != req.RedirectURI {
+		err := errors.New("redirect_uri does not match the authorization request")
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidGrant, err))
+		return
+	}
+
+	if !verifyCodeChallenge(req.CodeVerifier, code.CodeChallenge) {
+		err := errors.New("code_verifier does not match the code challenge")
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidGrant, err))
+		return
+	}
+
+	// delegated tokens never count as a recent authentication of the user,
+	// so clients can't perform transfers that require step-up
+	accessToken, err := server.tokenMaker.CreateToken(
+		consent.Username,
+		server.config.OAuthAccessTokenDuration,
+		token.WithAuthTime(time.Time{}),
+		token.WithScopes(consent.Scopes...),
+		token.WithClientID(client.ClientID),
+		token.WithConsent(consent.ID, consent.AccountIds...),
+	)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrorServerError, err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+
+	rsp := oauthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(server.config.OAuthAccessTokenDuration.Seconds()),
+		Scope:       strings.Join(consent.Scopes, " "),
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type introspectOAuthTokenRequest struct {
+	Token        string `form:"token" binding:"required"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+type introspectOAuthTokenResponse struct {
+	Active   bool   `json:"active"`
+	Scope    string `json:"scope,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Exp      int64  `json:"exp,omitempty"`
+	Iat      int64  `json:"iat,omitempty"`
+}
+
+// introspectOAuthToken implements RFC 7662 token introspection for
+// confidential clients.
+func (server *Server) introspectOAuthToken(ctx *gin.Context) {
+	var req introspectOAuthTokenRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidRequest, err))
+		return
+	}
+
+	if clientID, clientSecret, ok := ctx.Request.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = clientID, clientSecret
+	}
+
+	client, ok := server.authenticateOAuthClient(ctx, req.ClientID, req.ClientSecret)
+	if !ok {
+		return
+	}
+
+	if client.HashedSecret == "" {
+		err := errors.New("only confidential clients can introspect tokens")
+		ctx.JSON(http.StatusUnauthorized, oauthErrorResponse(oauthErrorInvalidClient, err))
+		return
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(req.Token)
+	if err != nil {
+		ctx.JSON(http.StatusOK, introspectOAuthTokenResponse{Active: false})
+		return
+	}
+
+	if payload.ConsentID != 0 {
+		consent, err := server.store.GetOAuthConsent(ctx, payload.ConsentID)
+		if err != nil || consent.RevokedAt.Valid {
+			ctx.JSON(http.StatusOK, introspectOAuthTokenResponse{Active: false})
+			return
+		}
+	}
+
+	rsp := introspectOAuthTokenResponse{
+		Active:   true,
+		Scope:    strings.Join(payload.Scopes, " "),
+		ClientID: payload.ClientID,
+		Username: payload.Username,
+		Exp:      payload.ExpiredAt.Unix(),
+		Iat:      payload.IssuedAt.Unix(),
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// authenticateOAuthClient loads the client and checks its secret when it is
+// a confidential client. It writes the error response when it fails.
+func (server *Server) authenticateOAuthClient(ctx *gin.Context, clientID string, clientSecret string) (db.OauthClient, bool) {
+	client, err := server.store.GetOAuthClient(ctx, clientID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := errors.New("unknown client")
+			ctx.JSON(http.StatusUnauthorized, oauthErrorResponse(oauthErrorInvalidClient, err))
+			return client, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrorServerError, err))
+		return client, false
+	}
+
+	if client.HashedSecret != "" && !util.CheckSecretHash(clientSecret, client.HashedSecret) {
+		err := errors.New("client authentication failed")
+		ctx.JSON(http.StatusUnauthorized, oauthErrorResponse(oauthErrorInvalidClient, err))
+		return client, false
+	}
+
+	return client, true
+}
+
+// verifyCodeChallenge checks a PKCE code verifier against an S256 challenge
+func verifyCodeChallenge(verifier string, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type oauthConsentResponse struct {
+	ID         int64     `json:"id"`
+	ClientID   string    `json:"client_id"`
+	Scopes     []string  `json:"scopes"`
+	AccountIDs []int64   `json:"account_ids"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newOAuthConsentResponse(consent db.OauthConsent) oauthConsentResponse {
+	return oauthConsentResponse{
+		ID:         consent.ID,
+		ClientID:   consent.ClientID,
+		Scopes:     consent.Scopes,
+		AccountIDs: consent.AccountIds,
+		CreatedAt:  consent.CreatedAt,
+	}
+}
+
+func (server *Server) listOAuthConsents(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	consents, err := server.store.ListOAuthConsents(ctx, authPayload.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]oauthConsentResponse, len(consents))
+
+	for i, consent := range consents {
+		rsp[i] = newOAuthConsentResponse(consent)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type revokeOAuthConsentRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) revokeOAuthConsent(ctx *gin.Context) {
+	var req revokeOAuthConsentRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.RevokeOAuthConsentParams{
+		ID:       req.ID,
+		Username: authPayload.Username,
+	}
+
+	audit := newAuditParams(ctx, auditOAuthConsentRevoke, auditTargetOAuthConsent, strconv.FormatInt(req.ID, 10))
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		consent, err := q.RevokeOAuthConsent(ctx, arg)
+		audit.After = newOAuthConsentResponse(consent)
+		return err
+	})
+
+	if err != nil {
+
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "consent revoked"})
+}
@@ -0,0 +1,384 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomOAuthClient(owner string) db.OauthClient {
+	return db.OauthClient{
+		ClientID:     util.RandomString(16),
+		Name:         util.RandomString(6),
+		Owner:        owner,
+		RedirectUris: []string{"https://partner.example.com/callback"},
+		Scopes:       []string{scopeAccountsRead, scopeTransfersRead},
+		CreatedAt:    time.Now(),
+	}
+}
+
+func randomCodeVerifier(t *testing.T) (verifier string, challenge string) {
+	verifier, err := util.RandomSecret(32)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestAuthorizeOAuthClientApi(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	client := randomOAuthClient("partner")
+	_, challenge := randomCodeVerifier(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"response_type":         "code",
+				"client_id":             client.ClientID,
+				"redirect_uri":          client.RedirectUris[0],
+				"scope":                 scopeAccountsRead,
+				"account_ids":           []int64{account.ID},
+				"state":                 "xyz",
+				"code_challenge":        challenge,
+				"code_challenge_method": "S256",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					OAuthAuthorizeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.OAuthAuthorizeTxParams) (db.OAuthAuthorizeTxResult, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, []string{scopeAccountsRead}, arg.Scopes)
+						require.Equal(t, []int64{account.ID}, arg.AccountIDs)
+						require.Equal(t, challenge, arg.CodeChallenge)
+						return db.OAuthAuthorizeTxResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res authorizeOAuthClientResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+
+				redirect, err := url.Parse(res.RedirectURI)
+				require.NoError(t, err)
+				require.Equal(t, res.Code, redirect.Query().Get("code"))
+				require.Equal(t, "xyz", redirect.Query().Get("state"))
+			},
+		},
+		{
+			name: "ScopeNotAllowed",
+			body: gin.H{
+				"response_type":         "code",
+				"client_id":             client.ClientID,
+				"redirect_uri":          client.RedirectUris[0],
+				"scope":                 scopeTransfersWrite,
+				"account_ids":           []int64{account.ID},
+				"code_challenge":        challenge,
+				"code_challenge_method": "S256",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().OAuthAuthorizeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidScope)
+			},
+		},
+		{
+			name: "BlankScope",
+			body: gin.H{
+				"response_type":         "code",
+				"client_id":             client.ClientID,
+				"redirect_uri":          client.RedirectUris[0],
+				"scope":                 "   ",
+				"account_ids":           []int64{account.ID},
+				"code_challenge":        challenge,
+				"code_challenge_method": "S256",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().OAuthAuthorizeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidScope)
+			},
+		},
+		{
+			name: "UnregisteredRedirectURI",
+			body: gin.H{
+				"response_type":         "code",
+				"client_id":             client.ClientID,
+				"redirect_uri":          "https://evil.example.com/callback",
+				"scope":                 scopeAccountsRead,
+				"account_ids":           []int64{account.ID},
+				"code_challenge":        challenge,
+				"code_challenge_method": "S256",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().OAuthAuthorizeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AccountOfAnotherUser",
+			body: gin.H{
+				"response_type":         "code",
+				"client_id":             client.ClientID,
+				"redirect_uri":          client.RedirectUris[0],
+				"scope":                 scopeAccountsRead,
+				"account_ids":           []int64{account.ID},
+				"code_challenge":        challenge,
+				"code_challenge_method": "S256",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				other := account
+				other.Owner = "someone"
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(other, nil)
+				store.EXPECT().OAuthAuthorizeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/oauth/authorize", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestOAuthTokenApi(t *testing.T) {
+	user, _ := randomUser(t)
+	client := randomOAuthClient("partner")
+	verifier, challenge := randomCodeVerifier(t)
+	code := util.RandomString(32)
+
+	consent := db.OauthConsent{
+		ID:         util.RandomInt(1, 1000),
+		Username:   user.Username,
+		ClientID:   client.ClientID,
+		Scopes:     []string{scopeAccountsRead},
+		AccountIds: []int64{util.RandomInt(1, 1000)},
+	}
+
+	authorizationCode := db.OauthAuthorizationCode{
+		HashedCode:    util.HashSecret(code),
+		ConsentID:     consent.ID,
+		RedirectUri:   client.RedirectUris[0],
+		CodeChallenge: challenge,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+
+	testCases := []struct {
+		name          string
+		form          url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker)
+	}{
+		{
+			name: "OK",
+			form: url.Values{
+				"grant_type":    {"authorization_code"},
+				"code":          {code},
+				"redirect_uri":  {client.RedirectUris[0]},
+				"client_id":     {client.ClientID},
+				"code_verifier": {verifier},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), gomock.Eq(util.HashSecret(code))).Times(1).Return(authorizationCode, nil)
+				store.EXPECT().GetOAuthConsent(gomock.Any(), gomock.Eq(consent.ID)).Times(1).Return(consent, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res oauthTokenResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, scopeAccountsRead, res.Scope)
+
+				payload, err := tokenMaker.VerifyToken(res.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.Equal(t, client.ClientID, payload.ClientID)
+				require.Equal(t, consent.ID, payload.ConsentID)
+				require.Equal(t, consent.AccountIds, payload.AccountIDs)
+				require.True(t, payload.HasScope(scopeAccountsRead))
+				require.False(t, payload.HasScope(scopeTransfersWrite))
+			},
+		},
+		{
+			name: "WrongCodeVerifier",
+			form: url.Values{
+				"grant_type":    {"authorization_code"},
+				"code":          {code},
+				"redirect_uri":  {client.RedirectUris[0]},
+				"client_id":     {client.ClientID},
+				"code_verifier": {strings.Repeat("a", 43)},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), gomock.Any()).Times(1).Return(authorizationCode, nil)
+				store.EXPECT().GetOAuthConsent(gomock.Any(), gomock.Eq(consent.ID)).Times(1).Return(consent, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidGrant)
+			},
+		},
+		{
+			name: "CodeAlreadyUsed",
+			form: url.Values{
+				"grant_type":    {"authorization_code"},
+				"code":          {code},
+				"redirect_uri":  {client.RedirectUris[0]},
+				"client_id":     {client.ClientID},
+				"code_verifier": {verifier},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+				store.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthAuthorizationCode{}, sql.ErrNoRows)
+				store.EXPECT().GetOAuthConsent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidGrant)
+			},
+		},
+		{
+			name: "WrongClientSecret",
+			form: url.Values{
+				"grant_type":    {"authorization_code"},
+				"code":          {code},
+				"redirect_uri":  {client.RedirectUris[0]},
+				"client_id":     {client.ClientID},
+				"client_secret": {"wrong"},
+				"code_verifier": {verifier},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				confidential := client
+				confidential.HashedSecret = util.HashSecret("secret")
+				store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(confidential, nil)
+				store.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidClient)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(tc.form.Encode()))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server.tokenMaker)
+		})
+	}
+}
+
+func TestRevokedConsentToken(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	consent := db.OauthConsent{
+		ID:         util.RandomInt(1, 1000),
+		Username:   user.Username,
+		Scopes:     []string{scopeAccountsRead},
+		AccountIds: []int64{account.ID},
+		RevokedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetOAuthConsent(gomock.Any(), gomock.Eq(consent.ID)).Times(1).Return(consent, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, time.Minute,
+		token.WithScopes(consent.Scopes...),
+		token.WithConsent(consent.ID, consent.AccountIds...),
+	)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -0,0 +1,569 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/iso20022"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errPaymentExportInvalid = errors.New("the payments can't be exported")
+	errUnknownPaymentExport = errors.New("the status report is for a message we didn't send")
+)
+
+// outboundPaymentResponse is a transfer paid out to an external beneficiary
+// and how far the correspondent bank has taken it
+type outboundPaymentResponse struct {
+	ID            int64       `json:"id"`
+	TransferID    int64       `json:"transfer_id"`
+	AccountID     int64       `json:"account_id"`
+	BeneficiaryID *int64      `json:"beneficiary_id"`
+	Amount        money.Money `json:"amount"`
+	Currency      string      `json:"currency"`
+	CreditorName  string      `json:"creditor_name"`
+	IBAN          string      `json:"iban,omitempty"`
+	AccountNumber string      `json:"account_number,omitempty"`
+	BIC           string      `json:"bic,omitempty"`
+	ABARouting    string      `json:"aba_routing,omitempty"`
+	EndToEndID    string      `json:"end_to_end_id"`
+	// Status is initiated, sent, settled or returned
+	Status           string     `json:"status"`
+	ReturnReason     string     `json:"return_reason,omitempty"`
+	ReturnTransferID *int64     `json:"return_transfer_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	SentAt           *time.Time `json:"sent_at"`
+	SettledAt        *time.Time `json:"settled_at"`
+	ReturnedAt       *time.Time `json:"returned_at"`
+}
+
+func newOutboundPaymentResponse(payment db.OutboundPayment) outboundPaymentResponse {
+	return outboundPaymentResponse{
+		ID:               payment.ID,
+		TransferID:       payment.TransferID,
+		AccountID:        payment.AccountID,
+		BeneficiaryID:    nullInt64Ptr(payment.BeneficiaryID),
+		Amount:           money.New(payment.Amount, payment.Currency),
+		Currency:         payment.Currency,
+		CreditorName:     payment.CreditorName,
+		IBAN:             payment.CreditorIban,
+		AccountNumber:    payment.CreditorAccountNumber,
+		BIC:              payment.CreditorBic,
+		ABARouting:       payment.CreditorAbaRouting,
+		EndToEndID:       payment.EndToEndID,
+		Status:           payment.Status,
+		ReturnReason:     payment.ReturnReason,
+		ReturnTransferID: nullInt64Ptr(payment.ReturnTransferID),
+		CreatedAt:        payment.CreatedAt,
+		SentAt:           nullTimePtr(payment.SentAt),
+		SettledAt:        nullTimePtr(payment.SettledAt),
+		ReturnedAt:       nullTimePtr(payment.ReturnedAt),
+	}
+}
+
+func newOutboundPaymentResponses(payments []db.OutboundPayment) []outboundPaymentResponse {
+	rsp := make([]outboundPaymentResponse, len(payments))
+	for i, payment := range payments {
+		rsp[i] = newOutboundPaymentResponse(payment)
+	}
+	return rsp
+}
+
+type getOutboundPaymentRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getOutboundPayment shows the holder of the account an external transfer
+// was made from where it has got to
+func (server *Server) getOutboundPayment(ctx *gin.Context) {
+	var req getOutboundPaymentRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payment, err := server.store.GetOutboundPayment(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, payment.AccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !authPayload.CanAccessAccount(account.ID) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotGranted))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newOutboundPaymentResponse(payment))
+}
+
+type listOutboundPaymentsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=initiated sent settled returned"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listOutboundPayments shows bankers the payments out to external
+// beneficiaries, newest first
+func (server *Server) listOutboundPayments(ctx *gin.Context) {
+	var req listOutboundPaymentsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payments, err := server.store.ListOutboundPayments(ctx, db.ListOutboundPaymentsParams{
+		Status: nullString(req.Status),
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newOutboundPaymentResponses(payments))
+}
+
+// settleOutboundPayment lets a banker record that the correspondent bank
+// settled a sent payment
+func (server *Server) settleOutboundPayment(ctx *gin.Context) {
+	var req getOutboundPaymentRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payment, err := server.store.SettleOutboundPaymentTx(ctx, db.SettleOutboundPaymentTxParams{
+		ID:    req.ID,
+		Audit: newAuditParams(ctx, auditOutboundPaymentSettle, auditTargetOutbound, strconv.FormatInt(req.ID, 10)),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		if errors.Is(err, db.ErrOutboundPaymentNotSent) || errors.Is(err, db.ErrOutboundPaymentNotPending) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newOutboundPaymentResponse(payment))
+}
+
+type returnOutboundPaymentRequest struct {
+	Reason string `json:"reason" binding:"required,max=140"`
+}
+
+type returnOutboundPaymentResponse struct {
+	Payment  outboundPaymentResponse `json:"payment"`
+	Transfer transferResponse        `json:"transfer"`
+}
+
+// returnOutboundPayment lets a banker credit a payment that won't reach its
+// beneficiary back to the customer, e.g. when the receiving bank sends it
+// back
+func (server *Server) returnOutboundPayment(ctx *gin.Context) {
+	var uri getOutboundPaymentRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req returnOutboundPaymentRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.ReturnOutboundPaymentTx(ctx, db.ReturnOutboundPaymentTxParams{
+		ID:     uri.ID,
+		Reason: req.Reason,
+		Audit:  newAuditParams(ctx, auditOutboundPaymentReturn, auditTargetOutbound, strconv.FormatInt(uri.ID, 10)),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		if errors.Is(err, db.ErrOutboundPaymentReturned) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, returnOutboundPaymentResponse{
+		Payment:  newOutboundPaymentResponse(result.Payment),
+		Transfer: newTransferResponse(result.Transfer.Transfer, result.Payment.Currency),
+	})
+}
+
+// paymentExportResponse is an export of outbound payments and how many of
+// them the correspondent bank has settled and returned so far. The payments
+// themselves are only listed when the export is made.
+type paymentExportResponse struct {
+	ID        int64  `json:"id"`
+	MessageID string `json:"message_id"`
+	// Status is sent, settled, returned or partially_settled
+	Status      string                    `json:"status"`
+	Total       int32                     `json:"total"`
+	Settled     int32                     `json:"settled"`
+	Returned    int32                     `json:"returned"`
+	CreatedBy   string                    `json:"created_by"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	CompletedAt *time.Time                `json:"completed_at"`
+	Payments    []outboundPaymentResponse `json:"payments,omitempty"`
+}
+
+func newPaymentExportResponse(export db.PaymentExport) paymentExportResponse {
+	return paymentExportResponse{
+		ID:          export.ID,
+		MessageID:   export.MessageID,
+		Status:      export.Status,
+		Total:       export.Payments,
+		Settled:     export.Settled,
+		Returned:    export.Returned,
+		CreatedBy:   export.CreatedBy,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: nullTimePtr(export.CompletedAt),
+	}
+}
+
+// Formats the outbound payments are exported in, chosen by
+// PAYMENT_EXPORT_FORMAT
+const (
+	paymentExportPain001 = "pain.001"
+	paymentExportPacs008 = "pacs.008"
+)
+
+// paymentExportDocument writes the message asking the correspondent bank to
+// pay out payments from our nostro account: a pain.001 initiation or, when we
+// take part in the clearing ourselves, a pacs.008 interbank transfer
+func (server *Server) paymentExportDocument(messageID string, payments []db.OutboundPayment) ([]byte, error) {
+	now := time.Now()
+	debtor := iso20022.Party{
+		Name: server.config.NostroAccountName,
+		IBAN: server.config.NostroIBAN,
+		BIC:  server.config.NostroBIC,
+	}
+
+	transfers := make([]iso20022.CreditTransfer, len(payments))
+	for i, payment := range payments {
+		transfers[i] = iso20022.CreditTransfer{
+			EndToEndID: payment.EndToEndID,
+			Amount:     payment.Amount,
+			Currency:   payment.Currency,
+			Creditor: iso20022.Party{
+				Name:          payment.CreditorName,
+				IBAN:          payment.CreditorIban,
+				AccountNumber: payment.CreditorAccountNumber,
+				BIC:           payment.CreditorBic,
+				ABARouting:    payment.CreditorAbaRouting,
+			},
+			RemittanceInfo: payment.RemittanceInfo,
+		}
+	}
+
+	var document []byte
+	var err error
+
+	switch server.config.PaymentExportFormat {
+	case paymentExportPacs008:
+		document, err = iso20022.MarshalPacs008(iso20022.CustomerCreditTransfer{
+			MessageID:           messageID,
+			CreatedAt:           now,
+			SettlementDate:      now,
+			SettlementMethod:    iso20022.SettlementInstructedAgent,
+			InstructingAgentBIC: server.config.NostroBIC,
+			InstructedAgentBIC:  server.config.CorrespondentBIC,
+			Debtor:              debtor,
+			Transfers:           transfers,
+		})
+	case paymentExportPain001, "":
+		document, err = iso20022.MarshalPain001(iso20022.CreditTransferInitiation{
+			MessageID:       messageID,
+			CreatedAt:       now,
+			InitiatingParty: server.config.NostroAccountName,
+			PaymentInfoID:   messageID,
+			ExecutionDate:   now,
+			Debtor:          debtor,
+			Transfers:       transfers,
+		})
+	default:
+		err = fmt.Errorf("unknown export format %q", server.config.PaymentExportFormat)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errPaymentExportInvalid, err)
+	}
+
+	return document, nil
+}
+
+// createPaymentExport lets a banker export the initiated outbound payments
+// as a message for the correspondent bank. The payments are marked sent; the
+// message is downloaded with getPaymentExportDocument.
+func (server *Server) createPaymentExport(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.ExportOutboundPaymentsTx(ctx, db.ExportOutboundPaymentsTxParams{
+		MaxPayments: int32(server.config.PaymentExportMaxItems),
+		CreatedBy:   authPayload.Username,
+		Document:    server.paymentExportDocument,
+		Audit:       newAuditParams(ctx, auditPaymentExportCreate, auditTargetPaymentExport, ""),
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrNoOutboundPayments) || errors.Is(err, errPaymentExportInvalid) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := newPaymentExportResponse(result.Export)
+	rsp.Payments = newOutboundPaymentResponses(result.Payments)
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type listPaymentExportsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=sent settled returned partially_settled"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listPaymentExports shows bankers the exports of outbound payments, newest
+// first, e.g. those still waiting for status reports
+func (server *Server) listPaymentExports(ctx *gin.Context) {
+	var req listPaymentExportsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	exports, err := server.store.ListPaymentExports(ctx, db.ListPaymentExportsParams{
+		Status: nullString(req.Status),
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]paymentExportResponse, len(exports))
+	for i, export := range exports {
+		rsp[i] = newPaymentExportResponse(db.PaymentExport{
+			ID:          export.ID,
+			MessageID:   export.MessageID,
+			Payments:    export.Payments,
+			CreatedBy:   export.CreatedBy,
+			CreatedAt:   export.CreatedAt,
+			Status:      export.Status,
+			Settled:     export.Settled,
+			Returned:    export.Returned,
+			CompletedAt: export.CompletedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type getPaymentExportRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getPaymentExport shows a banker how far the payments of an export have got
+func (server *Server) getPaymentExport(ctx *gin.Context) {
+	var req getPaymentExportRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	export, err := server.store.GetPaymentExport(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentExportResponse(export))
+}
+
+// getPaymentExportDocument downloads the message of an export
+func (server *Server) getPaymentExportDocument(ctx *gin.Context) {
+	var req getPaymentExportRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	export, err := server.store.GetPaymentExport(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.MessageID+".xml"))
+	ctx.Data(http.StatusOK, "application/xml", export.Document)
+}
+
+type paymentStatusReportResponse struct {
+	OriginalMessageID string                    `json:"original_message_id"`
+	Settled           []outboundPaymentResponse `json:"settled"`
+	Returned          []outboundPaymentResponse `json:"returned"`
+	Ignored           []string                  `json:"ignored"`
+}
+
+// importPaymentStatusReport applies a pain.002 status report from the
+// correspondent bank to the payments of the export it reports on: accepted
+// payments are settled and rejected ones returned to the customer. Payments
+// still pending are left as sent, and so is the export until none are.
+func (server *Server) importPaymentStatusReport(ctx *gin.Context) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	report, err := iso20022.ParsePain002(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	endToEndIDs, err := server.store.ListExportedEndToEndIDs(ctx, report.OriginalMessageID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if len(endToEndIDs) == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errUnknownPaymentExport))
+		return
+	}
+
+	arg := db.ApplyPaymentStatusReportTxParams{
+		Audit: newAuditParams(ctx, auditPaymentReportApply, auditTargetPaymentExport, report.OriginalMessageID),
+	}
+
+	for _, endToEndID := range endToEndIDs {
+		status, ok := report.Status(endToEndID)
+		if !ok {
+			continue
+		}
+
+		switch iso20022.Outcome(status.Status) {
+		case iso20022.OutcomeAccepted:
+			arg.Statuses = append(arg.Statuses, db.OutboundPaymentStatus{
+				EndToEndID: endToEndID,
+				Status:     db.OutboundPaymentSettled,
+			})
+		case iso20022.OutcomeRejected:
+			arg.Statuses = append(arg.Statuses, db.OutboundPaymentStatus{
+				EndToEndID: endToEndID,
+				Status:     db.OutboundPaymentReturned,
+				Reason:     rejectionReason(status),
+			})
+		}
+	}
+
+	result, err := server.store.ApplyPaymentStatusReportTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrAccountClosed) || errors.Is(err, db.ErrAccountFrozen) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, paymentStatusReportResponse{
+		OriginalMessageID: report.OriginalMessageID,
+		Settled:           newOutboundPaymentResponses(result.Settled),
+		Returned:          newOutboundPaymentResponses(result.Returned),
+		Ignored:           result.Ignored,
+	})
+}
+
+// rejectionReason describes why the correspondent bank rejected a payment,
+// e.g. "AC04: account closed"
+func rejectionReason(status iso20022.TransactionStatus) string {
+	switch {
+	case status.Reason != "" && status.AdditionalInfo != "":
+		return status.Reason + ": " + status.AdditionalInfo
+	case status.Reason != "":
+		return status.Reason
+	case status.AdditionalInfo != "":
+		return status.AdditionalInfo
+	}
+
+	return "rejected by the correspondent bank"
+}
@@ -0,0 +1,657 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/iso20022"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomOutboundPayment(account db.Account) db.OutboundPayment {
+	transferID := util.RandomInt(1, 1000)
+
+	return db.OutboundPayment{
+		ID:           util.RandomInt(1, 1000),
+		TransferID:   transferID,
+		AccountID:    account.ID,
+		Amount:       util.RandomInt(1, 1000),
+		Currency:     account.Currency,
+		CreditorName: util.RandomOwner(),
+		CreditorIban: "GB82WEST12345698765432",
+		EndToEndID:   db.OutboundEndToEndID(transferID),
+		Status:       db.OutboundPaymentSent,
+		CreatedAt:    time.Now(),
+	}
+}
+
+func TestGetOutboundPaymentApi(t *testing.T) {
+	user := util.RandomOwner()
+	account := randomAccount(user)
+	payment := randomOutboundPayment(account)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOutboundPayment(gomock.Any(), gomock.Eq(payment.ID)).Times(1).Return(payment, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, payment.EndToEndID, res["end_to_end_id"])
+				require.Equal(t, payment.CreditorIban, res["iban"])
+			},
+		},
+		{
+			name:     "UnauthorizedUser",
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOutboundPayment(gomock.Any(), gomock.Eq(payment.ID)).Times(1).Return(payment, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetOutboundPayment(gomock.Any(), gomock.Eq(payment.ID)).Times(1).Return(db.OutboundPayment{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/outbound-payments/%d", payment.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreatePaymentExportApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+	account.Currency = util.EUR
+	payment := randomOutboundPayment(account)
+
+	testCases := []struct {
+		name          string
+		role          string
+		format        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportOutboundPaymentsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ExportOutboundPaymentsTxParams) (db.ExportOutboundPaymentsTxResult, error) {
+						require.Equal(t, int32(100), arg.MaxPayments)
+						require.Equal(t, banker, arg.CreatedBy)
+						require.Equal(t, auditPaymentExportCreate, arg.Audit.Action)
+
+						document, err := arg.Document("SIMPLEBANK-1", []db.OutboundPayment{payment})
+						require.NoError(t, err)
+						require.Contains(t, string(document), "<EndToEndId>"+payment.EndToEndID+"</EndToEndId>")
+						require.Contains(t, string(document), "DE89370400440532013000")
+
+						return db.ExportOutboundPaymentsTxResult{
+							Export: db.PaymentExport{
+								ID:        1,
+								MessageID: "SIMPLEBANK-1",
+								Payments:  1,
+								Document:  document,
+								CreatedBy: banker,
+								Status:    db.PaymentExportSent,
+							},
+							Payments: []db.OutboundPayment{payment},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "SIMPLEBANK-1", res["message_id"])
+				require.Equal(t, db.PaymentExportSent, res["status"])
+				require.Len(t, res["payments"], 1)
+			},
+		},
+		{
+			name:   "Pacs008",
+			role:   util.BankerRole,
+			format: paymentExportPacs008,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportOutboundPaymentsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ExportOutboundPaymentsTxParams) (db.ExportOutboundPaymentsTxResult, error) {
+						document, err := arg.Document("SIMPLEBANK-1", []db.OutboundPayment{payment})
+						require.NoError(t, err)
+						require.Contains(t, string(document), `xmlns="`+iso20022.Pacs008Namespace+`"`)
+						require.Contains(t, string(document), "<TxId>"+payment.EndToEndID+"</TxId>")
+						require.Contains(t, string(document), "<BIC>DEUTDEFFXXX</BIC>")
+
+						return db.ExportOutboundPaymentsTxResult{
+							Export:   db.PaymentExport{ID: 1, MessageID: "SIMPLEBANK-1", Payments: 1, Document: document, CreatedBy: banker},
+							Payments: []db.OutboundPayment{payment},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "UnknownFormat",
+			role:   util.BankerRole,
+			format: "mt103",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportOutboundPaymentsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ExportOutboundPaymentsTxParams) (db.ExportOutboundPaymentsTxResult, error) {
+						_, err := arg.Document("SIMPLEBANK-1", []db.OutboundPayment{payment})
+						return db.ExportOutboundPaymentsTxResult{}, err
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "NothingToExport",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportOutboundPaymentsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExportOutboundPaymentsTxResult{}, db.ErrNoOutboundPayments)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "NotBanker",
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ExportOutboundPaymentsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			server.config.PaymentExportFormat = tc.format
+			server.config.CorrespondentBIC = "DEUTDEFFXXX"
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/payment-exports", nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, tc.role)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func newPaymentStatusReportRequest(t *testing.T, content string) *http.Request {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+
+	file, err := form.CreateFormFile("file", "report.xml")
+	require.NoError(t, err)
+	_, err = file.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	request, err := http.NewRequest(http.MethodPost, "/payment-exports/status-reports", body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", form.FormDataContentType())
+
+	return request
+}
+
+func TestImportPaymentStatusReportApi(t *testing.T) {
+	banker := util.RandomOwner()
+
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03">
+  <CstmrPmtStsRpt>
+    <GrpHdr><MsgId>BANK-77</MsgId></GrpHdr>
+    <OrgnlGrpInfAndSts>
+      <OrgnlMsgId>SIMPLEBANK-1</OrgnlMsgId>
+      <GrpSts>PART</GrpSts>
+    </OrgnlGrpInfAndSts>
+    <OrgnlPmtInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>SIMPLEBANK-1</OrgnlEndToEndId>
+        <TxSts>ACSC</TxSts>
+      </TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>SIMPLEBANK-2</OrgnlEndToEndId>
+        <TxSts>RJCT</TxSts>
+        <StsRsnInf>
+          <Rsn><Cd>AC04</Cd></Rsn>
+          <AddtlInf>Account closed</AddtlInf>
+        </StsRsnInf>
+      </TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>SIMPLEBANK-3</OrgnlEndToEndId>
+        <TxSts>PDNG</TxSts>
+      </TxInfAndSts>
+    </OrgnlPmtInfAndSts>
+  </CstmrPmtStsRpt>
+</Document>`
+
+	testCases := []struct {
+		name          string
+		role          string
+		content       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			role:    util.BankerRole,
+			content: report,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListExportedEndToEndIDs(gomock.Any(), gomock.Eq("SIMPLEBANK-1")).
+					Times(1).
+					Return([]string{"SIMPLEBANK-1", "SIMPLEBANK-2", "SIMPLEBANK-3"}, nil)
+
+				arg := db.ApplyPaymentStatusReportTxParams{
+					Statuses: []db.OutboundPaymentStatus{
+						{EndToEndID: "SIMPLEBANK-1", Status: db.OutboundPaymentSettled},
+						{EndToEndID: "SIMPLEBANK-2", Status: db.OutboundPaymentReturned, Reason: "AC04: Account closed"},
+					},
+				}
+				store.EXPECT().
+					ApplyPaymentStatusReportTx(gomock.Any(), EqAuditedParams(arg, auditPaymentReportApply)).
+					Times(1).
+					Return(db.ApplyPaymentStatusReportTxResult{
+						Settled:  []db.OutboundPayment{{ID: 1, EndToEndID: "SIMPLEBANK-1", Status: db.OutboundPaymentSettled}},
+						Returned: []db.OutboundPayment{{ID: 2, EndToEndID: "SIMPLEBANK-2", Status: db.OutboundPaymentReturned}},
+						Ignored:  []string{},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "SIMPLEBANK-1", res["original_message_id"])
+				require.Len(t, res["settled"], 1)
+				require.Len(t, res["returned"], 1)
+			},
+		},
+		{
+			name:    "UnknownMessage",
+			role:    util.BankerRole,
+			content: report,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExportedEndToEndIDs(gomock.Any(), gomock.Eq("SIMPLEBANK-1")).Times(1).Return([]string{}, nil)
+				store.EXPECT().ApplyPaymentStatusReportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidReport",
+			role:    util.BankerRole,
+			content: "<Document><CstmrPmtStsRpt/></Document>",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExportedEndToEndIDs(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ApplyPaymentStatusReportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotBanker",
+			role:    util.DepositorRole,
+			content: report,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListExportedEndToEndIDs(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ApplyPaymentStatusReportTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := newPaymentStatusReportRequest(t, tc.content)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, tc.role)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestReturnOutboundPaymentApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+	payment := randomOutboundPayment(account)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"reason": "Beneficiary account closed"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReturnOutboundPaymentTxParams{
+					ID:     payment.ID,
+					Reason: "Beneficiary account closed",
+				}
+
+				returned := payment
+				returned.Status = db.OutboundPaymentReturned
+				returned.ReturnReason = arg.Reason
+
+				store.EXPECT().
+					ReturnOutboundPaymentTx(gomock.Any(), EqAuditedParams(arg, auditOutboundPaymentReturn)).
+					Times(1).
+					Return(db.ReturnOutboundPaymentTxResult{
+						Payment: returned,
+						Transfer: db.TransferTxResult{
+							Transfer: db.Transfer{ID: 7, ToAccountID: account.ID, Amount: payment.Amount},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.OutboundPaymentReturned, res["payment"].(map[string]any)["status"])
+			},
+		},
+		{
+			name: "AlreadyReturned",
+			body: gin.H{"reason": "Beneficiary account closed"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReturnOutboundPaymentTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReturnOutboundPaymentTxResult{}, db.ErrOutboundPaymentReturned)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "NoReason",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReturnOutboundPaymentTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/outbound-payments/%d/return", payment.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSettleOutboundPaymentApi(t *testing.T) {
+	banker := util.RandomOwner()
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "OK", code: http.StatusOK},
+		{name: "NotSent", err: db.ErrOutboundPaymentNotSent, code: http.StatusUnprocessableEntity},
+		{name: "NotPending", err: db.ErrOutboundPaymentNotPending, code: http.StatusUnprocessableEntity},
+		{name: "NotFound", err: sql.ErrNoRows, code: http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				SettleOutboundPaymentTx(gomock.Any(), EqAuditedParams(db.SettleOutboundPaymentTxParams{ID: 5}, auditOutboundPaymentSettle)).
+				Times(1).
+				Return(db.OutboundPayment{ID: 5, Status: db.OutboundPaymentSettled}, tc.err)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/outbound-payments/5/settle", nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.code, recorder.Code)
+		})
+	}
+}
+
+func TestGetPaymentExportApi(t *testing.T) {
+	banker := util.RandomOwner()
+	export := db.PaymentExport{
+		ID:          3,
+		MessageID:   "SIMPLEBANK-3",
+		Payments:    3,
+		Document:    []byte("<Document/>"),
+		CreatedBy:   banker,
+		Status:      db.PaymentExportPartiallySettled,
+		Settled:     2,
+		Returned:    1,
+		CompletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	for _, tc := range []struct {
+		name string
+		role string
+		err  error
+		code int
+	}{
+		{name: "OK", role: util.BankerRole, code: http.StatusOK},
+		{name: "NotFound", role: util.BankerRole, err: sql.ErrNoRows, code: http.StatusNotFound},
+		{name: "NotBanker", role: util.DepositorRole, code: http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			times := 1
+			if tc.role != util.BankerRole {
+				times = 0
+			}
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetPaymentExport(gomock.Any(), gomock.Eq(export.ID)).Times(times).Return(export, tc.err)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/payment-exports/%d", export.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, tc.role)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.code, recorder.Code)
+
+			if tc.code == http.StatusOK {
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.PaymentExportPartiallySettled, res["status"])
+				require.Equal(t, float64(3), res["total"])
+				require.Equal(t, float64(1), res["returned"])
+				require.NotNil(t, res["completed_at"])
+				require.NotContains(t, res, "document")
+			}
+		})
+	}
+}
+
+func TestListPaymentExportsApi(t *testing.T) {
+	banker := util.RandomOwner()
+
+	for _, tc := range []struct {
+		name       string
+		query      string
+		buildStubs func(store *mockdb.MockStore)
+		code       int
+	}{
+		{
+			name:  "Sent",
+			query: "?status=sent&page_id=2&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListPaymentExports(gomock.Any(), gomock.Eq(db.ListPaymentExportsParams{
+						Status: sql.NullString{String: db.PaymentExportSent, Valid: true},
+						Limit:  5,
+						Offset: 5,
+					})).
+					Times(1).
+					Return([]db.ListPaymentExportsRow{{ID: 1, MessageID: "SIMPLEBANK-1", Payments: 2, Status: db.PaymentExportSent}}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "InvalidStatus",
+			query: "?status=accepted&page_id=1&page_size=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPaymentExports(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/payment-exports"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.code, recorder.Code)
+		})
+	}
+}
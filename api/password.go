@@ -0,0 +1,219 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+)
+
+const forgotPasswordMessage = "if the email is registered, a password reset link has been sent"
+
+var (
+	errPasswordReused    = errors.New("password was used recently, choose a different one")
+	errInvalidResetToken = errors.New("reset token is invalid or has expired")
+	errWrongPassword     = errors.New("current password is incorrect")
+)
+
+// validateNewPassword checks a new password against the password policy and
+// the user's last PASSWORD_HISTORY_SIZE passwords, including the current one.
+func (server *Server) validateNewPassword(ctx *gin.Context, user db.User, password string) error {
+	if err := server.passwordPolicy.Validate(password); err != nil {
+		return err
+	}
+
+	size := server.config.PasswordHistorySize
+	if size <= 0 {
+		return nil
+	}
+
+	if server.passwordHasher.Check(password, user.HashedPassword) == nil {
+		return errPasswordReused
+	}
+
+	if size == 1 {
+		return nil
+	}
+
+	history, err := server.store.ListPasswordHistory(ctx, db.ListPasswordHistoryParams{
+		Username: user.Username,
+		Limit:    int32(size - 1),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, old := range history {
+		if server.passwordHasher.Check(password, old.HashedPassword) == nil {
+			return errPasswordReused
+		}
+	}
+
+	return nil
+}
+
+// newPasswordErrorStatus maps an error from validateNewPassword to a status code
+func newPasswordErrorStatus(err error) int {
+	if errors.Is(err, util.ErrPasswordPolicy) || errors.Is(err, errPasswordReused) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// forgotPassword emails a single-use reset token to the user. It responds the
+// same way whether or not the email is registered so it can't be used to
+// discover accounts.
+func (server *Server) forgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, req.Email)
+
+	if err == nil && user.DeactivatedAt.Valid {
+		err = sql.ErrNoRows
+	}
+
+	if err != nil {
+
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, gin.H{"status": forgotPasswordMessage})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resetToken, err := util.RandomSecret(32)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	expiresAt := time.Now().Add(server.config.PasswordResetDuration)
+
+	audit := newAuditParams(ctx, auditUserPasswordForgot, auditTargetUser, user.Username)
+	audit.Actor = user.Username
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		_, err := q.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+			HashedToken: util.HashSecret(resetToken),
+			Username:    user.Username,
+			ExpiresAt:   expiresAt,
+		})
+		return err
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	link := fmt.Sprintf("%s?token=%s", server.config.PasswordResetURL, url.QueryEscape(resetToken))
+	content := fmt.Sprintf(
+		"Hello %s,\n\nWe received a request to reset your Simple Bank password. "+
+			"Use the link below to choose a new one. It expires at %s and can only be used once.\n\n%s\n\n"+
+			"If you didn't ask for this you can ignore this email.\n",
+		user.FullName, expiresAt.UTC().Format(time.RFC1123), link,
+	)
+
+	err = server.mailer.SendEmail("Reset your password", content, []string{user.Email})
+
+	if err != nil {
+		// the response must not reveal whether the account exists
+		log.Printf("cannot send password reset email: %v", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": forgotPasswordMessage})
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hashedToken := util.HashSecret(req.Token)
+
+	resetToken, err := server.store.GetPasswordResetToken(ctx, hashedToken)
+
+	if err != nil {
+
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidResetToken))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, resetToken.Username)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.validateNewPassword(ctx, user, req.NewPassword); err != nil {
+		ctx.JSON(newPasswordErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := server.passwordHasher.Hash(req.NewPassword)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	audit := newAuditParams(ctx, auditUserPasswordReset, auditTargetUser, user.Username)
+	audit.Actor = user.Username
+
+	err = server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		HashedToken: hashedToken,
+		UpdatePasswordTxParams: db.UpdatePasswordTxParams{
+			Username:          user.Username,
+			OldHashedPassword: user.HashedPassword,
+			HashedPassword:    hashedPassword,
+			Audit:             audit,
+		},
+	})
+
+	if err != nil {
+
+		// the token was used concurrently
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidResetToken))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "password updated"})
+}
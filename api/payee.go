@@ -0,0 +1,301 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	errPayeeOwnerMismatch = errors.New("account is not held by the given owner")
+	errPayeeCoolingOff    = errors.New("payee was added too recently to receive this amount")
+)
+
+type payeeResponse struct {
+	ID         int64      `json:"id"`
+	Nickname   string     `json:"nickname"`
+	AccountID  int64      `json:"account_id"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	// CoolingOffEndsAt is when the payee can first receive large amounts
+	CoolingOffEndsAt time.Time `json:"cooling_off_ends_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (server *Server) newPayeeResponse(payee db.Payee) payeeResponse {
+	return payeeResponse{
+		ID:               payee.ID,
+		Nickname:         payee.Nickname,
+		AccountID:        payee.AccountID,
+		Verified:         payee.VerifiedAt.Valid,
+		VerifiedAt:       nullTimePtr(payee.VerifiedAt),
+		CoolingOffEndsAt: payee.CreatedAt.Add(server.config.PayeeCoolingOff),
+		CreatedAt:        payee.CreatedAt,
+	}
+}
+
+type createPayeeRequest struct {
+	Nickname  string `json:"nickname" binding:"required,max=50"`
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	// AccountOwner is optional. When given it must match the holder of the
+	// account and the payee is saved as verified.
+	AccountOwner string `json:"account_owner"`
+}
+
+func (server *Server) createPayee(ctx *gin.Context) {
+	var req createPayeeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if account.Status == db.AccountStatusClosed {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(db.ErrAccountClosed))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreatePayeeParams{
+		Owner:     authPayload.Username,
+		Nickname:  req.Nickname,
+		AccountID: req.AccountID,
+	}
+
+	if req.AccountOwner != "" {
+		if req.AccountOwner != account.Owner {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errPayeeOwnerMismatch))
+			return
+		}
+		arg.VerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	var payee db.Payee
+
+	audit := newAuditParams(ctx, auditPayeeCreate, auditTargetPayee, "")
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		payee, err = q.CreatePayee(ctx, arg)
+		audit.TargetID = strconv.FormatInt(payee.ID, 10)
+		audit.After = payee
+		return err
+	})
+
+	if err != nil {
+		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newPayeeResponse(payee))
+}
+
+type getPayeeRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getPayee(ctx *gin.Context) {
+	var req getPayeeRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payee, err := server.store.GetPayee(ctx, db.GetPayeeParams{
+		ID:    req.ID,
+		Owner: authPayload.Username,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newPayeeResponse(payee))
+}
+
+type listPayeesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+func (server *Server) listPayees(ctx *gin.Context) {
+	var req listPayeesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	payees, err := server.store.ListPayees(ctx, db.ListPayeesParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]payeeResponse, len(payees))
+
+	for i, payee := range payees {
+		rsp[i] = server.newPayeeResponse(payee)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type updatePayeeRequest struct {
+	ID       int64  `json:"id" binding:"required,min=1"`
+	Nickname string `json:"nickname" binding:"required,max=50"`
+}
+
+// updatePayee renames a payee. The target account can't be changed, a new
+// payee has to be added instead so the cooling-off period applies.
+func (server *Server) updatePayee(ctx *gin.Context) {
+	var req updatePayeeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var payee db.Payee
+
+	audit := newAuditParams(ctx, auditPayeeUpdate, auditTargetPayee, strconv.FormatInt(req.ID, 10))
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		payee, err = q.UpdatePayee(ctx, db.UpdatePayeeParams{
+			ID:       req.ID,
+			Owner:    authPayload.Username,
+			Nickname: req.Nickname,
+		})
+		audit.After = payee
+		return err
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newPayeeResponse(payee))
+}
+
+func (server *Server) deletePayee(ctx *gin.Context) {
+	var req getPayeeRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	audit := newAuditParams(ctx, auditPayeeDelete, auditTargetPayee, strconv.FormatInt(req.ID, 10))
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		payee, err := q.DeletePayee(ctx, db.DeletePayeeParams{
+			ID:    req.ID,
+			Owner: authPayload.Username,
+		})
+		audit.Before = payee
+		return err
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "payee deleted"})
+}
+
+// transferPayee looks up the caller's payee a transfer is sent to. It writes
+// the response and returns false when the payee can't be used.
+func (server *Server) transferPayee(ctx *gin.Context, payeeID int64, owner string) (db.Payee, bool) {
+	payee, err := server.store.GetPayee(ctx, db.GetPayeeParams{
+		ID:    payeeID,
+		Owner: owner,
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return payee, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return payee, false
+	}
+
+	return payee, true
+}
+
+// payeeCoolingOff reports whether a payee is still too new to receive amount
+// and when the cooling-off period ends
+func (server *Server) payeeCoolingOff(payee db.Payee, currency string, amount int64) (time.Time, bool) {
+	endsAt := payee.CreatedAt.Add(server.config.PayeeCoolingOff)
+
+	threshold, ok := server.config.PayeeCoolingOffAmounts[currency]
+	if !ok || amount <= threshold {
+		return endsAt, false
+	}
+
+	return endsAt, time.Now().Before(endsAt)
+}
@@ -0,0 +1,435 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/risk"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errPaymentRequestSelf        = errors.New("payment can't be requested from yourself")
+	errPaymentRequestExpiry      = errors.New("expires_at must be in the future and within the maximum expiry")
+	errPaymentRequestNotInvolved = errors.New("payment request doesn't involve the authenticated user")
+	errPaymentRequestNotPayer    = errors.New("payment request isn't addressed to the authenticated user")
+	errPaymentNeedsReview        = errors.New("the risk rules would hold this payment for review; send it as a transfer instead")
+)
+
+// paymentRequestResponse is a request for money with its amount as a decimal
+// in its currency. Pending requests past their expiry are reported as expired.
+type paymentRequestResponse struct {
+	ID                 int64       `json:"id"`
+	Requester          string      `json:"requester"`
+	RequesterAccountID int64       `json:"requester_account_id"`
+	Payer              string      `json:"payer"`
+	Amount             money.Money `json:"amount"`
+	Currency           string      `json:"currency"`
+	Memo               string      `json:"memo"`
+	Status             string      `json:"status"`
+	TransferID         *int64      `json:"transfer_id"`
+	ExpiresAt          time.Time   `json:"expires_at"`
+	CreatedAt          time.Time   `json:"created_at"`
+	ResolvedAt         *time.Time  `json:"resolved_at"`
+}
+
+func newPaymentRequestResponse(request db.PaymentRequest) paymentRequestResponse {
+	status := request.Status
+	if request.Expired(time.Now()) {
+		status = db.PaymentRequestExpired
+	}
+
+	return paymentRequestResponse{
+		ID:                 request.ID,
+		Requester:          request.Requester,
+		RequesterAccountID: request.RequesterAccountID,
+		Payer:              request.Payer,
+		Amount:             money.New(request.Amount, request.Currency),
+		Currency:           request.Currency,
+		Memo:               request.Memo,
+		Status:             status,
+		TransferID:         nullInt64Ptr(request.TransferID),
+		ExpiresAt:          request.ExpiresAt,
+		CreatedAt:          request.CreatedAt,
+		ResolvedAt:         nullTimePtr(request.ResolvedAt),
+	}
+}
+
+// createPaymentRequestRequest asks payer for money into one of the caller's
+// accounts. Without expires_at the request expires after the configured
+// duration.
+type createPaymentRequestRequest struct {
+	AccountID int64       `json:"account_id" binding:"required,min=1"`
+	Payer     string      `json:"payer" binding:"required,alphanum"`
+	Amount    json.Number `json:"amount" binding:"required"`
+	Currency  string      `json:"currency" binding:"required,currency"`
+	Memo      string      `json:"memo" binding:"max=140"`
+	ExpiresAt *time.Time  `json:"expires_at"`
+}
+
+func (server *Server) createPaymentRequest(ctx *gin.Context) {
+	var req createPaymentRequestRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, err := transferAmount(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if req.Payer == authPayload.Username {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errPaymentRequestSelf))
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(server.config.PaymentRequestDuration)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+
+	if !expiresAt.After(now) || (server.config.PaymentRequestMaxExpiry > 0 && expiresAt.After(now.Add(server.config.PaymentRequestMaxExpiry))) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errPaymentRequestExpiry))
+		return
+	}
+
+	account, valid := server.validAccount(ctx, req.AccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !authPayload.CanAccessAccount(account.ID) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotGranted))
+		return
+	}
+
+	payer, err := server.store.GetUser(ctx, req.Payer)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if payer.DeactivatedAt.Valid {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errUserDeactivated))
+		return
+	}
+
+	var request db.PaymentRequest
+	audit := newAuditParams(ctx, auditPaymentRequestCreate, auditTargetPaymentRequest, "")
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		request, err = q.CreatePaymentRequest(ctx, db.CreatePaymentRequestParams{
+			Requester:          authPayload.Username,
+			RequesterAccountID: account.ID,
+			Payer:              payer.Username,
+			Amount:             amount.Amount(),
+			Currency:           req.Currency,
+			Memo:               req.Memo,
+			ExpiresAt:          expiresAt,
+		})
+		audit.TargetID = strconv.FormatInt(request.ID, 10)
+		audit.After = request
+		return err
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentRequestResponse(request))
+}
+
+type listPaymentRequestsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listIncomingPaymentRequests lists the requests others sent the caller,
+// newest first
+func (server *Server) listIncomingPaymentRequests(ctx *gin.Context) {
+	var req listPaymentRequestsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	requests, err := server.store.ListIncomingPaymentRequests(ctx, db.ListIncomingPaymentRequestsParams{
+		Payer:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentRequestResponses(requests))
+}
+
+// listOutgoingPaymentRequests lists the requests the caller sent, newest first
+func (server *Server) listOutgoingPaymentRequests(ctx *gin.Context) {
+	var req listPaymentRequestsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	requests, err := server.store.ListOutgoingPaymentRequests(ctx, db.ListOutgoingPaymentRequestsParams{
+		Requester: authPayload.Username,
+		Limit:     req.PageSize,
+		Offset:    (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentRequestResponses(requests))
+}
+
+func newPaymentRequestResponses(requests []db.PaymentRequest) []paymentRequestResponse {
+	rsp := make([]paymentRequestResponse, len(requests))
+	for i, request := range requests {
+		rsp[i] = newPaymentRequestResponse(request)
+	}
+	return rsp
+}
+
+type getPaymentRequestRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// paymentRequest looks up a request the caller sent or received. It writes
+// the response and returns false when there is none.
+func (server *Server) paymentRequest(ctx *gin.Context) (db.PaymentRequest, bool) {
+	var req getPaymentRequestRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.PaymentRequest{}, false
+	}
+
+	request, err := server.store.GetPaymentRequest(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return request, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return request, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Requester != authPayload.Username && request.Payer != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errPaymentRequestNotInvolved))
+		return request, false
+	}
+
+	return request, true
+}
+
+func (server *Server) getPaymentRequest(ctx *gin.Context) {
+	request, ok := server.paymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentRequestResponse(request))
+}
+
+// declinePaymentRequest lets the payer turn down a pending request
+func (server *Server) declinePaymentRequest(ctx *gin.Context) {
+	request, ok := server.paymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Payer != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errPaymentRequestNotPayer))
+		return
+	}
+
+	audit := newAuditParams(ctx, auditPaymentRequestDecline, auditTargetPaymentRequest, strconv.FormatInt(request.ID, 10))
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		before, err := q.GetPaymentRequestForUpdate(ctx, request.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := before.CheckPending(time.Now()); err != nil {
+			return err
+		}
+
+		request, err = q.DeclinePaymentRequest(ctx, request.ID)
+		audit.Before = before
+		audit.After = request
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, db.ErrPaymentRequestNotPending) || errors.Is(err, db.ErrPaymentRequestExpired) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPaymentRequestResponse(request))
+}
+
+type payPaymentRequestRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
+}
+
+type payPaymentRequestResponse struct {
+	Request  paymentRequestResponse `json:"request"`
+	Transfer transferTxResponse     `json:"transfer"`
+}
+
+// payPaymentRequest pays a pending request from one of the payer's accounts.
+// The transfer is checked like any other; one the risk rules would hold for
+// review is refused, as the request can't wait on it.
+func (server *Server) payPaymentRequest(ctx *gin.Context) {
+	request, ok := server.paymentRequest(ctx)
+	if !ok {
+		return
+	}
+
+	var req payPaymentRequestRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Payer != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errPaymentRequestNotPayer))
+		return
+	}
+
+	if err := request.CheckPending(time.Now()); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+
+	if !server.currencies.enabled(ctx, request.Currency) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errCurrencyDisabled))
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, request.Currency)
+	if !valid {
+		return
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account does not belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !authPayload.CanAccessAccount(fromAccount.ID) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotGranted))
+		return
+	}
+
+	if server.requiresStepUp(authPayload, request.Currency, request.Amount) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errStepUpRequired))
+		return
+	}
+
+	result, err := server.riskEngine.Evaluate(ctx, risk.Transfer{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   request.RequesterAccountID,
+		Owner:         authPayload.Username,
+		Amount:        request.Amount,
+		Currency:      request.Currency,
+		Time:          time.Now(),
+	}, riskHistory{store: server.store})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.Decision != risk.Allow {
+		err := errTransferDenied
+		if result.Decision == risk.Review {
+			err = errPaymentNeedsReview
+		}
+
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"rules": result.Rules,
+		})
+		return
+	}
+
+	paid, err := server.store.PayPaymentRequestTx(ctx, db.PayPaymentRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: fromAccount.ID,
+		Limits:        server.transferLimits(request.Currency),
+		Audit:         newAuditParams(ctx, auditPaymentRequestPay, auditTargetPaymentRequest, strconv.FormatInt(request.ID, 10)),
+	})
+
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, limitErrorResponse(limitErr, request.Currency))
+			return
+		}
+
+		if errors.Is(err, db.ErrPaymentRequestNotPending) || errors.Is(err, db.ErrPaymentRequestExpired) ||
+			errors.Is(err, db.ErrAccountClosed) || errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrWithdrawalLimit) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, payPaymentRequestResponse{
+		Request:  newPaymentRequestResponse(paid.Request),
+		Transfer: newTransferTxResponse(paid.Transfer),
+	})
+}
@@ -0,0 +1,606 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPaymentRequest(requester db.Account, payer string) db.PaymentRequest {
+	return db.PaymentRequest{
+		ID:                 util.RandomInt(1, 1000),
+		Requester:          requester.Owner,
+		RequesterAccountID: requester.ID,
+		Payer:              payer,
+		Amount:             500,
+		Currency:           requester.Currency,
+		Memo:               "Dinner",
+		Status:             db.PaymentRequestPending,
+		ExpiresAt:          time.Now().Add(time.Hour),
+		CreatedAt:          time.Now(),
+	}
+}
+
+func TestCreatePaymentRequestApi(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+
+	account := randomAccount(requester.Username)
+	account.Currency = util.USD
+
+	deactivated := payer
+	deactivated.DeactivatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	body := func(changes gin.H) gin.H {
+		body := gin.H{
+			"account_id": account.ID,
+			"payer":      payer.Username,
+			"amount":     "5.00",
+			"currency":   util.USD,
+			"memo":       "Dinner",
+		}
+		for key, value := range changes {
+			body[key] = value
+		}
+		return body
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			body:     body(nil),
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+				store.EXPECT().
+					CreatePaymentRequest(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+						require.Equal(t, requester.Username, arg.Requester)
+						require.Equal(t, account.ID, arg.RequesterAccountID)
+						require.Equal(t, payer.Username, arg.Payer)
+						require.Equal(t, int64(500), arg.Amount)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Minute)
+
+						request := randomPaymentRequest(account, payer.Username)
+						request.ExpiresAt = arg.ExpiresAt
+						return request, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "5.00", res["amount"])
+				require.Equal(t, db.PaymentRequestPending, res["status"])
+			},
+		},
+		{
+			name:     "FromSelf",
+			body:     body(gin.H{"payer": requester.Username}),
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ExpiresTooLate",
+			body:     body(gin.H{"expires_at": time.Now().Add(48 * time.Hour)}),
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyExpired",
+			body:     body(gin.H{"expires_at": time.Now().Add(-time.Minute)}),
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotOwned",
+			body:     body(nil),
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "PayerNotFound",
+			body:     body(nil),
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "PayerDeactivated",
+			body:     body(nil),
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(deactivated, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "CurrencyMismatch",
+			body:     body(gin.H{"currency": util.EUR}),
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/payment-requests", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListPaymentRequestsApi(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+	account := randomAccount(requester.Username)
+
+	pending := randomPaymentRequest(account, payer.Username)
+	expired := randomPaymentRequest(account, payer.Username)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	t.Run("Incoming", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			ListIncomingPaymentRequests(gomock.Any(), gomock.Eq(db.ListIncomingPaymentRequestsParams{Payer: payer.Username, Limit: 5, Offset: 5})).
+			Times(1).
+			Return([]db.PaymentRequest{pending, expired}, nil)
+
+		server := NewTestServer(t, store)
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodGet, "/payment-requests/incoming?page_id=2&page_size=5", nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var res []gin.H
+		err = json.Unmarshal(recorder.Body.Bytes(), &res)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Equal(t, db.PaymentRequestPending, res[0]["status"])
+		require.Equal(t, db.PaymentRequestExpired, res[1]["status"])
+	})
+
+	t.Run("Outgoing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			ListOutgoingPaymentRequests(gomock.Any(), gomock.Eq(db.ListOutgoingPaymentRequestsParams{Requester: requester.Username, Limit: 5, Offset: 0})).
+			Times(1).
+			Return([]db.PaymentRequest{pending}, nil)
+		store.EXPECT().ListIncomingPaymentRequests(gomock.Any(), gomock.Any()).Times(0)
+
+		server := NewTestServer(t, store)
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodGet, "/payment-requests/outgoing?page_id=1&page_size=5", nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, requester.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
+
+func TestGetPaymentRequestApi(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+	paymentRequest := randomPaymentRequest(randomAccount(requester.Username), payer.Username)
+
+	for _, tc := range []struct {
+		username string
+		status   int
+	}{
+		{requester.Username, http.StatusOK},
+		{payer.Username, http.StatusOK},
+		{util.RandomOwner(), http.StatusUnauthorized},
+	} {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+
+		server := NewTestServer(t, store)
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/payment-requests/%d", paymentRequest.ID), nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, tc.status, recorder.Code)
+	}
+}
+
+func TestDeclinePaymentRequestApi(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+	paymentRequest := randomPaymentRequest(randomAccount(requester.Username), payer.Username)
+
+	declined := paymentRequest
+	declined.Status = db.PaymentRequestDeclined
+	declined.ResolvedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	paid := paymentRequest
+	paid.Status = db.PaymentRequestPaid
+
+	expired := paymentRequest
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetPaymentRequestForUpdate(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(declined, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.PaymentRequestDeclined, res["status"])
+			},
+		},
+		{
+			name:     "NotPayer",
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyPaid",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetPaymentRequestForUpdate(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paid, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "Expired",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().GetPaymentRequestForUpdate(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(db.PaymentRequest{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/payment-requests/%d/decline", paymentRequest.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPayPaymentRequestApi(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+
+	requesterAccount := randomAccount(requester.Username)
+	requesterAccount.Currency = util.USD
+	payerAccount := randomAccount(payer.Username)
+	payerAccount.Currency = util.USD
+
+	paymentRequest := randomPaymentRequest(requesterAccount, payer.Username)
+
+	largeRequest := paymentRequest
+	largeRequest.Amount = 5000
+
+	expired := paymentRequest
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	disabled := paymentRequest
+	disabled.Currency = "JPY"
+
+	testCases := []struct {
+		name          string
+		request       db.PaymentRequest
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			request: paymentRequest,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+
+				paid := paymentRequest
+				paid.Status = db.PaymentRequestPaid
+				paid.TransferID = sql.NullInt64{Int64: 42, Valid: true}
+
+				arg := db.PayPaymentRequestTxParams{
+					RequestID:     paymentRequest.ID,
+					FromAccountID: payerAccount.ID,
+				}
+				store.EXPECT().
+					PayPaymentRequestTx(gomock.Any(), EqAuditedParams(arg, auditPaymentRequestPay)).
+					Times(1).
+					Return(db.PayPaymentRequestTxResult{
+						Request: paid,
+						Transfer: db.TransferTxResult{
+							Transfer:    db.Transfer{ID: 42, Amount: paymentRequest.Amount},
+							FromAccount: payerAccount,
+							ToAccount:   requesterAccount,
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					Request struct {
+						Status     string `json:"status"`
+						TransferID int64  `json:"transfer_id"`
+					} `json:"request"`
+					Transfer struct {
+						Transfer struct {
+							Amount string `json:"amount"`
+						} `json:"transfer"`
+					} `json:"transfer"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.PaymentRequestPaid, res.Request.Status)
+				require.Equal(t, int64(42), res.Request.TransferID)
+				require.Equal(t, "5.00", res.Transfer.Transfer.Amount)
+			},
+		},
+		{
+			name:    "NotPayer",
+			request: paymentRequest,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, requester.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:    "Expired",
+			request: expired,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:    "CurrencyDisabled",
+			request: disabled,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errCurrencyDisabled.Error())
+			},
+		},
+		{
+			name:    "AccountNotOwned",
+			request: paymentRequest,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				otherAccount := payerAccount
+				otherAccount.Owner = util.RandomOwner()
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:    "StepUpRequired",
+			request: largeRequest,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithAuthTime(t, request, tokenMaker, payer.Username, time.Now().Add(-time.Hour))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().PayPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "PaidMeanwhile",
+			request: paymentRequest,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(payerAccount.ID)).Times(1).Return(payerAccount, nil)
+				store.EXPECT().
+					PayPaymentRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PayPaymentRequestTxResult{}, db.ErrPaymentRequestNotPending)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(tc.request.ID)).Times(1).Return(tc.request, nil)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"from_account_id": payerAccount.ID})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/payment-requests/%d/pay", tc.request.ID), bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
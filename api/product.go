@@ -0,0 +1,26 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errUnknownProduct      = errors.New("unknown account product")
+	errProductAccountLimit = errors.New("you already have the most open accounts the product allows in this currency")
+)
+
+// listAccountProducts lists the kinds of account that can be opened and
+// their rules
+func (server *Server) listAccountProducts(ctx *gin.Context) {
+	products, err := server.store.ListAccountProducts(ctx)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, products)
+}
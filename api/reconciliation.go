@@ -0,0 +1,185 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/gin-gonic/gin"
+)
+
+type listReconciliationRunsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listReconciliationRuns shows bankers the reconciliation runs, latest first
+func (server *Server) listReconciliationRuns(ctx *gin.Context) {
+	var req listReconciliationRunsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	runs, err := server.store.ListReconciliationRuns(ctx, db.ListReconciliationRunsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// reconciliationTotalResponse is the ledger totals of a currency with its
+// amounts as decimals
+type reconciliationTotalResponse struct {
+	Currency      string      `json:"currency"`
+	Accounts      int64       `json:"accounts"`
+	BalanceTotal  money.Money `json:"balance_total"`
+	EntryTotal    money.Money `json:"entry_total"`
+	InternalTotal money.Money `json:"internal_total"`
+}
+
+type reconciliationRunResponse struct {
+	ID              int64                         `json:"id"`
+	AccountsChecked int64                         `json:"accounts_checked"`
+	Discrepancies   int64                         `json:"discrepancies"`
+	StartedAt       time.Time                     `json:"started_at"`
+	FinishedAt      *time.Time                    `json:"finished_at"`
+	Totals          []reconciliationTotalResponse `json:"totals"`
+}
+
+type getReconciliationRunRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getReconciliationRun shows bankers a reconciliation run with the ledger
+// totals it found per currency
+func (server *Server) getReconciliationRun(ctx *gin.Context) {
+	var req getReconciliationRunRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	run, err := server.store.GetReconciliationRun(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	totals, err := server.store.ListReconciliationTotals(ctx, run.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := reconciliationRunResponse{
+		ID:              run.ID,
+		AccountsChecked: run.AccountsChecked,
+		Discrepancies:   run.Discrepancies,
+		StartedAt:       run.StartedAt,
+		FinishedAt:      nullTimePtr(run.FinishedAt),
+		Totals:          make([]reconciliationTotalResponse, len(totals)),
+	}
+
+	for i, total := range totals {
+		rsp.Totals[i] = reconciliationTotalResponse{
+			Currency:      total.Currency,
+			Accounts:      total.Accounts,
+			BalanceTotal:  money.New(total.BalanceTotal, total.Currency),
+			EntryTotal:    money.New(total.EntryTotal, total.Currency),
+			InternalTotal: money.New(total.InternalTotal, total.Currency),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// reconciliationDiscrepancyResponse is a discrepancy with its amounts as
+// decimals. Drift is how far the actual amount is off the expected one.
+type reconciliationDiscrepancyResponse struct {
+	ID        int64       `json:"id"`
+	RunID     int64       `json:"run_id"`
+	Kind      string      `json:"kind"`
+	AccountID *int64      `json:"account_id"`
+	Currency  string      `json:"currency"`
+	Expected  money.Money `json:"expected"`
+	Actual    money.Money `json:"actual"`
+	Drift     money.Money `json:"drift"`
+}
+
+type listReconciliationDiscrepanciesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listReconciliationDiscrepancies shows bankers the discrepancies a
+// reconciliation run found
+func (server *Server) listReconciliationDiscrepancies(ctx *gin.Context) {
+	var uri getReconciliationRunRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listReconciliationDiscrepanciesRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	run, err := server.store.GetReconciliationRun(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	discrepancies, err := server.store.ListReconciliationDiscrepancies(ctx, db.ListReconciliationDiscrepanciesParams{
+		RunID:  run.ID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]reconciliationDiscrepancyResponse, len(discrepancies))
+
+	for i, d := range discrepancies {
+		rsp[i] = reconciliationDiscrepancyResponse{
+			ID:        d.ID,
+			RunID:     d.RunID,
+			Kind:      d.Kind,
+			AccountID: nullInt64Ptr(d.AccountID),
+			Currency:  d.Currency,
+			Expected:  money.New(d.Expected, d.Currency),
+			Actual:    money.New(d.Actual, d.Currency),
+			Drift:     money.New(d.Actual-d.Expected, d.Currency),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
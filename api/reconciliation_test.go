@@ -0,0 +1,222 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomReconciliationRun() db.ReconciliationRun {
+	return db.ReconciliationRun{
+		ID:              util.RandomInt(1, 1000),
+		AccountsChecked: util.RandomInt(1, 1000),
+		Discrepancies:   1,
+		StartedAt:       time.Now(),
+		FinishedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+	}
+}
+
+func TestListReconciliationRunsApi(t *testing.T) {
+	runs := []db.ReconciliationRun{randomReconciliationRun(), randomReconciliationRun()}
+
+	testCases := []struct {
+		name          string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: util.BankerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListReconciliationRuns(gomock.Any(), gomock.Eq(db.ListReconciliationRunsParams{
+						Limit:  5,
+						Offset: 0,
+					})).
+					Times(1).
+					Return(runs, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res []db.ReconciliationRun
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res, 2)
+			},
+		},
+		{
+			name: "NotBanker",
+			role: util.DepositorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListReconciliationRuns(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/reconciliation-runs?page_id=1&page_size=5", nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, util.RandomOwner(), tc.role)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetReconciliationRunApi(t *testing.T) {
+	run := randomReconciliationRun()
+	totals := []db.ReconciliationTotal{
+		{
+			RunID:         run.ID,
+			Currency:      util.USD,
+			Accounts:      2,
+			BalanceTotal:  12345,
+			EntryTotal:    12300,
+			InternalTotal: 0,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetReconciliationRun(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(run, nil)
+				store.EXPECT().ListReconciliationTotals(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(totals, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					ID     int64 `json:"id"`
+					Totals []struct {
+						Currency     string `json:"currency"`
+						BalanceTotal string `json:"balance_total"`
+						EntryTotal   string `json:"entry_total"`
+					} `json:"totals"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, run.ID, res.ID)
+				require.Len(t, res.Totals, 1)
+				require.Equal(t, "123.45", res.Totals[0].BalanceTotal)
+				require.Equal(t, "123.00", res.Totals[0].EntryTotal)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetReconciliationRun(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(db.ReconciliationRun{}, sql.ErrNoRows)
+				store.EXPECT().ListReconciliationTotals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/reconciliation-runs/%d", run.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, util.RandomOwner(), util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListReconciliationDiscrepanciesApi(t *testing.T) {
+	run := randomReconciliationRun()
+	account := randomAccount(util.RandomOwner())
+	discrepancies := []db.ReconciliationDiscrepancy{
+		{
+			ID:        1,
+			RunID:     run.ID,
+			Kind:      db.DiscrepancyAccountDrift,
+			AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+			Currency:  util.USD,
+			Expected:  1000,
+			Actual:    1250,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetReconciliationRun(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(run, nil)
+	store.EXPECT().
+		ListReconciliationDiscrepancies(gomock.Any(), gomock.Eq(db.ListReconciliationDiscrepanciesParams{
+			RunID:  run.ID,
+			Limit:  5,
+			Offset: 0,
+		})).
+		Times(1).
+		Return(discrepancies, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/reconciliation-runs/%d/discrepancies?page_id=1&page_size=5", run.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorizationWithRole(t, request, server.tokenMaker, util.RandomOwner(), util.BankerRole)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res []struct {
+		AccountID *int64 `json:"account_id"`
+		Expected  string `json:"expected"`
+		Actual    string `json:"actual"`
+		Drift     string `json:"drift"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Len(t, res, 1)
+	require.Equal(t, account.ID, *res[0].AccountID)
+	require.Equal(t, "10.00", res[0].Expected)
+	require.Equal(t, "12.50", res[0].Actual)
+	require.Equal(t, "2.50", res[0].Drift)
+}
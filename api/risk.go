@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/risk"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+)
+
+var errTransferDenied = errors.New("transfer denied by risk rules")
+
+var errExternalTransferNeedsReview = errors.New("the risk rules would hold this transfer for review, which external transfers can't be")
+
+// riskHistory answers the risk engine's questions from the store
+type riskHistory struct {
+	store db.Store
+}
+
+func (history riskHistory) CountTransfersBetween(ctx context.Context, fromAccountID int64, toAccountID int64) (int64, error) {
+	return history.store.CountTransfersBetween(ctx, db.CountTransfersBetweenParams{
+		FromAccountID: fromAccountID,
+		ToAccountID:   toAccountID,
+	})
+}
+
+func (history riskHistory) CountTransfersSince(ctx context.Context, fromAccountID int64, since time.Time) (int64, error) {
+	return history.store.CountTransfersSince(ctx, db.CountTransfersSinceParams{
+		FromAccountID: fromAccountID,
+		CreatedAt:     since,
+	})
+}
+
+// transferReviewResponse is a held transfer with its amount as a decimal
+type transferReviewResponse struct {
+	ID            int64           `json:"id"`
+	FromAccountID int64           `json:"from_account_id"`
+	ToAccountID   int64           `json:"to_account_id"`
+	Amount        money.Money     `json:"amount"`
+	Currency      string          `json:"currency"`
+	Memo          string          `json:"memo"`
+	Reference     string          `json:"reference"`
+	Metadata      json.RawMessage `json:"metadata"`
+	RequestedBy   string          `json:"requested_by"`
+	Rules         []string        `json:"rules"`
+	Status        string          `json:"status"`
+	ReviewedBy    string          `json:"reviewed_by"`
+	ReviewedAt    *time.Time      `json:"reviewed_at"`
+	TransferID    *int64          `json:"transfer_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func newTransferReviewResponse(review db.TransferReview) transferReviewResponse {
+	return transferReviewResponse{
+		ID:            review.ID,
+		FromAccountID: review.FromAccountID,
+		ToAccountID:   review.ToAccountID,
+		Amount:        money.New(review.Amount, review.Currency),
+		Currency:      review.Currency,
+		Memo:          review.Memo,
+		Reference:     review.Reference,
+		Metadata:      review.Metadata,
+		RequestedBy:   review.RequestedBy,
+		Rules:         review.Rules,
+		Status:        review.Status,
+		ReviewedBy:    review.ReviewedBy.String,
+		ReviewedAt:    nullTimePtr(review.ReviewedAt),
+		TransferID:    nullInt64Ptr(review.TransferID),
+		CreatedAt:     review.CreatedAt,
+	}
+}
+
+type approveTransferReviewResponse struct {
+	Review   transferReviewResponse `json:"review"`
+	Transfer transferTxResponse     `json:"transfer"`
+}
+
+// evaluateTransferRisk runs the risk rules on a transfer. It writes the
+// response and returns false unless the transfer may go ahead right away.
+func (server *Server) evaluateTransferRisk(ctx *gin.Context, arg db.TransferTxParams, currency string, owner string) bool {
+	transfer := risk.Transfer{
+		FromAccountID: arg.FromAccID,
+		ToAccountID:   arg.ToAccID,
+		Owner:         owner,
+		Amount:        arg.Amount,
+		Currency:      currency,
+		Time:          time.Now(),
+	}
+
+	result, err := server.riskEngine.Evaluate(ctx, transfer, riskHistory{store: server.store})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	switch result.Decision {
+	case risk.Deny:
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": errTransferDenied.Error(),
+			"rules": result.Rules,
+		})
+		return false
+
+	case risk.Review:
+		// held transfers are made to an account once approved, which an
+		// external beneficiary doesn't have
+		if arg.BeneficiaryID != 0 {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": errExternalTransferNeedsReview.Error(),
+				"rules": result.Rules,
+			})
+			return false
+		}
+
+		var review db.TransferReview
+		audit := newAuditParams(ctx, auditTransferReviewCreate, auditTargetTransferReview, "")
+
+		err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+			var err error
+			review, err = createTransferReview(ctx, q, arg, currency, owner, result.Rules)
+			audit.TargetID = strconv.FormatInt(review.ID, 10)
+			audit.After = review
+			return err
+		})
+
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return false
+		}
+
+		ctx.JSON(http.StatusAccepted, newTransferReviewResponse(review))
+		return false
+	}
+
+	return true
+}
+
+// createTransferReview holds a transfer the risk rules flagged for a banker
+// to review
+func createTransferReview(ctx context.Context, q db.Querier, arg db.TransferTxParams, currency string, owner string, rules []string) (db.TransferReview, error) {
+	metadata := arg.Metadata
+	if len(metadata) == 0 {
+		metadata = json.RawMessage("{}")
+	}
+
+	return q.CreateTransferReview(ctx, db.CreateTransferReviewParams{
+		FromAccountID: arg.FromAccID,
+		ToAccountID:   arg.ToAccID,
+		Amount:        arg.Amount,
+		Currency:      currency,
+		RequestedBy:   owner,
+		Rules:         rules,
+		Memo:          arg.Memo,
+		Reference:     arg.Reference,
+		Metadata:      metadata,
+	})
+}
+
+type listTransferReviewsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listTransferReviews shows bankers the transfers held by the risk rules,
+// pending ones unless asked otherwise
+func (server *Server) listTransferReviews(ctx *gin.Context) {
+	var req listTransferReviewsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Status == "" {
+		req.Status = db.TransferReviewPending
+	}
+
+	reviews, err := server.store.ListTransferReviews(ctx, db.ListTransferReviewsParams{
+		Status: req.Status,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]transferReviewResponse, len(reviews))
+
+	for i, review := range reviews {
+		rsp[i] = newTransferReviewResponse(review)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type transferReviewRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// approveTransferReview releases a held transfer
+func (server *Server) approveTransferReview(ctx *gin.Context) {
+	var req transferReviewRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := server.store.GetTransferReview(ctx, req.ID)
+	if err != nil {
+		writeTransferReviewError(ctx, err)
+		return
+	}
+
+	if !server.currencies.enabled(ctx, review.Currency) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errCurrencyDisabled))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.ApproveTransferReviewTxParams{
+		ReviewID:   req.ID,
+		ReviewedBy: authPayload.Username,
+		Limits:     server.transferLimits(review.Currency),
+		Audit:      newAuditParams(ctx, auditTransferReviewApprove, auditTargetTransferReview, strconv.FormatInt(req.ID, 10)),
+	}
+
+	result, err := server.store.ApproveTransferReviewTx(ctx, arg)
+
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, limitErrorResponse(limitErr, review.Currency))
+			return
+		}
+
+		writeTransferReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, approveTransferReviewResponse{
+		Review:   newTransferReviewResponse(result.Review),
+		Transfer: newTransferTxResponse(result.Transfer),
+	})
+}
+
+// rejectTransferReview cancels a held transfer; no money moves
+func (server *Server) rejectTransferReview(ctx *gin.Context) {
+	var req transferReviewRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var review db.TransferReview
+	audit := newAuditParams(ctx, auditTransferReviewReject, auditTargetTransferReview, strconv.FormatInt(req.ID, 10))
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		before, err := q.GetTransferReviewForUpdate(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if before.Status != db.TransferReviewPending {
+			return db.ErrReviewNotPending
+		}
+
+		review, err = q.RejectTransferReview(ctx, db.RejectTransferReviewParams{
+			ReviewedBy: authPayload.Username,
+			ID:         req.ID,
+		})
+		audit.Before = before
+		audit.After = review
+		return err
+	})
+
+	if err != nil {
+		writeTransferReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newTransferReviewResponse(review))
+}
+
+func writeTransferReviewError(ctx *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, db.ErrReviewNotPending):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrAccountClosed), errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrWithdrawalLimit):
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
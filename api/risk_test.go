@@ -0,0 +1,419 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/risk"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTransferReview(from db.Account, to db.Account) db.TransferReview {
+	return db.TransferReview{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        util.RandomMoney(),
+		Currency:      from.Currency,
+		RequestedBy:   from.Owner,
+		Rules:         []string{"new_payee"},
+		Status:        db.TransferReviewPending,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// transferReviewBody decodes a transfer review response, keeping the amount
+// as the decimal string it is sent as
+type transferReviewBody struct {
+	transferReviewResponse
+	Amount string `json:"amount"`
+}
+
+func TestCreateTransferRiskApi(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	amount := int64(500)
+	review := randomTransferReview(account1, account2)
+	review.Amount = amount
+
+	testCases := []struct {
+		name          string
+		rules         []risk.Rule
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Review",
+			rules: []risk.Rule{&risk.NewPayeeAmountRule{RuleName: "new_payee", Outcome: risk.Review, MinAmounts: map[string]int64{util.USD: 100}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersBetween(gomock.Any(), gomock.Eq(db.CountTransfersBetweenParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+				})).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateTransferReview(gomock.Any(), gomock.Eq(db.CreateTransferReviewParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        amount,
+					Currency:      util.USD,
+					RequestedBy:   user1.Username,
+					Rules:         []string{"new_payee"},
+					Memo:          "rent",
+					Metadata:      json.RawMessage("{}"),
+				})).Times(1).Return(review, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var res transferReviewBody
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, review.ID, res.ID)
+				require.Equal(t, "5.00", res.Amount)
+				require.Equal(t, db.TransferReviewPending, res.Status)
+			},
+		},
+		{
+			name:  "Deny",
+			rules: []risk.Rule{&risk.VelocityRule{RuleName: "velocity", Outcome: risk.Deny, Window: time.Minute, MaxCount: 3}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().CreateTransferReview(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var res gin.H
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, errTransferDenied.Error(), res["error"])
+				require.Equal(t, []interface{}{"velocity"}, res["rules"])
+			},
+		},
+		{
+			name:  "Allow",
+			rules: []risk.Rule{&risk.NewPayeeAmountRule{RuleName: "new_payee", Outcome: risk.Review, MinAmounts: map[string]int64{util.USD: 100}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersBetween(gomock.Any(), gomock.Any()).Times(1).Return(int64(2), nil)
+				store.EXPECT().CreateTransferReview(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "HistoryError",
+			rules: []risk.Rule{&risk.VelocityRule{RuleName: "velocity", Outcome: risk.Deny, Window: time.Minute, MaxCount: 3}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), sql.ErrConnDone)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			server.riskEngine = risk.NewEngine(tc.rules...)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          money.New(amount, util.USD),
+				"currency":        util.USD,
+				"memo":            "rent",
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user1.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestApproveTransferReviewApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account1 := randomAccount(util.RandomOwner())
+	account2 := randomAccount(util.RandomOwner())
+	account2.Currency = account1.Currency
+	review := randomTransferReview(account1, account2)
+
+	approved := review
+	approved.Status = db.TransferReviewApproved
+	approved.ReviewedBy = sql.NullString{String: banker, Valid: true}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+
+				arg := db.ApproveTransferReviewTxParams{
+					ReviewID:   review.ID,
+					ReviewedBy: banker,
+				}
+				store.EXPECT().
+					ApproveTransferReviewTx(gomock.Any(), EqAuditedParams(arg, auditTransferReviewApprove)).
+					Times(1).
+					Return(db.ApproveTransferReviewTxResult{Review: approved}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					Review transferReviewBody `json:"review"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.TransferReviewApproved, res.Review.Status)
+			},
+		},
+		{
+			name: "NotBanker",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.DepositorRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ApproveTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(db.TransferReview{}, sql.ErrNoRows)
+				store.EXPECT().ApproveTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotPending",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+				store.EXPECT().
+					ApproveTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTransferReviewTxResult{}, db.ErrReviewNotPending)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "AccountFrozen",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithRole(t, request, tokenMaker, banker, util.BankerRole)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+				store.EXPECT().
+					ApproveTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveTransferReviewTxResult{}, db.ErrAccountFrozen)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfer-reviews/%d/approve", review.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRejectTransferReviewApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account1 := randomAccount(util.RandomOwner())
+	account2 := randomAccount(util.RandomOwner())
+	review := randomTransferReview(account1, account2)
+
+	rejected := review
+	rejected.Status = db.TransferReviewRejected
+	rejected.ReviewedBy = sql.NullString{String: banker, Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReviewForUpdate(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(review, nil)
+				store.EXPECT().RejectTransferReview(gomock.Any(), gomock.Eq(db.RejectTransferReviewParams{
+					ReviewedBy: banker,
+					ID:         review.ID,
+				})).Times(1).Return(rejected, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res transferReviewBody
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.TransferReviewRejected, res.Status)
+			},
+		},
+		{
+			name: "NotPending",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReviewForUpdate(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(rejected, nil)
+				store.EXPECT().RejectTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReviewForUpdate(gomock.Any(), gomock.Eq(review.ID)).Times(1).Return(db.TransferReview{}, sql.ErrNoRows)
+				store.EXPECT().RejectTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/transfer-reviews/%d/reject", review.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTransferReviewsApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account1 := randomAccount(util.RandomOwner())
+	account2 := randomAccount(util.RandomOwner())
+	reviews := []db.TransferReview{
+		randomTransferReview(account1, account2),
+		randomTransferReview(account2, account1),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListTransferReviews(gomock.Any(), gomock.Eq(db.ListTransferReviewsParams{
+			Status: db.TransferReviewPending,
+			Limit:  5,
+			Offset: 0,
+		})).
+		Times(1).
+		Return(reviews, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/transfer-reviews?page_id=1&page_size=5", nil)
+	require.NoError(t, err)
+
+	addAuthorizationWithRole(t, request, server.tokenMaker, banker, util.BankerRole)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res []transferReviewBody
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+}
@@ -0,0 +1,29 @@
+package api
+
+// Scopes that can be granted to API keys
+const (
+	scopeUsersRead      = "users:read"
+	scopeAccountsRead   = "accounts:read"
+	scopeAccountsWrite  = "accounts:write"
+	scopeEntriesRead    = "entries:read"
+	scopeTransfersRead  = "transfers:read"
+	scopeTransfersWrite = "transfers:write"
+)
+
+var apiKeyScopes = []string{
+	scopeUsersRead,
+	scopeAccountsRead,
+	scopeAccountsWrite,
+	scopeEntriesRead,
+	scopeTransfersRead,
+	scopeTransfersWrite,
+}
+
+func isSupportedScope(scope string) bool {
+	for _, s := range apiKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
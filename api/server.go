@@ -2,8 +2,11 @@ package api
 
 import (
 	"fmt"
+	"sync"
 
 	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/mail"
+	"github.com/Srinath-exe/simplebank/risk"
 	"github.com/Srinath-exe/simplebank/token"
 	"github.com/Srinath-exe/simplebank/util"
 	"github.com/gin-gonic/gin"
@@ -12,22 +15,61 @@ import (
 )
 
 type Server struct {
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
-	config     util.Config
+	store          db.Store
+	tokenMaker     token.Maker
+	router         *gin.Engine
+	config         util.Config
+	passwordPolicy util.PasswordPolicy
+	passwordHasher util.PasswordHasher
+	mailer         mail.EmailSender
+	riskEngine     *risk.Engine
+	currencies     *currencyRegistry
+	// batches are the transfer batches being made in the background
+	batches sync.WaitGroup
 }
 
 // NewServer creates a new HTTP server and set up routing.
 func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	tokenMaker, err := newTokenMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
-	server := &Server{store: store, tokenMaker: tokenMaker, config: config}
+
+	passwordPolicy, err := util.NewPasswordPolicy(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password policy: %w", err)
+	}
+
+	passwordHasher, err := util.NewPasswordHasher(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password hasher: %w", err)
+	}
+
+	riskEngine, err := risk.LoadEngine(config.RiskRulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load risk rules: %w", err)
+	}
+
+	mailer := mail.NewSMTPSender(config.EmailSenderName, config.EmailSenderAddress, config.SMTPAddress, config.SMTPUsername, config.SMTPPassword)
+
+	server := &Server{
+		store:          store,
+		tokenMaker:     tokenMaker,
+		config:         config,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		mailer:         mailer,
+		riskEngine:     riskEngine,
+		currencies:     newCurrencyRegistry(store, config.CurrencyCacheTTL),
+	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("currency", server.validCurrency)
+		v.RegisterValidation("scope", validScope)
+		v.RegisterValidation("freeze_reason", validFreezeReason)
+		v.RegisterValidation("iban", validIBAN)
+		v.RegisterValidation("bic", validBIC)
+		v.RegisterValidation("aba_routing", validABARouting)
 	}
 
 	server.setupRouter()
@@ -35,6 +77,31 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	return server, nil
 }
 
+// newTokenMaker creates the token maker selected by TOKEN_MAKER_TYPE.
+// Asymmetric makers load their keys from TOKEN_KEYSET_DIR.
+func newTokenMaker(config util.Config) (token.Maker, error) {
+	switch config.TokenMakerType {
+	case "", token.MakerTypePasetoLocal:
+		return token.NewPasetoMaker(config.TokenSymmetricKey)
+	case token.MakerTypeJWTHS256:
+		return token.NewJWTMaker(config.TokenSymmetricKey)
+	}
+
+	keySet, err := token.LoadKeySet(config.TokenKeySetDir, config.TokenActiveKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.TokenMakerType {
+	case token.MakerTypePasetoPublic:
+		return token.NewPasetoPublicMaker(keySet)
+	case token.MakerTypeJWTPublic:
+		return token.NewJWTAsymmetricMaker(keySet)
+	}
+
+	return nil, fmt.Errorf("unsupported token maker type %q", config.TokenMakerType)
+}
+
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
@@ -45,33 +112,109 @@ func errorResponse(err error) gin.H {
 
 func (server *Server) setupRouter() {
 	router := gin.Default()
+	router.Use(requestIDMiddleware())
 
 	router.POST("/users", server.createUser)
 	router.POST("users/login", server.loginUser)
-
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
-
-	authRoutes.GET("/users/:username", server.getUser)
-	authRoutes.POST("/users/update-password", server.updatePassword)
-	authRoutes.DELETE("/users/delete/:username", server.deleteUser)
-	authRoutes.POST("/users/search", server.searchUsers)
-	authRoutes.POST("/fetch-users", server.getUsers)
-
-	authRoutes.POST("/accounts", server.createAccount)
-	authRoutes.GET("/accounts/:id", server.getAccount)
-	authRoutes.GET("/accounts", server.getAccountsList)
-	authRoutes.DELETE("/accounts/delete/:id", server.deleteAccount)
-	authRoutes.POST("/accounts/update", server.updateAccount)
-	authRoutes.POST("/accounts/search", server.searchAccounts)
-
-	authRoutes.POST("/entries/search", server.searchEntries)
-	authRoutes.GET("/entries/:id", server.getEntry)
-	authRoutes.POST("/entries", server.listEntriesFromAccountId)
-
-	authRoutes.POST("/transfers", server.createTransfer)
-	authRoutes.GET("/transfers/:id", server.getTransfer)
-	authRoutes.POST("/transfers/account", server.listTransfersFromAccountId)
-	authRoutes.POST("/transfers/search", server.searchTransfers)
+	router.POST("/users/password/forgot", server.forgotPassword)
+	router.POST("/users/password/reset", server.resetPassword)
+	router.GET("/.well-known/jwks.json", server.getJWKS)
+	router.POST("/oauth/token", server.oauthToken)
+	router.POST("/oauth/introspect", server.introspectOAuthToken)
+	router.GET("/currencies", server.listCurrencies)
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store))
+
+	authRoutes.GET("/users/:username", requireScope(scopeUsersRead), server.getUser)
+	authRoutes.POST("/users/reauthenticate", requireSession(), server.reauthenticateUser)
+	authRoutes.POST("/users/update-password", requireSession(), server.updatePassword)
+	authRoutes.DELETE("/users/delete/:username", requireSession(), server.deleteUser)
+	authRoutes.POST("/users/search", requireScope(scopeUsersRead), server.searchUsers)
+	authRoutes.POST("/fetch-users", requireScope(scopeUsersRead), server.getUsers)
+
+	authRoutes.POST("/api-keys", requireSession(), server.createAPIKey)
+	authRoutes.GET("/api-keys", requireSession(), server.listAPIKeys)
+	authRoutes.DELETE("/api-keys/:id", requireSession(), server.revokeAPIKey)
+
+	authRoutes.POST("/oauth/clients", requireSession(), server.registerOAuthClient)
+	authRoutes.POST("/oauth/authorize", requireSession(), server.authorizeOAuthClient)
+	authRoutes.GET("/oauth/consents", requireSession(), server.listOAuthConsents)
+	authRoutes.DELETE("/oauth/consents/:id", requireSession(), server.revokeOAuthConsent)
+
+	authRoutes.POST("/accounts", requireScope(scopeAccountsWrite), server.createAccount)
+	authRoutes.GET("/accounts/:id", requireScope(scopeAccountsRead), server.getAccount)
+	authRoutes.GET("/accounts", requireScope(scopeAccountsRead), server.getAccountsList)
+	authRoutes.GET("/accounts/:id/limits", requireScope(scopeAccountsRead), server.getAccountLimits)
+	authRoutes.GET("/accounts/:id/interest", requireScope(scopeAccountsRead), server.getAccountInterest)
+	authRoutes.DELETE("/accounts/delete/:id", requireScope(scopeAccountsWrite), server.deleteAccount)
+	authRoutes.POST("/accounts/update", requireSession(), requireRole(util.BankerRole), server.updateAccount)
+	authRoutes.POST("/accounts/close", requireScope(scopeAccountsWrite), server.closeAccount)
+	authRoutes.POST("/accounts/nickname", requireScope(scopeAccountsWrite), server.renameAccount)
+	authRoutes.POST("/accounts/freeze", requireSession(), requireRole(util.BankerRole), server.freezeAccount)
+	authRoutes.POST("/accounts/unfreeze", requireSession(), requireRole(util.BankerRole), server.unfreezeAccount)
+	authRoutes.GET("/accounts/:id/freezes", requireSession(), requireRole(util.BankerRole), server.listAccountFreezes)
+	authRoutes.POST("/accounts/search", requireScope(scopeAccountsRead), server.searchAccounts)
+	authRoutes.GET("/account-products", requireScope(scopeAccountsRead), server.listAccountProducts)
+	authRoutes.GET("/interest-rates", requireScope(scopeAccountsRead), server.listInterestRates)
+	authRoutes.POST("/interest-rates", requireSession(), requireRole(util.BankerRole), server.createInterestRate)
+	authRoutes.GET("/fee-schedules", requireScope(scopeAccountsRead), server.listFeeSchedules)
+	authRoutes.POST("/fee-schedules", requireSession(), requireRole(util.BankerRole), server.upsertFeeSchedule)
+
+	authRoutes.POST("/entries/search", requireScope(scopeEntriesRead), server.searchEntries)
+	authRoutes.GET("/entries/:id", requireScope(scopeEntriesRead), server.getEntry)
+	authRoutes.POST("/entries", requireScope(scopeEntriesRead), server.listEntriesFromAccountId)
+
+	authRoutes.POST("/payees", requireScope(scopeTransfersWrite), server.createPayee)
+	authRoutes.GET("/payees", requireScope(scopeTransfersRead), server.listPayees)
+	authRoutes.GET("/payees/:id", requireScope(scopeTransfersRead), server.getPayee)
+	authRoutes.POST("/payees/update", requireScope(scopeTransfersWrite), server.updatePayee)
+	authRoutes.DELETE("/payees/:id", requireScope(scopeTransfersWrite), server.deletePayee)
+
+	authRoutes.POST("/beneficiaries", requireScope(scopeTransfersWrite), server.createBeneficiary)
+	authRoutes.GET("/beneficiaries", requireScope(scopeTransfersRead), server.listBeneficiaries)
+	authRoutes.GET("/beneficiaries/:id", requireScope(scopeTransfersRead), server.getBeneficiary)
+	authRoutes.DELETE("/beneficiaries/:id", requireScope(scopeTransfersWrite), server.deleteBeneficiary)
+
+	authRoutes.POST("/payment-requests", requireScope(scopeTransfersWrite), server.createPaymentRequest)
+	authRoutes.GET("/payment-requests/incoming", requireScope(scopeTransfersRead), server.listIncomingPaymentRequests)
+	authRoutes.GET("/payment-requests/outgoing", requireScope(scopeTransfersRead), server.listOutgoingPaymentRequests)
+	authRoutes.GET("/payment-requests/:id", requireScope(scopeTransfersRead), server.getPaymentRequest)
+	authRoutes.POST("/payment-requests/:id/decline", requireScope(scopeTransfersWrite), server.declinePaymentRequest)
+	authRoutes.POST("/payment-requests/:id/pay", requireScope(scopeTransfersWrite), server.payPaymentRequest)
+
+	authRoutes.POST("/transfers", requireScope(scopeTransfersWrite), server.createTransfer)
+	authRoutes.GET("/transfers/:id", requireScope(scopeTransfersRead), server.getTransfer)
+	authRoutes.POST("/transfers/account", requireScope(scopeTransfersRead), server.listTransfersFromAccountId)
+	authRoutes.POST("/transfers/search", requireScope(scopeTransfersRead), server.searchTransfers)
+	authRoutes.POST("/transfers/batch", requireScope(scopeTransfersWrite), server.createTransferBatch)
+	authRoutes.GET("/transfer-batches/:id", requireScope(scopeTransfersRead), server.getTransferBatch)
+
+	authRoutes.GET("/outbound-payments/:id", requireScope(scopeTransfersRead), server.getOutboundPayment)
+	authRoutes.GET("/outbound-payments", requireSession(), requireRole(util.BankerRole), server.listOutboundPayments)
+	authRoutes.POST("/outbound-payments/:id/settle", requireSession(), requireRole(util.BankerRole), server.settleOutboundPayment)
+	authRoutes.POST("/outbound-payments/:id/return", requireSession(), requireRole(util.BankerRole), server.returnOutboundPayment)
+	authRoutes.POST("/payment-exports", requireSession(), requireRole(util.BankerRole), server.createPaymentExport)
+	authRoutes.GET("/payment-exports", requireSession(), requireRole(util.BankerRole), server.listPaymentExports)
+	authRoutes.GET("/payment-exports/:id", requireSession(), requireRole(util.BankerRole), server.getPaymentExport)
+	authRoutes.GET("/payment-exports/:id/document", requireSession(), requireRole(util.BankerRole), server.getPaymentExportDocument)
+	authRoutes.POST("/payment-exports/status-reports", requireSession(), requireRole(util.BankerRole), server.importPaymentStatusReport)
+
+	authRoutes.GET("/transfer-reviews", requireSession(), requireRole(util.BankerRole), server.listTransferReviews)
+	authRoutes.POST("/transfer-reviews/:id/approve", requireSession(), requireRole(util.BankerRole), server.approveTransferReview)
+	authRoutes.POST("/transfer-reviews/:id/reject", requireSession(), requireRole(util.BankerRole), server.rejectTransferReview)
+
+	authRoutes.GET("/audit-events", requireSession(), requireRole(util.BankerRole), server.listAuditEvents)
+
+	authRoutes.GET("/reconciliation-runs", requireSession(), requireRole(util.BankerRole), server.listReconciliationRuns)
+	authRoutes.GET("/reconciliation-runs/:id", requireSession(), requireRole(util.BankerRole), server.getReconciliationRun)
+	authRoutes.GET("/reconciliation-runs/:id/discrepancies", requireSession(), requireRole(util.BankerRole), server.listReconciliationDiscrepancies)
+
+	authRoutes.POST("/statement-imports", requireSession(), requireRole(util.BankerRole), server.importStatement)
+	authRoutes.GET("/statement-imports", requireSession(), requireRole(util.BankerRole), server.listStatementImports)
+	authRoutes.GET("/statement-imports/:id", requireSession(), requireRole(util.BankerRole), server.getStatementImport)
+
+	authRoutes.POST("/currencies/:code/enable", requireSession(), requireRole(util.BankerRole), server.enableCurrency)
+	authRoutes.POST("/currencies/:code/disable", requireSession(), requireRole(util.BankerRole), server.disableCurrency)
 
 	// search routes
 
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/statement"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	errStatementFormat   = errors.New("cannot tell the statement format from the file name, set format")
+	errStatementEmpty    = errors.New("statement has no booked lines")
+	errStatementCurrency = errors.New("currency doesn't match the account")
+	errStatementMatched  = errors.New("an entry was matched by another import meanwhile, retry the import")
+)
+
+// importStatementRequest is a statement file uploaded as multipart form data,
+// with the account it is matched against. The column fields map the columns
+// of CSV statements; see statement.CSVMapping.
+type importStatementRequest struct {
+	AccountID         int64  `form:"account_id" binding:"required,min=1"`
+	Format            string `form:"format" binding:"omitempty,oneof=csv ofx camt053"`
+	DateColumn        string `form:"date_column"`
+	AmountColumn      string `form:"amount_column"`
+	CreditColumn      string `form:"credit_column"`
+	DebitColumn       string `form:"debit_column"`
+	CurrencyColumn    string `form:"currency_column"`
+	ReferenceColumn   string `form:"reference_column"`
+	DescriptionColumn string `form:"description_column"`
+	DateLayout        string `form:"date_layout"`
+	Delimiter         string `form:"delimiter"`
+}
+
+// statementLineResponse is a statement line with its amount as a decimal
+type statementLineResponse struct {
+	ID          int64       `json:"id"`
+	Position    int32       `json:"position"`
+	BookedOn    string      `json:"booked_on"`
+	Amount      money.Money `json:"amount"`
+	Reference   string      `json:"reference"`
+	Description string      `json:"description"`
+	EntryID     *int64      `json:"entry_id"`
+	TransferID  *int64      `json:"transfer_id"`
+}
+
+func newStatementLineResponse(line db.StatementLine) statementLineResponse {
+	return statementLineResponse{
+		ID:          line.ID,
+		Position:    line.Position,
+		BookedOn:    line.BookedOn.Format(time.DateOnly),
+		Amount:      money.New(line.Amount, line.Currency),
+		Reference:   line.Reference,
+		Description: line.Description,
+		EntryID:     nullInt64Ptr(line.EntryID),
+		TransferID:  nullInt64Ptr(line.TransferID),
+	}
+}
+
+// statementReportResponse is an import with the lines that matched no entry
+type statementReportResponse struct {
+	Import    db.StatementImport      `json:"import"`
+	Unmatched []statementLineResponse `json:"unmatched"`
+}
+
+func newStatementReportResponse(statementImport db.StatementImport, unmatched []db.StatementLine) statementReportResponse {
+	rsp := statementReportResponse{
+		Import:    statementImport,
+		Unmatched: make([]statementLineResponse, len(unmatched)),
+	}
+
+	for i, line := range unmatched {
+		rsp.Unmatched[i] = newStatementLineResponse(line)
+	}
+
+	return rsp
+}
+
+// importStatement stages the lines of a statement from a partner bank and
+// matches them to the entries of the account, reporting the lines left
+// unmatched for a banker to look into
+func (server *Server) importStatement(ctx *gin.Context) {
+	var req importStatementRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = statement.DetectFormat(header.Filename)
+	}
+
+	if req.Format == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errStatementFormat))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	lines, err := statement.Parse(file, req.Format, statement.Options{
+		Currency: account.Currency,
+		CSV: statement.CSVMapping{
+			Date:        req.DateColumn,
+			Amount:      req.AmountColumn,
+			Credit:      req.CreditColumn,
+			Debit:       req.DebitColumn,
+			Currency:    req.CurrencyColumn,
+			Reference:   req.ReferenceColumn,
+			Description: req.DescriptionColumn,
+			DateLayout:  req.DateLayout,
+			Delimiter:   req.Delimiter,
+		},
+	})
+	if err == nil && len(lines) == 0 {
+		err = errStatementEmpty
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ImportStatementTxParams{
+		AccountID:   account.ID,
+		Format:      req.Format,
+		Filename:    header.Filename,
+		MatchWindow: server.config.StatementMatchWindow,
+		Lines:       make([]db.StatementLineParams, len(lines)),
+	}
+
+	for i, line := range lines {
+		if line.Currency != account.Currency {
+			err := fmt.Errorf("statement line %d: %w", line.Position, errStatementCurrency)
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
+		arg.Lines[i] = db.StatementLineParams{
+			Position:    int32(line.Position),
+			BookedOn:    line.BookedOn,
+			Amount:      line.Amount,
+			Currency:    line.Currency,
+			Reference:   line.Reference,
+			Description: line.Description,
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg.ImportedBy = authPayload.Username
+	arg.Audit = newAuditParams(ctx, auditStatementImportCreate, auditTargetStatement, "")
+
+	result, err := server.store.ImportStatementTx(ctx, arg)
+	if err != nil {
+		if pqerr, ok := err.(*pq.Error); ok && pqerr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(errStatementMatched))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newStatementReportResponse(result.Import, result.Unmatched))
+}
+
+type listStatementImportsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listStatementImports shows bankers the statements imported, latest first
+func (server *Server) listStatementImports(ctx *gin.Context) {
+	var req listStatementImportsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	imports, err := server.store.ListStatementImports(ctx, db.ListStatementImportsParams{
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, imports)
+}
+
+type getStatementImportRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getStatementImport reports an import with the lines that are still
+// unmatched
+func (server *Server) getStatementImport(ctx *gin.Context) {
+	var req getStatementImportRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	statementImport, err := server.store.GetStatementImport(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	unmatched, err := server.store.ListUnmatchedStatementLines(ctx, statementImport.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newStatementReportResponse(statementImport, unmatched))
+}
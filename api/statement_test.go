@@ -0,0 +1,325 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// newStatementRequest uploads a statement file with the given form fields
+func newStatementRequest(t *testing.T, filename string, content string, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+
+	for name, value := range fields {
+		require.NoError(t, form.WriteField(name, value))
+	}
+
+	file, err := form.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = file.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	request, err := http.NewRequest(http.MethodPost, "/statement-imports", body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", form.FormDataContentType())
+
+	return request
+}
+
+func TestImportStatementApi(t *testing.T) {
+	banker := util.RandomOwner()
+	account := randomAccount(util.RandomOwner())
+	account.Currency = util.USD
+
+	statementImport := db.StatementImport{
+		ID:         util.RandomInt(1, 1000),
+		AccountID:  account.ID,
+		Format:     "csv",
+		Filename:   "march.csv",
+		ImportedBy: banker,
+		Lines:      2,
+		Matched:    1,
+	}
+	unmatched := db.StatementLine{
+		ID:        1,
+		ImportID:  statementImport.ID,
+		Position:  2,
+		BookedOn:  time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Amount:    -2000,
+		Currency:  util.USD,
+		Reference: "",
+	}
+
+	csv := "date,amount,reference\n2024-03-01,125.50,INV-1\n2024-03-02,-20,\n"
+	fields := map[string]string{"account_id": fmt.Sprint(account.ID)}
+
+	testCases := []struct {
+		name          string
+		role          string
+		filename      string
+		content       string
+		fields        map[string]string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			role:     util.BankerRole,
+			filename: "march.csv",
+			content:  csv,
+			fields:   fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ImportStatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ImportStatementTxParams) (db.ImportStatementTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, "csv", arg.Format)
+						require.Equal(t, "march.csv", arg.Filename)
+						require.Equal(t, banker, arg.ImportedBy)
+						require.Equal(t, 72*time.Hour, arg.MatchWindow)
+						require.Equal(t, auditStatementImportCreate, arg.Audit.Action)
+						require.Equal(t, []db.StatementLineParams{
+							{Position: 1, BookedOn: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 12550, Currency: util.USD, Reference: "INV-1"},
+							{Position: 2, BookedOn: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Amount: -2000, Currency: util.USD},
+						}, arg.Lines)
+
+						return db.ImportStatementTxResult{Import: statementImport, Unmatched: []db.StatementLine{unmatched}}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res struct {
+					Import    db.StatementImport `json:"import"`
+					Unmatched []struct {
+						Position int32  `json:"position"`
+						BookedOn string `json:"booked_on"`
+						Amount   string `json:"amount"`
+					} `json:"unmatched"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, statementImport.ID, res.Import.ID)
+				require.Len(t, res.Unmatched, 1)
+				require.Equal(t, int32(2), res.Unmatched[0].Position)
+				require.Equal(t, "2024-03-02", res.Unmatched[0].BookedOn)
+				require.Equal(t, "-20.00", res.Unmatched[0].Amount)
+			},
+		},
+		{
+			name:     "NotBanker",
+			role:     util.DepositorRole,
+			filename: "march.csv",
+			content:  csv,
+			fields:   fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "UnknownFormat",
+			role:     util.BankerRole,
+			filename: "march.txt",
+			content:  csv,
+			fields:   fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ImportStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			role:     util.BankerRole,
+			filename: "march.csv",
+			content:  csv,
+			fields:   fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().ImportStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidLine",
+			role:     util.BankerRole,
+			filename: "march.csv",
+			content:  "date,amount\n2024-03-01,1.234\n",
+			fields:   fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ImportStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "line 2")
+			},
+		},
+		{
+			name:     "CurrencyMismatch",
+			role:     util.BankerRole,
+			filename: "march.csv",
+			content:  "date,amount,currency\n2024-03-01,10,EUR\n",
+			fields:   fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ImportStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:     "MatchedMeanwhile",
+			role:     util.BankerRole,
+			filename: "march.csv",
+			content:  csv,
+			fields:   fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ImportStatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportStatementTxResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := newStatementRequest(t, tc.filename, tc.content, tc.fields)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, banker, tc.role)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetStatementImportApi(t *testing.T) {
+	statementImport := db.StatementImport{
+		ID:        util.RandomInt(1, 1000),
+		AccountID: util.RandomInt(1, 1000),
+		Format:    "camt053",
+		Lines:     3,
+		Matched:   3,
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStatementImport(gomock.Any(), gomock.Eq(statementImport.ID)).Times(1).Return(statementImport, nil)
+				store.EXPECT().
+					ListUnmatchedStatementLines(gomock.Any(), gomock.Eq(statementImport.ID)).
+					Times(1).
+					Return([]db.StatementLine{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"unmatched":[]`)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetStatementImport(gomock.Any(), gomock.Eq(statementImport.ID)).
+					Times(1).
+					Return(db.StatementImport{}, sql.ErrNoRows)
+				store.EXPECT().ListUnmatchedStatementLines(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/statement-imports/%d", statementImport.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorizationWithRole(t, request, server.tokenMaker, util.RandomOwner(), util.BankerRole)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListStatementImportsApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListStatementImports(gomock.Any(), gomock.Eq(db.ListStatementImportsParams{
+			Limit:  5,
+			Offset: 5,
+		})).
+		Times(1).
+		Return([]db.StatementImport{}, nil)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/statement-imports?page_id=2&page_size=5", nil)
+	require.NoError(t, err)
+
+	addAuthorizationWithRole(t, request, server.tokenMaker, util.RandomOwner(), util.BankerRole)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
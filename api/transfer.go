@@ -2,20 +2,128 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
 	"github.com/Srinath-exe/simplebank/token"
 	"github.com/gin-gonic/gin"
 )
 
+var errStepUpRequired = errors.New("step_up_required")
+
+var errTransferRecipient = errors.New("exactly one of to_account_id, payee_id and beneficiary_id is required")
+
+var (
+	errAmountNotPositive = errors.New("amount must be greater than zero")
+	errAmountZero        = errors.New("amount must not be zero")
+)
+
+// transferRequest sends money to an account, given either by its id or by
+// one of the caller's payees, or to one of the caller's external
+// beneficiaries through the correspondent bank. Amount is a decimal in the
+// major units of the currency, e.g. "12.34", with no more decimal places than
+// it has minor units. With Quote set nothing is sent; the response is the fee
+// the transfer would be charged. MaxFee, in the same currency, fails the
+// transfer if the fee has gone up since.
 type transferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=1"`
-	Currency      string `json:"currency" binding:"required,currency"`
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"omitempty,min=1"`
+	PayeeID       int64       `json:"payee_id" binding:"omitempty,min=1"`
+	BeneficiaryID int64       `json:"beneficiary_id" binding:"omitempty,min=1"`
+	Amount        json.Number `json:"amount" binding:"required"`
+	Currency      string      `json:"currency" binding:"required,currency"`
+	Memo          string      `json:"memo" binding:"max=140"`
+	Reference     string      `json:"reference" binding:"max=64"`
+	// Metadata is stored as given and can be matched in searchTransfers
+	Metadata map[string]interface{} `json:"metadata" binding:"max=20"`
+	Quote    bool                   `json:"quote"`
+	MaxFee   json.Number            `json:"max_fee"`
+}
+
+// transferMetadata encodes the metadata of a request, nil when there is none
+func transferMetadata(metadata map[string]interface{}) (json.RawMessage, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(metadata)
+}
+
+// transferAmount parses the amount of a transfer request in its currency
+func transferAmount(amount json.Number, currency string) (money.Money, error) {
+	m, err := money.Parse(amount.String(), currency)
+	if err != nil {
+		return m, err
+	}
+
+	if !m.IsPositive() {
+		return m, errAmountNotPositive
+	}
+
+	return m, nil
+}
+
+// transferResponse is a transfer with its amount as a decimal in the currency
+// of its accounts
+type transferResponse struct {
+	ID            int64           `json:"id"`
+	FromAccountID int64           `json:"from_account_id"`
+	ToAccountID   int64           `json:"to_account_id"`
+	Amount        money.Money     `json:"amount"`
+	Currency      string          `json:"currency"`
+	Memo          string          `json:"memo"`
+	Reference     string          `json:"reference"`
+	Metadata      json.RawMessage `json:"metadata"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func newTransferResponse(transfer db.Transfer, currency string) transferResponse {
+	return transferResponse{
+		ID:            transfer.ID,
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+		Amount:        money.New(transfer.Amount, currency),
+		Currency:      currency,
+		Memo:          transfer.Memo,
+		Reference:     transfer.Reference,
+		Metadata:      transfer.Metadata,
+		CreatedAt:     transfer.CreatedAt,
+	}
+}
+
+type transferTxResponse struct {
+	Transfer    transferResponse `json:"transfer"`
+	FromAccount accountResponse  `json:"from_account"`
+	ToAccount   accountResponse  `json:"to_account"`
+	FromEntry   entryResponse    `json:"from_entry"`
+	ToEntry     entryResponse    `json:"to_entry"`
+	Fees        []feeResponse    `json:"fees"`
+	// OutboundPayment tracks a transfer to an external beneficiary
+	OutboundPayment *outboundPaymentResponse `json:"outbound_payment,omitempty"`
+}
+
+func newTransferTxResponse(result db.TransferTxResult) transferTxResponse {
+	currency := result.FromAccount.Currency
+
+	rsp := transferTxResponse{
+		Transfer:    newTransferResponse(result.Transfer, currency),
+		FromAccount: newAccountResponse(result.FromAccount),
+		ToAccount:   newAccountResponse(result.ToAccount),
+		FromEntry:   newTransferEntryResponse(result.FromEntry, result.Transfer, result.ToAccount),
+		ToEntry:     newTransferEntryResponse(result.ToEntry, result.Transfer, result.FromAccount),
+		Fees:        newFeeResponses(result.Fees, currency),
+	}
+
+	if result.OutboundPayment != nil {
+		payment := newOutboundPaymentResponse(*result.OutboundPayment)
+		rsp.OutboundPayment = &payment
+	}
+
+	return rsp
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -26,6 +134,24 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	recipients := 0
+	for _, id := range []int64{req.ToAccountID, req.PayeeID, req.BeneficiaryID} {
+		if id != 0 {
+			recipients++
+		}
+	}
+
+	if recipients != 1 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTransferRecipient))
+		return
+	}
+
+	amount, err := transferAmount(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
 	if !valid {
 		return
@@ -39,29 +165,193 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	if !authPayload.CanAccessAccount(fromAccount.ID) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotGranted))
+		return
+	}
 
-	if !valid {
+	if !req.Quote && server.requiresStepUp(authPayload, req.Currency, amount.Amount()) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errStepUpRequired))
+		return
+	}
+
+	if req.PayeeID != 0 {
+		payee, ok := server.transferPayee(ctx, req.PayeeID, authPayload.Username)
+		if !ok {
+			return
+		}
+
+		if endsAt, coolingOff := server.payeeCoolingOff(payee, req.Currency, amount.Amount()); coolingOff {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":               errPayeeCoolingOff.Error(),
+				"cooling_off_ends_at": endsAt,
+			})
+			return
+		}
+
+		req.ToAccountID = payee.AccountID
+	}
+
+	if req.BeneficiaryID != 0 {
+		settlementAccountID, ok := server.transferBeneficiary(ctx, req.BeneficiaryID, authPayload.Username, req.Currency)
+		if !ok {
+			return
+		}
+
+		req.ToAccountID = settlementAccountID
+	} else if _, valid = server.validAccount(ctx, req.ToAccountID, req.Currency); !valid {
+		return
+	}
+
+	metadata, err := transferMetadata(req.Metadata)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Quote {
+		server.quoteTransfer(ctx, fromAccount, amount)
 		return
 	}
 
 	arg := db.TransferTxParams{
-		FromAccID: req.FromAccountID,
-		ToAccID:   req.ToAccountID,
-		Amount:    req.Amount,
+		FromAccID:     req.FromAccountID,
+		ToAccID:       req.ToAccountID,
+		Amount:        amount.Amount(),
+		Memo:          req.Memo,
+		Reference:     req.Reference,
+		Metadata:      metadata,
+		Limits:        server.transferLimits(req.Currency),
+		BeneficiaryID: req.BeneficiaryID,
+		Audit:         newAuditParams(ctx, auditTransferCreate, auditTargetTransfer, ""),
+	}
+
+	if req.MaxFee != "" {
+		maxFee, err := feeAmount(req.MaxFee, req.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.MaxFee = &maxFee
+	}
+
+	if !server.evaluateTransferRisk(ctx, arg, req.Currency, authPayload.Username) {
+		return
 	}
 
 	result, err := server.store.TransferTx(ctx, arg)
 
 	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, limitErrorResponse(limitErr, req.Currency))
+			return
+		}
+
+		if errors.Is(err, db.ErrAccountClosed) || errors.Is(err, db.ErrAccountFrozen) || errors.Is(err, db.ErrWithdrawalLimit) ||
+			errors.Is(err, db.ErrFeeAboveMax) || errors.Is(err, db.ErrNoExternalSettlement) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, newTransferTxResponse(result))
 
 }
 
+// quoteTransfer responds with the fee a transfer of amount from account
+// would be charged under the current schedule of its product
+func (server *Server) quoteTransfer(ctx *gin.Context, account db.Account, amount money.Money) {
+	quote, err := db.QuoteFee(ctx, server.store, db.FeeTypeTransfer, account, amount.Amount())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := newTransferQuoteResponse(amount, quote)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// transferLimits returns the configured limits for transfers in currency
+func (server *Server) transferLimits(currency string) db.TransferLimits {
+	return db.TransferLimits{
+		MaxPerTransfer: server.config.TransferMaxAmounts[currency],
+		AccountDaily:   server.config.AccountDailyLimits[currency],
+		AccountMonthly: server.config.AccountMonthlyLimits[currency],
+		UserDaily:      server.config.UserDailyLimits[currency],
+		UserMonthly:    server.config.UserMonthlyLimits[currency],
+	}
+}
+
+// limitErrorResponse describes a limit a transfer exceeded, with the amounts
+// written as decimals in currency
+func limitErrorResponse(err *db.LimitExceededError, currency string) gin.H {
+	max := money.New(err.Max, currency)
+	remaining := money.New(err.Remaining, currency)
+
+	return gin.H{
+		"error":     fmt.Sprintf("transfer exceeds the %s limit of %s, %s remaining", err.Limit, max, remaining),
+		"limit":     err.Limit,
+		"max":       max,
+		"remaining": remaining,
+	}
+}
+
+type limitUsageResponse struct {
+	Limit     money.Money `json:"limit"`
+	Used      money.Money `json:"used"`
+	Remaining money.Money `json:"remaining"`
+}
+
+func newLimitUsageResponse(usage db.LimitUsage, currency string) limitUsageResponse {
+	return limitUsageResponse{
+		Limit:     money.New(usage.Limit, currency),
+		Used:      money.New(usage.Used, currency),
+		Remaining: money.New(usage.Remaining, currency),
+	}
+}
+
+// limitsResponse is the usage of the transfer limits of an account as decimal
+// amounts. Limits of 0 are unlimited.
+type limitsResponse struct {
+	Currency       string             `json:"currency"`
+	MaxPerTransfer money.Money        `json:"max_per_transfer"`
+	AccountDaily   limitUsageResponse `json:"account_daily"`
+	AccountMonthly limitUsageResponse `json:"account_monthly"`
+	UserDaily      limitUsageResponse `json:"user_daily"`
+	UserMonthly    limitUsageResponse `json:"user_monthly"`
+}
+
+func newLimitsResponse(usage db.TransferLimitUsage) limitsResponse {
+	return limitsResponse{
+		Currency:       usage.Currency,
+		MaxPerTransfer: money.New(usage.MaxPerTransfer, usage.Currency),
+		AccountDaily:   newLimitUsageResponse(usage.AccountDaily, usage.Currency),
+		AccountMonthly: newLimitUsageResponse(usage.AccountMonthly, usage.Currency),
+		UserDaily:      newLimitUsageResponse(usage.UserDaily, usage.Currency),
+		UserMonthly:    newLimitUsageResponse(usage.UserMonthly, usage.Currency),
+	}
+}
+
+// requiresStepUp reports whether a transfer is above the configured threshold
+// for its currency and the token's authentication is too old to authorize it.
+func (server *Server) requiresStepUp(payload *token.Payload, currency string, amount int64) bool {
+	threshold, ok := server.config.StepUpThresholds[currency]
+	if !ok || amount <= threshold {
+		return false
+	}
+
+	return payload.AuthAge() > server.config.StepUpMaxAuthAge
+}
+
 func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
 
@@ -81,13 +371,22 @@ func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency s
 		return account, false
 	}
 
+	if account.Status == db.AccountStatusClosed {
+		err := fmt.Errorf("account %d: %w", accountID, db.ErrAccountClosed)
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return account, false
+	}
+
 	return account, true
 }
 
+// searchTransferRequest matches the query against the owners of both
+// accounts, the memo and the reference. Transfers must also contain Metadata.
 type searchTransferRequest struct {
-	SearchQuery string `json:"search_query" binding:"required"`
-	Offset      *int32 `json:"offset,omitempty"`
-	Limit       *int32 `json:"limit,omitempty"`
+	SearchQuery string                 `json:"search_query" binding:"required"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	Offset      *int32                 `json:"offset,omitempty"`
+	Limit       *int32                 `json:"limit,omitempty"`
 }
 
 func (server *Server) searchTransfers(ctx *gin.Context) {
@@ -98,7 +397,6 @@ func (server *Server) searchTransfers(ctx *gin.Context) {
 		return
 	}
 
-	//  := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	limit := int32(2)  // Set default limit
 	offset := int32(0) // Set default offset
 
@@ -109,8 +407,25 @@ func (server *Server) searchTransfers(ctx *gin.Context) {
 		offset = *searchRequest.Offset
 	}
 
+	metadata := json.RawMessage("{}")
+	if len(searchRequest.Metadata) > 0 {
+		var err error
+		metadata, err = json.Marshal(searchRequest.Metadata)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	// only transfers to or from the caller's accounts are searched, and only
+	// the accounts of their consent when the token was issued for one
 	req := db.SeachTransfersByAccountOwnerParams{
 		SearchQuery: sql.NullString{String: searchRequest.SearchQuery, Valid: true},
+		Metadata:    metadata,
+		Owner:       authPayload.Username,
+		AccountIds:  authPayload.AccountIDs,
 		Limit:       limit,
 		Offset:      offset,
 	}
@@ -128,7 +443,22 @@ func (server *Server) searchTransfers(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, transfers)
+	rsp := make([]transferResponse, len(transfers))
+
+	for i, transfer := range transfers {
+		rsp[i] = newTransferResponse(db.Transfer{
+			ID:            transfer.ID,
+			FromAccountID: transfer.FromAccountID,
+			ToAccountID:   transfer.ToAccountID,
+			Amount:        transfer.Amount,
+			CreatedAt:     transfer.CreatedAt,
+			Memo:          transfer.Memo,
+			Reference:     transfer.Reference,
+			Metadata:      transfer.Metadata,
+		}, transfer.Currency)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 
 }
 
@@ -147,6 +477,11 @@ func (server *Server) listTransfersFromAccountId(ctx *gin.Context) {
 		return
 	}
 
+	account, ok := server.ownedAccount(ctx, req.ID)
+	if !ok {
+		return
+	}
+
 	limit := int32(2)  // Set default limit
 	offset := int32(0) // Set default offset
 
@@ -174,8 +509,57 @@ func (server *Server) listTransfersFromAccountId(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, transfers)
+	// transfers are always between accounts of the same currency
+	rsp := make([]transferResponse, len(transfers))
+
+	for i, transfer := range transfers {
+		rsp[i] = newTransferResponse(db.Transfer{
+			ID:            transfer.ID,
+			FromAccountID: transfer.FromAccountID,
+			ToAccountID:   transfer.ToAccountID,
+			Amount:        transfer.Amount,
+			CreatedAt:     transfer.CreatedAt,
+			Memo:          transfer.Memo,
+			Reference:     transfer.Reference,
+			Metadata:      transfer.Metadata,
+		}, account.Currency)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+
+}
+
+// transferParty reports whether the caller owns, and is allowed to access,
+// one of the accounts of a transfer, writing the error response if not
+func (server *Server) transferParty(ctx *gin.Context, accounts ...db.Account) bool {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	owned := false
+	for _, account := range accounts {
+		if account.Owner != authPayload.Username {
+			continue
+		}
+
+		if authPayload.CanAccessAccount(account.ID) {
+			return true
+		}
+		owned = true
+	}
+
+	if owned {
+		ctx.JSON(http.StatusForbidden, errorResponse(errAccountNotGranted))
+		return false
+	}
+
+	err := errors.New("transfer doesn't belong to the authenticated user")
+	ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	return false
+}
 
+// transferDetailResponse is a transfer with the fees charged on it
+type transferDetailResponse struct {
+	transferResponse
+	Fees []feeResponse `json:"fees"`
 }
 
 type getTransferRequest struct {
@@ -203,6 +587,32 @@ func (server *Server) getTransfer(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, transfer)
+	// transfers are always between accounts of the same currency
+	fromAccount, err := server.store.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !server.transferParty(ctx, fromAccount, toAccount) {
+		return
+	}
+
+	fees, err := server.store.ListFeesByTransfer(ctx, sql.NullInt64{Int64: transfer.ID, Valid: true})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transferDetailResponse{
+		transferResponse: newTransferResponse(transfer, fromAccount.Currency),
+		Fees:             newFeeResponses(fees, fromAccount.Currency),
+	})
 
 }
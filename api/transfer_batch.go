@@ -0,0 +1,752 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/risk"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var (
+	errBatchTooLarge        = errors.New("the batch has too many transfers")
+	errBatchInvalid         = errors.New("some transfers in the batch are invalid, none were made")
+	errBatchNotOwned        = errors.New("transfer batch doesn't belong to the authenticated user")
+	errBatchItemNeedsReview = errors.New("the risk rules would hold this transfer for review; send it on its own or in a best_effort batch")
+)
+
+// transferBatchItemRequest is a transfer of a batch, as in transferRequest
+type transferBatchItemRequest struct {
+	FromAccountID int64                  `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64                  `json:"to_account_id" binding:"omitempty,min=1"`
+	PayeeID       int64                  `json:"payee_id" binding:"omitempty,min=1"`
+	Amount        json.Number            `json:"amount" binding:"required"`
+	Currency      string                 `json:"currency" binding:"required,currency"`
+	Memo          string                 `json:"memo" binding:"max=140"`
+	Reference     string                 `json:"reference" binding:"max=64"`
+	Metadata      map[string]interface{} `json:"metadata" binding:"max=20"`
+}
+
+// createTransferBatchRequest sends many transfers from the caller's
+// accounts. In atomic mode they are made in one transaction, all or none; in
+// best_effort mode each is made on its own and the batch reports which went
+// through. Transfers can also be uploaded as a CSV file, see
+// parseTransferBatchCSV.
+type createTransferBatchRequest struct {
+	Mode      string                     `json:"mode" binding:"required,oneof=atomic best_effort"`
+	Transfers []transferBatchItemRequest `json:"transfers" binding:"required,min=1,dive"`
+}
+
+// transferBatchCSVColumns are the columns a CSV batch may have, in any order
+var transferBatchCSVColumns = []string{"from_account_id", "to_account_id", "payee_id", "amount", "currency", "memo", "reference"}
+
+// parseTransferBatchCSV reads transfers from a CSV file with a header row
+// naming its columns, e.g.
+//
+//	from_account_id,to_account_id,amount,currency,memo
+//	12,34,1500.00,USD,March salary
+//
+// Empty cells are left out. Metadata can't be given in CSV.
+func parseTransferBatchCSV(r io.Reader) ([]transferBatchItemRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, column := range transferBatchCSVColumns {
+			known = known || column == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		columns[name] = i
+	}
+
+	transfers := []transferBatchItemRequest{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		id := func(name string) (int64, error) {
+			value := field(name)
+			if value == "" {
+				return 0, nil
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid %s %q", line, name, value)
+			}
+			return n, nil
+		}
+
+		transfer := transferBatchItemRequest{
+			Amount:    json.Number(field("amount")),
+			Currency:  field("currency"),
+			Memo:      field("memo"),
+			Reference: field("reference"),
+		}
+
+		if transfer.FromAccountID, err = id("from_account_id"); err != nil {
+			return nil, err
+		}
+		if transfer.ToAccountID, err = id("to_account_id"); err != nil {
+			return nil, err
+		}
+		if transfer.PayeeID, err = id("payee_id"); err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
+
+// bindTransferBatch reads a batch from a JSON body or from a multipart form
+// with a mode field and the transfers in a CSV file field
+func bindTransferBatch(ctx *gin.Context, req *createTransferBatchRequest) error {
+	if ctx.ContentType() != binding.MIMEMultipartPOSTForm {
+		return ctx.ShouldBindJSON(req)
+	}
+
+	req.Mode = ctx.PostForm("mode")
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return err
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if req.Transfers, err = parseTransferBatchCSV(file); err != nil {
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(req)
+}
+
+// batchItemError is why a transfer of a batch is invalid
+type batchItemError struct {
+	Position int    `json:"position"`
+	Error    string `json:"error"`
+}
+
+// batchTransfer is a validated transfer of a batch
+type batchTransfer struct {
+	arg      db.TransferTxParams
+	currency string
+	// holdRules are the risk rules that hold the transfer for review
+	holdRules []string
+}
+
+// batchValidator checks the transfers of a batch, looking each account up
+// only once
+type batchValidator struct {
+	server   *Server
+	ctx      *gin.Context
+	payload  *token.Payload
+	mode     string
+	accounts map[int64]db.Account
+	// validated are the transfers of the batch accepted so far, which the
+	// risk rules count along with those already made
+	validated []batchTransfer
+}
+
+// batchRiskHistory is riskHistory with the transfers validated earlier in the
+// same batch counted as if they were made, so a batch can't be used to get
+// round the velocity and new payee rules. A transfer held for review doesn't
+// make its recipient a known one.
+type batchRiskHistory struct {
+	riskHistory
+	transfers []batchTransfer
+}
+
+func (history batchRiskHistory) CountTransfersBetween(ctx context.Context, fromAccountID int64, toAccountID int64) (int64, error) {
+	count, err := history.riskHistory.CountTransfersBetween(ctx, fromAccountID, toAccountID)
+	if err != nil {
+		return count, err
+	}
+
+	for _, transfer := range history.transfers {
+		if transfer.arg.FromAccID == fromAccountID && transfer.arg.ToAccID == toAccountID && len(transfer.holdRules) == 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (history batchRiskHistory) CountTransfersSince(ctx context.Context, fromAccountID int64, since time.Time) (int64, error) {
+	count, err := history.riskHistory.CountTransfersSince(ctx, fromAccountID, since)
+	if err != nil {
+		return count, err
+	}
+
+	// the transfers of the batch are all made now, within any window
+	for _, transfer := range history.transfers {
+		if transfer.arg.FromAccID == fromAccountID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (v *batchValidator) account(id int64) (db.Account, error) {
+	if account, ok := v.accounts[id]; ok {
+		return account, nil
+	}
+
+	account, err := v.server.store.GetAccount(v.ctx, id)
+	if err != nil {
+		return account, err
+	}
+
+	v.accounts[id] = account
+	return account, nil
+}
+
+// transferAccount returns an account of the batch, or why it can't be used.
+// Errors other than a missing account are returned as err.
+func (v *batchValidator) transferAccount(id int64, currency string) (account db.Account, invalid error, err error) {
+	account, err = v.account(id)
+	if err == sql.ErrNoRows {
+		return account, fmt.Errorf("account %d not found", id), nil
+	}
+	if err != nil {
+		return account, nil, err
+	}
+
+	if account.Currency != currency {
+		return account, fmt.Errorf("account %d currency mismatch: %s vs %s", id, account.Currency, currency), nil
+	}
+
+	if account.Status == db.AccountStatusClosed {
+		return account, fmt.Errorf("account %d: %w", id, db.ErrAccountClosed), nil
+	}
+
+	return account, nil, nil
+}
+
+// validate checks a transfer the way createTransfer does. It returns why the
+// transfer is invalid, or err if it couldn't be checked.
+func (v *batchValidator) validate(req transferBatchItemRequest) (transfer batchTransfer, invalid error, err error) {
+	if (req.ToAccountID == 0) == (req.PayeeID == 0) {
+		return transfer, errTransferRecipient, nil
+	}
+
+	amount, invalid := transferAmount(req.Amount, req.Currency)
+	if invalid != nil {
+		return transfer, invalid, nil
+	}
+
+	fromAccount, invalid, err := v.transferAccount(req.FromAccountID, req.Currency)
+	if invalid != nil || err != nil {
+		return transfer, invalid, err
+	}
+
+	if fromAccount.Owner != v.payload.Username {
+		return transfer, errors.New("from account does not belong to the authenticated user"), nil
+	}
+
+	if !v.payload.CanAccessAccount(fromAccount.ID) {
+		return transfer, errAccountNotGranted, nil
+	}
+
+	if req.PayeeID != 0 {
+		payee, err := v.server.store.GetPayee(v.ctx, db.GetPayeeParams{ID: req.PayeeID, Owner: v.payload.Username})
+		if err == sql.ErrNoRows {
+			return transfer, fmt.Errorf("payee %d not found", req.PayeeID), nil
+		}
+		if err != nil {
+			return transfer, nil, err
+		}
+
+		if _, coolingOff := v.server.payeeCoolingOff(payee, req.Currency, amount.Amount()); coolingOff {
+			return transfer, errPayeeCoolingOff, nil
+		}
+
+		req.ToAccountID = payee.AccountID
+	}
+
+	if _, invalid, err := v.transferAccount(req.ToAccountID, req.Currency); invalid != nil || err != nil {
+		return transfer, invalid, err
+	}
+
+	metadata, invalid := transferMetadata(req.Metadata)
+	if invalid != nil {
+		return transfer, invalid, nil
+	}
+
+	transfer = batchTransfer{
+		arg: db.TransferTxParams{
+			FromAccID: req.FromAccountID,
+			ToAccID:   req.ToAccountID,
+			Amount:    amount.Amount(),
+			Memo:      req.Memo,
+			Reference: req.Reference,
+			Metadata:  metadata,
+		},
+		currency: req.Currency,
+	}
+
+	result, err := v.server.riskEngine.Evaluate(v.ctx, risk.Transfer{
+		FromAccountID: transfer.arg.FromAccID,
+		ToAccountID:   transfer.arg.ToAccID,
+		Owner:         v.payload.Username,
+		Amount:        transfer.arg.Amount,
+		Currency:      req.Currency,
+		Time:          time.Now(),
+	}, batchRiskHistory{
+		riskHistory: riskHistory{store: v.server.store},
+		transfers:   v.validated,
+	})
+	if err != nil {
+		return transfer, nil, err
+	}
+
+	switch result.Decision {
+	case risk.Deny:
+		return transfer, fmt.Errorf("%w: %s", errTransferDenied, strings.Join(result.Rules, ", ")), nil
+	case risk.Review:
+		if v.mode == db.TransferBatchAtomic {
+			return transfer, errBatchItemNeedsReview, nil
+		}
+		transfer.holdRules = result.Rules
+	}
+
+	v.validated = append(v.validated, transfer)
+	return transfer, nil, nil
+}
+
+// createTransferBatch validates every transfer of a batch before any is
+// made and records the batch. It answers 202 with the batch still
+// processing, and makes the transfers as the mode says in the background;
+// getTransferBatch shows how far it got.
+func (server *Server) createTransferBatch(ctx *gin.Context) {
+	var req createTransferBatchRequest
+
+	if err := bindTransferBatch(ctx, &req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if max := server.config.TransferBatchMaxItems; max > 0 && len(req.Transfers) > max {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("%w, at most %d are allowed", errBatchTooLarge, max)))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	validator := &batchValidator{
+		server:   server,
+		ctx:      ctx,
+		payload:  authPayload,
+		mode:     req.Mode,
+		accounts: map[int64]db.Account{},
+	}
+
+	transfers := make([]batchTransfer, len(req.Transfers))
+	invalid := []batchItemError{}
+	totals := map[string]money.Money{}
+
+	for i, item := range req.Transfers {
+		transfer, itemErr, err := validator.validate(item)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if itemErr == nil {
+			total, ok := totals[transfer.currency]
+			if !ok {
+				total = money.New(0, transfer.currency)
+			}
+			totals[transfer.currency], itemErr = total.Add(money.New(transfer.arg.Amount, transfer.currency))
+		}
+
+		if itemErr != nil {
+			invalid = append(invalid, batchItemError{Position: i + 1, Error: itemErr.Error()})
+			continue
+		}
+
+		transfers[i] = transfer
+	}
+
+	if len(invalid) > 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": errBatchInvalid.Error(),
+			"items": invalid,
+		})
+		return
+	}
+
+	// the batch as a whole is held to step-up, so it can't be used to split
+	// a large payment into small ones
+	for currency, total := range totals {
+		if server.requiresStepUp(authPayload, currency, total.Amount()) {
+			ctx.JSON(http.StatusForbidden, errorResponse(errStepUpRequired))
+			return
+		}
+	}
+
+	batch, items, ok := server.saveTransferBatch(ctx, authPayload.Username, req.Mode, transfers)
+	if !ok {
+		return
+	}
+
+	run := batchRun{
+		batch:         batch,
+		items:         items,
+		transfers:     transfers,
+		limits:        make(map[string]db.TransferLimits, len(totals)),
+		transferAudit: newAuditParams(ctx, auditTransferCreate, auditTargetTransfer, ""),
+		reviewAudit:   newAuditParams(ctx, auditTransferReviewCreate, auditTargetTransferReview, ""),
+	}
+	for currency := range totals {
+		run.limits[currency] = server.transferLimits(currency)
+	}
+
+	// the request may be gone by the time the batch is done, so the worker
+	// gets a context of its own
+	server.batches.Add(1)
+	go func() {
+		defer server.batches.Done()
+		server.executeTransferBatch(context.Background(), run)
+	}()
+
+	ctx.JSON(http.StatusAccepted, newTransferBatchResponse(batch, items))
+}
+
+// saveTransferBatch records a batch and its transfers, all pending
+func (server *Server) saveTransferBatch(ctx *gin.Context, owner string, mode string, transfers []batchTransfer) (db.TransferBatch, []db.TransferBatchItem, bool) {
+	var batch db.TransferBatch
+	items := make([]db.TransferBatchItem, len(transfers))
+	audit := newAuditParams(ctx, auditTransferBatchCreate, auditTargetTransferBatch, "")
+
+	err := server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		batch, err = q.CreateTransferBatch(ctx, db.CreateTransferBatchParams{
+			Owner: owner,
+			Mode:  mode,
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, transfer := range transfers {
+			metadata := transfer.arg.Metadata
+			if len(metadata) == 0 {
+				metadata = json.RawMessage("{}")
+			}
+
+			items[i], err = q.CreateTransferBatchItem(ctx, db.CreateTransferBatchItemParams{
+				BatchID:       batch.ID,
+				Position:      int32(i + 1),
+				FromAccountID: transfer.arg.FromAccID,
+				ToAccountID:   transfer.arg.ToAccID,
+				Amount:        transfer.arg.Amount,
+				Currency:      transfer.currency,
+				Memo:          transfer.arg.Memo,
+				Reference:     transfer.arg.Reference,
+				Metadata:      metadata,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		audit.TargetID = strconv.FormatInt(batch.ID, 10)
+		audit.After = batch
+		return nil
+	})
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return batch, nil, false
+	}
+
+	return batch, items, true
+}
+
+// batchRun is a recorded batch for executeTransferBatch to make, with what
+// it needs from the request that created it
+type batchRun struct {
+	batch     db.TransferBatch
+	items     []db.TransferBatchItem
+	transfers []batchTransfer
+	limits    map[string]db.TransferLimits
+	// transferAudit is recorded for each transfer and reviewAudit for each
+	// transfer held for review
+	transferAudit db.AuditParams
+	reviewAudit   db.AuditParams
+}
+
+// executeTransferBatch makes the transfers of a batch as its mode says. It
+// runs after the response is sent, so errors are only logged.
+func (server *Server) executeTransferBatch(ctx context.Context, run batchRun) {
+	var err error
+	if run.batch.Mode == db.TransferBatchAtomic {
+		err = server.executeAtomicBatch(ctx, run)
+	} else {
+		err = server.executeBestEffortBatch(ctx, run)
+	}
+
+	if err != nil {
+		log.Printf("cannot execute transfer batch %d: %v", run.batch.ID, err)
+	}
+}
+
+// executeAtomicBatch makes every transfer of a batch or, if one fails, none
+// and records which one failed the batch
+func (server *Server) executeAtomicBatch(ctx context.Context, run batchRun) error {
+	_, err := server.store.ExecuteTransferBatchTx(ctx, db.ExecuteTransferBatchTxParams{
+		BatchID: run.batch.ID,
+		Limits:  run.limits,
+		Audit:   run.transferAudit,
+	})
+	if err == nil {
+		return nil
+	}
+
+	var itemErr *db.BatchItemError
+	if !errors.As(err, &itemErr) {
+		// nothing was made, so the batch is failed rather than left
+		// processing for good
+		_, finishErr := server.store.FinishTransferBatch(ctx, db.FinishTransferBatchParams{
+			ID:     run.batch.ID,
+			Status: db.TransferBatchFailed,
+		})
+		return errors.Join(err, finishErr)
+	}
+
+	_, err = server.store.FailTransferBatchItem(ctx, db.FailTransferBatchItemParams{
+		ID:    itemErr.Item.ID,
+		Error: itemErr.Err.Error(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return server.finishTransferBatch(ctx, run.batch, db.TransferBatchFailed)
+}
+
+// executeBestEffortBatch makes each transfer of a batch on its own, holding
+// those the risk rules flagged for review
+func (server *Server) executeBestEffortBatch(ctx context.Context, run batchRun) error {
+	completed, failed := 0, 0
+
+	for i, item := range run.items {
+		if rules := run.transfers[i].holdRules; len(rules) > 0 {
+			if err := server.holdBatchItem(ctx, run, item, run.transfers[i]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := server.store.ExecuteTransferBatchItemTx(ctx, db.ExecuteTransferBatchItemTxParams{
+			ItemID: item.ID,
+			Limits: run.limits[item.Currency],
+			Audit:  run.transferAudit,
+		})
+		if err == nil {
+			completed++
+			continue
+		}
+
+		failed++
+		_, err = server.store.FailTransferBatchItem(ctx, db.FailTransferBatchItemParams{
+			ID:    item.ID,
+			Error: err.Error(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	status := db.TransferBatchPartiallyCompleted
+	switch {
+	case completed == len(run.items):
+		status = db.TransferBatchCompleted
+	case failed == len(run.items):
+		status = db.TransferBatchFailed
+	}
+
+	return server.finishTransferBatch(ctx, run.batch, status)
+}
+
+// holdBatchItem creates the review of a batch transfer the risk rules flagged
+func (server *Server) holdBatchItem(ctx context.Context, run batchRun, item db.TransferBatchItem, transfer batchTransfer) error {
+	return server.store.AuditTx(ctx, run.reviewAudit, func(q db.Querier, audit *db.AuditParams) error {
+		review, err := createTransferReview(ctx, q, transfer.arg, transfer.currency, run.batch.Owner, transfer.holdRules)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.HoldTransferBatchItem(ctx, db.HoldTransferBatchItemParams{
+			ID:       item.ID,
+			ReviewID: sql.NullInt64{Int64: review.ID, Valid: true},
+		})
+		audit.TargetID = strconv.FormatInt(review.ID, 10)
+		audit.After = review
+		return err
+	})
+}
+
+func (server *Server) finishTransferBatch(ctx context.Context, batch db.TransferBatch, status string) error {
+	_, err := server.store.FinishTransferBatch(ctx, db.FinishTransferBatchParams{
+		ID:     batch.ID,
+		Status: status,
+	})
+	return err
+}
+
+// transferBatchItemResponse is a transfer of a batch and what became of it
+type transferBatchItemResponse struct {
+	Position      int32       `json:"position"`
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Amount        money.Money `json:"amount"`
+	Currency      string      `json:"currency"`
+	Memo          string      `json:"memo"`
+	Reference     string      `json:"reference"`
+	Status        string      `json:"status"`
+	TransferID    *int64      `json:"transfer_id"`
+	ReviewID      *int64      `json:"review_id"`
+	Error         string      `json:"error,omitempty"`
+}
+
+// transferBatchResponse is a batch with the number of its transfers in each
+// status and the transfers themselves
+type transferBatchResponse struct {
+	ID          int64                       `json:"id"`
+	Mode        string                      `json:"mode"`
+	Status      string                      `json:"status"`
+	Total       int                         `json:"total"`
+	Pending     int                         `json:"pending"`
+	Completed   int                         `json:"completed"`
+	Failed      int                         `json:"failed"`
+	Held        int                         `json:"held"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	CompletedAt *time.Time                  `json:"completed_at"`
+	Items       []transferBatchItemResponse `json:"items"`
+}
+
+func newTransferBatchResponse(batch db.TransferBatch, items []db.TransferBatchItem) transferBatchResponse {
+	rsp := transferBatchResponse{
+		ID:          batch.ID,
+		Mode:        batch.Mode,
+		Status:      batch.Status,
+		Total:       len(items),
+		CreatedAt:   batch.CreatedAt,
+		CompletedAt: nullTimePtr(batch.CompletedAt),
+		Items:       make([]transferBatchItemResponse, len(items)),
+	}
+
+	for i, item := range items {
+		switch item.Status {
+		case db.TransferBatchItemPending:
+			rsp.Pending++
+		case db.TransferBatchItemCompleted:
+			rsp.Completed++
+		case db.TransferBatchItemFailed:
+			rsp.Failed++
+		case db.TransferBatchItemHeld:
+			rsp.Held++
+		}
+
+		rsp.Items[i] = transferBatchItemResponse{
+			Position:      item.Position,
+			FromAccountID: item.FromAccountID,
+			ToAccountID:   item.ToAccountID,
+			Amount:        money.New(item.Amount, item.Currency),
+			Currency:      item.Currency,
+			Memo:          item.Memo,
+			Reference:     item.Reference,
+			Status:        item.Status,
+			TransferID:    nullInt64Ptr(item.TransferID),
+			ReviewID:      nullInt64Ptr(item.ReviewID),
+			Error:         item.Error,
+		}
+	}
+
+	return rsp
+}
+
+func (server *Server) writeTransferBatch(ctx *gin.Context, status int, batch db.TransferBatch) {
+	items, err := server.store.ListTransferBatchItems(ctx, batch.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(status, newTransferBatchResponse(batch, items))
+}
+
+type getTransferBatchRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getTransferBatch shows the progress of one of the caller's batches
+func (server *Server) getTransferBatch(ctx *gin.Context) {
+	var req getTransferBatchRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	batch, err := server.store.GetTransferBatch(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if batch.Owner != authPayload.Username {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errBatchNotOwned))
+		return
+	}
+
+	server.writeTransferBatch(ctx, http.StatusOK, batch)
+}
@@ -0,0 +1,673 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/Srinath-exe/simplebank/db/mock"
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/risk"
+	"github.com/Srinath-exe/simplebank/token"
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// transferBatchResult is the part of a batch response the tests check
+type transferBatchResult struct {
+	ID        int64  `json:"id"`
+	Mode      string `json:"mode"`
+	Status    string `json:"status"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+	Held      int    `json:"held"`
+	Items     []struct {
+		Position int32  `json:"position"`
+		Amount   string `json:"amount"`
+		Status   string `json:"status"`
+		Error    string `json:"error"`
+	} `json:"items"`
+}
+
+// expectCreateTransferBatch stubs the creation of a batch of n transfers. The
+// items it returns are filled in as the handler creates them.
+func expectCreateTransferBatch(store *mockdb.MockStore, batch db.TransferBatch, n int) []db.TransferBatchItem {
+	items := make([]db.TransferBatchItem, 0, n)
+
+	store.EXPECT().
+		CreateTransferBatch(gomock.Any(), gomock.Eq(db.CreateTransferBatchParams{Owner: batch.Owner, Mode: batch.Mode})).
+		Times(1).
+		Return(batch, nil)
+	store.EXPECT().
+		CreateTransferBatchItem(gomock.Any(), gomock.Any()).
+		Times(n).
+		DoAndReturn(func(_ context.Context, arg db.CreateTransferBatchItemParams) (db.TransferBatchItem, error) {
+			item := db.TransferBatchItem{
+				ID:            batch.ID*100 + int64(arg.Position),
+				BatchID:       arg.BatchID,
+				Position:      arg.Position,
+				FromAccountID: arg.FromAccountID,
+				ToAccountID:   arg.ToAccountID,
+				Amount:        arg.Amount,
+				Currency:      arg.Currency,
+				Memo:          arg.Memo,
+				Reference:     arg.Reference,
+				Metadata:      arg.Metadata,
+				Status:        db.TransferBatchItemPending,
+			}
+			items = append(items, item)
+			return item, nil
+		})
+
+	return items[:n]
+}
+
+func TestCreateTransferBatchApi(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Currency: util.USD, Balance: 100_000, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	toAccount1 := db.Account{ID: 2, Owner: other.Username, Currency: util.USD, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	toAccount2 := db.Account{ID: 3, Owner: other.Username, Currency: util.USD, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	eurAccount := db.Account{ID: 4, Owner: other.Username, Currency: util.EUR, Status: db.AccountStatusActive, Product: db.ProductChecking}
+
+	atomicBatch := db.TransferBatch{ID: 7, Owner: user.Username, Mode: db.TransferBatchAtomic, Status: db.TransferBatchProcessing, CreatedAt: time.Now()}
+	bestEffortBatch := atomicBatch
+	bestEffortBatch.Mode = db.TransferBatchBestEffort
+
+	payroll := []gin.H{
+		{"from_account_id": fromAccount.ID, "to_account_id": toAccount1.ID, "amount": "1.50", "currency": util.USD, "memo": "Salary"},
+		{"from_account_id": fromAccount.ID, "to_account_id": toAccount2.ID, "amount": "2.25", "currency": util.USD, "memo": "Salary"},
+	}
+
+	expectAccounts := func(store *mockdb.MockStore) {
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount1.ID)).Times(1).Return(toAccount1, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount2.ID)).Times(1).Return(toAccount2, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Atomic",
+			body: gin.H{"mode": db.TransferBatchAtomic, "transfers": payroll},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				expectCreateTransferBatch(store, atomicBatch, 2)
+
+				completed := atomicBatch
+				completed.Status = db.TransferBatchCompleted
+				store.EXPECT().
+					ExecuteTransferBatchTx(gomock.Any(), EqAuditedParams(db.ExecuteTransferBatchTxParams{
+						BatchID: atomicBatch.ID,
+						Limits:  map[string]db.TransferLimits{util.USD: {}},
+					}, auditTransferCreate)).
+					Times(1).
+					Return(db.ExecuteTransferBatchTxResult{Batch: completed}, nil)
+				store.EXPECT().ListTransferBatchItems(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// the batch is made after the response is sent
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var res transferBatchResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.TransferBatchProcessing, res.Status)
+				require.Equal(t, 2, res.Total)
+				require.Zero(t, res.Completed)
+				require.Equal(t, db.TransferBatchItemPending, res.Items[0].Status)
+				require.Equal(t, "2.25", res.Items[1].Amount)
+			},
+		},
+		{
+			name: "AtomicItemFails",
+			body: gin.H{"mode": db.TransferBatchAtomic, "transfers": payroll},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				expectCreateTransferBatch(store, atomicBatch, 2)
+
+				failed := atomicBatch
+				failed.Status = db.TransferBatchFailed
+				store.EXPECT().
+					ExecuteTransferBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExecuteTransferBatchTxResult{}, &db.BatchItemError{
+						Item: db.TransferBatchItem{ID: 702, Position: 2},
+						Err:  db.ErrAccountFrozen,
+					})
+				store.EXPECT().
+					FailTransferBatchItem(gomock.Any(), gomock.Eq(db.FailTransferBatchItemParams{ID: 702, Error: db.ErrAccountFrozen.Error()})).
+					Times(1)
+				store.EXPECT().
+					FinishTransferBatch(gomock.Any(), gomock.Eq(db.FinishTransferBatchParams{ID: atomicBatch.ID, Status: db.TransferBatchFailed})).
+					Times(1).
+					Return(failed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "AtomicExecuteError",
+			body: gin.H{"mode": db.TransferBatchAtomic, "transfers": payroll},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				expectCreateTransferBatch(store, atomicBatch, 2)
+
+				store.EXPECT().
+					ExecuteTransferBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExecuteTransferBatchTxResult{}, sql.ErrConnDone)
+				store.EXPECT().FailTransferBatchItem(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					FinishTransferBatch(gomock.Any(), gomock.Eq(db.FinishTransferBatchParams{ID: atomicBatch.ID, Status: db.TransferBatchFailed})).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "BestEffortPartial",
+			body: gin.H{"mode": db.TransferBatchBestEffort, "transfers": payroll},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				expectCreateTransferBatch(store, bestEffortBatch, 2)
+
+				partial := bestEffortBatch
+				partial.Status = db.TransferBatchPartiallyCompleted
+
+				limitErr := &db.LimitExceededError{Limit: db.LimitAccountDaily, Max: 500}
+				gomock.InOrder(
+					store.EXPECT().
+						ExecuteTransferBatchItemTx(gomock.Any(), EqAuditedParams(db.ExecuteTransferBatchItemTxParams{ItemID: 701}, auditTransferCreate)).
+						Times(1),
+					store.EXPECT().
+						ExecuteTransferBatchItemTx(gomock.Any(), EqAuditedParams(db.ExecuteTransferBatchItemTxParams{ItemID: 702}, auditTransferCreate)).
+						Times(1).
+						Return(db.TransferBatchItem{}, limitErr),
+				)
+				store.EXPECT().
+					FailTransferBatchItem(gomock.Any(), gomock.Eq(db.FailTransferBatchItemParams{ID: 702, Error: limitErr.Error()})).
+					Times(1)
+				store.EXPECT().
+					FinishTransferBatch(gomock.Any(), gomock.Eq(db.FinishTransferBatchParams{ID: bestEffortBatch.ID, Status: db.TransferBatchPartiallyCompleted})).
+					Times(1).
+					Return(partial, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var res transferBatchResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.TransferBatchBestEffort, res.Mode)
+				require.Equal(t, db.TransferBatchProcessing, res.Status)
+			},
+		},
+		{
+			name: "InvalidItems",
+			body: gin.H{"mode": db.TransferBatchBestEffort, "transfers": []gin.H{
+				payroll[0],
+				{"from_account_id": fromAccount.ID, "to_account_id": eurAccount.ID, "amount": "1.00", "currency": util.USD},
+				{"from_account_id": fromAccount.ID, "to_account_id": toAccount2.ID, "amount": "0", "currency": util.USD},
+			}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount1.ID)).Times(1).Return(toAccount1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(eurAccount.ID)).Times(1).Return(eurAccount, nil)
+				store.EXPECT().CreateTransferBatch(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var res struct {
+					Error string           `json:"error"`
+					Items []batchItemError `json:"items"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, errBatchInvalid.Error(), res.Error)
+				require.Len(t, res.Items, 2)
+				require.Equal(t, 2, res.Items[0].Position)
+				require.Contains(t, res.Items[0].Error, "currency mismatch")
+				require.Equal(t, 3, res.Items[1].Position)
+				require.Equal(t, errAmountNotPositive.Error(), res.Items[1].Error)
+			},
+		},
+		{
+			name: "NotOwner",
+			body: gin.H{"mode": db.TransferBatchAtomic, "transfers": payroll[:1]},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, other.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().CreateTransferBatch(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "StepUpRequired",
+			body: gin.H{"mode": db.TransferBatchAtomic, "transfers": []gin.H{
+				{"from_account_id": fromAccount.ID, "to_account_id": toAccount1.ID, "amount": "6.00", "currency": util.USD},
+				{"from_account_id": fromAccount.ID, "to_account_id": toAccount2.ID, "amount": "6.00", "currency": util.USD},
+			}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithAuthTime(t, request, tokenMaker, user.Username, time.Now().Add(-time.Hour))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expectAccounts(store)
+				store.EXPECT().CreateTransferBatch(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyTransfers",
+			body: gin.H{"mode": db.TransferBatchAtomic, "transfers": []gin.H{payroll[0], payroll[0], payroll[0], payroll[0]}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidMode",
+			body: gin.H{"mode": "eventually", "transfers": payroll},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			server.batches.Wait()
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateTransferBatchRunsInBackgroundApi(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Currency: util.USD, Balance: 100_000, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	toAccount := db.Account{ID: 2, Owner: other.Username, Currency: util.USD, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	batch := db.TransferBatch{ID: 8, Owner: user.Username, Mode: db.TransferBatchAtomic, Status: db.TransferBatchProcessing}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the transfers aren't made until the test lets them
+	release := make(chan struct{})
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	expectCreateTransferBatch(store, batch, 1)
+	store.EXPECT().
+		ExecuteTransferBatchTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, _ db.ExecuteTransferBatchTxParams) (db.ExecuteTransferBatchTxResult, error) {
+			<-release
+			return db.ExecuteTransferBatchTxResult{}, nil
+		})
+	allowAuditTx(store)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"mode": db.TransferBatchAtomic, "transfers": []gin.H{
+		{"from_account_id": fromAccount.ID, "to_account_id": toAccount.ID, "amount": "1.00", "currency": util.USD},
+	}})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+
+	// the batch can be polled while its transfers are made
+	store.EXPECT().GetTransferBatch(gomock.Any(), gomock.Eq(batch.ID)).Times(1).Return(batch, nil)
+	store.EXPECT().ListTransferBatchItems(gomock.Any(), gomock.Eq(batch.ID)).Times(1)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("/transfer-batches/%d", batch.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res transferBatchResult
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Equal(t, db.TransferBatchProcessing, res.Status)
+
+	close(release)
+	server.batches.Wait()
+}
+
+func TestCreateTransferBatchRiskApi(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Currency: util.USD, Balance: 100_000, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	toAccount := db.Account{ID: 2, Owner: other.Username, Currency: util.USD, Status: db.AccountStatusActive, Product: db.ProductChecking}
+
+	transfer := gin.H{"from_account_id": fromAccount.ID, "to_account_id": toAccount.ID, "amount": "5.00", "currency": util.USD}
+
+	testCases := []struct {
+		name          string
+		mode          string
+		rules         []risk.Rule
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "VelocityCountsEarlierItems",
+			mode:  db.TransferBatchAtomic,
+			rules: []risk.Rule{&risk.VelocityRule{RuleName: "velocity", Outcome: risk.Deny, Window: time.Hour, MaxCount: 3}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountTransfersSince(gomock.Any(), gomock.Any()).
+					Times(3).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var res struct {
+					Items []batchItemError `json:"items"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Len(t, res.Items, 1)
+				require.Equal(t, 3, res.Items[0].Position)
+				require.Contains(t, res.Items[0].Error, "velocity")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+			store.EXPECT().CreateTransferBatch(gomock.Any(), gomock.Any()).Times(0)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			server.riskEngine = risk.NewEngine(tc.rules...)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"mode": tc.mode, "transfers": []gin.H{transfer, transfer, transfer}})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestBatchRiskHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().CountTransfersBetween(gomock.Any(), gomock.Any()).AnyTimes().Return(int64(1), nil)
+	store.EXPECT().CountTransfersSince(gomock.Any(), gomock.Any()).AnyTimes().Return(int64(1), nil)
+
+	history := batchRiskHistory{
+		riskHistory: riskHistory{store: store},
+		transfers: []batchTransfer{
+			{arg: db.TransferTxParams{FromAccID: 1, ToAccID: 2}},
+			{arg: db.TransferTxParams{FromAccID: 1, ToAccID: 3}, holdRules: []string{"new_payee"}},
+			{arg: db.TransferTxParams{FromAccID: 4, ToAccID: 2}},
+		},
+	}
+	ctx := context.Background()
+
+	count, err := history.CountTransfersBetween(ctx, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	// a held transfer doesn't make its recipient a known one
+	count, err = history.CountTransfersBetween(ctx, 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	count, err = history.CountTransfersSince(ctx, 1, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+}
+
+func TestCreateTransferBatchCSVApi(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	fromAccount := db.Account{ID: 1, Owner: user.Username, Currency: util.CAD, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	toAccount := db.Account{ID: 2, Owner: other.Username, Currency: util.CAD, Status: db.AccountStatusActive, Product: db.ProductChecking}
+	batch := db.TransferBatch{ID: 9, Owner: user.Username, Mode: db.TransferBatchBestEffort, Status: db.TransferBatchProcessing}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	expectCreateTransferBatch(store, batch, 2)
+	store.EXPECT().ExecuteTransferBatchItemTx(gomock.Any(), gomock.Any()).Times(2)
+
+	completed := batch
+	completed.Status = db.TransferBatchCompleted
+	store.EXPECT().
+		FinishTransferBatch(gomock.Any(), gomock.Eq(db.FinishTransferBatchParams{ID: batch.ID, Status: db.TransferBatchCompleted})).
+		Times(1).
+		Return(completed, nil)
+	allowAuditTx(store)
+
+	server := NewTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	require.NoError(t, form.WriteField("mode", db.TransferBatchBestEffort))
+
+	file, err := form.CreateFormFile("file", "payroll.csv")
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(file, "from_account_id,to_account_id,amount,currency,memo\n%d,%d,10.00,CAD,\"Salary, March\"\n%d,%d,0.99,CAD,\n",
+		fromAccount.ID, toAccount.ID, fromAccount.ID, toAccount.ID)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers/batch", body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", form.FormDataContentType())
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	server.batches.Wait()
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+
+	var res transferBatchResult
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Equal(t, db.TransferBatchProcessing, res.Status)
+	require.Equal(t, "10.00", res.Items[0].Amount)
+}
+
+func TestParseTransferBatchCSV(t *testing.T) {
+	transfers, err := parseTransferBatchCSV(strings.NewReader("Amount, currency,payee_id,from_account_id\n1.00,USD,5,1\n"))
+	require.NoError(t, err)
+	require.Equal(t, []transferBatchItemRequest{{FromAccountID: 1, PayeeID: 5, Amount: "1.00", Currency: util.USD}}, transfers)
+
+	for _, csv := range []string{
+		"",
+		"from_account_id,iban\n1,DE89\n",
+		"from_account_id,to_account_id,amount,currency\none,2,1.00,USD\n",
+		"from_account_id,to_account_id,amount,currency\n1,2,1.00\n",
+	} {
+		_, err := parseTransferBatchCSV(strings.NewReader(csv))
+		require.Error(t, err, csv)
+	}
+}
+
+func TestGetTransferBatchApi(t *testing.T) {
+	user, _ := randomUser(t)
+	batch := db.TransferBatch{ID: 5, Owner: user.Username, Mode: db.TransferBatchBestEffort, Status: db.TransferBatchPartiallyCompleted}
+	items := []db.TransferBatchItem{
+		{ID: 1, BatchID: batch.ID, Position: 1, Amount: 100, Currency: util.USD, Status: db.TransferBatchItemCompleted, TransferID: sql.NullInt64{Int64: 3, Valid: true}},
+		{ID: 2, BatchID: batch.ID, Position: 2, Amount: 100, Currency: util.USD, Status: db.TransferBatchItemFailed, Error: "account is frozen"},
+		{ID: 3, BatchID: batch.ID, Position: 3, Amount: 100, Currency: util.USD, Status: db.TransferBatchItemHeld, ReviewID: sql.NullInt64{Int64: 4, Valid: true}},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferBatch(gomock.Any(), gomock.Eq(batch.ID)).Times(1).Return(batch, nil)
+				store.EXPECT().ListTransferBatchItems(gomock.Any(), gomock.Eq(batch.ID)).Times(1).Return(items, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res transferBatchResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, 3, res.Total)
+				require.Equal(t, 1, res.Completed)
+				require.Equal(t, 1, res.Failed)
+				require.Equal(t, 1, res.Held)
+				require.Equal(t, "account is frozen", res.Items[1].Error)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferBatch(gomock.Any(), gomock.Eq(batch.ID)).Times(1).Return(db.TransferBatch{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "NotOwner",
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferBatch(gomock.Any(), gomock.Eq(batch.ID)).Times(1).Return(batch, nil)
+				store.EXPECT().ListTransferBatchItems(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/transfer-batches/%d", batch.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -3,29 +3,31 @@ package api
 import (
 	"database/sql"
 	"errors"
+	"log"
 	"net/http"
 	"time"
 
 	db "github.com/Srinath-exe/simplebank/db/sqlc"
 	"github.com/Srinath-exe/simplebank/token"
-	"github.com/Srinath-exe/simplebank/util"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
 type createUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 	FullName string `json:"full_name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 }
 
 type userResponse struct {
-	Username          string    `json:"username"`
-	FullName          string    `json:"full_name"`
-	Email             string    `json:"email"`
-	PasswordChangedAt time.Time `json:"password_changed_at"`
-	CreatedAt         time.Time `json:"created_at"`
+	Username          string     `json:"username"`
+	FullName          string     `json:"full_name"`
+	Email             string     `json:"email"`
+	PasswordChangedAt time.Time  `json:"password_changed_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	Role              string     `json:"role"`
+	DeactivatedAt     *time.Time `json:"deactivated_at,omitempty"`
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -35,6 +37,8 @@ func newUserResponse(user db.User) userResponse {
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
+		Role:              user.Role,
+		DeactivatedAt:     nullTimePtr(user.DeactivatedAt),
 	}
 }
 
@@ -46,7 +50,12 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
-	hashedPassword, err := util.HashPassword(req.Password)
+	if err := server.passwordPolicy.Validate(req.Password); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := server.passwordHasher.Hash(req.Password)
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -60,7 +69,17 @@ func (server *Server) createUser(ctx *gin.Context) {
 		Email:          req.Email,
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	audit := newAuditParams(ctx, auditUserCreate, auditTargetUser, req.Username)
+	audit.Actor = req.Username
+
+	var user db.User
+
+	err = server.store.AuditTx(ctx, audit, func(q db.Querier, audit *db.AuditParams) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg)
+		audit.After = newUserResponse(user)
+		return err
+	})
 
 	if err != nil {
 
@@ -143,14 +162,23 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
-	err = util.CheckPasswordHash(req.Password, user.HashedPassword)
+	err = server.passwordHasher.Check(req.Password, user.HashedPassword)
 
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	if user.DeactivatedAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(errUserDeactivated))
+		return
+	}
+
+	if server.passwordHasher.NeedsRehash(user.HashedPassword) {
+		server.rehashPassword(ctx, user, req.Password)
+	}
+
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration, token.WithAMR(token.AMRPassword), token.WithRole(user.Role))
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -166,25 +194,96 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 }
 
-type updatePasswordRequest struct {
-	NewPassword string `json:"new_password" binding:"required,min=6"`
-	Username    string `json:"username" binding:"required,alphanum"`
+// rehashPassword upgrades a hash made with an outdated algorithm or cost while
+// the plaintext password is at hand. Failing to upgrade must not fail the login,
+// the old hash keeps working and is retried on the next login.
+func (server *Server) rehashPassword(ctx *gin.Context, user db.User, password string) {
+	hashedPassword, err := server.passwordHasher.Hash(password)
+	if err != nil {
+		log.Printf("cannot rehash password of %s: %v", user.Username, err)
+		return
+	}
+
+	err = server.store.UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{
+		HashedPassword:    hashedPassword,
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+	})
+	if err != nil {
+		log.Printf("cannot rehash password of %s: %v", user.Username, err)
+	}
 }
 
-func (server *Server) updatePassword(ctx *gin.Context) {
-	var req updatePasswordRequest
+type reauthenticateUserRequest struct {
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type reauthenticateUserResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// reauthenticateUser verifies the password of the already authenticated user
+// again and issues a short-lived elevated token with a fresh auth_time, which
+// is required for transfers above the step-up threshold.
+func (server *Server) reauthenticateUser(ctx *gin.Context) {
+	var req reauthenticateUserRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	hashedPassword, err := util.HashPassword(req.NewPassword)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
 
 	if err != nil {
+
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+
+	err = server.passwordHasher.Check(req.Password, user.HashedPassword)
+
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if user.DeactivatedAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(errUserDeactivated))
+		return
+	}
+
+	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.ElevatedTokenDuration, token.WithAMR(token.AMRPassword), token.WithRole(user.Role))
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reauthenticateUserResponse{AccessToken: accessToken})
+}
+
+type updatePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	Username        string `json:"username" binding:"required,alphanum"`
+}
+
+func (server *Server) updatePassword(ctx *gin.Context) {
+	var req updatePasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	user, err := server.store.GetUser(ctx, req.Username)
 
 	if err != nil {
@@ -205,12 +304,31 @@ func (server *Server) updatePassword(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.UpdatePasswordParams{
-		Username:       req.Username,
-		HashedPassword: hashedPassword,
+	if err := server.passwordHasher.Check(req.CurrentPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errWrongPassword))
+		return
+	}
+
+	if err := server.validateNewPassword(ctx, user, req.NewPassword); err != nil {
+		ctx.JSON(newPasswordErrorStatus(err), errorResponse(err))
+		return
 	}
 
-	err = server.store.UpdatePassword(ctx, arg)
+	hashedPassword, err := server.passwordHasher.Hash(req.NewPassword)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdatePasswordTxParams{
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+		HashedPassword:    hashedPassword,
+		Audit:             newAuditParams(ctx, auditUserPasswordUpdate, auditTargetUser, user.Username),
+	}
+
+	err = server.store.UpdatePasswordTx(ctx, arg)
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -220,6 +338,8 @@ func (server *Server) updatePassword(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "password updated"})
 }
 
+var errUserDeactivated = errors.New("user has been deactivated")
+
 type deleteUserRequest struct {
 	Username string `uri:"username" binding:"required,alphanum"`
 }
@@ -240,7 +360,12 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 		return
 	}
 
-	err := server.store.DeleteUserWithAccountsTx(ctx, req.Username)
+	arg := db.DeactivateUserTxParams{
+		Username: req.Username,
+		Audit:    newAuditParams(ctx, auditUserDeactivate, auditTargetUser, req.Username),
+	}
+
+	err := server.store.DeactivateUserTx(ctx, arg)
 
 	if err != nil {
 
@@ -249,11 +374,16 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, db.ErrAccountNotEmpty) || errors.Is(err, db.ErrAccountFrozen) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "user deleted"})
+	ctx.JSON(http.StatusOK, gin.H{"status": "user deactivated"})
 }
 
 type SearchUsersRequest struct {
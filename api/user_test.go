@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -183,6 +184,7 @@ func TestCreateUserApi(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -358,6 +360,21 @@ func TestLoginUser(t *testing.T) {
 				require.Equal(t, user.Username, res.User.Username)
 			},
 		},
+		{
+			name: "Deactivated",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				deactivated := user
+				deactivated.DeactivatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(deactivated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "InvalidUsername",
 			body: gin.H{
@@ -435,6 +452,7 @@ func TestLoginUser(t *testing.T) {
 			defer ctrl.Finish()
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 			recorder := httptest.NewRecorder()
 
 			server := NewTestServer(t, store)
@@ -454,9 +472,56 @@ func TestLoginUser(t *testing.T) {
 
 }
 
+func TestLoginUserRehashesPassword(t *testing.T) {
+	user, password := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().
+		UpdatePasswordHash(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.UpdatePasswordHashParams) error {
+			require.Equal(t, user.Username, arg.Username)
+			require.Equal(t, user.HashedPassword, arg.OldHashedPassword)
+			require.True(t, strings.HasPrefix(arg.HashedPassword, "$argon2id$"))
+			return nil
+		})
+
+	server := NewTestServer(t, store)
+
+	// the stored bcrypt hash is outdated once the server moves to argon2id
+	hasher, err := util.NewPasswordHasher(util.Config{
+		PasswordHashAlgorithm: util.PasswordHashArgon2id,
+		Argon2Memory:          1024,
+		Argon2Iterations:      1,
+		Argon2Parallelism:     1,
+	})
+	require.NoError(t, err)
+	server.passwordHasher = hasher
+
+	data, err := json.Marshal(gin.H{
+		"username": user.Username,
+		"password": password,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
 func TestUpdatePassword(t *testing.T) {
-	user, _ := randomUser(t)
-	newPassword := util.RandomString(6)
+	user, password := randomUser(t)
+	newPassword := util.RandomString(8)
+
+	usedHashedPassword, err := util.HashPassword(newPassword)
+	require.NoError(t, err)
 
 	testCases := []struct {
 		name          string
@@ -465,41 +530,46 @@ func TestUpdatePassword(t *testing.T) {
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
-		// {
-		// 	name: "OK",
-		// 	body: gin.H{
-		// 		"username":     user.Username,
-		// 		"new_password": newPassword,
-		// 	},
-		// 	buildStubs: func(store *mockdb.MockStore) {
-		// 		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
-
-		// 		hashedPassword, err := util.HashPassword(newPassword)
-		// 		require.NoError(t, err)
-		// 		req := db.UpdatePasswordParams{
-		// 			Username:       user.Username,
-		// 			HashedPassword: hashedPassword,
-		// 		}
-
-		// 		store.EXPECT().UpdatePassword(gomock.Any(), gomock.Eq(req)).Times(1).Return(nil)
-		// 	},
-		// 	setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-		// 		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
-		// 	},
-		// 	checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-		// 		require.Equal(t, http.StatusOK, recorder.Code)
-		// 	},
-		// },
+		{
+			name: "OK",
+			body: gin.H{
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ListPasswordHistory(gomock.Any(), gomock.Eq(db.ListPasswordHistoryParams{Username: user.Username, Limit: 2})).
+					Times(1).
+					Return([]db.PasswordHistory{}, nil)
+				store.EXPECT().
+					UpdatePasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdatePasswordTxParams) error {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, user.HashedPassword, arg.OldHashedPassword)
+						require.NoError(t, util.CheckPasswordHash(newPassword, arg.HashedPassword))
+						return nil
+					})
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name: "NoAuthorization",
 			body: gin.H{
-				"username":     user.Username,
-				"new_password": newPassword,
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     newPassword,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(0)
-
-				store.EXPECT().UpdatePassword(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// Do nothing
@@ -508,14 +578,65 @@ func TestUpdatePassword(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		}, {
-			name: "InvalidUsername",
+			name: "UnauthorizedUser",
 			body: gin.H{
-				"username":     "",
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		}, {
+			name: "WrongCurrentPassword",
+			body: gin.H{
+				"username":         user.Username,
+				"current_password": "wrong-password",
+				"new_password":     newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		}, {
+			name: "MissingCurrentPassword",
+			body: gin.H{
+				"username":     user.Username,
 				"new_password": newPassword,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
-				store.EXPECT().UpdatePassword(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name: "InvalidUsername",
+			body: gin.H{
+				"username":         "",
+				"current_password": password,
+				"new_password":     newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -526,11 +647,52 @@ func TestUpdatePassword(t *testing.T) {
 		}, {
 			name: "InvalidPassword",
 			body: gin.H{
-				"username":     user.Username,
-				"new_password": "123",
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     "123",
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().UpdatePassword(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name: "SameAsCurrentPassword",
+			body: gin.H{
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListPasswordHistory(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		}, {
+			name: "RecentlyUsedPassword",
+			body: gin.H{
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ListPasswordHistory(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.PasswordHistory{{Username: user.Username, HashedPassword: usedHashedPassword}}, nil)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -541,12 +703,13 @@ func TestUpdatePassword(t *testing.T) {
 		}, {
 			name: "UserNotFound",
 			body: gin.H{
-				"username":     user.Username,
-				"new_password": newPassword,
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     newPassword,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
-				store.EXPECT().UpdatePassword(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -557,12 +720,32 @@ func TestUpdatePassword(t *testing.T) {
 		}, {
 			name: "InternalError",
 			body: gin.H{
-				"username":     user.Username,
-				"new_password": newPassword,
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     newPassword,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, sql.ErrConnDone)
-				store.EXPECT().UpdatePassword(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError UpdatePasswordTx",
+			body: gin.H{
+				"username":         user.Username,
+				"current_password": password,
+				"new_password":     newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListPasswordHistory(gomock.Any(), gomock.Any()).Times(1).Return([]db.PasswordHistory{}, nil)
+				store.EXPECT().UpdatePasswordTx(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -571,31 +754,6 @@ func TestUpdatePassword(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
-		// {
-		// 	name: "InternalError UpdatePassword",
-		// 	body: gin.H{
-		// 		"username":     user.Username,
-		// 		"new_password": newPassword,
-		// 	},
-		// 	buildStubs: func(store *mockdb.MockStore) {
-		// 		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
-
-		// 		hashedPassword, err := util.HashPassword(newPassword)
-		// 		require.NoError(t, err)
-		// 		req := db.UpdatePasswordParams{
-		// 			Username:       user.Username,
-		// 			HashedPassword: hashedPassword,
-		// 		}
-
-		// 		store.EXPECT().UpdatePassword(gomock.Any(), gomock.Eq(req)).Times(1).Return(sql.ErrConnDone)
-		// 	},
-		// 	setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-		// 		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
-		// 	},
-		// 	checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-		// 		require.Equal(t, http.StatusInternalServerError, recorder.Code)
-		// 	},
-		// },
 	}
 
 	for i := range testCases {
@@ -608,6 +766,7 @@ func TestUpdatePassword(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -643,7 +802,7 @@ func TestDeleteUser(t *testing.T) {
 			name:     "OK",
 			username: user.Username,
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteUserWithAccountsTx(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil)
+				store.EXPECT().DeactivateUserTx(gomock.Any(), EqAuditedParams(db.DeactivateUserTxParams{Username: user.Username}, auditUserDeactivate)).Times(1).Return(nil)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -655,7 +814,7 @@ func TestDeleteUser(t *testing.T) {
 			name:     "NoAuthorization",
 			username: user.Username,
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteUserWithAccountsTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeactivateUserTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// Do nothing
@@ -667,7 +826,7 @@ func TestDeleteUser(t *testing.T) {
 			name:     "InternalError",
 			username: user.Username,
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteUserWithAccountsTx(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(sql.ErrConnDone)
+				store.EXPECT().DeactivateUserTx(gomock.Any(), EqAuditedParams(db.DeactivateUserTxParams{Username: user.Username}, auditUserDeactivate)).Times(1).Return(sql.ErrConnDone)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -679,7 +838,7 @@ func TestDeleteUser(t *testing.T) {
 			name:     "UserNotFound",
 			username: user.Username,
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteUserWithAccountsTx(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(sql.ErrNoRows)
+				store.EXPECT().DeactivateUserTx(gomock.Any(), EqAuditedParams(db.DeactivateUserTxParams{Username: user.Username}, auditUserDeactivate)).Times(1).Return(sql.ErrNoRows)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -687,11 +846,23 @@ func TestDeleteUser(t *testing.T) {
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
+		}, {
+			name:     "AccountNotEmpty",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeactivateUserTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrAccountNotEmpty)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
 		}, {
 			name:     "Bad Request",
 			username: "235$",
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteUserWithAccountsTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeactivateUserTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
@@ -704,7 +875,7 @@ func TestDeleteUser(t *testing.T) {
 			name:     "account doesn't belong to the authenticated user",
 			username: user.Username,
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteUserWithAccountsTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeactivateUserTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "other-user", time.Minute)
@@ -725,6 +896,7 @@ func TestDeleteUser(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -911,6 +1083,7 @@ func TestSearchUsers(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -1101,6 +1274,7 @@ func TestGetUsers(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			allowAuditTx(store)
 
 			server := NewTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -1121,3 +1295,115 @@ func TestGetUsers(t *testing.T) {
 	}
 
 }
+
+func TestReauthenticateUser(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorizationWithAuthTime(t, request, tokenMaker, user.Username, time.Now().Add(-time.Hour))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res reauthenticateUserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&res)
+				require.NoError(t, err)
+
+				payload, err := tokenMaker.VerifyToken(res.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.WithinDuration(t, time.Now(), payload.AuthTime, time.Second)
+				require.Equal(t, []string{token.AMRPassword}, payload.AMR)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{
+				"password": "wrong-password",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Deactivated",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				deactivated := user
+				deactivated.DeactivatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(deactivated, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				// Do nothing
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			allowAuditTx(store)
+
+			server := NewTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/users/reauthenticate"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server.tokenMaker)
+		})
+	}
+}
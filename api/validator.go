@@ -1,13 +1,51 @@
 package api
 
 import (
+	"context"
+
 	"github.com/Srinath-exe/simplebank/util"
 	"github.com/go-playground/validator/v10"
 )
 
-var validCurrency validator.Func = func(fieldlevel validator.FieldLevel) bool {
+// validCurrency accepts the currencies enabled in the currencies table
+func (server *Server) validCurrency(fieldlevel validator.FieldLevel) bool {
 	if currency, ok := fieldlevel.Field().Interface().(string); ok {
-		return util.IsSupportedCurrency(currency)
+		return server.currencies.enabled(context.Background(), currency)
+	}
+	return false
+}
+
+var validScope validator.Func = func(fieldlevel validator.FieldLevel) bool {
+	if scope, ok := fieldlevel.Field().Interface().(string); ok {
+		return isSupportedScope(scope)
+	}
+	return false
+}
+
+var validFreezeReason validator.Func = func(fieldlevel validator.FieldLevel) bool {
+	if reason, ok := fieldlevel.Field().Interface().(string); ok {
+		return isFreezeReason(reason)
+	}
+	return false
+}
+
+var validIBAN validator.Func = func(fieldlevel validator.FieldLevel) bool {
+	if iban, ok := fieldlevel.Field().Interface().(string); ok {
+		return util.IsValidIBAN(util.NormalizeIBAN(iban))
+	}
+	return false
+}
+
+var validBIC validator.Func = func(fieldlevel validator.FieldLevel) bool {
+	if bic, ok := fieldlevel.Field().Interface().(string); ok {
+		return util.IsValidBIC(util.NormalizeBIC(bic))
+	}
+	return false
+}
+
+var validABARouting validator.Func = func(fieldlevel validator.FieldLevel) bool {
+	if routing, ok := fieldlevel.Field().Interface().(string); ok {
+		return util.IsValidABARouting(routing)
 	}
 	return false
 }
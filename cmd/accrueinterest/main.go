@@ -0,0 +1,44 @@
+// Command accrueinterest records a day of interest on every interest-bearing
+// account. Run it once a day, after midnight UTC, for the day that just
+// ended; running it again for the same day does nothing.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+	date := flag.String("date", yesterday, "UTC day to accrue interest for, as YYYY-MM-DD")
+	flag.Parse()
+
+	day, err := time.Parse(time.DateOnly, *date)
+	if err != nil {
+		log.Fatal("invalid date: ", err)
+	}
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db: ", err)
+	}
+
+	accrued, err := db.AccrueInterest(context.Background(), db.New(conn), day)
+	if err != nil {
+		log.Fatalf("interest accrual for %s failed after %d accounts: %v", *date, accrued, err)
+	}
+
+	log.Printf("accrued interest for %s: %d accounts", *date, accrued)
+}
@@ -0,0 +1,79 @@
+// Command chargefees charges the monthly maintenance fee of every active
+// account whose product has one in its currency. Run it on the first of the
+// month for the month just ended. Accounts already charged for the month are
+// skipped, so it can be rerun; accounts that fail are logged and the command
+// exits with a non-zero status.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	now := time.Now().UTC()
+	previousMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format("2006-01")
+	month := flag.String("month", previousMonth, "month to charge maintenance fees for, as YYYY-MM")
+	flag.Parse()
+
+	start, err := time.Parse("2006-01", *month)
+	if err != nil {
+		log.Fatal("invalid month: ", err)
+	}
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db: ", err)
+	}
+
+	ctx := context.Background()
+	store := db.NewStore(conn)
+
+	accountIDs, err := store.ListMaintenanceFeeAccounts(ctx, db.ListMaintenanceFeeAccountsParams{
+		PeriodEnd:   start.AddDate(0, 1, 0),
+		PeriodStart: sql.NullTime{Time: start, Valid: true},
+	})
+	if err != nil {
+		log.Fatal("cannot list accounts: ", err)
+	}
+
+	charged, failed := 0, 0
+	for _, accountID := range accountIDs {
+		result, err := store.ChargeMaintenanceFeeTx(ctx, db.ChargeMaintenanceFeeTxParams{
+			AccountID:   accountID,
+			PeriodStart: start,
+			Audit: db.AuditParams{
+				Actor:      "_system",
+				Action:     "fee.charge",
+				TargetType: "fee",
+			},
+		})
+		if err != nil {
+			log.Printf("account %d: cannot charge maintenance fee: %v", accountID, err)
+			failed++
+			continue
+		}
+
+		if result.Fee != nil {
+			charged++
+		}
+	}
+
+	log.Printf("charged maintenance fees for %s: %d accounts charged, %d failed", *month, charged, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,120 @@
+// Command importstatement stages the lines of a statement from a partner bank
+// and matches them to the entries of an account, as the statement import
+// endpoint does, then lists the lines left unmatched. The format is told from
+// the file name unless -format is set; the column flags map the columns of
+// CSV statements.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/Srinath-exe/simplebank/statement"
+	"github.com/Srinath-exe/simplebank/util"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	path := flag.String("file", "", "statement file to import")
+	accountID := flag.Int64("account", 0, "ID of the account to match the statement against")
+	format := flag.String("format", "", "statement format: csv, ofx or camt053")
+
+	var mapping statement.CSVMapping
+	flag.StringVar(&mapping.Date, "date-column", "", "CSV column of the booking date")
+	flag.StringVar(&mapping.Amount, "amount-column", "", "CSV column of the signed amount")
+	flag.StringVar(&mapping.Credit, "credit-column", "", "CSV column of credited amounts")
+	flag.StringVar(&mapping.Debit, "debit-column", "", "CSV column of debited amounts")
+	flag.StringVar(&mapping.Currency, "currency-column", "", "CSV column of the currency")
+	flag.StringVar(&mapping.Reference, "reference-column", "", "CSV column of the payment reference")
+	flag.StringVar(&mapping.Description, "description-column", "", "CSV column of the description")
+	flag.StringVar(&mapping.DateLayout, "date-layout", "", "layout of CSV dates, as for Go's time.Parse")
+	flag.StringVar(&mapping.Delimiter, "delimiter", "", "CSV field delimiter")
+	flag.Parse()
+
+	if *path == "" || *accountID == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		*format = statement.DetectFormat(*path)
+	}
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db: ", err)
+	}
+
+	ctx := context.Background()
+	store := db.NewStore(conn)
+
+	account, err := store.GetAccount(ctx, *accountID)
+	if err != nil {
+		log.Fatal("cannot get account: ", err)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatal("cannot open statement: ", err)
+	}
+	defer file.Close()
+
+	lines, err := statement.Parse(file, *format, statement.Options{Currency: account.Currency, CSV: mapping})
+	if err != nil {
+		log.Fatal("cannot parse statement: ", err)
+	}
+
+	arg := db.ImportStatementTxParams{
+		AccountID:   account.ID,
+		Format:      *format,
+		Filename:    filepath.Base(*path),
+		ImportedBy:  "_system",
+		MatchWindow: config.StatementMatchWindow,
+		Lines:       make([]db.StatementLineParams, len(lines)),
+		Audit: db.AuditParams{
+			Actor:      "_system",
+			Action:     "statement_import.create",
+			TargetType: "statement_import",
+		},
+	}
+
+	for i, line := range lines {
+		if line.Currency != account.Currency {
+			log.Fatalf("statement line %d: currency %s doesn't match the account", line.Position, line.Currency)
+		}
+
+		arg.Lines[i] = db.StatementLineParams{
+			Position:    int32(line.Position),
+			BookedOn:    line.BookedOn,
+			Amount:      line.Amount,
+			Currency:    line.Currency,
+			Reference:   line.Reference,
+			Description: line.Description,
+		}
+	}
+
+	result, err := store.ImportStatementTx(ctx, arg)
+	if err != nil {
+		log.Fatal("cannot import statement: ", err)
+	}
+
+	for _, line := range result.Unmatched {
+		log.Printf("unmatched line %d: %s %s %s %q %q", line.Position, line.BookedOn.Format(time.DateOnly),
+			money.New(line.Amount, line.Currency), line.Currency, line.Reference, line.Description)
+	}
+
+	log.Printf("statement import %d: %d lines, %d matched, %d unmatched",
+		result.Import.ID, result.Import.Lines, result.Import.Matched, len(result.Unmatched))
+}
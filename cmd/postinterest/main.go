@@ -0,0 +1,71 @@
+// Command postinterest pays the interest accrued in a month into each
+// account. Run it on the first of the month, after accrueinterest has run
+// for the last day of the previous month. Accounts that fail are logged and
+// left for the next run, and the command exits with a non-zero status.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	now := time.Now().UTC()
+	previousMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format("2006-01")
+	month := flag.String("month", previousMonth, "month to post interest for, as YYYY-MM")
+	flag.Parse()
+
+	start, err := time.Parse("2006-01", *month)
+	if err != nil {
+		log.Fatal("invalid month: ", err)
+	}
+	periodEnd := start.AddDate(0, 1, 0)
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db: ", err)
+	}
+
+	ctx := context.Background()
+	store := db.NewStore(conn)
+
+	accountIDs, err := store.ListAccountsWithUnpostedInterest(ctx, periodEnd)
+	if err != nil {
+		log.Fatal("cannot list accounts: ", err)
+	}
+
+	failed := 0
+	for _, accountID := range accountIDs {
+		_, err := store.PostInterestTx(ctx, db.PostInterestTxParams{
+			AccountID: accountID,
+			PeriodEnd: periodEnd,
+			Audit: db.AuditParams{
+				Actor:      "_system",
+				Action:     "interest.post",
+				TargetType: "interest_posting",
+			},
+		})
+		if err != nil {
+			log.Printf("account %d: cannot post interest: %v", accountID, err)
+			failed++
+		}
+	}
+
+	log.Printf("posted interest for %s: %d accounts, %d failed", *month, len(accountIDs)-failed, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
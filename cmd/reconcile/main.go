@@ -0,0 +1,190 @@
+// Command reconcile checks every account balance against the sum of its
+// entries and the transfer and fee entries of every currency against 0, and
+// records the totals and discrepancies as a reconciliation run that bankers
+// can review. Run once, it exits with a non-zero status if it found any
+// discrepancy; with -interval it keeps reconciling until stopped.
+//
+// With -metrics-file the result of the last run is written in the Prometheus
+// text format, for the node exporter's textfile collector to pick up. With
+// -alert-webhook a JSON summary is posted to the URL whenever a run finds a
+// discrepancy.
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	interval := flag.Duration("interval", 0, "reconcile every interval until stopped, 0 to reconcile once")
+	metricsFile := flag.String("metrics-file", "", "file to write Prometheus metrics of the last run to")
+	alertWebhook := flag.String("alert-webhook", "", "URL to post a JSON summary to when a run finds discrepancies")
+	flag.Parse()
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db: ", err)
+	}
+
+	store := db.NewStore(conn)
+
+	if *interval == 0 {
+		result, err := reconcile(store, *metricsFile, *alertWebhook)
+		if err != nil {
+			log.Fatal("cannot reconcile: ", err)
+		}
+
+		if result.Run.Discrepancies > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	for {
+		if _, err := reconcile(store, *metricsFile, *alertWebhook); err != nil {
+			log.Print("cannot reconcile: ", err)
+		}
+
+		time.Sleep(*interval)
+	}
+}
+
+// reconcile records a reconciliation run and reports it
+func reconcile(store db.Store, metricsFile, alertWebhook string) (db.ReconcileTxResult, error) {
+	ctx := context.Background()
+
+	result, err := store.ReconcileTx(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	for _, d := range result.Discrepancies {
+		if d.AccountID.Valid {
+			log.Printf("run %d: account %d drifted: balance %d, entries %d %s", result.Run.ID, d.AccountID.Int64, d.Actual, d.Expected, d.Currency)
+			continue
+		}
+
+		log.Printf("run %d: %s ledger imbalanced by %d", result.Run.ID, d.Currency, d.Actual)
+	}
+
+	log.Printf("reconciliation run %d: %d accounts checked, %d discrepancies", result.Run.ID, result.Run.AccountsChecked, result.Run.Discrepancies)
+
+	if metricsFile != "" {
+		if err := writeMetrics(metricsFile, result); err != nil {
+			log.Print("cannot write metrics: ", err)
+		}
+	}
+
+	if alertWebhook != "" && result.Run.Discrepancies > 0 {
+		if err := sendAlert(ctx, alertWebhook, result); err != nil {
+			log.Print("cannot send alert: ", err)
+		}
+	}
+
+	return result, nil
+}
+
+// writeMetrics replaces path with the metrics of the run. The file is renamed
+// into place so a collector never reads it half written.
+func writeMetrics(path string, result db.ReconcileTxResult) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "# HELP simplebank_reconciliation_last_run_timestamp_seconds Time the last reconciliation run finished.")
+	fmt.Fprintln(&buf, "# TYPE simplebank_reconciliation_last_run_timestamp_seconds gauge")
+	fmt.Fprintf(&buf, "simplebank_reconciliation_last_run_timestamp_seconds %d\n", result.Run.FinishedAt.Time.Unix())
+	fmt.Fprintln(&buf, "# HELP simplebank_reconciliation_accounts_checked Accounts checked by the last reconciliation run.")
+	fmt.Fprintln(&buf, "# TYPE simplebank_reconciliation_accounts_checked gauge")
+	fmt.Fprintf(&buf, "simplebank_reconciliation_accounts_checked %d\n", result.Run.AccountsChecked)
+
+	discrepancies := map[string]int{db.DiscrepancyAccountDrift: 0, db.DiscrepancyLedgerImbalance: 0}
+	for _, d := range result.Discrepancies {
+		discrepancies[d.Kind]++
+	}
+
+	fmt.Fprintln(&buf, "# HELP simplebank_reconciliation_discrepancies Discrepancies found by the last reconciliation run.")
+	fmt.Fprintln(&buf, "# TYPE simplebank_reconciliation_discrepancies gauge")
+	for _, kind := range []string{db.DiscrepancyAccountDrift, db.DiscrepancyLedgerImbalance} {
+		fmt.Fprintf(&buf, "simplebank_reconciliation_discrepancies{kind=%q} %d\n", kind, discrepancies[kind])
+	}
+
+	totals := []struct {
+		name  string
+		help  string
+		value func(db.ReconciliationTotal) int64
+	}{
+		{"balance_total", "Sum of the account balances, in minor units.", func(t db.ReconciliationTotal) int64 { return t.BalanceTotal }},
+		{"entry_total", "Sum of the account entries, in minor units.", func(t db.ReconciliationTotal) int64 { return t.EntryTotal }},
+		{"internal_total", "Sum of the transfer and fee entries, in minor units.", func(t db.ReconciliationTotal) int64 { return t.InternalTotal }},
+	}
+	for _, total := range totals {
+		fmt.Fprintf(&buf, "# HELP simplebank_reconciliation_%s %s\n", total.name, total.help)
+		fmt.Fprintf(&buf, "# TYPE simplebank_reconciliation_%s gauge\n", total.name)
+		for _, t := range result.Totals {
+			fmt.Fprintf(&buf, "simplebank_reconciliation_%s{currency=%q} %d\n", total.name, t.Currency, total.value(t))
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// sendAlert posts a summary of the run to url
+func sendAlert(ctx context.Context, url string, result db.ReconcileTxResult) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", res.Status)
+	}
+
+	return nil
+}
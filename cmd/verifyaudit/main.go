@@ -0,0 +1,32 @@
+// Command verifyaudit walks the audit log and checks its hash chain, exiting
+// with a non-zero status if any event was altered, removed or reordered.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	db "github.com/Srinath-exe/simplebank/db/sqlc"
+	"github.com/Srinath-exe/simplebank/util"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db: ", err)
+	}
+
+	checked, err := db.VerifyAuditChain(context.Background(), db.New(conn))
+	if err != nil {
+		log.Fatalf("audit chain broken after %d events: %v", checked, err)
+	}
+
+	log.Printf("audit chain ok: %d events", checked)
+}
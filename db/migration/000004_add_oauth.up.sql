@@ -0,0 +1,45 @@
+CREATE TABLE "oauth_clients" (
+  "client_id" varchar PRIMARY KEY,
+  "hashed_secret" varchar NOT NULL DEFAULT '',
+  "name" varchar NOT NULL,
+  "owner" varchar NOT NULL,
+  "redirect_uris" varchar[] NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "oauth_consents" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "client_id" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "account_ids" bigint[] NOT NULL,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "oauth_authorization_codes" (
+  "hashed_code" varchar PRIMARY KEY,
+  "consent_id" bigint NOT NULL,
+  "redirect_uri" varchar NOT NULL,
+  "code_challenge" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "oauth_clients"."hashed_secret" IS 'empty for public clients, which rely on PKCE only';
+
+COMMENT ON COLUMN "oauth_consents"."account_ids" IS 'accounts the client may access on behalf of the user';
+
+COMMENT ON COLUMN "oauth_authorization_codes"."code_challenge" IS 'PKCE S256 code challenge';
+
+ALTER TABLE "oauth_clients" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "oauth_consents" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "oauth_consents" ADD FOREIGN KEY ("client_id") REFERENCES "oauth_clients" ("client_id");
+
+ALTER TABLE "oauth_authorization_codes" ADD FOREIGN KEY ("consent_id") REFERENCES "oauth_consents" ("id");
+
+CREATE INDEX ON "oauth_consents" ("username");
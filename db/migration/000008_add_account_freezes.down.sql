@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "account_freezes";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_freeze_mode_check";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "freeze_mode";
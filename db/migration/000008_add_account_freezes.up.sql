@@ -0,0 +1,33 @@
+ALTER TABLE "accounts" ADD COLUMN "freeze_mode" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_freeze_mode_check" CHECK (
+  ("status" = 'frozen' AND "freeze_mode" IN ('debit', 'full')) OR
+  ("status" <> 'frozen' AND "freeze_mode" = '')
+);
+
+COMMENT ON COLUMN "accounts"."freeze_mode" IS 'debit or full while the account is frozen, empty otherwise';
+
+CREATE TABLE "account_freezes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "mode" varchar NOT NULL,
+  "reason_code" varchar NOT NULL,
+  "note" varchar NOT NULL,
+  "frozen_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "lifted_by" varchar,
+  "lifted_at" timestamptz
+);
+
+COMMENT ON COLUMN "account_freezes"."mode" IS 'debit blocks outgoing money, full blocks both directions';
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("frozen_by") REFERENCES "users" ("username");
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("lifted_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "account_freezes" ("account_id");
+
+-- at most one freeze is in force per account
+CREATE UNIQUE INDEX ON "account_freezes" ("account_id") WHERE "lifted_at" IS NULL;
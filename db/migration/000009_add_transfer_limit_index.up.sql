@@ -0,0 +1,2 @@
+-- speeds up the daily and monthly transfer totals checked against the limits
+CREATE INDEX "transfers_from_account_id_created_at_idx" ON "transfers" ("from_account_id", "created_at");
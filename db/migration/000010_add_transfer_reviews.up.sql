@@ -0,0 +1,36 @@
+CREATE TABLE "transfer_reviews" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "requested_by" varchar NOT NULL,
+  "rules" varchar[] NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "reviewed_by" varchar,
+  "reviewed_at" timestamptz,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "transfer_reviews"."rules" IS 'risk rules that held the transfer';
+
+COMMENT ON COLUMN "transfer_reviews"."status" IS 'pending, approved or rejected';
+
+COMMENT ON COLUMN "transfer_reviews"."transfer_id" IS 'transfer made when the review was approved';
+
+ALTER TABLE "transfer_reviews" ADD CONSTRAINT "transfer_reviews_status_check" CHECK ("status" IN ('pending', 'approved', 'rejected'));
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("reviewed_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfer_reviews" ("status");
+
+CREATE INDEX ON "transfers" ("from_account_id", "to_account_id", "created_at");
@@ -0,0 +1,13 @@
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "transfer_id";
+
+ALTER TABLE "transfer_reviews" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE "transfer_reviews" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE "transfer_reviews" DROP COLUMN IF EXISTS "memo";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "metadata";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "reference";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "memo";
@@ -0,0 +1,29 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN "transfers"."memo" IS 'what the payment was for, shown to both sides';
+
+COMMENT ON COLUMN "transfers"."reference" IS 'reference given by the sender, e.g. an invoice number';
+
+COMMENT ON COLUMN "transfers"."metadata" IS 'free-form JSON object supplied by the client';
+
+CREATE INDEX ON "transfers" ("reference");
+
+CREATE INDEX ON "transfers" USING GIN ("metadata");
+
+ALTER TABLE "transfer_reviews" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer_reviews" ADD COLUMN "reference" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfer_reviews" ADD COLUMN "metadata" jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that created the entry';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("transfer_id");
@@ -0,0 +1,24 @@
+ALTER TABLE "entries" ADD COLUMN "entry_type" varchar NOT NULL DEFAULT 'adjustment';
+
+COMMENT ON COLUMN "entries"."entry_type" IS 'transfer_debit, transfer_credit or adjustment';
+
+-- A transfer and its two entries are written in one transaction, so they
+-- share created_at. Use it to link entries written before transfer_id existed.
+UPDATE "entries" e
+SET "transfer_id" = t."id"
+FROM "transfers" t
+WHERE e."transfer_id" IS NULL
+AND e."created_at" = t."created_at"
+AND (
+  (e."account_id" = t."from_account_id" AND e."amount" = -t."amount") OR
+  (e."account_id" = t."to_account_id" AND e."amount" = t."amount")
+);
+
+UPDATE "entries" e
+SET "entry_type" = CASE WHEN e."account_id" = t."from_account_id" AND e."amount" < 0 THEN 'transfer_debit' ELSE 'transfer_credit' END
+FROM "transfers" t
+WHERE e."transfer_id" = t."id";
+
+ALTER TABLE "entries" ALTER COLUMN "entry_type" DROP DEFAULT;
+
+ALTER TABLE "entries" ADD CONSTRAINT "entries_entry_type_check" CHECK ("entry_type" IN ('transfer_debit', 'transfer_credit', 'adjustment'));
@@ -0,0 +1,35 @@
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "minor_units" int NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT false,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "currencies_minor_units_check" CHECK ("minor_units" BETWEEN 0 AND 4)
+);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 code';
+
+COMMENT ON COLUMN "currencies"."minor_units" IS 'number of decimal places, e.g. 2 for USD';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'accounts can only be opened and used in enabled currencies';
+
+INSERT INTO "currencies" ("code", "name", "minor_units", "enabled") VALUES
+  ('USD', 'US Dollar', 2, true),
+  ('EUR', 'Euro', 2, true),
+  ('CAD', 'Canadian Dollar', 2, true),
+  ('GBP', 'Pound Sterling', 2, false),
+  ('CHF', 'Swiss Franc', 2, false),
+  ('AUD', 'Australian Dollar', 2, false),
+  ('NZD', 'New Zealand Dollar', 2, false),
+  ('JPY', 'Yen', 0, false),
+  ('SEK', 'Swedish Krona', 2, false),
+  ('NOK', 'Norwegian Krone', 2, false),
+  ('DKK', 'Danish Krone', 2, false),
+  ('SGD', 'Singapore Dollar', 2, false),
+  ('HKD', 'Hong Kong Dollar', 2, false),
+  ('INR', 'Indian Rupee', 2, false),
+  ('MXN', 'Mexican Peso', 2, false),
+  ('KWD', 'Kuwaiti Dinar', 3, false),
+  ('BHD', 'Bahraini Dinar', 3, false);
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
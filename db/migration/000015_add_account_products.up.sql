@@ -0,0 +1,30 @@
+CREATE TABLE "account_products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "max_accounts" int NOT NULL DEFAULT 1,
+  "max_monthly_withdrawals" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "account_products_max_accounts_check" CHECK ("max_accounts" > 0),
+  CONSTRAINT "account_products_max_monthly_withdrawals_check" CHECK ("max_monthly_withdrawals" >= 0)
+);
+
+COMMENT ON COLUMN "account_products"."max_accounts" IS 'open accounts of the product a user can hold in each currency';
+
+COMMENT ON COLUMN "account_products"."max_monthly_withdrawals" IS 'transfers out of an account per calendar month, 0 for no limit';
+
+INSERT INTO "account_products" ("code", "name", "max_accounts", "max_monthly_withdrawals") VALUES
+  ('checking', 'Checking', 1, 0),
+  ('savings', 'Savings', 5, 6);
+
+ALTER TABLE "accounts" ADD COLUMN "product" varchar NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts" ADD COLUMN "nickname" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product") REFERENCES "account_products" ("code");
+
+COMMENT ON COLUMN "accounts"."nickname" IS 'name given by the owner, unique among their open accounts of a product and currency';
+
+-- several accounts of a product in one currency are told apart by their nickname
+DROP INDEX IF EXISTS "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_product_key" ON "accounts" ("owner", "currency", "product", "nickname") WHERE "status" <> 'closed';
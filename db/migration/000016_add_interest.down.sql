@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+DROP TABLE IF EXISTS "interest_postings";
+
+DROP TABLE IF EXISTS "interest_rates";
+
+DROP TABLE IF EXISTS "system_accounts";
+
+-- the _system user and its accounts are kept, since interest already posted
+-- left entries and transfers against them
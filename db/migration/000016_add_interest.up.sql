@@ -0,0 +1,100 @@
+-- The bank's own accounts belong to a user that can't sign in. Usernames
+-- must be alphanumeric, so no one can register this one.
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email", "deactivated_at") VALUES
+  ('_system', '', 'Simple Bank', 'ledger@simplebank.internal', now());
+
+CREATE TABLE "system_accounts" (
+  "purpose" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "account_id" bigint UNIQUE NOT NULL,
+  PRIMARY KEY ("purpose", "currency")
+);
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'what the bank uses the account for, e.g. interest_expense';
+
+ALTER TABLE "system_accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "system_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+WITH created AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency", "product", "nickname")
+  SELECT '_system', 0, "code", 'checking', 'interest_expense' FROM "currencies"
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'interest_expense', "currency", "id" FROM created;
+
+CREATE TABLE "interest_rates" (
+  "id" bigserial PRIMARY KEY,
+  "product" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "rate_bps" int NOT NULL,
+  "effective_from" date NOT NULL,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "interest_rates_rate_bps_check" CHECK ("rate_bps" >= 0)
+);
+
+COMMENT ON COLUMN "interest_rates"."rate_bps" IS 'annual rate in basis points, e.g. 150 for 1.50%';
+
+COMMENT ON COLUMN "interest_rates"."effective_from" IS 'the rate applies from this day until the next rate of the product and currency';
+
+ALTER TABLE "interest_rates" ADD FOREIGN KEY ("product") REFERENCES "account_products" ("code");
+
+ALTER TABLE "interest_rates" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "interest_rates" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+CREATE UNIQUE INDEX ON "interest_rates" ("product", "currency", "effective_from");
+
+INSERT INTO "interest_rates" ("product", "currency", "rate_bps", "effective_from", "created_by") VALUES
+  ('savings', 'USD', 150, '2020-01-01', '_system'),
+  ('savings', 'EUR', 100, '2020-01-01', '_system'),
+  ('savings', 'CAD', 125, '2020-01-01', '_system');
+
+CREATE TABLE "interest_postings" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "period_end" date NOT NULL,
+  "accrued_micros" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "interest_postings"."period_end" IS 'the posting covers accruals before this day';
+
+COMMENT ON COLUMN "interest_postings"."accrued_micros" IS 'accrued interest in millionths of a minor unit, including what the previous posting carried over';
+
+COMMENT ON COLUMN "interest_postings"."amount" IS 'whole minor units credited, the rest is carried over to the next posting';
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE UNIQUE INDEX ON "interest_postings" ("account_id", "period_end");
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "rate_bps" int NOT NULL,
+  "amount_micros" bigint NOT NULL,
+  "posting_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'balance at the end of the day';
+
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'interest for the day in millionths of a minor unit';
+
+COMMENT ON COLUMN "interest_accruals"."posting_id" IS 'posting that paid the interest, null while it is accrued but unposted';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("posting_id") REFERENCES "interest_postings" ("id");
+
+CREATE UNIQUE INDEX ON "interest_accruals" ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("account_id") WHERE "posting_id" IS NULL;
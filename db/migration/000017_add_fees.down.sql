@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS "fees";
+
+DROP TABLE IF EXISTS "fee_schedules";
+
+-- fees already charged moved money, so their entries stay as adjustments
+UPDATE "entries" SET "entry_type" = 'adjustment' WHERE "entry_type" = 'fee';
+
+ALTER TABLE "entries" DROP CONSTRAINT IF EXISTS "entries_entry_type_check";
+
+ALTER TABLE "entries" ADD CONSTRAINT "entries_entry_type_check" CHECK ("entry_type" IN ('transfer_debit', 'transfer_credit', 'adjustment'));
+
+COMMENT ON COLUMN "entries"."entry_type" IS 'transfer_debit, transfer_credit or adjustment';
+
+DELETE FROM "system_accounts" WHERE "purpose" = 'fee_revenue';
@@ -0,0 +1,90 @@
+CREATE TABLE "fee_schedules" (
+  "id" bigserial PRIMARY KEY,
+  "fee_type" varchar NOT NULL,
+  "product" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "method" varchar NOT NULL,
+  "flat_amount" bigint NOT NULL DEFAULT 0,
+  "rate_bps" int NOT NULL DEFAULT 0,
+  "min_amount" bigint NOT NULL DEFAULT 0,
+  "max_amount" bigint NOT NULL DEFAULT 0,
+  "tiers" jsonb NOT NULL DEFAULT '[]',
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "fee_schedules_fee_type_check" CHECK ("fee_type" IN ('transfer', 'maintenance')),
+  CONSTRAINT "fee_schedules_method_check" CHECK ("method" IN ('flat', 'percentage', 'tiered')),
+  CONSTRAINT "fee_schedules_amounts_check" CHECK ("flat_amount" >= 0 AND "rate_bps" >= 0 AND "min_amount" >= 0 AND "max_amount" >= 0)
+);
+
+COMMENT ON COLUMN "fee_schedules"."fee_type" IS 'transfer fees are charged on each outgoing transfer, maintenance fees once a month';
+
+COMMENT ON COLUMN "fee_schedules"."method" IS 'flat, percentage or tiered';
+
+COMMENT ON COLUMN "fee_schedules"."flat_amount" IS 'fee in minor units for the flat method, added to the percentage for the others';
+
+COMMENT ON COLUMN "fee_schedules"."rate_bps" IS 'percentage of the amount in basis points';
+
+COMMENT ON COLUMN "fee_schedules"."min_amount" IS 'smallest fee charged, 0 for none';
+
+COMMENT ON COLUMN "fee_schedules"."max_amount" IS 'largest fee charged, 0 for no cap';
+
+COMMENT ON COLUMN "fee_schedules"."tiers" IS 'for the tiered method, [{"up_to": 100000, "flat_amount": 50, "rate_bps": 0}, ...] in ascending order, the last tier without up_to';
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("product") REFERENCES "account_products" ("code");
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+CREATE UNIQUE INDEX ON "fee_schedules" ("fee_type", "product", "currency");
+
+ALTER TABLE "entries" DROP CONSTRAINT "entries_entry_type_check";
+
+ALTER TABLE "entries" ADD CONSTRAINT "entries_entry_type_check" CHECK ("entry_type" IN ('transfer_debit', 'transfer_credit', 'adjustment', 'fee'));
+
+COMMENT ON COLUMN "entries"."entry_type" IS 'transfer_debit, transfer_credit, adjustment or fee';
+
+CREATE TABLE "fees" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "fee_type" varchar NOT NULL,
+  "schedule_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "transfer_id" bigint,
+  "period_start" date,
+  "entry_id" bigint NOT NULL,
+  "revenue_entry_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "fees"."transfer_id" IS 'transfer the fee was charged on, for transfer fees';
+
+COMMENT ON COLUMN "fees"."period_start" IS 'first day of the month a maintenance fee is for';
+
+COMMENT ON COLUMN "fees"."entry_id" IS 'entry debiting the account';
+
+COMMENT ON COLUMN "fees"."revenue_entry_id" IS 'entry crediting the fee revenue account';
+
+ALTER TABLE "fees" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "fees" ADD FOREIGN KEY ("schedule_id") REFERENCES "fee_schedules" ("id");
+
+ALTER TABLE "fees" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "fees" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "fees" ADD FOREIGN KEY ("revenue_entry_id") REFERENCES "entries" ("id");
+
+CREATE INDEX ON "fees" ("transfer_id");
+
+CREATE UNIQUE INDEX ON "fees" ("entry_id");
+
+CREATE UNIQUE INDEX ON "fees" ("account_id", "period_start") WHERE "fee_type" = 'maintenance';
+
+WITH created AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency", "product", "nickname")
+  SELECT '_system', 0, "code", 'checking', 'fee_revenue' FROM "currencies"
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'fee_revenue', "currency", "id" FROM created;
@@ -0,0 +1,59 @@
+CREATE TABLE "transfer_batches" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "mode" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'processing',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz
+);
+
+COMMENT ON COLUMN "transfer_batches"."mode" IS 'atomic makes every transfer or none, best_effort makes each on its own';
+
+COMMENT ON COLUMN "transfer_batches"."status" IS 'processing, completed, partially_completed or failed';
+
+ALTER TABLE "transfer_batches" ADD CONSTRAINT "transfer_batches_mode_check" CHECK ("mode" IN ('atomic', 'best_effort'));
+
+ALTER TABLE "transfer_batches" ADD CONSTRAINT "transfer_batches_status_check" CHECK ("status" IN ('processing', 'completed', 'partially_completed', 'failed'));
+
+ALTER TABLE "transfer_batches" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+CREATE INDEX ON "transfer_batches" ("owner");
+
+CREATE TABLE "transfer_batch_items" (
+  "id" bigserial PRIMARY KEY,
+  "batch_id" bigint NOT NULL,
+  "position" int NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "reference" varchar NOT NULL DEFAULT '',
+  "metadata" jsonb NOT NULL DEFAULT '{}',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "review_id" bigint,
+  "error" varchar NOT NULL DEFAULT ''
+);
+
+COMMENT ON COLUMN "transfer_batch_items"."position" IS 'place of the transfer in the request, from 1';
+
+COMMENT ON COLUMN "transfer_batch_items"."status" IS 'pending, completed, failed or held';
+
+COMMENT ON COLUMN "transfer_batch_items"."review_id" IS 'review the risk rules held the transfer for';
+
+COMMENT ON COLUMN "transfer_batch_items"."error" IS 'why the transfer failed';
+
+ALTER TABLE "transfer_batch_items" ADD CONSTRAINT "transfer_batch_items_status_check" CHECK ("status" IN ('pending', 'completed', 'failed', 'held'));
+
+ALTER TABLE "transfer_batch_items" ADD FOREIGN KEY ("batch_id") REFERENCES "transfer_batches" ("id");
+
+ALTER TABLE "transfer_batch_items" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_batch_items" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_batch_items" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_batch_items" ADD FOREIGN KEY ("review_id") REFERENCES "transfer_reviews" ("id");
+
+CREATE UNIQUE INDEX ON "transfer_batch_items" ("batch_id", "position");
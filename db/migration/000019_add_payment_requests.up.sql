@@ -0,0 +1,36 @@
+CREATE TABLE "payment_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "requester_account_id" bigint NOT NULL,
+  "payer" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "resolved_at" timestamptz
+);
+
+COMMENT ON COLUMN "payment_requests"."requester_account_id" IS 'account the payment is made to';
+
+COMMENT ON COLUMN "payment_requests"."status" IS 'pending, paid or declined; a pending request past expires_at can no longer be paid';
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'transfer that paid the request';
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "payment_requests_status_check" CHECK ("status" IN ('pending', 'paid', 'declined'));
+
+ALTER TABLE "payment_requests" ADD CONSTRAINT "payment_requests_amount_check" CHECK ("amount" > 0);
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "payment_requests" ("requester");
+
+CREATE INDEX ON "payment_requests" ("payer");
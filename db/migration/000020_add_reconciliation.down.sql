@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "reconciliation_discrepancies";
+
+DROP TABLE IF EXISTS "reconciliation_totals";
+
+DROP TABLE IF EXISTS "reconciliation_runs";
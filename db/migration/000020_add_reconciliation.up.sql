@@ -0,0 +1,55 @@
+CREATE TABLE "reconciliation_runs" (
+  "id" bigserial PRIMARY KEY,
+  "accounts_checked" bigint NOT NULL DEFAULT 0,
+  "discrepancies" bigint NOT NULL DEFAULT 0,
+  "started_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz
+);
+
+CREATE TABLE "reconciliation_totals" (
+  "run_id" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "accounts" bigint NOT NULL,
+  "balance_total" bigint NOT NULL,
+  "entry_total" bigint NOT NULL,
+  "internal_total" bigint NOT NULL,
+  PRIMARY KEY ("run_id", "currency")
+);
+
+COMMENT ON COLUMN "reconciliation_totals"."balance_total" IS 'sum of the balances of the accounts in the currency';
+
+COMMENT ON COLUMN "reconciliation_totals"."entry_total" IS 'sum of the entries of those accounts, equal to balance_total when the ledger is sound';
+
+COMMENT ON COLUMN "reconciliation_totals"."internal_total" IS 'sum of the transfer and fee entries, which move money between accounts and so add up to 0';
+
+ALTER TABLE "reconciliation_totals" ADD FOREIGN KEY ("run_id") REFERENCES "reconciliation_runs" ("id");
+
+ALTER TABLE "reconciliation_totals" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+CREATE TABLE "reconciliation_discrepancies" (
+  "id" bigserial PRIMARY KEY,
+  "run_id" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "account_id" bigint,
+  "currency" varchar NOT NULL,
+  "expected" bigint NOT NULL,
+  "actual" bigint NOT NULL
+);
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."kind" IS 'account_drift when an account balance differs from the sum of its entries, ledger_imbalance when the internal entries of a currency don''t add up to 0';
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."account_id" IS 'drifted account, NULL for ledger imbalances';
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."expected" IS 'sum of the account entries, or 0 for ledger imbalances';
+
+COMMENT ON COLUMN "reconciliation_discrepancies"."actual" IS 'account balance, or the sum of the internal entries for ledger imbalances';
+
+ALTER TABLE "reconciliation_discrepancies" ADD CONSTRAINT "reconciliation_discrepancies_kind_check" CHECK ("kind" IN ('account_drift', 'ledger_imbalance'));
+
+ALTER TABLE "reconciliation_discrepancies" ADD FOREIGN KEY ("run_id") REFERENCES "reconciliation_runs" ("id");
+
+ALTER TABLE "reconciliation_discrepancies" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "reconciliation_discrepancies" ("run_id");
+
+CREATE INDEX ON "reconciliation_discrepancies" ("account_id");
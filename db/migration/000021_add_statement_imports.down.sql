@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "statement_lines";
+
+DROP TABLE IF EXISTS "statement_imports";
@@ -0,0 +1,54 @@
+CREATE TABLE "statement_imports" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "format" varchar NOT NULL,
+  "filename" varchar NOT NULL DEFAULT '',
+  "imported_by" varchar NOT NULL,
+  "lines" bigint NOT NULL DEFAULT 0,
+  "matched" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "statement_imports"."account_id" IS 'account whose ledger the statement is matched against, e.g. the settlement account of the partner bank';
+
+COMMENT ON COLUMN "statement_imports"."format" IS 'csv, ofx or camt053';
+
+COMMENT ON COLUMN "statement_imports"."imported_by" IS 'username of the banker, or _system when imported by the command';
+
+ALTER TABLE "statement_imports" ADD CONSTRAINT "statement_imports_format_check" CHECK ("format" IN ('csv', 'ofx', 'camt053'));
+
+ALTER TABLE "statement_imports" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "statement_imports" ("account_id");
+
+CREATE TABLE "statement_lines" (
+  "id" bigserial PRIMARY KEY,
+  "import_id" bigint NOT NULL,
+  "position" integer NOT NULL,
+  "booked_on" date NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "reference" varchar NOT NULL DEFAULT '',
+  "description" varchar NOT NULL DEFAULT '',
+  "entry_id" bigint,
+  "transfer_id" bigint
+);
+
+COMMENT ON COLUMN "statement_lines"."amount" IS 'positive for credits to the account, negative for debits';
+
+COMMENT ON COLUMN "statement_lines"."entry_id" IS 'entry the line was matched to, NULL while unmatched';
+
+COMMENT ON COLUMN "statement_lines"."transfer_id" IS 'transfer of the matched entry';
+
+ALTER TABLE "statement_lines" ADD FOREIGN KEY ("import_id") REFERENCES "statement_imports" ("id");
+
+ALTER TABLE "statement_lines" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "statement_lines" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "statement_lines" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE UNIQUE INDEX ON "statement_lines" ("import_id", "position");
+
+-- an entry is matched to one statement line at most
+CREATE UNIQUE INDEX ON "statement_lines" ("entry_id");
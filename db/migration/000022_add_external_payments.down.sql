@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS "outbound_payments";
+
+DROP TABLE IF EXISTS "payment_exports";
+
+DROP TABLE IF EXISTS "external_beneficiaries";
+
+DELETE FROM "system_accounts" WHERE "purpose" = 'external_settlement';
+
+-- settlement accounts that payments already went through are kept, since
+-- their entries and transfers stay
+DELETE FROM "accounts" a
+WHERE a."owner" = '_system'
+  AND a."nickname" = 'external_settlement'
+  AND NOT EXISTS (SELECT 1 FROM "entries" e WHERE e."account_id" = a."id")
+  AND NOT EXISTS (SELECT 1 FROM "transfers" t WHERE t."from_account_id" = a."id" OR t."to_account_id" = a."id");
@@ -0,0 +1,102 @@
+CREATE TABLE "external_beneficiaries" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "nickname" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "iban" varchar NOT NULL DEFAULT '',
+  "account_number" varchar NOT NULL DEFAULT '',
+  "bic" varchar NOT NULL DEFAULT '',
+  "aba_routing" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "external_beneficiaries"."name" IS 'holder of the account as the receiving bank knows them';
+
+COMMENT ON COLUMN "external_beneficiaries"."iban" IS 'IBAN in electronic format, empty for accounts given by account_number';
+
+COMMENT ON COLUMN "external_beneficiaries"."account_number" IS 'account number at the bank given by bic or aba_routing, for countries without IBANs';
+
+COMMENT ON COLUMN "external_beneficiaries"."aba_routing" IS 'US routing transit number of the receiving bank';
+
+ALTER TABLE "external_beneficiaries" ADD CONSTRAINT "external_beneficiaries_account_check" CHECK (("iban" <> '') <> ("account_number" <> '' AND ("bic" <> '' OR "aba_routing" <> '')));
+
+ALTER TABLE "external_beneficiaries" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+CREATE UNIQUE INDEX ON "external_beneficiaries" ("owner", "nickname");
+
+CREATE TABLE "payment_exports" (
+  "id" bigserial PRIMARY KEY,
+  "message_id" varchar UNIQUE NOT NULL,
+  "payments" int NOT NULL,
+  "document" bytea NOT NULL,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "payment_exports"."document" IS 'pain.001 credit transfer initiation sent to the correspondent bank';
+
+ALTER TABLE "payment_exports" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+CREATE TABLE "outbound_payments" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint UNIQUE NOT NULL,
+  "account_id" bigint NOT NULL,
+  "beneficiary_id" bigint,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "creditor_name" varchar NOT NULL,
+  "creditor_iban" varchar NOT NULL,
+  "creditor_account_number" varchar NOT NULL,
+  "creditor_bic" varchar NOT NULL,
+  "creditor_aba_routing" varchar NOT NULL,
+  "remittance_info" varchar NOT NULL,
+  "end_to_end_id" varchar UNIQUE NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'initiated',
+  "export_id" bigint,
+  "return_reason" varchar NOT NULL DEFAULT '',
+  "return_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz,
+  "settled_at" timestamptz,
+  "returned_at" timestamptz
+);
+
+COMMENT ON COLUMN "outbound_payments"."transfer_id" IS 'transfer from the customer to the external settlement account';
+
+COMMENT ON COLUMN "outbound_payments"."account_id" IS 'customer account the payment was debited from';
+
+COMMENT ON COLUMN "outbound_payments"."creditor_name" IS 'beneficiary details as they were when the payment was made';
+
+COMMENT ON COLUMN "outbound_payments"."end_to_end_id" IS 'identifies the payment in the pain.001 export and the pain.002 status reports';
+
+COMMENT ON COLUMN "outbound_payments"."status" IS 'initiated, sent once exported, then settled or returned';
+
+COMMENT ON COLUMN "outbound_payments"."return_transfer_id" IS 'transfer from the settlement account crediting a returned payment back';
+
+ALTER TABLE "outbound_payments" ADD CONSTRAINT "outbound_payments_status_check" CHECK ("status" IN ('initiated', 'sent', 'settled', 'returned'));
+
+ALTER TABLE "outbound_payments" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "outbound_payments" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "outbound_payments" ADD FOREIGN KEY ("beneficiary_id") REFERENCES "external_beneficiaries" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "outbound_payments" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "outbound_payments" ADD FOREIGN KEY ("export_id") REFERENCES "payment_exports" ("id");
+
+ALTER TABLE "outbound_payments" ADD FOREIGN KEY ("return_transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "outbound_payments" ("status");
+
+CREATE INDEX ON "outbound_payments" ("account_id");
+
+CREATE INDEX ON "outbound_payments" ("export_id");
+
+WITH created AS (
+  INSERT INTO "accounts" ("owner", "balance", "currency", "product", "nickname")
+  SELECT '_system', 0, "code", 'checking', 'external_settlement' FROM "currencies"
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'external_settlement', "currency", "id" FROM created;
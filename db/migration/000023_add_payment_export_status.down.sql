@@ -0,0 +1,9 @@
+ALTER TABLE IF EXISTS "payment_exports" DROP CONSTRAINT IF EXISTS "payment_exports_status_check";
+
+ALTER TABLE IF EXISTS "payment_exports" DROP COLUMN IF EXISTS "completed_at";
+
+ALTER TABLE IF EXISTS "payment_exports" DROP COLUMN IF EXISTS "returned";
+
+ALTER TABLE IF EXISTS "payment_exports" DROP COLUMN IF EXISTS "settled";
+
+ALTER TABLE IF EXISTS "payment_exports" DROP COLUMN IF EXISTS "status";
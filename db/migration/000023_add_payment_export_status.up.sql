@@ -0,0 +1,37 @@
+ALTER TABLE "payment_exports" ADD COLUMN "status" varchar NOT NULL DEFAULT 'sent';
+
+ALTER TABLE "payment_exports" ADD COLUMN "settled" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "payment_exports" ADD COLUMN "returned" int NOT NULL DEFAULT 0;
+
+ALTER TABLE "payment_exports" ADD COLUMN "completed_at" timestamptz;
+
+COMMENT ON COLUMN "payment_exports"."status" IS 'sent until every payment is settled or returned, then settled, returned or partially_settled';
+
+COMMENT ON COLUMN "payment_exports"."completed_at" IS 'when the last payment of the export got a final status';
+
+ALTER TABLE "payment_exports" ADD CONSTRAINT "payment_exports_status_check" CHECK ("status" IN ('sent', 'settled', 'returned', 'partially_settled'));
+
+CREATE INDEX ON "payment_exports" ("status");
+
+-- exports made before the status was tracked are brought up to date
+UPDATE "payment_exports" e
+SET "settled" = c."settled", "returned" = c."returned"
+FROM (
+  SELECT "export_id",
+    COUNT(*) FILTER (WHERE "status" = 'settled') AS "settled",
+    COUNT(*) FILTER (WHERE "status" = 'returned') AS "returned"
+  FROM "outbound_payments"
+  WHERE "export_id" IS NOT NULL
+  GROUP BY "export_id"
+) c
+WHERE c."export_id" = e."id";
+
+UPDATE "payment_exports"
+SET "status" = CASE
+    WHEN "returned" = 0 THEN 'settled'
+    WHEN "settled" = 0 THEN 'returned'
+    ELSE 'partially_settled'
+  END,
+  "completed_at" = now()
+WHERE "settled" + "returned" = "payments";
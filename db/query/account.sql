@@ -2,23 +2,38 @@
 INSERT INTO accounts (
     owner,
     balance,
-    currency
+    currency,
+    product,
+    nickname
     ) VALUES (
     $1,
     $2,
-    $3
+    $3,
+    $4,
+    $5
     ) RETURNING *;
 
+-- name: CountOpenAccounts :one
+SELECT count(*) FROM accounts
+WHERE owner = $1 AND currency = $2 AND product = $3 AND status <> 'closed';
+
+-- name: LockUserAccounts :exec
+SELECT pg_advisory_xact_lock(hashtext('accounts:' || sqlc.arg(owner)::varchar));
 
 -- name: GetAccount :one
 SELECT * FROM accounts WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountForUpdate :one
+SELECT * FROM accounts WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: ListAccounts :many
 SELECT * FROM accounts 
-WHERE owner = $1
+WHERE owner = sqlc.arg(owner) AND status <> 'closed'
+    AND (sqlc.narg(product)::varchar IS NULL OR product = sqlc.narg(product))
 ORDER BY id
-LIMIT $2
-OFFSET $3;
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
 
 -- name: AddAccountBalance :one
 UPDATE accounts 
@@ -26,12 +41,36 @@ SET balance = balance+ sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: ListOpenAccountsForUpdate :many
+SELECT * FROM accounts
+WHERE owner = $1 AND status <> 'closed'
+ORDER BY id
+FOR NO KEY UPDATE;
+
+-- name: CloseAccount :one
+UPDATE accounts
+SET status = 'closed', closed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountNickname :one
+UPDATE accounts
+SET nickname = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: SetAccountFreeze :one
+UPDATE accounts
+SET status = $2, freeze_mode = $3
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteAccount :exec
 DELETE FROM accounts WHERE id = $1;
 
 -- name: SearchAccounts :many
 SELECT * FROM accounts 
-WHERE owner ILIKE '%' || $1 || '%'
+WHERE owner ILIKE '%' || $1 || '%' AND status <> 'closed' AND owner = $4
 LIMIT $2
 OFFSET $3;
 
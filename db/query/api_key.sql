@@ -0,0 +1,40 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    owner,
+    name,
+    prefix,
+    hashed_key,
+    scopes,
+    expires_at
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+    ) RETURNING *;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT * FROM api_keys WHERE prefix = $1 LIMIT 1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+WHERE owner = $1
+ORDER BY id;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1 AND owner = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeAPIKeysByOwner :exec
+UPDATE api_keys
+SET revoked_at = now()
+WHERE owner = $1 AND revoked_at IS NULL;
+
+-- name: UpdateAPIKeyLastUsed :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1;
@@ -1,28 +1,55 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transfer_id,
+    entry_type
     ) VALUES (
     $1,
-    $2
+    $2,
+    $3,
+    $4
     ) RETURNING *;
 
 -- name: GetEntry :one
-SELECT * FROM entries WHERE id = $1 LIMIT 1;
+SELECT e.*, a.currency, t.memo, t.reference,
+c.id AS counterparty_account_id, c.owner AS counterparty_owner,
+f.fee_type, f.transfer_id AS fee_transfer_id
+FROM entries e
+INNER JOIN accounts a ON e.account_id = a.id
+LEFT JOIN transfers t ON e.transfer_id = t.id
+LEFT JOIN accounts c ON c.id = CASE WHEN e.entry_type = 'transfer_debit' THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN fees f ON f.entry_id = e.id
+WHERE e.id = $1 LIMIT 1;
 
 
 -- name: ListEntryFromAccountId :many
-SELECT * FROM entries
-WHERE account_id = $1
-ORDER BY created_at
+SELECT e.*, a.currency, t.memo, t.reference,
+c.id AS counterparty_account_id, c.owner AS counterparty_owner,
+f.fee_type, f.transfer_id AS fee_transfer_id
+FROM entries e
+INNER JOIN accounts a ON e.account_id = a.id
+LEFT JOIN transfers t ON e.transfer_id = t.id
+LEFT JOIN accounts c ON c.id = CASE WHEN e.entry_type = 'transfer_debit' THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN fees f ON f.entry_id = e.id
+WHERE e.account_id = $1
+ORDER BY e.created_at
 LIMIT $2
 OFFSET $3;
 
 -- name: SeachEntriesByAccountOwner :many
-SELECT e.*
+SELECT e.*, a.currency, t.memo, t.reference,
+c.id AS counterparty_account_id, c.owner AS counterparty_owner,
+f.fee_type, f.transfer_id AS fee_transfer_id
 FROM entries e
 INNER JOIN accounts a ON e.account_id = a.id
+LEFT JOIN transfers t ON e.transfer_id = t.id
+LEFT JOIN accounts c ON c.id = CASE WHEN e.entry_type = 'transfer_debit' THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN fees f ON f.entry_id = e.id
 WHERE a.owner ILIKE '%' || sqlc.arg(search_query) || '%'
+AND a.owner = sqlc.arg(owner)
+AND (sqlc.narg(account_ids)::bigint[] IS NULL OR e.account_id = ANY(sqlc.narg(account_ids)::bigint[]))
+AND (sqlc.narg(currency)::varchar IS NULL OR a.currency = sqlc.narg(currency)::varchar)
 AND e.created_at >= sqlc.arg(start_date) AND e.created_at <= sqlc.arg(end_date)
 AND e.amount >= sqlc.arg(min_amount) AND e.amount <= sqlc.arg(max_amount)
 ORDER BY
@@ -34,4 +61,3 @@ CASE WHEN  sqlc.arg(field) = 'id' AND  sqlc.arg(order_by) = 'ASC' THEN e.id END
 CASE WHEN  sqlc.arg(field) = 'id' AND  sqlc.arg(order_by) = 'DESC' THEN e.id END DESC
 LIMIT $1
 OFFSET $2;
-
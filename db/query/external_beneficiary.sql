@@ -0,0 +1,35 @@
+-- name: CreateExternalBeneficiary :one
+INSERT INTO external_beneficiaries (
+    owner,
+    nickname,
+    name,
+    iban,
+    account_number,
+    bic,
+    aba_routing
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+    ) RETURNING *;
+
+-- name: DeleteExternalBeneficiary :one
+DELETE FROM external_beneficiaries
+WHERE id = $1 AND owner = $2
+RETURNING *;
+
+-- name: GetExternalBeneficiary :one
+SELECT * FROM external_beneficiaries
+WHERE id = $1 AND owner = $2
+LIMIT 1;
+
+-- name: ListExternalBeneficiaries :many
+SELECT * FROM external_beneficiaries
+WHERE owner = $1
+ORDER BY nickname
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,79 @@
+-- name: UpsertFeeSchedule :one
+INSERT INTO fee_schedules (
+    fee_type,
+    product,
+    currency,
+    method,
+    flat_amount,
+    rate_bps,
+    min_amount,
+    max_amount,
+    tiers,
+    updated_by
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+    ) ON CONFLICT (fee_type, product, currency) DO UPDATE
+SET method = EXCLUDED.method,
+    flat_amount = EXCLUDED.flat_amount,
+    rate_bps = EXCLUDED.rate_bps,
+    min_amount = EXCLUDED.min_amount,
+    max_amount = EXCLUDED.max_amount,
+    tiers = EXCLUDED.tiers,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE fee_type = $1 AND product = $2 AND currency = $3
+LIMIT 1;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY fee_type, product, currency;
+
+-- name: CreateFee :one
+INSERT INTO fees (
+    account_id,
+    fee_type,
+    schedule_id,
+    amount,
+    transfer_id,
+    period_start,
+    entry_id,
+    revenue_entry_id
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+    ) RETURNING *;
+
+-- name: ListFeesByTransfer :many
+SELECT * FROM fees
+WHERE transfer_id = $1
+ORDER BY id;
+
+-- name: ListMaintenanceFeeAccounts :many
+SELECT a.id FROM accounts a
+INNER JOIN fee_schedules s
+    ON s.fee_type = 'maintenance' AND s.product = a.product AND s.currency = a.currency
+WHERE a.status = 'active' AND a.owner <> '_system' AND a.created_at < sqlc.arg(period_end)
+    AND NOT EXISTS (
+        SELECT 1 FROM fees f
+        WHERE f.account_id = a.id AND f.fee_type = 'maintenance' AND f.period_start = sqlc.arg(period_start)
+    )
+ORDER BY a.id;
@@ -0,0 +1,25 @@
+-- name: CreateAccountFreeze :one
+INSERT INTO account_freezes (
+    account_id,
+    mode,
+    reason_code,
+    note,
+    frozen_by
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+    ) RETURNING *;
+
+-- name: LiftAccountFreeze :one
+UPDATE account_freezes
+SET lifted_by = sqlc.arg(lifted_by)::varchar, lifted_at = now()
+WHERE account_id = sqlc.arg(account_id) AND lifted_at IS NULL
+RETURNING *;
+
+-- name: ListAccountFreezes :many
+SELECT * FROM account_freezes
+WHERE account_id = $1
+ORDER BY id DESC;
@@ -0,0 +1,92 @@
+-- name: CreateInterestRate :one
+INSERT INTO interest_rates (
+    product,
+    currency,
+    rate_bps,
+    effective_from,
+    created_by
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+    ) RETURNING *;
+
+-- name: ListInterestRates :many
+SELECT * FROM interest_rates
+WHERE product = $1
+ORDER BY currency, effective_from;
+
+-- name: GetInterestRate :one
+SELECT * FROM interest_rates
+WHERE product = $1 AND currency = $2 AND effective_from <= $3
+ORDER BY effective_from DESC
+LIMIT 1;
+
+-- name: ListAccrualCandidates :many
+SELECT a.id, a.currency, r.rate_bps,
+(a.balance - COALESCE((
+    SELECT SUM(e.amount) FROM entries e
+    WHERE e.account_id = a.id AND e.created_at >= sqlc.arg(day_end)
+), 0))::bigint AS balance
+FROM accounts a
+INNER JOIN LATERAL (
+    SELECT rate_bps FROM interest_rates
+    WHERE product = a.product AND currency = a.currency AND effective_from <= sqlc.arg(accrual_date)
+    ORDER BY effective_from DESC
+    LIMIT 1
+) r ON true
+WHERE a.status <> 'closed' AND a.created_at < sqlc.arg(day_end) AND r.rate_bps > 0
+ORDER BY a.id;
+
+-- name: CreateInterestAccrual :execrows
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    rate_bps,
+    amount_micros
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+    ) ON CONFLICT (account_id, accrual_date) DO NOTHING;
+
+-- name: GetUnpostedInterest :one
+SELECT COALESCE(SUM(amount_micros), 0)::bigint AS accrued_micros, COUNT(*) AS days
+FROM interest_accruals
+WHERE account_id = $1 AND posting_id IS NULL AND accrual_date < $2;
+
+-- name: ListAccountsWithUnpostedInterest :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE posting_id IS NULL AND accrual_date < $1
+ORDER BY account_id;
+
+-- name: MarkInterestAccrualsPosted :exec
+UPDATE interest_accruals
+SET posting_id = $1
+WHERE account_id = $2 AND posting_id IS NULL AND accrual_date < $3;
+
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+    account_id,
+    period_end,
+    accrued_micros,
+    amount,
+    transfer_id
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+    ) RETURNING *;
+
+-- name: GetLastInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = $1
+ORDER BY period_end DESC
+LIMIT 1;
@@ -0,0 +1,17 @@
+-- name: GetAccountTransferTotals :one
+SELECT
+    COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(day_start)), 0)::bigint AS daily,
+    COALESCE(SUM(amount), 0)::bigint AS monthly
+FROM transfers
+WHERE from_account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(month_start);
+
+-- name: GetUserTransferTotals :one
+SELECT
+    COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= sqlc.arg(day_start)), 0)::bigint AS daily,
+    COALESCE(SUM(t.amount), 0)::bigint AS monthly
+FROM transfers t
+INNER JOIN accounts a ON t.from_account_id = a.id
+WHERE a.owner = sqlc.arg(owner) AND a.currency = sqlc.arg(currency) AND t.created_at >= sqlc.arg(month_start);
+
+-- name: LockUserTransfers :exec
+SELECT pg_advisory_xact_lock(hashtext('transfers:' || sqlc.arg(owner)::varchar));
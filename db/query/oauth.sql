@@ -0,0 +1,72 @@
+-- name: CreateOAuthClient :one
+INSERT INTO oauth_clients (
+    client_id,
+    hashed_secret,
+    name,
+    owner,
+    redirect_uris,
+    scopes
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+    ) RETURNING *;
+
+-- name: GetOAuthClient :one
+SELECT * FROM oauth_clients WHERE client_id = $1 LIMIT 1;
+
+-- name: CreateOAuthConsent :one
+INSERT INTO oauth_consents (
+    username,
+    client_id,
+    scopes,
+    account_ids
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+    ) RETURNING *;
+
+-- name: GetOAuthConsent :one
+SELECT * FROM oauth_consents WHERE id = $1 LIMIT 1;
+
+-- name: ListOAuthConsents :many
+SELECT * FROM oauth_consents
+WHERE username = $1 AND revoked_at IS NULL
+ORDER BY id;
+
+-- name: RevokeOAuthConsent :one
+UPDATE oauth_consents
+SET revoked_at = now()
+WHERE id = $1 AND username = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeOAuthConsentsByUser :exec
+UPDATE oauth_consents
+SET revoked_at = now()
+WHERE username = $1 AND revoked_at IS NULL;
+
+-- name: CreateOAuthAuthorizationCode :one
+INSERT INTO oauth_authorization_codes (
+    hashed_code,
+    consent_id,
+    redirect_uri,
+    code_challenge,
+    expires_at
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+    ) RETURNING *;
+
+-- name: ConsumeOAuthAuthorizationCode :one
+UPDATE oauth_authorization_codes
+SET used_at = now()
+WHERE hashed_code = $1 AND used_at IS NULL AND expires_at > now()
+RETURNING *;
@@ -0,0 +1,128 @@
+-- name: CountExportPaymentsByStatus :one
+SELECT COUNT(*) FILTER (WHERE status = 'settled') AS settled,
+    COUNT(*) FILTER (WHERE status = 'returned') AS returned
+FROM outbound_payments
+WHERE export_id = $1;
+
+-- name: CreateOutboundPayment :one
+INSERT INTO outbound_payments (
+    transfer_id,
+    account_id,
+    beneficiary_id,
+    amount,
+    currency,
+    creditor_name,
+    creditor_iban,
+    creditor_account_number,
+    creditor_bic,
+    creditor_aba_routing,
+    remittance_info,
+    end_to_end_id
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12
+    ) RETURNING *;
+
+-- name: CreatePaymentExport :one
+INSERT INTO payment_exports (
+    message_id,
+    payments,
+    document,
+    created_by
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+    ) RETURNING *;
+
+-- name: GetOutboundPayment :one
+SELECT * FROM outbound_payments
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetOutboundPaymentByEndToEndIDForUpdate :one
+SELECT * FROM outbound_payments
+WHERE end_to_end_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetOutboundPaymentForUpdate :one
+SELECT * FROM outbound_payments
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetPaymentExport :one
+SELECT * FROM payment_exports
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetPaymentExportForUpdate :one
+SELECT * FROM payment_exports
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListExportedEndToEndIDs :many
+SELECT p.end_to_end_id FROM outbound_payments p
+JOIN payment_exports e ON e.id = p.export_id
+WHERE e.message_id = $1
+ORDER BY p.id;
+
+-- name: ListInitiatedOutboundPaymentsForUpdate :many
+SELECT * FROM outbound_payments
+WHERE status = 'initiated'
+ORDER BY id
+LIMIT $1
+FOR NO KEY UPDATE;
+
+-- name: ListOutboundPayments :many
+SELECT * FROM outbound_payments
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListPaymentExports :many
+SELECT id, message_id, payments, created_by, created_at, status, settled, returned, completed_at FROM payment_exports
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: MarkOutboundPaymentsSent :exec
+UPDATE outbound_payments
+SET status = 'sent', export_id = sqlc.arg(export_id), sent_at = now()
+WHERE id = ANY(sqlc.arg(ids)::bigint[]) AND status = 'initiated';
+
+-- name: ReturnOutboundPayment :one
+UPDATE outbound_payments
+SET status = 'returned', return_reason = $2, return_transfer_id = $3, returned_at = now()
+WHERE id = $1 AND status <> 'returned'
+RETURNING *;
+
+-- name: SettleOutboundPayment :one
+UPDATE outbound_payments
+SET status = 'settled', settled_at = now()
+WHERE id = $1 AND status = 'sent'
+RETURNING *;
+
+-- name: UpdatePaymentExportStatus :one
+UPDATE payment_exports
+SET status = sqlc.arg(status),
+    settled = sqlc.arg(settled),
+    returned = sqlc.arg(returned),
+    completed_at = sqlc.narg(completed_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
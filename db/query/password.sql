@@ -0,0 +1,41 @@
+-- name: CreatePasswordHistory :one
+INSERT INTO password_history (
+    username,
+    hashed_password
+    ) VALUES (
+    $1,
+    $2
+    ) RETURNING *;
+
+-- name: ListPasswordHistory :many
+SELECT * FROM password_history
+WHERE username = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+    hashed_token,
+    username,
+    expires_at
+    ) VALUES (
+    $1,
+    $2,
+    $3
+    ) RETURNING *;
+
+-- name: GetPasswordResetToken :one
+SELECT * FROM password_reset_tokens
+WHERE hashed_token = $1 AND used_at IS NULL AND expires_at > now()
+LIMIT 1;
+
+-- name: ConsumePasswordResetToken :one
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE hashed_token = $1 AND used_at IS NULL AND expires_at > now()
+RETURNING *;
+
+-- name: InvalidatePasswordResetTokens :exec
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE username = $1 AND used_at IS NULL;
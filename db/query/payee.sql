@@ -0,0 +1,35 @@
+-- name: CreatePayee :one
+INSERT INTO payees (
+    owner,
+    nickname,
+    account_id,
+    verified_at
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+    ) RETURNING *;
+
+-- name: DeletePayee :one
+DELETE FROM payees
+WHERE id = $1 AND owner = $2
+RETURNING *;
+
+-- name: GetPayee :one
+SELECT * FROM payees
+WHERE id = $1 AND owner = $2
+LIMIT 1;
+
+-- name: ListPayees :many
+SELECT * FROM payees
+WHERE owner = $1
+ORDER BY nickname
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdatePayee :one
+UPDATE payees
+SET nickname = $3
+WHERE id = $1 AND owner = $2
+RETURNING *;
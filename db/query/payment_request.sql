@@ -0,0 +1,56 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+    requester,
+    requester_account_id,
+    payer,
+    amount,
+    currency,
+    memo,
+    expires_at
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+    ) RETURNING *;
+
+-- name: DeclinePaymentRequest :one
+UPDATE payment_requests
+SET status = 'declined',
+    resolved_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListIncomingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE payer = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListOutgoingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE requester = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: PayPaymentRequest :one
+UPDATE payment_requests
+SET status = 'paid',
+    transfer_id = $2,
+    resolved_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
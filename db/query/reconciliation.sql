@@ -0,0 +1,92 @@
+-- name: CreateReconciliationDiscrepancy :one
+INSERT INTO reconciliation_discrepancies (
+    run_id,
+    kind,
+    account_id,
+    currency,
+    expected,
+    actual
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+    ) RETURNING *;
+
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs DEFAULT VALUES
+RETURNING *;
+
+-- name: CreateReconciliationTotal :one
+INSERT INTO reconciliation_totals (
+    run_id,
+    currency,
+    accounts,
+    balance_total,
+    entry_total,
+    internal_total
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+    ) RETURNING *;
+
+-- name: FinishReconciliationRun :one
+UPDATE reconciliation_runs
+SET accounts_checked = $2,
+    discrepancies = $3,
+    finished_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetReconciliationRun :one
+SELECT * FROM reconciliation_runs
+WHERE id = $1 LIMIT 1;
+
+-- name: ListAccountDrift :many
+SELECT a.id AS account_id, a.currency, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entry_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListLedgerTotals :many
+SELECT a.currency,
+    COUNT(*) AS accounts,
+    COALESCE(SUM(a.balance), 0)::bigint AS balance_total,
+    COALESCE(SUM(t.entry_total), 0)::bigint AS entry_total,
+    COALESCE(SUM(t.internal_total), 0)::bigint AS internal_total
+FROM accounts a
+LEFT JOIN (
+    SELECT account_id,
+        SUM(amount) AS entry_total,
+        SUM(amount) FILTER (WHERE entry_type <> 'adjustment') AS internal_total
+    FROM entries
+    GROUP BY account_id
+) t ON t.account_id = a.id
+GROUP BY a.currency
+ORDER BY a.currency;
+
+-- name: ListReconciliationDiscrepancies :many
+SELECT * FROM reconciliation_discrepancies
+WHERE run_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ListReconciliationRuns :many
+SELECT * FROM reconciliation_runs
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: ListReconciliationTotals :many
+SELECT * FROM reconciliation_totals
+WHERE run_id = $1
+ORDER BY currency;
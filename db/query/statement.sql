@@ -0,0 +1,68 @@
+-- name: CreateStatementImport :one
+INSERT INTO statement_imports (
+    account_id,
+    format,
+    filename,
+    imported_by
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+    ) RETURNING *;
+
+-- name: CreateStatementLine :one
+INSERT INTO statement_lines (
+    import_id,
+    position,
+    booked_on,
+    amount,
+    currency,
+    reference,
+    description,
+    entry_id,
+    transfer_id
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+    ) RETURNING *;
+
+-- name: FindStatementMatch :one
+SELECT e.* FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = sqlc.arg(account_id)
+    AND e.amount = sqlc.arg(amount)
+    AND e.created_at >= sqlc.arg(created_from)
+    AND e.created_at < sqlc.arg(created_to)
+    AND NOT EXISTS (SELECT 1 FROM statement_lines l WHERE l.entry_id = e.id)
+ORDER BY (sqlc.arg(reference)::varchar <> '' AND t.reference = sqlc.arg(reference)) DESC, e.created_at, e.id
+LIMIT 1;
+
+-- name: FinishStatementImport :one
+UPDATE statement_imports
+SET lines = $2,
+    matched = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: GetStatementImport :one
+SELECT * FROM statement_imports
+WHERE id = $1 LIMIT 1;
+
+-- name: ListStatementImports :many
+SELECT * FROM statement_imports
+ORDER BY id DESC
+LIMIT $1
+OFFSET $2;
+
+-- name: ListUnmatchedStatementLines :many
+SELECT * FROM statement_lines
+WHERE import_id = $1 AND entry_id IS NULL
+ORDER BY position;
@@ -2,9 +2,12 @@
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    memo,
+    reference,
+    metadata
     ) VALUES (  
-    $1,$2,$3
+    $1,$2,$3,$4,$5,$6
     ) RETURNING *;
 
 -- name: GetTransfer :one    
@@ -28,13 +31,27 @@ OFFSET $3;
 -- name: SeachTransfersByAccountOwner :many
 SELECT t.* , 
 json_build_object('owner', a1.owner, 'balance', a1.balance) AS from_account,
-json_build_object('owner', a2.owner, 'balance', a2.balance) AS to_account
+json_build_object('owner', a2.owner, 'balance', a2.balance) AS to_account,
+a1.currency
 FROM transfers t
 INNER JOIN accounts a1 ON t.from_account_id = a1.id
 INNER JOIN accounts a2 ON t.to_account_id = a2.id
-WHERE a1.owner ILIKE '%' || sqlc.arg(search_query) || '%'
+WHERE (a1.owner ILIKE '%' || sqlc.arg(search_query) || '%'
 OR a2.owner ILIKE '%' || sqlc.arg(search_query) || '%'
+OR t.memo ILIKE '%' || sqlc.arg(search_query) || '%'
+OR t.reference ILIKE '%' || sqlc.arg(search_query) || '%')
+AND t.metadata @> sqlc.arg(metadata)::jsonb
+AND (
+    (a1.owner = sqlc.arg(owner) AND (sqlc.narg(account_ids)::bigint[] IS NULL OR t.from_account_id = ANY(sqlc.narg(account_ids)::bigint[])))
+    OR (a2.owner = sqlc.arg(owner) AND (sqlc.narg(account_ids)::bigint[] IS NULL OR t.to_account_id = ANY(sqlc.narg(account_ids)::bigint[])))
+)
 LIMIT $1
 OFFSET $2;
 
+-- name: CountTransfersBetween :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = $1 AND to_account_id = $2;
 
+-- name: CountTransfersSince :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = $1 AND created_at >= $2;
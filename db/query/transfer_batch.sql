@@ -0,0 +1,78 @@
+-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches (
+    owner,
+    mode
+    ) VALUES (
+    $1,
+    $2
+    ) RETURNING *;
+
+-- name: GetTransferBatch :one
+SELECT * FROM transfer_batches
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferBatchForUpdate :one
+SELECT * FROM transfer_batches
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: FinishTransferBatch :one
+UPDATE transfer_batches
+SET status = $2,
+    completed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateTransferBatchItem :one
+INSERT INTO transfer_batch_items (
+    batch_id,
+    position,
+    from_account_id,
+    to_account_id,
+    amount,
+    currency,
+    memo,
+    reference,
+    metadata
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+    ) RETURNING *;
+
+-- name: GetTransferBatchItemForUpdate :one
+SELECT * FROM transfer_batch_items
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListTransferBatchItems :many
+SELECT * FROM transfer_batch_items
+WHERE batch_id = $1
+ORDER BY position;
+
+-- name: CompleteTransferBatchItem :one
+UPDATE transfer_batch_items
+SET status = 'completed',
+    transfer_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: FailTransferBatchItem :one
+UPDATE transfer_batch_items
+SET status = 'failed',
+    error = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: HoldTransferBatchItem :one
+UPDATE transfer_batch_items
+SET status = 'held',
+    review_id = $2
+WHERE id = $1
+RETURNING *;
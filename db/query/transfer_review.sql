@@ -0,0 +1,48 @@
+-- name: ApproveTransferReview :one
+UPDATE transfer_reviews
+SET status = 'approved', reviewed_by = sqlc.arg(reviewed_by)::varchar, reviewed_at = now(), transfer_id = sqlc.arg(transfer_id)::bigint
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateTransferReview :one
+INSERT INTO transfer_reviews (
+    from_account_id,
+    to_account_id,
+    amount,
+    currency,
+    requested_by,
+    rules,
+    memo,
+    reference,
+    metadata
+    ) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+    ) RETURNING *;
+
+-- name: GetTransferReview :one
+SELECT * FROM transfer_reviews WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferReviewForUpdate :one
+SELECT * FROM transfer_reviews WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListTransferReviews :many
+SELECT * FROM transfer_reviews
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: RejectTransferReview :one
+UPDATE transfer_reviews
+SET status = 'rejected', reviewed_by = sqlc.arg(reviewed_by)::varchar, reviewed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
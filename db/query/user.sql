@@ -15,6 +15,9 @@ INSERT INTO users (
 -- name: GetUser :one
 SELECT * FROM users WHERE username = $1 LIMIT 1;
 
+-- name: GetUserByEmail :one
+SELECT * FROM users WHERE email = $1 LIMIT 1;
+
 -- name: GetUsers :many
 SELECT * FROM users
 WHERE username = ANY(sqlc.arg(usernames)::text[])
@@ -24,9 +27,14 @@ OFFSET $2;
 
 -- name: UpdatePassword :exec
 UPDATE users
-SET hashed_password = $2
+SET hashed_password = $2, password_changed_at = now()
 WHERE username = $1;
 
+-- name: UpdatePasswordHash :exec
+UPDATE users
+SET hashed_password = sqlc.arg(hashed_password)
+WHERE username = sqlc.arg(username) AND hashed_password = sqlc.arg(old_hashed_password);
+
 -- name: SearchUsers :many
 SELECT * FROM users
 WHERE username ILIKE '%' || $1 || '%'
@@ -34,6 +42,12 @@ ORDER BY username
 LIMIT $2
 OFFSET $3;
 
+-- name: DeactivateUser :one
+UPDATE users
+SET deactivated_at = now()
+WHERE username = $1 AND deactivated_at IS NULL
+RETURNING *;
+
 -- name: DeleteUser :exec
 DELETE FROM users WHERE username = $1;
 
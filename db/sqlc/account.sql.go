@@ -14,7 +14,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts 
 SET balance = balance+ $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname
 `
 
 type AddAccountBalanceParams struct {
@@ -31,30 +31,90 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.FreezeMode,
+		&i.Product,
+		&i.Nickname,
 	)
 	return i, err
 }
 
+const closeAccount = `-- name: CloseAccount :one
+UPDATE accounts
+SET status = 'closed', closed_at = now()
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname
+`
+
+func (q *Queries) CloseAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, closeAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.FreezeMode,
+		&i.Product,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const countOpenAccounts = `-- name: CountOpenAccounts :one
+SELECT count(*) FROM accounts
+WHERE owner = $1 AND currency = $2 AND product = $3 AND status <> 'closed'
+`
+
+type CountOpenAccountsParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+	Product  string `json:"product"`
+}
+
+func (q *Queries) CountOpenAccounts(ctx context.Context, arg CountOpenAccountsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOpenAccounts, arg.Owner, arg.Currency, arg.Product)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
     owner,
     balance,
-    currency
+    currency,
+    product,
+    nickname
     ) VALUES (
     $1,
     $2,
-    $3
-    ) RETURNING id, owner, balance, currency, created_at
+    $3,
+    $4,
+    $5
+    ) RETURNING id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Product  string `json:"product"`
+	Nickname string `json:"nickname"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Product,
+		arg.Nickname,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -62,6 +122,11 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.FreezeMode,
+		&i.Product,
+		&i.Nickname,
 	)
 	return i, err
 }
@@ -76,7 +141,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts WHERE id = $1 LIMIT 1
+SELECT id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname FROM accounts WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
@@ -88,26 +153,102 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.FreezeMode,
+		&i.Product,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const getAccountForUpdate = `-- name: GetAccountForUpdate :one
+SELECT id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname FROM accounts WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountForUpdate, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.FreezeMode,
+		&i.Product,
+		&i.Nickname,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts 
-WHERE owner = $1
+SELECT id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname FROM accounts 
+WHERE owner = $1 AND status <> 'closed'
+    AND ($2::varchar IS NULL OR product = $2)
 ORDER BY id
-LIMIT $2
-OFFSET $3
+LIMIT $3
+OFFSET $4
 `
 
 type ListAccountsParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Owner   string         `json:"owner"`
+	Product sql.NullString `json:"product"`
+	Limit   int32          `json:"limit"`
+	Offset  int32          `json:"offset"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listAccounts,
+		arg.Owner,
+		arg.Product,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.Status,
+			&i.ClosedAt,
+			&i.FreezeMode,
+			&i.Product,
+			&i.Nickname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenAccountsForUpdate = `-- name: ListOpenAccountsForUpdate :many
+SELECT id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname FROM accounts
+WHERE owner = $1 AND status <> 'closed'
+ORDER BY id
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) ListOpenAccountsForUpdate(ctx context.Context, owner string) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenAccountsForUpdate, owner)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +262,11 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Status,
+			&i.ClosedAt,
+			&i.FreezeMode,
+			&i.Product,
+			&i.Nickname,
 		); err != nil {
 			return nil, err
 		}
@@ -135,9 +281,18 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
+const lockUserAccounts = `-- name: LockUserAccounts :exec
+SELECT pg_advisory_xact_lock(hashtext('accounts:' || $1::varchar))
+`
+
+func (q *Queries) LockUserAccounts(ctx context.Context, owner string) error {
+	_, err := q.db.ExecContext(ctx, lockUserAccounts, owner)
+	return err
+}
+
 const searchAccounts = `-- name: SearchAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts 
-WHERE owner ILIKE '%' || $1 || '%'
+SELECT id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname FROM accounts 
+WHERE owner ILIKE '%' || $1 || '%' AND status <> 'closed' AND owner = $4
 LIMIT $2
 OFFSET $3
 `
@@ -146,10 +301,16 @@ type SearchAccountsParams struct {
 	Column1 sql.NullString `json:"column_1"`
 	Limit   int32          `json:"limit"`
 	Offset  int32          `json:"offset"`
+	Owner   string         `json:"owner"`
 }
 
 func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, searchAccounts, arg.Column1, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, searchAccounts,
+		arg.Column1,
+		arg.Limit,
+		arg.Offset,
+		arg.Owner,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +324,11 @@ func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams)
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Status,
+			&i.ClosedAt,
+			&i.FreezeMode,
+			&i.Product,
+			&i.Nickname,
 		); err != nil {
 			return nil, err
 		}
@@ -176,3 +342,64 @@ func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams)
 	}
 	return items, nil
 }
+
+const setAccountFreeze = `-- name: SetAccountFreeze :one
+UPDATE accounts
+SET status = $2, freeze_mode = $3
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname
+`
+
+type SetAccountFreezeParams struct {
+	ID         int64  `json:"id"`
+	Status     string `json:"status"`
+	FreezeMode string `json:"freeze_mode"`
+}
+
+func (q *Queries) SetAccountFreeze(ctx context.Context, arg SetAccountFreezeParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, setAccountFreeze, arg.ID, arg.Status, arg.FreezeMode)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.FreezeMode,
+		&i.Product,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountNickname = `-- name: UpdateAccountNickname :one
+UPDATE accounts
+SET nickname = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, status, closed_at, freeze_mode, product, nickname
+`
+
+type UpdateAccountNicknameParams struct {
+	ID       int64  `json:"id"`
+	Nickname string `json:"nickname"`
+}
+
+func (q *Queries) UpdateAccountNickname(ctx context.Context, arg UpdateAccountNicknameParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountNickname, arg.ID, arg.Nickname)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ClosedAt,
+		&i.FreezeMode,
+		&i.Product,
+		&i.Nickname,
+	)
+	return i, err
+}
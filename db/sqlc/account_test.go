@@ -18,6 +18,7 @@ func createRandomAccount(t *testing.T) Account {
 		Owner:    user.Username,
 		Balance:  util.RandomMoney(),
 		Currency: util.RandomCurrency(),
+		Product:  ProductChecking,
 	}
 	account, err := testQueries.CreateAccount(context.Background(), arg)
 	require.NoError(t, err)
@@ -25,6 +26,7 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.Product, account.Product)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -111,14 +113,144 @@ func TestSearchAccounts(t *testing.T) {
 		Column1: sql.NullString{String: account.Owner, Valid: true},
 		Limit:   5,
 		Offset:  0,
+		Owner:   account.Owner,
 	}
 
 	accounts, err := testQueries.SearchAccounts(context.Background(), arg)
 	require.NoError(t, err)
 	require.Len(t, accounts, 1)
 
+	// other users can't find the accounts
+	arg.Owner = createRandomUser(t).Username
+	accounts, err = testQueries.SearchAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+
 	for _, account := range accounts {
 		require.NotEmpty(t, account)
 		require.Equal(t, arg.Column1.String, account.Owner)
 	}
 }
+
+func TestCloseAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	account := createRandomAccount(t)
+	otherAccount, err := testQueries.CreateAccount(ctx, CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: otherCurrency(account.Currency),
+		Product:  ProductChecking,
+	})
+	require.NoError(t, err)
+
+	_, err = store.CloseAccountTx(ctx, CloseAccountTxParams{AccountID: account.ID})
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	_, err = store.CloseAccountTx(ctx, CloseAccountTxParams{AccountID: account.ID, SweepAccountID: otherAccount.ID})
+	require.ErrorIs(t, err, ErrInvalidSweepAccount)
+
+	sweepAccount := createRandomAccount(t)
+	_, err = testDB.ExecContext(ctx, "UPDATE accounts SET currency = $1 WHERE id = $2", account.Currency, sweepAccount.ID)
+	require.NoError(t, err)
+
+	result, err := store.CloseAccountTx(ctx, CloseAccountTxParams{AccountID: account.ID, SweepAccountID: sweepAccount.ID})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, result.Account.Status)
+	require.True(t, result.Account.ClosedAt.Valid)
+	require.Zero(t, result.Account.Balance)
+	require.NotNil(t, result.Sweep)
+	require.Equal(t, account.Balance, result.Sweep.Transfer.Amount)
+	require.Equal(t, sweepAccount.Balance+account.Balance, result.Sweep.ToAccount.Balance)
+
+	_, err = store.CloseAccountTx(ctx, CloseAccountTxParams{AccountID: account.ID})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// the closed account no longer shows up, but a new one in its currency can be opened
+	accounts, err := testQueries.ListAccounts(ctx, ListAccountsParams{Owner: account.Owner, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, otherAccount.ID, accounts[0].ID)
+
+	_, err = testQueries.CreateAccount(ctx, CreateAccountParams{Owner: account.Owner, Currency: account.Currency, Product: ProductChecking})
+	require.NoError(t, err)
+}
+
+func otherCurrency(currency string) string {
+	if currency == util.USD {
+		return util.EUR
+	}
+	return util.USD
+}
+
+func TestAccountProducts(t *testing.T) {
+	account := createRandomAccount(t)
+	ctx := context.Background()
+
+	// a second checking account in the currency breaks the unique index
+	_, err := testQueries.CreateAccount(ctx, CreateAccountParams{Owner: account.Owner, Currency: account.Currency, Product: ProductChecking})
+	require.Error(t, err)
+
+	// savings accounts in the same currency are told apart by their nickname
+	for _, nickname := range []string{"Holiday", "Rainy day"} {
+		_, err := testQueries.CreateAccount(ctx, CreateAccountParams{
+			Owner:    account.Owner,
+			Currency: account.Currency,
+			Product:  ProductSavings,
+			Nickname: nickname,
+		})
+		require.NoError(t, err)
+	}
+
+	count, err := testQueries.CountOpenAccounts(ctx, CountOpenAccountsParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+		Product:  ProductSavings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	savings, err := testQueries.ListAccounts(ctx, ListAccountsParams{
+		Owner:   account.Owner,
+		Product: sql.NullString{String: ProductSavings, Valid: true},
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	require.Len(t, savings, 2)
+
+	_, err = testQueries.UpdateAccountNickname(ctx, UpdateAccountNicknameParams{ID: savings[1].ID, Nickname: "Holiday"})
+	require.Error(t, err)
+
+	product, err := testQueries.GetAccountProduct(ctx, ProductSavings)
+	require.NoError(t, err)
+	require.Equal(t, int32(6), product.MaxMonthlyWithdrawals)
+}
+
+func TestTransferTxWithdrawalLimit(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	account := createRandomAccount(t)
+
+	savings, err := testQueries.CreateAccount(ctx, CreateAccountParams{
+		Owner:    account.Owner,
+		Balance:  100,
+		Currency: account.Currency,
+		Product:  ProductSavings,
+	})
+	require.NoError(t, err)
+
+	product, err := testQueries.GetAccountProduct(ctx, ProductSavings)
+	require.NoError(t, err)
+
+	for i := 0; i < int(product.MaxMonthlyWithdrawals); i++ {
+		_, err := store.TransferTx(ctx, TransferTxParams{FromAccID: savings.ID, ToAccID: account.ID, Amount: 1})
+		require.NoError(t, err)
+	}
+
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: savings.ID, ToAccID: account.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrWithdrawalLimit)
+
+	// money can still come in, and checking accounts have no limit
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: account.ID, ToAccID: savings.ID, Amount: 1})
+	require.NoError(t, err)
+}
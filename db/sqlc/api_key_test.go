@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAPIKey(t *testing.T, owner string) ApiKey {
+	arg := CreateAPIKeyParams{
+		Owner:     owner,
+		Name:      util.RandomString(6),
+		Prefix:    util.RandomString(8),
+		HashedKey: util.HashSecret(util.RandomString(32)),
+		Scopes:    []string{"accounts:read", "transfers:write"},
+		ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+
+	key, err := testQueries.CreateAPIKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+
+	require.Equal(t, arg.Owner, key.Owner)
+	require.Equal(t, arg.Name, key.Name)
+	require.Equal(t, arg.Prefix, key.Prefix)
+	require.Equal(t, arg.HashedKey, key.HashedKey)
+	require.Equal(t, arg.Scopes, key.Scopes)
+	require.WithinDuration(t, arg.ExpiresAt.Time, key.ExpiresAt.Time, time.Second)
+	require.False(t, key.LastUsedAt.Valid)
+	require.False(t, key.RevokedAt.Valid)
+	require.NotZero(t, key.CreatedAt)
+
+	return key
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomAPIKey(t, user.Username)
+}
+
+func TestGetAPIKeyByPrefix(t *testing.T) {
+	user := createRandomUser(t)
+	key1 := createRandomAPIKey(t, user.Username)
+
+	key2, err := testQueries.GetAPIKeyByPrefix(context.Background(), key1.Prefix)
+	require.NoError(t, err)
+	require.Equal(t, key1.ID, key2.ID)
+	require.Equal(t, key1.HashedKey, key2.HashedKey)
+	require.Equal(t, key1.Scopes, key2.Scopes)
+}
+
+func TestListAPIKeys(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomAPIKey(t, user.Username)
+	}
+
+	keys, err := testQueries.ListAPIKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	for _, key := range keys {
+		require.Equal(t, user.Username, key.Owner)
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	user := createRandomUser(t)
+	key := createRandomAPIKey(t, user.Username)
+
+	revoked, err := testQueries.RevokeAPIKey(context.Background(), RevokeAPIKeyParams{ID: key.ID, Owner: user.Username})
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+
+	// a key can only be revoked once
+	_, err = testQueries.RevokeAPIKey(context.Background(), RevokeAPIKeyParams{ID: key.ID, Owner: user.Username})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestUpdateAPIKeyLastUsed(t *testing.T) {
+	user := createRandomUser(t)
+	key := createRandomAPIKey(t, user.Username)
+
+	err := testQueries.UpdateAPIKeyLastUsed(context.Background(), key.ID)
+	require.NoError(t, err)
+
+	key, err = testQueries.GetAPIKeyByPrefix(context.Background(), key.Prefix)
+	require.NoError(t, err)
+	require.True(t, key.LastUsedAt.Valid)
+	require.WithinDuration(t, time.Now(), key.LastUsedAt.Time, time.Minute)
+}
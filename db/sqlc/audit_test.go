@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	audit := AuditParams{
+		Actor:      account.Owner,
+		Action:     "account.update",
+		TargetType: "account",
+		TargetID:   fmt.Sprint(account.ID),
+		RequestID:  util.RandomString(16),
+		IP:         "192.0.2.1",
+		Before:     account,
+	}
+
+	var updated Account
+	err := store.AuditTx(context.Background(), audit, func(q Querier, audit *AuditParams) error {
+		var err error
+		updated, err = q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: 10,
+		})
+		audit.After = updated
+		return err
+	})
+	require.NoError(t, err)
+
+	events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		TargetType: sql.NullString{String: audit.TargetType, Valid: true},
+		TargetID:   sql.NullString{String: audit.TargetID, Valid: true},
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, audit.Actor, event.Actor)
+	require.Equal(t, audit.Action, event.Action)
+	require.Equal(t, audit.RequestID, event.RequestID)
+	require.Equal(t, audit.IP, event.Ip)
+	require.Contains(t, string(event.After), fmt.Sprintf(`"balance":%d`, updated.Balance))
+	require.NotEqual(t, event.PrevHash, event.Hash)
+
+	checked, err := VerifyAuditChain(context.Background(), testQueries)
+	require.NoError(t, err)
+	require.Positive(t, checked)
+}
+
+func TestAuditTxRollback(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	errFailed := errors.New("failed")
+
+	audit := AuditParams{
+		Actor:      account.Owner,
+		Action:     "account.update",
+		TargetType: "account",
+		TargetID:   fmt.Sprint(account.ID),
+	}
+
+	err := store.AuditTx(context.Background(), audit, func(q Querier, audit *AuditParams) error {
+		_, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: 10,
+		})
+		require.NoError(t, err)
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+
+	events, err := testQueries.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		TargetType: sql.NullString{String: audit.TargetType, Valid: true},
+		TargetID:   sql.NullString{String: audit.TargetID, Valid: true},
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	account2, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, account2.Balance)
+}
+
+func TestAuditEventsAppendOnly(t *testing.T) {
+	_, err := testDB.ExecContext(context.Background(), "DELETE FROM audit_events")
+	require.Error(t, err)
+}
+
+func TestAuditEventHash(t *testing.T) {
+	event := CreateAuditEventParams{
+		Actor:      util.RandomOwner(),
+		Action:     "account.update",
+		TargetType: "account",
+		TargetID:   "1",
+		Before:     []byte(`{"balance":10}`),
+		After:      []byte(`{"balance":20}`),
+		CreatedAt:  time.Now(),
+	}
+	hash := AuditEventHash(event)
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, AuditEventHash(event))
+
+	tampered := event
+	tampered.After = []byte(`{"balance":2000}`)
+	require.NotEqual(t, hash, AuditEventHash(tampered))
+
+	relinked := event
+	relinked.PrevHash = hash
+	require.NotEqual(t, hash, AuditEventHash(relinked))
+}
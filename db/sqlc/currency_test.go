@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+
+	enabled := map[string]bool{}
+	for _, currency := range currencies {
+		enabled[currency.Code] = currency.Enabled
+	}
+
+	require.True(t, enabled[util.USD])
+	require.True(t, enabled[util.EUR])
+	require.True(t, enabled[util.CAD])
+}
+
+func TestUpdateCurrencyEnabled(t *testing.T) {
+	ctx := context.Background()
+
+	currency, err := testQueries.UpdateCurrencyEnabled(ctx, UpdateCurrencyEnabledParams{Code: "JPY", Enabled: true})
+	require.NoError(t, err)
+	require.True(t, currency.Enabled)
+	require.Equal(t, int32(0), currency.MinorUnits)
+
+	currency, err = testQueries.UpdateCurrencyEnabled(ctx, UpdateCurrencyEnabledParams{Code: "JPY", Enabled: false})
+	require.NoError(t, err)
+	require.False(t, currency.Enabled)
+
+	got, err := testQueries.GetCurrency(ctx, "JPY")
+	require.NoError(t, err)
+	require.Equal(t, currency, got)
+}
@@ -9,55 +9,110 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transfer_id,
+    entry_type
     ) VALUES (
     $1,
-    $2
-    ) RETURNING id, account_id, amount, created_at
+    $2,
+    $3,
+    $4
+    ) RETURNING id, account_id, amount, created_at, transfer_id, entry_type
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64         `json:"account_id"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	EntryType  string        `json:"entry_type"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.EntryType,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.EntryType,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries WHERE id = $1 LIMIT 1
+SELECT e.id, e.account_id, e.amount, e.created_at, e.transfer_id, e.entry_type, a.currency, t.memo, t.reference,
+c.id AS counterparty_account_id, c.owner AS counterparty_owner,
+f.fee_type, f.transfer_id AS fee_transfer_id
+FROM entries e
+INNER JOIN accounts a ON e.account_id = a.id
+LEFT JOIN transfers t ON e.transfer_id = t.id
+LEFT JOIN accounts c ON c.id = CASE WHEN e.entry_type = 'transfer_debit' THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN fees f ON f.entry_id = e.id
+WHERE e.id = $1 LIMIT 1
 `
 
-func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
+type GetEntryRow struct {
+	ID                    int64          `json:"id"`
+	AccountID             int64          `json:"account_id"`
+	Amount                int64          `json:"amount"`
+	CreatedAt             time.Time      `json:"created_at"`
+	TransferID            sql.NullInt64  `json:"transfer_id"`
+	EntryType             string         `json:"entry_type"`
+	Currency              string         `json:"currency"`
+	Memo                  sql.NullString `json:"memo"`
+	Reference             sql.NullString `json:"reference"`
+	CounterpartyAccountID sql.NullInt64  `json:"counterparty_account_id"`
+	CounterpartyOwner     sql.NullString `json:"counterparty_owner"`
+	FeeType               sql.NullString `json:"fee_type"`
+	FeeTransferID         sql.NullInt64  `json:"fee_transfer_id"`
+}
+
+func (q *Queries) GetEntry(ctx context.Context, id int64) (GetEntryRow, error) {
 	row := q.db.QueryRowContext(ctx, getEntry, id)
-	var i Entry
+	var i GetEntryRow
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.EntryType,
+		&i.Currency,
+		&i.Memo,
+		&i.Reference,
+		&i.CounterpartyAccountID,
+		&i.CounterpartyOwner,
+		&i.FeeType,
+		&i.FeeTransferID,
 	)
 	return i, err
 }
 
 const listEntryFromAccountId = `-- name: ListEntryFromAccountId :many
-SELECT id, account_id, amount, created_at FROM entries
-WHERE account_id = $1
-ORDER BY created_at
+SELECT e.id, e.account_id, e.amount, e.created_at, e.transfer_id, e.entry_type, a.currency, t.memo, t.reference,
+c.id AS counterparty_account_id, c.owner AS counterparty_owner,
+f.fee_type, f.transfer_id AS fee_transfer_id
+FROM entries e
+INNER JOIN accounts a ON e.account_id = a.id
+LEFT JOIN transfers t ON e.transfer_id = t.id
+LEFT JOIN accounts c ON c.id = CASE WHEN e.entry_type = 'transfer_debit' THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN fees f ON f.entry_id = e.id
+WHERE e.account_id = $1
+ORDER BY e.created_at
 LIMIT $2
 OFFSET $3
 `
@@ -68,20 +123,45 @@ type ListEntryFromAccountIdParams struct {
 	Offset    int32 `json:"offset"`
 }
 
-func (q *Queries) ListEntryFromAccountId(ctx context.Context, arg ListEntryFromAccountIdParams) ([]Entry, error) {
+type ListEntryFromAccountIdRow struct {
+	ID                    int64          `json:"id"`
+	AccountID             int64          `json:"account_id"`
+	Amount                int64          `json:"amount"`
+	CreatedAt             time.Time      `json:"created_at"`
+	TransferID            sql.NullInt64  `json:"transfer_id"`
+	EntryType             string         `json:"entry_type"`
+	Currency              string         `json:"currency"`
+	Memo                  sql.NullString `json:"memo"`
+	Reference             sql.NullString `json:"reference"`
+	CounterpartyAccountID sql.NullInt64  `json:"counterparty_account_id"`
+	CounterpartyOwner     sql.NullString `json:"counterparty_owner"`
+	FeeType               sql.NullString `json:"fee_type"`
+	FeeTransferID         sql.NullInt64  `json:"fee_transfer_id"`
+}
+
+func (q *Queries) ListEntryFromAccountId(ctx context.Context, arg ListEntryFromAccountIdParams) ([]ListEntryFromAccountIdRow, error) {
 	rows, err := q.db.QueryContext(ctx, listEntryFromAccountId, arg.AccountID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Entry{}
+	items := []ListEntryFromAccountIdRow{}
 	for rows.Next() {
-		var i Entry
+		var i ListEntryFromAccountIdRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.EntryType,
+			&i.Currency,
+			&i.Memo,
+			&i.Reference,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyOwner,
+			&i.FeeType,
+			&i.FeeTransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -97,19 +177,27 @@ func (q *Queries) ListEntryFromAccountId(ctx context.Context, arg ListEntryFromA
 }
 
 const seachEntriesByAccountOwner = `-- name: SeachEntriesByAccountOwner :many
-SELECT e.id, e.account_id, e.amount, e.created_at
+SELECT e.id, e.account_id, e.amount, e.created_at, e.transfer_id, e.entry_type, a.currency, t.memo, t.reference,
+c.id AS counterparty_account_id, c.owner AS counterparty_owner,
+f.fee_type, f.transfer_id AS fee_transfer_id
 FROM entries e
 INNER JOIN accounts a ON e.account_id = a.id
+LEFT JOIN transfers t ON e.transfer_id = t.id
+LEFT JOIN accounts c ON c.id = CASE WHEN e.entry_type = 'transfer_debit' THEN t.to_account_id ELSE t.from_account_id END
+LEFT JOIN fees f ON f.entry_id = e.id
 WHERE a.owner ILIKE '%' || $3 || '%'
-AND e.created_at >= $4 AND e.created_at <= $5
-AND e.amount >= $6 AND e.amount <= $7
+AND a.owner = $4
+AND ($5::bigint[] IS NULL OR e.account_id = ANY($5::bigint[]))
+AND ($6::varchar IS NULL OR a.currency = $6::varchar)
+AND e.created_at >= $7 AND e.created_at <= $8
+AND e.amount >= $9 AND e.amount <= $10
 ORDER BY
-CASE WHEN  $8 = 'amount' AND  $9 = 'ASC' THEN e.amount END  ASC,
-CASE WHEN  $8 = 'amount' AND  $9 = 'DESC' THEN e.amount END DESC,
-CASE WHEN  $8 = 'created_at' AND  $9 = 'ASC' THEN e.created_at END  ASC,
-CASE WHEN  $8 = 'created_at' AND  $9 = 'DESC' THEN e.created_at END DESC,
-CASE WHEN  $8 = 'id' AND  $9 = 'ASC' THEN e.id END  ASC,
-CASE WHEN  $8 = 'id' AND  $9 = 'DESC' THEN e.id END DESC
+CASE WHEN  $11 = 'amount' AND  $12 = 'ASC' THEN e.amount END  ASC,
+CASE WHEN  $11 = 'amount' AND  $12 = 'DESC' THEN e.amount END DESC,
+CASE WHEN  $11 = 'created_at' AND  $12 = 'ASC' THEN e.created_at END  ASC,
+CASE WHEN  $11 = 'created_at' AND  $12 = 'DESC' THEN e.created_at END DESC,
+CASE WHEN  $11 = 'id' AND  $12 = 'ASC' THEN e.id END  ASC,
+CASE WHEN  $11 = 'id' AND  $12 = 'DESC' THEN e.id END DESC
 LIMIT $1
 OFFSET $2
 `
@@ -118,6 +206,9 @@ type SeachEntriesByAccountOwnerParams struct {
 	Limit       int32          `json:"limit"`
 	Offset      int32          `json:"offset"`
 	SearchQuery sql.NullString `json:"search_query"`
+	Owner       string         `json:"owner"`
+	AccountIds  []int64        `json:"account_ids"`
+	Currency    sql.NullString `json:"currency"`
 	StartDate   time.Time      `json:"start_date"`
 	EndDate     time.Time      `json:"end_date"`
 	MinAmount   int64          `json:"min_amount"`
@@ -126,11 +217,30 @@ type SeachEntriesByAccountOwnerParams struct {
 	OrderBy     interface{}    `json:"order_by"`
 }
 
-func (q *Queries) SeachEntriesByAccountOwner(ctx context.Context, arg SeachEntriesByAccountOwnerParams) ([]Entry, error) {
+type SeachEntriesByAccountOwnerRow struct {
+	ID                    int64          `json:"id"`
+	AccountID             int64          `json:"account_id"`
+	Amount                int64          `json:"amount"`
+	CreatedAt             time.Time      `json:"created_at"`
+	TransferID            sql.NullInt64  `json:"transfer_id"`
+	EntryType             string         `json:"entry_type"`
+	Currency              string         `json:"currency"`
+	Memo                  sql.NullString `json:"memo"`
+	Reference             sql.NullString `json:"reference"`
+	CounterpartyAccountID sql.NullInt64  `json:"counterparty_account_id"`
+	CounterpartyOwner     sql.NullString `json:"counterparty_owner"`
+	FeeType               sql.NullString `json:"fee_type"`
+	FeeTransferID         sql.NullInt64  `json:"fee_transfer_id"`
+}
+
+func (q *Queries) SeachEntriesByAccountOwner(ctx context.Context, arg SeachEntriesByAccountOwnerParams) ([]SeachEntriesByAccountOwnerRow, error) {
 	rows, err := q.db.QueryContext(ctx, seachEntriesByAccountOwner,
 		arg.Limit,
 		arg.Offset,
 		arg.SearchQuery,
+		arg.Owner,
+		pq.Array(arg.AccountIds),
+		arg.Currency,
 		arg.StartDate,
 		arg.EndDate,
 		arg.MinAmount,
@@ -142,14 +252,23 @@ func (q *Queries) SeachEntriesByAccountOwner(ctx context.Context, arg SeachEntri
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Entry{}
+	items := []SeachEntriesByAccountOwnerRow{}
 	for rows.Next() {
-		var i Entry
+		var i SeachEntriesByAccountOwnerRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.EntryType,
+			&i.Currency,
+			&i.Memo,
+			&i.Reference,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyOwner,
+			&i.FeeType,
+			&i.FeeTransferID,
 		); err != nil {
 			return nil, err
 		}
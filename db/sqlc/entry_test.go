@@ -15,12 +15,15 @@ func createRandomEntry(t *testing.T) Entry {
 	arg := CreateEntryParams{
 		AccountID: account.ID,
 		Amount:    util.RandomMoney(),
+		EntryType: EntryTypeAdjustment,
 	}
 	entry, err := testQueries.CreateEntry(context.Background(), arg)
 	require.NoError(t, err)
 	require.NotEmpty(t, entry)
 	require.Equal(t, arg.AccountID, entry.AccountID)
 	require.Equal(t, arg.Amount, entry.Amount)
+	require.Equal(t, EntryTypeAdjustment, entry.EntryType)
+	require.False(t, entry.TransferID.Valid)
 	return entry
 }
 
@@ -36,6 +39,37 @@ func TestGetEntry(t *testing.T) {
 	require.Equal(t, entry1.ID, entry2.ID)
 	require.Equal(t, entry1.AccountID, entry2.AccountID)
 	require.Equal(t, entry1.Amount, entry2.Amount)
+	require.NotEmpty(t, entry2.Currency)
+	require.False(t, entry2.CounterpartyOwner.Valid)
+}
+
+func TestGetEntryFromTransfer(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccID: account1.ID,
+		ToAccID:   account2.ID,
+		Amount:    10,
+		Memo:      "lunch",
+	})
+	require.NoError(t, err)
+
+	debit, err := testQueries.GetEntry(context.Background(), result.FromEntry.ID)
+	require.NoError(t, err)
+	require.Equal(t, EntryTypeTransferDebit, debit.EntryType)
+	require.Equal(t, result.Transfer.ID, debit.TransferID.Int64)
+	require.Equal(t, account1.Currency, debit.Currency)
+	require.Equal(t, "lunch", debit.Memo.String)
+	require.Equal(t, account2.ID, debit.CounterpartyAccountID.Int64)
+	require.Equal(t, account2.Owner, debit.CounterpartyOwner.String)
+
+	credit, err := testQueries.GetEntry(context.Background(), result.ToEntry.ID)
+	require.NoError(t, err)
+	require.Equal(t, EntryTypeTransferCredit, credit.EntryType)
+	require.Equal(t, account1.ID, credit.CounterpartyAccountID.Int64)
+	require.Equal(t, account1.Owner, credit.CounterpartyOwner.String)
 }
 
 func TestListEntriesFromAccount(t *testing.T) {
@@ -47,6 +81,7 @@ func TestListEntriesFromAccount(t *testing.T) {
 		arg := CreateEntryParams{
 			AccountID: account.ID,
 			Amount:    util.RandomMoney(),
+			EntryType: EntryTypeAdjustment,
 		}
 		_, err := testQueries.CreateEntry(context.Background(), arg)
 		require.NoError(t, err)
@@ -87,6 +122,7 @@ func TestSearchEntryByAccount(t *testing.T) {
 		arg := CreateEntryParams{
 			AccountID: account.ID,
 			Amount:    60,
+			EntryType: EntryTypeAdjustment,
 		}
 		_, err := testQueries.CreateEntry(context.Background(), arg)
 		require.NoError(t, err)
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Fee types
+const (
+	FeeTypeTransfer    = "transfer"
+	FeeTypeMaintenance = "maintenance"
+)
+
+// Fee methods
+const (
+	FeeMethodFlat       = "flat"
+	FeeMethodPercentage = "percentage"
+	FeeMethodTiered     = "tiered"
+)
+
+var ErrFeeAboveMax = errors.New("the fee is above the maximum accepted")
+
+// FeeTier is a band of a tiered fee schedule. It applies to amounts up to and
+// including UpTo, or to any amount left when UpTo is 0.
+type FeeTier struct {
+	UpTo       int64 `json:"up_to,omitempty"`
+	FlatAmount int64 `json:"flat_amount"`
+	RateBps    int32 `json:"rate_bps"`
+}
+
+// ParseFeeTiers decodes and validates the tiers of a schedule. The tiers must
+// be in ascending order of UpTo and only the last one may be open ended.
+func ParseFeeTiers(raw json.RawMessage) ([]FeeTier, error) {
+	var tiers []FeeTier
+	if err := json.Unmarshal(raw, &tiers); err != nil {
+		return nil, fmt.Errorf("invalid fee tiers: %w", err)
+	}
+
+	if len(tiers) == 0 {
+		return nil, errors.New("a tiered fee needs at least one tier")
+	}
+
+	for i, tier := range tiers {
+		last := i == len(tiers)-1
+
+		switch {
+		case tier.FlatAmount < 0 || tier.RateBps < 0 || tier.RateBps > 10_000:
+			return nil, fmt.Errorf("tier %d: the flat amount and rate must be between 0 and 10000 bps", i+1)
+		case tier.UpTo < 0:
+			return nil, fmt.Errorf("tier %d: up_to must not be negative", i+1)
+		case last && tier.UpTo != 0:
+			return nil, errors.New("the last tier must not have an up_to")
+		case !last && tier.UpTo == 0:
+			return nil, fmt.Errorf("tier %d: only the last tier may leave out up_to", i+1)
+		case i > 0 && !last && tier.UpTo <= tiers[i-1].UpTo:
+			return nil, fmt.Errorf("tier %d: tiers must be in ascending order of up_to", i+1)
+		}
+	}
+
+	return tiers, nil
+}
+
+// Fee returns the fee the schedule charges on an amount in minor units: the
+// transfer amount for transfer fees or the balance for maintenance fees. The
+// percentage is rounded half up, and negative amounts are charged as 0.
+func (schedule FeeSchedule) Fee(amount int64) (int64, error) {
+	if amount < 0 {
+		amount = 0
+	}
+
+	flat, rate := schedule.FlatAmount, schedule.RateBps
+
+	switch schedule.Method {
+	case FeeMethodFlat:
+		rate = 0
+	case FeeMethodPercentage:
+	case FeeMethodTiered:
+		tiers, err := ParseFeeTiers(schedule.Tiers)
+		if err != nil {
+			return 0, fmt.Errorf("fee schedule %d: %w", schedule.ID, err)
+		}
+
+		for _, tier := range tiers {
+			if tier.UpTo == 0 || amount <= tier.UpTo {
+				flat, rate = tier.FlatAmount, tier.RateBps
+				break
+			}
+		}
+	default:
+		return 0, fmt.Errorf("fee schedule %d: unknown method %q", schedule.ID, schedule.Method)
+	}
+
+	fee := new(big.Int).Mul(big.NewInt(amount), big.NewInt(int64(rate)))
+	fee.Add(fee, big.NewInt(5_000))
+	fee.Quo(fee, big.NewInt(10_000))
+	fee.Add(fee, big.NewInt(flat))
+
+	if !fee.IsInt64() {
+		return 0, fmt.Errorf("fee on %d is out of range", amount)
+	}
+
+	result := fee.Int64()
+	if schedule.MinAmount > 0 && result < schedule.MinAmount {
+		result = schedule.MinAmount
+	}
+	if schedule.MaxAmount > 0 && result > schedule.MaxAmount {
+		result = schedule.MaxAmount
+	}
+
+	return result, nil
+}
+
+// FeeQuote is the fee an account would be charged
+type FeeQuote struct {
+	FeeType string
+	// ScheduleID is 0 when no schedule applies to the account
+	ScheduleID int64
+	Amount     int64
+}
+
+// QuoteFee works out the fee of feeType on amount for the product and
+// currency of account. Accounts without a schedule are charged nothing.
+func QuoteFee(ctx context.Context, q Querier, feeType string, account Account, amount int64) (FeeQuote, error) {
+	quote := FeeQuote{FeeType: feeType}
+
+	schedule, err := q.GetFeeSchedule(ctx, GetFeeScheduleParams{
+		FeeType:  feeType,
+		Product:  account.Product,
+		Currency: account.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return quote, nil
+	}
+	if err != nil {
+		return quote, err
+	}
+
+	quote.ScheduleID = schedule.ID
+	quote.Amount, err = schedule.Fee(amount)
+	return quote, err
+}
+
+// chargeFee debits a quoted fee from account and credits it to the fee
+// revenue account of its currency, within the transaction of q. The revenue
+// account is locked after the customer's accounts, so it is always taken
+// last. A negative quote refunds the fee instead. It returns the fee and the
+// account after the debit.
+func chargeFee(ctx context.Context, q *Queries, account Account, quote FeeQuote, transferID sql.NullInt64, periodStart sql.NullTime) (Fee, Account, error) {
+	revenue, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountFeeRevenue,
+		Currency: account.Currency,
+	})
+	if err != nil {
+		return Fee{}, account, fmt.Errorf("fee revenue account for %s: %w", account.Currency, err)
+	}
+
+	entry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: account.ID,
+		Amount:    -quote.Amount,
+		EntryType: EntryTypeFee,
+	})
+	if err != nil {
+		return Fee{}, account, err
+	}
+
+	revenueEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: revenue.AccountID,
+		Amount:    quote.Amount,
+		EntryType: EntryTypeFee,
+	})
+	if err != nil {
+		return Fee{}, account, err
+	}
+
+	account, _, err = AddMoney(ctx, q, account.ID, -quote.Amount, revenue.AccountID, quote.Amount)
+	if err != nil {
+		return Fee{}, account, err
+	}
+
+	fee, err := q.CreateFee(ctx, CreateFeeParams{
+		AccountID:      account.ID,
+		FeeType:        quote.FeeType,
+		ScheduleID:     quote.ScheduleID,
+		Amount:         quote.Amount,
+		TransferID:     transferID,
+		PeriodStart:    periodStart,
+		EntryID:        entry.ID,
+		RevenueEntryID: revenueEntry.ID,
+	})
+
+	return fee, account, err
+}
+
+// chargeTransferFee charges the transfer fee of the account money left in
+// result, if its schedule has one. It fails with ErrFeeAboveMax when the fee
+// is more than maxFee.
+func chargeTransferFee(ctx context.Context, q *Queries, result *TransferTxResult, maxFee *int64) error {
+	quote, err := QuoteFee(ctx, q, FeeTypeTransfer, result.FromAccount, result.Transfer.Amount)
+	if err != nil {
+		return err
+	}
+
+	if maxFee != nil && quote.Amount > *maxFee {
+		return ErrFeeAboveMax
+	}
+
+	if quote.Amount == 0 {
+		return nil
+	}
+
+	fee, account, err := chargeFee(ctx, q, result.FromAccount, quote, sql.NullInt64{Int64: result.Transfer.ID, Valid: true}, sql.NullTime{})
+	if err != nil {
+		return err
+	}
+
+	result.FromAccount = account
+	result.Fees = append(result.Fees, fee)
+	return nil
+}
+
+// refundTransferFees pays the transfer fees charged on transferID back to
+// account from the fee revenue account, within the transaction of q. Each
+// refund is recorded as a fee of minus the amount on the same transfer, so
+// the fees of a transfer add up to what the customer paid in the end. It
+// returns the refunds and the account after the credit.
+func refundTransferFees(ctx context.Context, q *Queries, account Account, transferID int64) ([]Fee, Account, error) {
+	id := sql.NullInt64{Int64: transferID, Valid: true}
+
+	charged, err := q.ListFeesByTransfer(ctx, id)
+	if err != nil {
+		return nil, account, err
+	}
+
+	refunds := []Fee{}
+	for _, fee := range charged {
+		if fee.FeeType != FeeTypeTransfer || fee.Amount <= 0 {
+			continue
+		}
+
+		quote := FeeQuote{FeeType: fee.FeeType, ScheduleID: fee.ScheduleID, Amount: -fee.Amount}
+
+		var refund Fee
+		refund, account, err = chargeFee(ctx, q, account, quote, id, sql.NullTime{})
+		if err != nil {
+			return refunds, account, err
+		}
+
+		refunds = append(refunds, refund)
+	}
+
+	return refunds, account, nil
+}
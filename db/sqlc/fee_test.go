@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeScheduleFee(t *testing.T) {
+	tiers := json.RawMessage(`[
+		{"up_to": 10000, "flat_amount": 25, "rate_bps": 0},
+		{"up_to": 100000, "flat_amount": 0, "rate_bps": 50},
+		{"flat_amount": 100, "rate_bps": 10}
+	]`)
+
+	testCases := []struct {
+		name     string
+		schedule FeeSchedule
+		amount   int64
+		fee      int64
+	}{
+		{"Flat", FeeSchedule{Method: FeeMethodFlat, FlatAmount: 30, RateBps: 100}, 5_000, 30},
+		{"Percentage", FeeSchedule{Method: FeeMethodPercentage, RateBps: 150}, 10_000, 150},
+		{"PercentagePlusFlat", FeeSchedule{Method: FeeMethodPercentage, FlatAmount: 25, RateBps: 100}, 5_000, 75},
+		{"RoundsHalfUp", FeeSchedule{Method: FeeMethodPercentage, RateBps: 50}, 100, 1},
+		{"RoundsDown", FeeSchedule{Method: FeeMethodPercentage, RateBps: 40}, 100, 0},
+		{"Min", FeeSchedule{Method: FeeMethodPercentage, RateBps: 10, MinAmount: 50}, 1_000, 50},
+		{"Max", FeeSchedule{Method: FeeMethodPercentage, RateBps: 100, MaxAmount: 500}, 1_000_000, 500},
+		{"NegativeBalance", FeeSchedule{Method: FeeMethodPercentage, FlatAmount: 10, RateBps: 100}, -5_000, 10},
+		{"FirstTier", FeeSchedule{Method: FeeMethodTiered, Tiers: tiers}, 10_000, 25},
+		{"MiddleTier", FeeSchedule{Method: FeeMethodTiered, Tiers: tiers}, 10_001, 50},
+		{"LastTier", FeeSchedule{Method: FeeMethodTiered, Tiers: tiers}, 1_000_000, 1_100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fee, err := tc.schedule.Fee(tc.amount)
+			require.NoError(t, err)
+			require.Equal(t, tc.fee, fee)
+		})
+	}
+}
+
+func TestParseFeeTiers(t *testing.T) {
+	for _, tiers := range []string{
+		`[]`,
+		`[{"up_to": 100, "flat_amount": 1}]`,
+		`[{"flat_amount": 1}, {"flat_amount": 2}]`,
+		`[{"up_to": 100, "flat_amount": 1}, {"up_to": 100, "flat_amount": 2}, {"flat_amount": 3}]`,
+		`[{"up_to": 100, "rate_bps": 20000}, {"flat_amount": 3}]`,
+	} {
+		_, err := ParseFeeTiers(json.RawMessage(tiers))
+		require.Error(t, err, tiers)
+	}
+
+	parsed, err := ParseFeeTiers(json.RawMessage(`[{"up_to": 100, "flat_amount": 1}, {"rate_bps": 10}]`))
+	require.NoError(t, err)
+	require.Equal(t, []FeeTier{{UpTo: 100, FlatAmount: 1}, {RateBps: 10}}, parsed)
+}
+
+func upsertTestFeeSchedule(t *testing.T, feeType string, flatAmount int64) FeeSchedule {
+	user := createRandomUser(t)
+
+	// savings in CAD are left to the fee tests, so the fees don't upset the
+	// balances other tests check
+	schedule, err := testQueries.UpsertFeeSchedule(context.Background(), UpsertFeeScheduleParams{
+		FeeType:    feeType,
+		Product:    ProductSavings,
+		Currency:   util.CAD,
+		Method:     FeeMethodFlat,
+		FlatAmount: flatAmount,
+		Tiers:      json.RawMessage("[]"),
+		UpdatedBy:  user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, flatAmount, schedule.FlatAmount)
+
+	return schedule
+}
+
+func createTestSavingsAccount(t *testing.T, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  balance,
+		Currency: util.CAD,
+		Product:  ProductSavings,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
+func TestTransferTxFee(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	schedule := upsertTestFeeSchedule(t, FeeTypeTransfer, 25)
+
+	from := createTestSavingsAccount(t, 1_000)
+	to := createTestSavingsAccount(t, 0)
+
+	revenue, err := testQueries.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountFeeRevenue,
+		Currency: util.CAD,
+	})
+	require.NoError(t, err)
+
+	revenueBefore, err := testQueries.GetAccount(ctx, revenue.AccountID)
+	require.NoError(t, err)
+
+	maxFee := schedule.FlatAmount
+	result, err := store.TransferTx(ctx, TransferTxParams{FromAccID: from.ID, ToAccID: to.ID, Amount: 100, MaxFee: &maxFee})
+	require.NoError(t, err)
+	require.Len(t, result.Fees, 1)
+	require.Equal(t, int64(25), result.Fees[0].Amount)
+	require.Equal(t, schedule.ID, result.Fees[0].ScheduleID)
+	require.Equal(t, result.Transfer.ID, result.Fees[0].TransferID.Int64)
+	require.Equal(t, from.Balance-100-25, result.FromAccount.Balance)
+	require.Equal(t, to.Balance+100, result.ToAccount.Balance)
+
+	// the revenue account has gained at least this fee; other tests may be
+	// charging fees at the same time
+	revenueAfter, err := testQueries.GetAccount(ctx, revenue.AccountID)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, revenueAfter.Balance, revenueBefore.Balance+25)
+
+	fees, err := testQueries.ListFeesByTransfer(ctx, sql.NullInt64{Int64: result.Transfer.ID, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, result.Fees, fees)
+
+	entry, err := testQueries.GetEntry(ctx, fees[0].EntryID)
+	require.NoError(t, err)
+	require.Equal(t, EntryTypeFee, entry.EntryType)
+	require.Equal(t, int64(-25), entry.Amount)
+	require.Equal(t, FeeTypeTransfer, entry.FeeType.String)
+	require.Equal(t, result.Transfer.ID, entry.FeeTransferID.Int64)
+
+	// a fee above the maximum fails the whole transfer
+	maxFee = 10
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: from.ID, ToAccID: to.ID, Amount: 100, MaxFee: &maxFee})
+	require.ErrorIs(t, err, ErrFeeAboveMax)
+
+	after, err := testQueries.GetAccount(ctx, from.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.FromAccount.Balance, after.Balance)
+}
+
+func TestChargeMaintenanceFeeTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	upsertTestFeeSchedule(t, FeeTypeMaintenance, 300)
+
+	account := createTestSavingsAccount(t, 5_000)
+	periodStart := time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	accountIDs, err := testQueries.ListMaintenanceFeeAccounts(ctx, ListMaintenanceFeeAccountsParams{
+		PeriodEnd:   periodStart.AddDate(0, 1, 0),
+		PeriodStart: sql.NullTime{Time: periodStart, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Contains(t, accountIDs, account.ID)
+
+	result, err := store.ChargeMaintenanceFeeTx(ctx, ChargeMaintenanceFeeTxParams{
+		AccountID:   account.ID,
+		PeriodStart: periodStart,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Fee)
+	require.Equal(t, int64(300), result.Fee.Amount)
+	require.Equal(t, account.Balance-300, result.Account.Balance)
+	require.False(t, result.Fee.TransferID.Valid)
+
+	// an account is charged once a month
+	accountIDs, err = testQueries.ListMaintenanceFeeAccounts(ctx, ListMaintenanceFeeAccountsParams{
+		PeriodEnd:   periodStart.AddDate(0, 1, 0),
+		PeriodStart: sql.NullTime{Time: periodStart, Valid: true},
+	})
+	require.NoError(t, err)
+	require.NotContains(t, accountIDs, account.ID)
+
+	_, err = store.ChargeMaintenanceFeeTx(ctx, ChargeMaintenanceFeeTxParams{
+		AccountID:   account.ID,
+		PeriodStart: periodStart,
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	banker := createRandomUser(t)
+	account := createRandomAccount(t)
+
+	result, err := store.FreezeAccountTx(ctx, FreezeAccountTxParams{
+		AccountID:  account.ID,
+		Mode:       FreezeModeFull,
+		ReasonCode: "aml_review",
+		Note:       "unusual activity",
+		FrozenBy:   banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, result.Account.Status)
+	require.Equal(t, FreezeModeFull, result.Account.FreezeMode)
+	require.Equal(t, account.ID, result.Freeze.AccountID)
+	require.Equal(t, banker.Username, result.Freeze.FrozenBy)
+	require.False(t, result.Freeze.LiftedAt.Valid)
+
+	_, err = store.FreezeAccountTx(ctx, FreezeAccountTxParams{
+		AccountID:  account.ID,
+		Mode:       FreezeModeDebit,
+		ReasonCode: "aml_review",
+		FrozenBy:   banker.Username,
+	})
+	require.ErrorIs(t, err, ErrAccountAlreadyFrozen)
+
+	result, err = store.UnfreezeAccountTx(ctx, UnfreezeAccountTxParams{
+		AccountID: account.ID,
+		LiftedBy:  banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusActive, result.Account.Status)
+	require.Empty(t, result.Account.FreezeMode)
+	require.True(t, result.Freeze.LiftedAt.Valid)
+	require.Equal(t, banker.Username, result.Freeze.LiftedBy.String)
+
+	_, err = store.UnfreezeAccountTx(ctx, UnfreezeAccountTxParams{
+		AccountID: account.ID,
+		LiftedBy:  banker.Username,
+	})
+	require.ErrorIs(t, err, ErrAccountNotFrozen)
+
+	freezes, err := testQueries.ListAccountFreezes(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, freezes, 1)
+}
+
+func TestTransferTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	banker := createRandomUser(t)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	_, err := store.FreezeAccountTx(ctx, FreezeAccountTxParams{
+		AccountID:  account2.ID,
+		Mode:       FreezeModeDebit,
+		ReasonCode: "fraud_investigation",
+		FrozenBy:   banker.Username,
+	})
+	require.NoError(t, err)
+
+	// a debit freeze still lets money in
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: account1.ID, ToAccID: account2.ID, Amount: 10})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: account2.ID, ToAccID: account1.ID, Amount: 10})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = store.UnfreezeAccountTx(ctx, UnfreezeAccountTxParams{AccountID: account2.ID, LiftedBy: banker.Username})
+	require.NoError(t, err)
+
+	_, err = store.FreezeAccountTx(ctx, FreezeAccountTxParams{
+		AccountID:  account2.ID,
+		Mode:       FreezeModeFull,
+		ReasonCode: "legal_order",
+		FrozenBy:   banker.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: account1.ID, ToAccID: account2.ID, Amount: 10})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	account1After, err := testQueries.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, account1After.Balance)
+}
+
+func TestAccountCanDebitCredit(t *testing.T) {
+	testCases := []struct {
+		account   Account
+		canDebit  bool
+		canCredit bool
+	}{
+		{Account{Status: AccountStatusActive}, true, true},
+		{Account{Status: AccountStatusFrozen, FreezeMode: FreezeModeDebit}, false, true},
+		{Account{Status: AccountStatusFrozen, FreezeMode: FreezeModeFull}, false, false},
+		{Account{Status: AccountStatusClosed}, false, false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.canDebit, tc.account.CanDebit(), tc.account)
+		require.Equal(t, tc.canCredit, tc.account.CanCredit(), tc.account)
+	}
+}
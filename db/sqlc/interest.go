@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Purposes of the bank's system accounts
+const (
+	SystemAccountInterestExpense = "interest_expense"
+	SystemAccountFeeRevenue      = "fee_revenue"
+	// SystemAccountExternalSettlement receives the transfers paid out to
+	// external beneficiaries until the correspondent bank settles them
+	SystemAccountExternalSettlement = "external_settlement"
+)
+
+// MicrosPerMinorUnit is the precision interest accrues at. Accruals are
+// kept in millionths of a minor unit so the daily amounts add up to the
+// interest for the month without rounding each day away.
+const MicrosPerMinorUnit = 1_000_000
+
+// daysPerYear is the day count of the actual/365 convention
+const daysPerYear = 365
+
+// DailyInterest returns the interest a non-negative balance earns in a day at
+// an annual rate in basis points, in millionths of a minor unit and rounded
+// half to even.
+func DailyInterest(balance int64, rateBps int32) (int64, error) {
+	num := new(big.Int).Mul(big.NewInt(balance), big.NewInt(int64(rateBps)))
+	num.Mul(num, big.NewInt(MicrosPerMinorUnit))
+
+	den := big.NewInt(10_000 * daysPerYear)
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	switch rem.Mul(rem, big.NewInt(2)).Cmp(den) {
+	case 1:
+		quo.Add(quo, big.NewInt(1))
+	case 0:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	if !quo.IsInt64() {
+		return 0, fmt.Errorf("interest on a balance of %d is out of range", balance)
+	}
+
+	return quo.Int64(), nil
+}
+
+// AccrueInterest records a day of interest for every open account whose
+// product pays interest in its currency, on the balance it had at the end of
+// the UTC day. It can be run late or more than once for a day: the balance is
+// worked back from the entries made since, and accounts that already accrued
+// for the day are skipped. It returns the number of accruals recorded.
+func AccrueInterest(ctx context.Context, q Querier, day time.Time) (int, error) {
+	day = day.UTC()
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	candidates, err := q.ListAccrualCandidates(ctx, ListAccrualCandidatesParams{
+		DayEnd:      day.AddDate(0, 0, 1),
+		AccrualDate: day,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	accrued := 0
+	for _, candidate := range candidates {
+		if candidate.Balance <= 0 {
+			continue
+		}
+
+		amount, err := DailyInterest(candidate.Balance, candidate.RateBps)
+		if err != nil {
+			return accrued, fmt.Errorf("account %d: %w", candidate.ID, err)
+		}
+
+		n, err := q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:    candidate.ID,
+			AccrualDate:  day,
+			Balance:      candidate.Balance,
+			RateBps:      candidate.RateBps,
+			AmountMicros: amount,
+		})
+		if err != nil {
+			return accrued, fmt.Errorf("account %d: %w", candidate.ID, err)
+		}
+
+		accrued += int(n)
+	}
+
+	return accrued, nil
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyInterest(t *testing.T) {
+	testCases := []struct {
+		balance int64
+		rateBps int32
+		micros  int64
+	}{
+		// $1,000.00 at 1.50%: 100000 * 0.015 / 365 cents
+		{balance: 100_000, rateBps: 150, micros: 4_109_589},
+		{balance: 1, rateBps: 100, micros: 27},
+		{balance: 999, rateBps: 1, micros: 274},
+		{balance: 12_345_678, rateBps: 425, micros: 1_437_510_452},
+		{balance: 0, rateBps: 150, micros: 0},
+		{balance: 100_000, rateBps: 0, micros: 0},
+	}
+
+	for _, tc := range testCases {
+		micros, err := DailyInterest(tc.balance, tc.rateBps)
+		require.NoError(t, err)
+		require.Equal(t, tc.micros, micros, "balance %d at %d bps", tc.balance, tc.rateBps)
+	}
+}
+
+func TestAccrueAndPostInterest(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	user := createRandomUser(t)
+
+	savings, err := testQueries.CreateAccount(ctx, CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  100_000,
+		Currency: util.USD,
+		Product:  ProductSavings,
+	})
+	require.NoError(t, err)
+
+	rate, err := testQueries.GetInterestRate(ctx, GetInterestRateParams{
+		Product:       ProductSavings,
+		Currency:      util.USD,
+		EffectiveFrom: time.Now(),
+	})
+	require.NoError(t, err)
+
+	daily, err := DailyInterest(savings.Balance, rate.RateBps)
+	require.NoError(t, err)
+
+	// accrue for the days since the account was opened, twice to show a
+	// rerun doesn't accrue again
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i := 0; i < 2; i++ {
+		_, err := AccrueInterest(ctx, testQueries, today)
+		require.NoError(t, err)
+	}
+
+	unposted, err := testQueries.GetUnpostedInterest(ctx, GetUnpostedInterestParams{
+		AccountID:   savings.ID,
+		AccrualDate: today.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unposted.Days)
+	require.Equal(t, daily, unposted.AccruedMicros)
+
+	result, err := store.PostInterestTx(ctx, PostInterestTxParams{
+		AccountID: savings.ID,
+		PeriodEnd: today.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	require.Equal(t, daily/MicrosPerMinorUnit, result.Posting.Amount)
+	require.Equal(t, daily, result.Posting.AccruedMicros)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, savings.Balance+result.Posting.Amount, result.Transfer.ToAccount.Balance)
+
+	// the fraction of a cent is carried, and nothing is left to post
+	unposted, err = testQueries.GetUnpostedInterest(ctx, GetUnpostedInterestParams{
+		AccountID:   savings.ID,
+		AccrualDate: today.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	require.Zero(t, unposted.Days)
+
+	last, err := testQueries.GetLastInterestPosting(ctx, savings.ID)
+	require.NoError(t, err)
+	require.Equal(t, daily%MicrosPerMinorUnit, last.AccruedMicros-last.Amount*MicrosPerMinorUnit)
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	limits := TransferLimits{
+		MaxPerTransfer: 60,
+		AccountDaily:   100,
+	}
+
+	_, err := store.TransferTx(ctx, TransferTxParams{FromAccID: account1.ID, ToAccID: account2.ID, Amount: 70, Limits: limits})
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitPerTransfer, limitErr.Limit)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: account1.ID, ToAccID: account2.ID, Amount: 60, Limits: limits})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: account1.ID, ToAccID: account2.ID, Amount: 50, Limits: limits})
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitAccountDaily, limitErr.Limit)
+	require.Equal(t, int64(40), limitErr.Remaining)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccID: account1.ID, ToAccID: account2.ID, Amount: 40, Limits: limits})
+	require.NoError(t, err)
+
+	account, err := testQueries.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-100, account.Balance)
+}
+
+func TestNewLimitUsage(t *testing.T) {
+	require.Equal(t, LimitUsage{Limit: 100, Used: 30, Remaining: 70}, newLimitUsage(100, 30))
+	require.Equal(t, LimitUsage{Limit: 100, Used: 130}, newLimitUsage(100, 130))
+	require.Equal(t, LimitUsage{Used: 30}, newLimitUsage(0, 30))
+}
@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
@@ -14,6 +16,81 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// active, frozen or closed
+	Status   string       `json:"status"`
+	ClosedAt sql.NullTime `json:"closed_at"`
+	// debit or full while the account is frozen, empty otherwise
+	FreezeMode string `json:"freeze_mode"`
+	Product    string `json:"product"`
+	// name given by the owner, unique among their open accounts of a product and currency
+	Nickname string `json:"nickname"`
+}
+
+type AccountFreeze struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// debit blocks outgoing money, full blocks both directions
+	Mode       string         `json:"mode"`
+	ReasonCode string         `json:"reason_code"`
+	Note       string         `json:"note"`
+	FrozenBy   string         `json:"frozen_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	LiftedBy   sql.NullString `json:"lifted_by"`
+	LiftedAt   sql.NullTime   `json:"lifted_at"`
+}
+
+type AccountProduct struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// open accounts of the product a user can hold in each currency
+	MaxAccounts int32 `json:"max_accounts"`
+	// transfers out of an account per calendar month, 0 for no limit
+	MaxMonthlyWithdrawals int32     `json:"max_monthly_withdrawals"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type ApiKey struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+	// public part of the key used for lookup
+	Prefix string `json:"prefix"`
+	// sha256 of the secret part of the key
+	HashedKey  string       `json:"hashed_key"`
+	Scopes     []string     `json:"scopes"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID         int64  `json:"id"`
+	Actor      string `json:"actor"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	RequestID  string `json:"request_id"`
+	Ip         string `json:"ip"`
+	// state of the target before the change, stored as json to keep the hashed text
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+	// hash of the previous event, empty for the first one
+	PrevHash string `json:"prev_hash"`
+	// sha256 over prev_hash and the fields of this event
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Currency struct {
+	// ISO 4217 code
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// number of decimal places, e.g. 2 for USD
+	MinorUnits int32 `json:"minor_units"`
+	// accounts can only be opened and used in enabled currencies
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Entry struct {
@@ -22,6 +99,352 @@ type Entry struct {
 	// can be postivie or negative
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer that created the entry
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	// transfer_debit, transfer_credit, adjustment or fee
+	EntryType string `json:"entry_type"`
+}
+
+type ExternalBeneficiary struct {
+	ID       int64  `json:"id"`
+	Owner    string `json:"owner"`
+	Nickname string `json:"nickname"`
+	// holder of the account as the receiving bank knows them
+	Name string `json:"name"`
+	// IBAN in electronic format, empty for accounts given by account_number
+	Iban string `json:"iban"`
+	// account number at the bank given by bic or aba_routing, for countries without IBANs
+	AccountNumber string `json:"account_number"`
+	Bic           string `json:"bic"`
+	// US routing transit number of the receiving bank
+	AbaRouting string    `json:"aba_routing"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Fee struct {
+	ID         int64  `json:"id"`
+	AccountID  int64  `json:"account_id"`
+	FeeType    string `json:"fee_type"`
+	ScheduleID int64  `json:"schedule_id"`
+	Amount     int64  `json:"amount"`
+	// transfer the fee was charged on, for transfer fees
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	// first day of the month a maintenance fee is for
+	PeriodStart sql.NullTime `json:"period_start"`
+	// entry debiting the account
+	EntryID int64 `json:"entry_id"`
+	// entry crediting the fee revenue account
+	RevenueEntryID int64     `json:"revenue_entry_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type FeeSchedule struct {
+	ID int64 `json:"id"`
+	// transfer fees are charged on each outgoing transfer, maintenance fees once a month
+	FeeType  string `json:"fee_type"`
+	Product  string `json:"product"`
+	Currency string `json:"currency"`
+	// flat, percentage or tiered
+	Method string `json:"method"`
+	// fee in minor units for the flat method, added to the percentage for the others
+	FlatAmount int64 `json:"flat_amount"`
+	// percentage of the amount in basis points
+	RateBps int32 `json:"rate_bps"`
+	// smallest fee charged, 0 for none
+	MinAmount int64 `json:"min_amount"`
+	// largest fee charged, 0 for no cap
+	MaxAmount int64 `json:"max_amount"`
+	// for the tiered method, [{"up_to": 100000, "flat_amount": 50, "rate_bps": 0}, ...] in ascending order, the last tier without up_to
+	Tiers     json.RawMessage `json:"tiers"`
+	UpdatedBy string          `json:"updated_by"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type InterestAccrual struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	// balance at the end of the day
+	Balance int64 `json:"balance"`
+	RateBps int32 `json:"rate_bps"`
+	// interest for the day in millionths of a minor unit
+	AmountMicros int64 `json:"amount_micros"`
+	// posting that paid the interest, null while it is accrued but unposted
+	PostingID sql.NullInt64 `json:"posting_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type InterestPosting struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// the posting covers accruals before this day
+	PeriodEnd time.Time `json:"period_end"`
+	// accrued interest in millionths of a minor unit, including what the previous posting carried over
+	AccruedMicros int64 `json:"accrued_micros"`
+	// whole minor units credited, the rest is carried over to the next posting
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type InterestRate struct {
+	ID       int64  `json:"id"`
+	Product  string `json:"product"`
+	Currency string `json:"currency"`
+	// annual rate in basis points, e.g. 150 for 1.50%
+	RateBps int32 `json:"rate_bps"`
+	// the rate applies from this day until the next rate of the product and currency
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type OauthAuthorizationCode struct {
+	HashedCode  string `json:"hashed_code"`
+	ConsentID   int64  `json:"consent_id"`
+	RedirectUri string `json:"redirect_uri"`
+	// PKCE S256 code challenge
+	CodeChallenge string       `json:"code_challenge"`
+	ExpiresAt     time.Time    `json:"expires_at"`
+	UsedAt        sql.NullTime `json:"used_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type OauthClient struct {
+	ClientID string `json:"client_id"`
+	// empty for public clients, which rely on PKCE only
+	HashedSecret string    `json:"hashed_secret"`
+	Name         string    `json:"name"`
+	Owner        string    `json:"owner"`
+	RedirectUris []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OauthConsent struct {
+	ID       int64    `json:"id"`
+	Username string   `json:"username"`
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes"`
+	// accounts the client may access on behalf of the user
+	AccountIds []int64      `json:"account_ids"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type OutboundPayment struct {
+	ID int64 `json:"id"`
+	// transfer from the customer to the external settlement account
+	TransferID int64 `json:"transfer_id"`
+	// customer account the payment was debited from
+	AccountID     int64         `json:"account_id"`
+	BeneficiaryID sql.NullInt64 `json:"beneficiary_id"`
+	Amount        int64         `json:"amount"`
+	Currency      string        `json:"currency"`
+	// beneficiary details as they were when the payment was made
+	CreditorName          string `json:"creditor_name"`
+	CreditorIban          string `json:"creditor_iban"`
+	CreditorAccountNumber string `json:"creditor_account_number"`
+	CreditorBic           string `json:"creditor_bic"`
+	CreditorAbaRouting    string `json:"creditor_aba_routing"`
+	RemittanceInfo        string `json:"remittance_info"`
+	// identifies the payment in the pain.001 export and the pain.002 status reports
+	EndToEndID string `json:"end_to_end_id"`
+	// initiated, sent once exported, then settled or returned
+	Status       string        `json:"status"`
+	ExportID     sql.NullInt64 `json:"export_id"`
+	ReturnReason string        `json:"return_reason"`
+	// transfer from the settlement account crediting a returned payment back
+	ReturnTransferID sql.NullInt64 `json:"return_transfer_id"`
+	CreatedAt        time.Time     `json:"created_at"`
+	SentAt           sql.NullTime  `json:"sent_at"`
+	SettledAt        sql.NullTime  `json:"settled_at"`
+	ReturnedAt       sql.NullTime  `json:"returned_at"`
+}
+
+type PasswordHistory struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// a password the user has replaced
+	HashedPassword string    `json:"hashed_password"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	// sha256 of the token emailed to the user
+	HashedToken string       `json:"hashed_token"`
+	Username    string       `json:"username"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	UsedAt      sql.NullTime `json:"used_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type Payee struct {
+	ID        int64  `json:"id"`
+	Owner     string `json:"owner"`
+	Nickname  string `json:"nickname"`
+	AccountID int64  `json:"account_id"`
+	// set when the owner confirmed who holds the account
+	VerifiedAt sql.NullTime `json:"verified_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type PaymentExport struct {
+	ID        int64  `json:"id"`
+	MessageID string `json:"message_id"`
+	Payments  int32  `json:"payments"`
+	// pain.001 credit transfer initiation sent to the correspondent bank
+	Document  []byte    `json:"document"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	// sent until every payment is settled or returned, then settled, returned or partially_settled
+	Status   string `json:"status"`
+	Settled  int32  `json:"settled"`
+	Returned int32  `json:"returned"`
+	// when the last payment of the export got a final status
+	CompletedAt sql.NullTime `json:"completed_at"`
+}
+
+type PaymentRequest struct {
+	ID        int64  `json:"id"`
+	Requester string `json:"requester"`
+	// account the payment is made to
+	RequesterAccountID int64  `json:"requester_account_id"`
+	Payer              string `json:"payer"`
+	Amount             int64  `json:"amount"`
+	Currency           string `json:"currency"`
+	Memo               string `json:"memo"`
+	// pending, paid or declined; a pending request past expires_at can no longer be paid
+	Status string `json:"status"`
+	// transfer that paid the request
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ResolvedAt sql.NullTime  `json:"resolved_at"`
+}
+
+type ReconciliationDiscrepancy struct {
+	ID    int64 `json:"id"`
+	RunID int64 `json:"run_id"`
+	// account_drift when an account balance differs from the sum of its entries, ledger_imbalance when the internal entries of a currency don't add up to 0
+	Kind string `json:"kind"`
+	// drifted account, NULL for ledger imbalances
+	AccountID sql.NullInt64 `json:"account_id"`
+	Currency  string        `json:"currency"`
+	// sum of the account entries, or 0 for ledger imbalances
+	Expected int64 `json:"expected"`
+	// account balance, or the sum of the internal entries for ledger imbalances
+	Actual int64 `json:"actual"`
+}
+
+type ReconciliationRun struct {
+	ID              int64        `json:"id"`
+	AccountsChecked int64        `json:"accounts_checked"`
+	Discrepancies   int64        `json:"discrepancies"`
+	StartedAt       time.Time    `json:"started_at"`
+	FinishedAt      sql.NullTime `json:"finished_at"`
+}
+
+type ReconciliationTotal struct {
+	RunID    int64  `json:"run_id"`
+	Currency string `json:"currency"`
+	Accounts int64  `json:"accounts"`
+	// sum of the balances of the accounts in the currency
+	BalanceTotal int64 `json:"balance_total"`
+	// sum of the entries of those accounts, equal to balance_total when the ledger is sound
+	EntryTotal int64 `json:"entry_total"`
+	// sum of the transfer and fee entries, which move money between accounts and so add up to 0
+	InternalTotal int64 `json:"internal_total"`
+}
+
+type StatementImport struct {
+	ID int64 `json:"id"`
+	// account whose ledger the statement is matched against, e.g. the settlement account of the partner bank
+	AccountID int64 `json:"account_id"`
+	// csv, ofx or camt053
+	Format   string `json:"format"`
+	Filename string `json:"filename"`
+	// username of the banker, or _system when imported by the command
+	ImportedBy string    `json:"imported_by"`
+	Lines      int64     `json:"lines"`
+	Matched    int64     `json:"matched"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type StatementLine struct {
+	ID       int64     `json:"id"`
+	ImportID int64     `json:"import_id"`
+	Position int32     `json:"position"`
+	BookedOn time.Time `json:"booked_on"`
+	// positive for credits to the account, negative for debits
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Reference   string `json:"reference"`
+	Description string `json:"description"`
+	// entry the line was matched to, NULL while unmatched
+	EntryID sql.NullInt64 `json:"entry_id"`
+	// transfer of the matched entry
+	TransferID sql.NullInt64 `json:"transfer_id"`
+}
+
+type SystemAccount struct {
+	// what the bank uses the account for, e.g. interest_expense
+	Purpose   string `json:"purpose"`
+	Currency  string `json:"currency"`
+	AccountID int64  `json:"account_id"`
+}
+
+type TransferBatchItem struct {
+	ID      int64 `json:"id"`
+	BatchID int64 `json:"batch_id"`
+	// place of the transfer in the request, from 1
+	Position      int32           `json:"position"`
+	FromAccountID int64           `json:"from_account_id"`
+	ToAccountID   int64           `json:"to_account_id"`
+	Amount        int64           `json:"amount"`
+	Currency      string          `json:"currency"`
+	Memo          string          `json:"memo"`
+	Reference     string          `json:"reference"`
+	Metadata      json.RawMessage `json:"metadata"`
+	// pending, completed, failed or held
+	Status     string        `json:"status"`
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	// review the risk rules held the transfer for
+	ReviewID sql.NullInt64 `json:"review_id"`
+	// why the transfer failed
+	Error string `json:"error"`
+}
+
+type TransferBatch struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	// atomic makes every transfer or none, best_effort makes each on its own
+	Mode string `json:"mode"`
+	// processing, completed, partially_completed or failed
+	Status      string       `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	CompletedAt sql.NullTime `json:"completed_at"`
+}
+
+type TransferReview struct {
+	ID            int64  `json:"id"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	RequestedBy   string `json:"requested_by"`
+	// risk rules that held the transfer
+	Rules []string `json:"rules"`
+	// pending, approved or rejected
+	Status     string         `json:"status"`
+	ReviewedBy sql.NullString `json:"reviewed_by"`
+	ReviewedAt sql.NullTime   `json:"reviewed_at"`
+	// transfer made when the review was approved
+	TransferID sql.NullInt64   `json:"transfer_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Memo       string          `json:"memo"`
+	Reference  string          `json:"reference"`
+	Metadata   json.RawMessage `json:"metadata"`
 }
 
 type Transfer struct {
@@ -31,6 +454,12 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// what the payment was for, shown to both sides
+	Memo string `json:"memo"`
+	// reference given by the sender, e.g. an invoice number
+	Reference string `json:"reference"`
+	// free-form JSON object supplied by the client
+	Metadata json.RawMessage `json:"metadata"`
 }
 
 type User struct {
@@ -40,4 +469,7 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	// depositor or banker
+	Role          string       `json:"role"`
+	DeactivatedAt sql.NullTime `json:"deactivated_at"`
 }
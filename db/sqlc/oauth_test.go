@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOAuthClient(t *testing.T, owner string) OauthClient {
+	arg := CreateOAuthClientParams{
+		ClientID:     util.RandomString(16),
+		Name:         util.RandomString(6),
+		Owner:        owner,
+		RedirectUris: []string{"https://partner.example.com/callback"},
+		Scopes:       []string{"accounts:read", "transfers:read"},
+	}
+
+	client, err := testQueries.CreateOAuthClient(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, client)
+
+	require.Equal(t, arg.ClientID, client.ClientID)
+	require.Empty(t, client.HashedSecret)
+	require.Equal(t, arg.Name, client.Name)
+	require.Equal(t, arg.Owner, client.Owner)
+	require.Equal(t, arg.RedirectUris, client.RedirectUris)
+	require.Equal(t, arg.Scopes, client.Scopes)
+	require.NotZero(t, client.CreatedAt)
+
+	return client
+}
+
+func TestGetOAuthClient(t *testing.T) {
+	user := createRandomUser(t)
+	client1 := createRandomOAuthClient(t, user.Username)
+
+	client2, err := testQueries.GetOAuthClient(context.Background(), client1.ClientID)
+	require.NoError(t, err)
+	require.Equal(t, client1.ClientID, client2.ClientID)
+	require.Equal(t, client1.RedirectUris, client2.RedirectUris)
+	require.Equal(t, client1.Scopes, client2.Scopes)
+}
+
+func TestOAuthAuthorizeTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := createRandomUser(t)
+	client := createRandomOAuthClient(t, user.Username)
+	account := createRandomAccount(t)
+
+	arg := OAuthAuthorizeTxParams{
+		Username:      user.Username,
+		ClientID:      client.ClientID,
+		Scopes:        []string{"accounts:read"},
+		AccountIDs:    []int64{account.ID},
+		HashedCode:    util.HashSecret(util.RandomString(32)),
+		RedirectUri:   client.RedirectUris[0],
+		CodeChallenge: util.RandomString(43),
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+
+	result, err := store.OAuthAuthorizeTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	consent := result.Consent
+	require.NotZero(t, consent.ID)
+	require.Equal(t, arg.Username, consent.Username)
+	require.Equal(t, arg.ClientID, consent.ClientID)
+	require.Equal(t, arg.Scopes, consent.Scopes)
+	require.Equal(t, arg.AccountIDs, consent.AccountIds)
+	require.False(t, consent.RevokedAt.Valid)
+
+	code := result.AuthorizationCode
+	require.Equal(t, arg.HashedCode, code.HashedCode)
+	require.Equal(t, consent.ID, code.ConsentID)
+	require.False(t, code.UsedAt.Valid)
+
+	// an authorization code can only be exchanged once
+	used, err := testQueries.ConsumeOAuthAuthorizationCode(context.Background(), arg.HashedCode)
+	require.NoError(t, err)
+	require.True(t, used.UsedAt.Valid)
+
+	_, err = testQueries.ConsumeOAuthAuthorizationCode(context.Background(), arg.HashedCode)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	consents, err := testQueries.ListOAuthConsents(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, consents, 1)
+
+	revoked, err := testQueries.RevokeOAuthConsent(context.Background(), RevokeOAuthConsentParams{
+		ID:       consent.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+
+	consents, err = testQueries.ListOAuthConsents(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, consents)
+}
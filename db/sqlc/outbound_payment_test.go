@@ -0,0 +1,325 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomExternalBeneficiary(t *testing.T, owner string) ExternalBeneficiary {
+	arg := CreateExternalBeneficiaryParams{
+		Owner:    owner,
+		Nickname: util.RandomOwner(),
+		Name:     util.RandomOwner(),
+		Iban:     "GB82WEST12345698765432",
+	}
+
+	beneficiary, err := testQueries.CreateExternalBeneficiary(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, beneficiary.Owner)
+	require.Equal(t, arg.Nickname, beneficiary.Nickname)
+	require.Equal(t, arg.Iban, beneficiary.Iban)
+	require.NotZero(t, beneficiary.CreatedAt)
+
+	return beneficiary
+}
+
+func TestCreateExternalBeneficiaryAccount(t *testing.T) {
+	user := createRandomUser(t)
+
+	// either an IBAN or an account number with its bank is required
+	_, err := testQueries.CreateExternalBeneficiary(context.Background(), CreateExternalBeneficiaryParams{
+		Owner:         user.Username,
+		Nickname:      util.RandomOwner(),
+		Name:          util.RandomOwner(),
+		AccountNumber: "000123456789",
+	})
+	require.Error(t, err)
+
+	beneficiary, err := testQueries.CreateExternalBeneficiary(context.Background(), CreateExternalBeneficiaryParams{
+		Owner:         user.Username,
+		Nickname:      util.RandomOwner(),
+		Name:          util.RandomOwner(),
+		AccountNumber: "000123456789",
+		AbaRouting:    "021000021",
+	})
+	require.NoError(t, err)
+	require.Empty(t, beneficiary.Iban)
+}
+
+// createTestOutboundPayment transfers amount from account to a new
+// beneficiary of its owner
+func createTestOutboundPayment(t *testing.T, store Store, account Account, amount int64) (TransferTxResult, ExternalBeneficiary) {
+	ctx := context.Background()
+	beneficiary := createRandomExternalBeneficiary(t, account.Owner)
+
+	settlement, err := testQueries.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountExternalSettlement,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccID:     account.ID,
+		Amount:        amount,
+		Memo:          "Invoice 1",
+		BeneficiaryID: beneficiary.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, settlement.AccountID, result.Transfer.ToAccountID)
+	require.NotNil(t, result.OutboundPayment)
+
+	return result, beneficiary
+}
+
+func TestTransferTxToBeneficiary(t *testing.T) {
+	store := NewStore(testDB)
+	account := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+
+	result, beneficiary := createTestOutboundPayment(t, store, account, 100)
+	require.Equal(t, account.Balance-100, result.FromAccount.Balance)
+
+	payment := *result.OutboundPayment
+	require.Equal(t, OutboundPaymentInitiated, payment.Status)
+	require.Equal(t, result.Transfer.ID, payment.TransferID)
+	require.Equal(t, account.ID, payment.AccountID)
+	require.Equal(t, beneficiary.ID, payment.BeneficiaryID.Int64)
+	require.Equal(t, int64(100), payment.Amount)
+	require.Equal(t, beneficiary.Name, payment.CreditorName)
+	require.Equal(t, beneficiary.Iban, payment.CreditorIban)
+	require.Equal(t, "Invoice 1", payment.RemittanceInfo)
+	require.Equal(t, OutboundEndToEndID(result.Transfer.ID), payment.EndToEndID)
+
+	// the payment keeps the details it was made with
+	_, err := testQueries.DeleteExternalBeneficiary(context.Background(), DeleteExternalBeneficiaryParams{
+		ID:    beneficiary.ID,
+		Owner: account.Owner,
+	})
+	require.NoError(t, err)
+
+	got, err := testQueries.GetOutboundPayment(context.Background(), payment.ID)
+	require.NoError(t, err)
+	require.False(t, got.BeneficiaryID.Valid)
+	require.Equal(t, beneficiary.Iban, got.CreditorIban)
+}
+
+func TestTransferTxToOtherOwnersBeneficiary(t *testing.T) {
+	store := NewStore(testDB)
+	account := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+	beneficiary := createRandomExternalBeneficiary(t, createRandomUser(t).Username)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccID:     account.ID,
+		Amount:        100,
+		BeneficiaryID: beneficiary.ID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestOutboundPaymentLifecycle(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	account := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+
+	settled, _ := createTestOutboundPayment(t, store, account, 100)
+	rejected, _ := createTestOutboundPayment(t, store, account, 200)
+
+	// settling waits for the payment to be sent
+	_, err := store.SettleOutboundPaymentTx(ctx, SettleOutboundPaymentTxParams{ID: settled.OutboundPayment.ID})
+	require.ErrorIs(t, err, ErrOutboundPaymentNotSent)
+
+	var exported []OutboundPayment
+	export, err := store.ExportOutboundPaymentsTx(ctx, ExportOutboundPaymentsTxParams{
+		MaxPayments: 10_000,
+		CreatedBy:   account.Owner,
+		Document: func(messageID string, payments []OutboundPayment) ([]byte, error) {
+			exported = payments
+			return []byte("<Document/>"), nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(len(exported)), export.Export.Payments)
+	require.Equal(t, []byte("<Document/>"), export.Export.Document)
+	require.Equal(t, PaymentExportSent, export.Export.Status)
+
+	endToEndIDs, err := testQueries.ListExportedEndToEndIDs(ctx, export.Export.MessageID)
+	require.NoError(t, err)
+	require.Contains(t, endToEndIDs, settled.OutboundPayment.EndToEndID)
+	require.Contains(t, endToEndIDs, rejected.OutboundPayment.EndToEndID)
+
+	for _, payment := range export.Payments {
+		require.Equal(t, OutboundPaymentSent, payment.Status)
+		require.Equal(t, export.Export.ID, payment.ExportID.Int64)
+	}
+
+	// nothing is exported twice
+	_, err = store.ExportOutboundPaymentsTx(ctx, ExportOutboundPaymentsTxParams{
+		MaxPayments: 10_000,
+		CreatedBy:   account.Owner,
+		Document: func(messageID string, payments []OutboundPayment) ([]byte, error) {
+			return nil, nil
+		},
+	})
+	require.ErrorIs(t, err, ErrNoOutboundPayments)
+
+	report := ApplyPaymentStatusReportTxParams{
+		Statuses: []OutboundPaymentStatus{
+			{EndToEndID: settled.OutboundPayment.EndToEndID, Status: OutboundPaymentSettled},
+			{EndToEndID: rejected.OutboundPayment.EndToEndID, Status: OutboundPaymentReturned, Reason: "AC04"},
+			{EndToEndID: "UNKNOWN", Status: OutboundPaymentSettled},
+		},
+	}
+
+	result, err := store.ApplyPaymentStatusReportTx(ctx, report)
+	require.NoError(t, err)
+	require.Len(t, result.Settled, 1)
+	require.True(t, result.Settled[0].SettledAt.Valid)
+	require.Len(t, result.Returned, 1)
+	require.Equal(t, "AC04", result.Returned[0].ReturnReason)
+	require.True(t, result.Returned[0].ReturnTransferID.Valid)
+	require.Equal(t, []string{"UNKNOWN"}, result.Ignored)
+
+	// the export counts its settled and returned payments, and stays sent
+	// while others it took in are still waiting for a report
+	got, err := testQueries.GetPaymentExport(ctx, export.Export.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), got.Settled)
+	require.Equal(t, int32(1), got.Returned)
+	require.Equal(t, PaymentExportStatus(got.Payments, 1, 1), got.Status)
+	require.Equal(t, got.Status != PaymentExportSent, got.CompletedAt.Valid)
+
+	// the rejected payment is credited back
+	gotAccount, err := testQueries.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-100, gotAccount.Balance)
+
+	// applying the report again changes nothing
+	result, err = store.ApplyPaymentStatusReportTx(ctx, report)
+	require.NoError(t, err)
+	require.Empty(t, result.Settled)
+	require.Empty(t, result.Returned)
+	require.Len(t, result.Ignored, 3)
+
+	_, err = store.ReturnOutboundPaymentTx(ctx, ReturnOutboundPaymentTxParams{
+		ID:     rejected.OutboundPayment.ID,
+		Reason: "again",
+	})
+	require.ErrorIs(t, err, ErrOutboundPaymentReturned)
+
+	// a settled payment is returned when the receiving bank sends it back
+	returned, err := store.ReturnOutboundPaymentTx(ctx, ReturnOutboundPaymentTxParams{
+		ID:     settled.OutboundPayment.ID,
+		Reason: "Beneficiary account closed",
+	})
+	require.NoError(t, err)
+	require.Equal(t, OutboundPaymentReturned, returned.Payment.Status)
+	require.Equal(t, account.Balance, returned.Transfer.ToAccount.Balance)
+	require.Equal(t, settled.OutboundPayment.EndToEndID, returned.Transfer.Transfer.Reference)
+
+	got, err = testQueries.GetPaymentExport(ctx, export.Export.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.Settled)
+	require.Equal(t, int32(2), got.Returned)
+}
+
+func TestReturnSettledOutboundPaymentFee(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	upsertTestFeeSchedule(t, FeeTypeTransfer, 25)
+	account := createTestSavingsAccount(t, 1_000)
+
+	payment, _ := createTestOutboundPayment(t, store, account, 100)
+	require.Len(t, payment.Fees, 1)
+	require.Equal(t, account.Balance-100-25, payment.FromAccount.Balance)
+
+	_, err := store.ExportOutboundPaymentsTx(ctx, ExportOutboundPaymentsTxParams{
+		MaxPayments: 10_000,
+		CreatedBy:   account.Owner,
+		Document: func(messageID string, payments []OutboundPayment) ([]byte, error) {
+			return []byte("<Document/>"), nil
+		},
+	})
+	require.NoError(t, err)
+
+	settled, err := store.SettleOutboundPaymentTx(ctx, SettleOutboundPaymentTxParams{ID: payment.OutboundPayment.ID})
+	require.NoError(t, err)
+	require.Equal(t, OutboundPaymentSettled, settled.Status)
+
+	// the receiving bank sends the money back after settling, and the fee
+	// is refunded with it
+	returned, err := store.ReturnOutboundPaymentTx(ctx, ReturnOutboundPaymentTxParams{
+		ID:     payment.OutboundPayment.ID,
+		Reason: "AC04",
+	})
+	require.NoError(t, err)
+	require.Equal(t, OutboundPaymentReturned, returned.Payment.Status)
+	require.Equal(t, account.Balance, returned.Transfer.ToAccount.Balance)
+	require.Len(t, returned.Transfer.Fees, 1)
+
+	refund := returned.Transfer.Fees[0]
+	require.Equal(t, int64(-25), refund.Amount)
+	require.Equal(t, payment.Transfer.ID, refund.TransferID.Int64)
+
+	got, err := testQueries.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, got.Balance)
+
+	revenue, err := testQueries.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountFeeRevenue,
+		Currency: util.CAD,
+	})
+	require.NoError(t, err)
+
+	revenueEntry, err := testQueries.GetEntry(ctx, refund.RevenueEntryID)
+	require.NoError(t, err)
+	require.Equal(t, revenue.AccountID, revenueEntry.AccountID)
+	require.Equal(t, int64(-25), revenueEntry.Amount)
+
+	// the fees of the transfer add up to nothing
+	fees, err := testQueries.ListFeesByTransfer(ctx, sql.NullInt64{Int64: payment.Transfer.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, fees, 2)
+	require.Zero(t, fees[0].Amount+fees[1].Amount)
+}
+
+func TestPaymentExportStatus(t *testing.T) {
+	require.Equal(t, PaymentExportSent, PaymentExportStatus(3, 1, 1))
+	require.Equal(t, PaymentExportSettled, PaymentExportStatus(3, 3, 0))
+	require.Equal(t, PaymentExportReturned, PaymentExportStatus(3, 0, 3))
+	require.Equal(t, PaymentExportPartiallySettled, PaymentExportStatus(3, 2, 1))
+}
+
+func TestReturnOutboundPaymentToFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	banker := createRandomUser(t)
+	account := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+
+	payment, _ := createTestOutboundPayment(t, store, account, 100)
+
+	_, err := store.FreezeAccountTx(ctx, FreezeAccountTxParams{
+		AccountID:  account.ID,
+		Mode:       FreezeModeFull,
+		ReasonCode: "fraud",
+		FrozenBy:   banker.Username,
+	})
+	require.NoError(t, err)
+
+	// the money is the customer's, so it is credited back to the frozen
+	// account rather than failing the report it came in
+	result, err := store.ApplyPaymentStatusReportTx(ctx, ApplyPaymentStatusReportTxParams{
+		Statuses: []OutboundPaymentStatus{
+			{EndToEndID: payment.OutboundPayment.EndToEndID, Status: OutboundPaymentReturned, Reason: "AC04"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Returned, 1)
+
+	got, err := testQueries.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, got.Status)
+	require.Equal(t, account.Balance, got.Balance)
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordResetToken(t *testing.T, username string) PasswordResetToken {
+	arg := CreatePasswordResetTokenParams{
+		HashedToken: util.HashSecret(util.RandomString(32)),
+		Username:    username,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	resetToken, err := testQueries.CreatePasswordResetToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.HashedToken, resetToken.HashedToken)
+	require.Equal(t, arg.Username, resetToken.Username)
+	require.WithinDuration(t, arg.ExpiresAt, resetToken.ExpiresAt, time.Second)
+	require.False(t, resetToken.UsedAt.Valid)
+
+	return resetToken
+}
+
+func TestUpdatePasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	resetToken := createRandomPasswordResetToken(t, user.Username)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+
+	err = store.UpdatePasswordTx(context.Background(), UpdatePasswordTxParams{
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+		HashedPassword:    hashedPassword,
+	})
+	require.NoError(t, err)
+
+	updated, err := testQueries.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, updated.HashedPassword)
+	require.WithinDuration(t, time.Now(), updated.PasswordChangedAt, time.Second)
+
+	history, err := testQueries.ListPasswordHistory(context.Background(), ListPasswordHistoryParams{
+		Username: user.Username,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, user.HashedPassword, history[0].HashedPassword)
+
+	// changing the password invalidates outstanding reset tokens
+	_, err = testQueries.GetPasswordResetToken(context.Background(), resetToken.HashedToken)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	resetToken := createRandomPasswordResetToken(t, user.Username)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+
+	arg := ResetPasswordTxParams{
+		HashedToken: resetToken.HashedToken,
+		UpdatePasswordTxParams: UpdatePasswordTxParams{
+			Username:          user.Username,
+			OldHashedPassword: user.HashedPassword,
+			HashedPassword:    hashedPassword,
+		},
+	}
+
+	err = store.ResetPasswordTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	updated, err := testQueries.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, updated.HashedPassword)
+
+	// reset tokens are single use
+	err = store.ResetPasswordTx(context.Background(), arg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPayee(t *testing.T, owner string) Payee {
+	account := createRandomAccount(t)
+
+	arg := CreatePayeeParams{
+		Owner:     owner,
+		Nickname:  util.RandomOwner(),
+		AccountID: account.ID,
+	}
+
+	payee, err := testQueries.CreatePayee(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, payee.Owner)
+	require.Equal(t, arg.Nickname, payee.Nickname)
+	require.Equal(t, arg.AccountID, payee.AccountID)
+	require.False(t, payee.VerifiedAt.Valid)
+	require.NotZero(t, payee.CreatedAt)
+
+	return payee
+}
+
+func TestCreatePayee(t *testing.T) {
+	user := createRandomUser(t)
+	payee := createRandomPayee(t, user.Username)
+
+	_, err := testQueries.CreatePayee(context.Background(), CreatePayeeParams{
+		Owner:     user.Username,
+		Nickname:  util.RandomOwner(),
+		AccountID: payee.AccountID,
+	})
+	require.Error(t, err)
+}
+
+func TestGetPayeeOtherOwner(t *testing.T) {
+	user := createRandomUser(t)
+	other := createRandomUser(t)
+	payee := createRandomPayee(t, user.Username)
+
+	got, err := testQueries.GetPayee(context.Background(), GetPayeeParams{ID: payee.ID, Owner: user.Username})
+	require.NoError(t, err)
+	require.Equal(t, payee.ID, got.ID)
+
+	_, err = testQueries.GetPayee(context.Background(), GetPayeeParams{ID: payee.ID, Owner: other.Username})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateAndDeletePayee(t *testing.T) {
+	user := createRandomUser(t)
+	payee := createRandomPayee(t, user.Username)
+	nickname := util.RandomOwner()
+
+	updated, err := testQueries.UpdatePayee(context.Background(), UpdatePayeeParams{
+		ID:       payee.ID,
+		Owner:    user.Username,
+		Nickname: nickname,
+	})
+	require.NoError(t, err)
+	require.Equal(t, nickname, updated.Nickname)
+	require.Equal(t, payee.AccountID, updated.AccountID)
+
+	_, err = testQueries.DeletePayee(context.Background(), DeletePayeeParams{ID: payee.ID, Owner: user.Username})
+	require.NoError(t, err)
+
+	_, err = testQueries.DeletePayee(context.Background(), DeletePayeeParams{ID: payee.ID, Owner: user.Username})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestListPayees(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomPayee(t, user.Username)
+	}
+
+	payees, err := testQueries.ListPayees(context.Background(), ListPayeesParams{
+		Owner:  user.Username,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, payees, 3)
+
+	for _, payee := range payees {
+		require.Equal(t, user.Username, payee.Owner)
+	}
+}
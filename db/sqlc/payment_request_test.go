@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPaymentRequest(t *testing.T, requester Account, payer string, expiresAt time.Time) PaymentRequest {
+	arg := CreatePaymentRequestParams{
+		Requester:          requester.Owner,
+		RequesterAccountID: requester.ID,
+		Payer:              payer,
+		Amount:             util.RandomInt(1, 100),
+		Currency:           requester.Currency,
+		Memo:               util.RandomString(10),
+		ExpiresAt:          expiresAt,
+	}
+
+	request, err := testQueries.CreatePaymentRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Amount, request.Amount)
+	require.Equal(t, PaymentRequestPending, request.Status)
+	require.False(t, request.TransferID.Valid)
+	require.False(t, request.ResolvedAt.Valid)
+
+	return request
+}
+
+func TestListPaymentRequests(t *testing.T) {
+	requester := createTestBatchAccount(t, createRandomUser(t).Username, 0)
+	payer := createRandomUser(t)
+
+	request := createRandomPaymentRequest(t, requester, payer.Username, time.Now().Add(time.Hour))
+
+	incoming, err := testQueries.ListIncomingPaymentRequests(context.Background(), ListIncomingPaymentRequestsParams{
+		Payer: payer.Username,
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []PaymentRequest{request}, incoming)
+
+	outgoing, err := testQueries.ListOutgoingPaymentRequests(context.Background(), ListOutgoingPaymentRequestsParams{
+		Requester: requester.Owner,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, outgoing, 1)
+	require.Equal(t, request.ID, outgoing[0].ID)
+}
+
+func TestPayPaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	requester := createTestBatchAccount(t, createRandomUser(t).Username, 0)
+	payer := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+	request := createRandomPaymentRequest(t, requester, payer.Owner, time.Now().Add(time.Hour))
+
+	result, err := store.PayPaymentRequestTx(ctx, PayPaymentRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: payer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPaid, result.Request.Status)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Request.TransferID.Int64)
+	require.True(t, result.Request.ResolvedAt.Valid)
+	require.Equal(t, payer.Balance-request.Amount, result.Transfer.FromAccount.Balance)
+	require.Equal(t, requester.Balance+request.Amount, result.Transfer.ToAccount.Balance)
+	require.Equal(t, request.Memo, result.Transfer.Transfer.Memo)
+
+	var metadata map[string]int64
+	require.NoError(t, json.Unmarshal(result.Transfer.Transfer.Metadata, &metadata))
+	require.Equal(t, request.ID, metadata["payment_request_id"])
+
+	// a request is paid once and can't be declined afterwards
+	_, err = store.PayPaymentRequestTx(ctx, PayPaymentRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: payer.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestNotPending)
+
+	_, err = testQueries.DeclinePaymentRequest(ctx, request.ID)
+	require.Error(t, err)
+}
+
+func TestPayPaymentRequestTxExpired(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	requester := createTestBatchAccount(t, createRandomUser(t).Username, 0)
+	payer := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+	request := createRandomPaymentRequest(t, requester, payer.Owner, time.Now().Add(-time.Minute))
+
+	_, err := store.PayPaymentRequestTx(ctx, PayPaymentRequestTxParams{
+		RequestID:     request.ID,
+		FromAccountID: payer.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestExpired)
+
+	after, err := testQueries.GetAccount(ctx, payer.ID)
+	require.NoError(t, err)
+	require.Equal(t, payer.Balance, after.Balance)
+}
+
+func TestDeclinePaymentRequest(t *testing.T) {
+	requester := createTestBatchAccount(t, createRandomUser(t).Username, 0)
+	request := createRandomPaymentRequest(t, requester, createRandomUser(t).Username, time.Now().Add(time.Hour))
+
+	declined, err := testQueries.DeclinePaymentRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestDeclined, declined.Status)
+	require.True(t, declined.ResolvedAt.Valid)
+	require.ErrorIs(t, declined.CheckPending(time.Now()), ErrPaymentRequestNotPending)
+}
@@ -6,29 +6,162 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	ApproveTransferReview(ctx context.Context, arg ApproveTransferReviewParams) (TransferReview, error)
+	CloseAccount(ctx context.Context, id int64) (Account, error)
+	CompleteTransferBatchItem(ctx context.Context, arg CompleteTransferBatchItemParams) (TransferBatchItem, error)
+	ConsumeOAuthAuthorizationCode(ctx context.Context, hashedCode string) (OauthAuthorizationCode, error)
+	ConsumePasswordResetToken(ctx context.Context, hashedToken string) (PasswordResetToken, error)
+	CountExportPaymentsByStatus(ctx context.Context, exportID sql.NullInt64) (CountExportPaymentsByStatusRow, error)
+	CountOpenAccounts(ctx context.Context, arg CountOpenAccountsParams) (int64, error)
+	CountTransfersBetween(ctx context.Context, arg CountTransfersBetweenParams) (int64, error)
+	CountTransfersSince(ctx context.Context, arg CountTransfersSinceParams) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountFreeze(ctx context.Context, arg CreateAccountFreezeParams) (AccountFreeze, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateExternalBeneficiary(ctx context.Context, arg CreateExternalBeneficiaryParams) (ExternalBeneficiary, error)
+	CreateFee(ctx context.Context, arg CreateFeeParams) (Fee, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (int64, error)
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error)
+	CreateInterestRate(ctx context.Context, arg CreateInterestRateParams) (InterestRate, error)
+	CreateOAuthAuthorizationCode(ctx context.Context, arg CreateOAuthAuthorizationCodeParams) (OauthAuthorizationCode, error)
+	CreateOAuthClient(ctx context.Context, arg CreateOAuthClientParams) (OauthClient, error)
+	CreateOAuthConsent(ctx context.Context, arg CreateOAuthConsentParams) (OauthConsent, error)
+	CreateOutboundPayment(ctx context.Context, arg CreateOutboundPaymentParams) (OutboundPayment, error)
+	CreatePasswordHistory(ctx context.Context, arg CreatePasswordHistoryParams) (PasswordHistory, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreatePayee(ctx context.Context, arg CreatePayeeParams) (Payee, error)
+	CreatePaymentExport(ctx context.Context, arg CreatePaymentExportParams) (PaymentExport, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreateReconciliationDiscrepancy(ctx context.Context, arg CreateReconciliationDiscrepancyParams) (ReconciliationDiscrepancy, error)
+	CreateReconciliationRun(ctx context.Context) (ReconciliationRun, error)
+	CreateReconciliationTotal(ctx context.Context, arg CreateReconciliationTotalParams) (ReconciliationTotal, error)
+	CreateStatementImport(ctx context.Context, arg CreateStatementImportParams) (StatementImport, error)
+	CreateStatementLine(ctx context.Context, arg CreateStatementLineParams) (StatementLine, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error)
+	CreateTransferBatchItem(ctx context.Context, arg CreateTransferBatchItemParams) (TransferBatchItem, error)
+	CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeactivateUser(ctx context.Context, username string) (User, error)
+	DeclinePaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteExternalBeneficiary(ctx context.Context, arg DeleteExternalBeneficiaryParams) (ExternalBeneficiary, error)
+	DeletePayee(ctx context.Context, arg DeletePayeeParams) (Payee, error)
 	DeleteUser(ctx context.Context, username string) error
+	FailTransferBatchItem(ctx context.Context, arg FailTransferBatchItemParams) (TransferBatchItem, error)
+	FindStatementMatch(ctx context.Context, arg FindStatementMatchParams) (Entry, error)
+	FinishReconciliationRun(ctx context.Context, arg FinishReconciliationRunParams) (ReconciliationRun, error)
+	FinishStatementImport(ctx context.Context, arg FinishStatementImportParams) (StatementImport, error)
+	FinishTransferBatch(ctx context.Context, arg FinishTransferBatchParams) (TransferBatch, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
-	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountProduct(ctx context.Context, code string) (AccountProduct, error)
+	GetAccountTransferTotals(ctx context.Context, arg GetAccountTransferTotalsParams) (GetAccountTransferTotalsRow, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	GetEntry(ctx context.Context, id int64) (GetEntryRow, error)
+	GetExternalBeneficiary(ctx context.Context, arg GetExternalBeneficiaryParams) (ExternalBeneficiary, error)
+	GetFeeSchedule(ctx context.Context, arg GetFeeScheduleParams) (FeeSchedule, error)
+	GetInterestRate(ctx context.Context, arg GetInterestRateParams) (InterestRate, error)
+	GetLastAuditEvent(ctx context.Context) (AuditEvent, error)
+	GetLastInterestPosting(ctx context.Context, accountID int64) (InterestPosting, error)
+	GetOAuthClient(ctx context.Context, clientID string) (OauthClient, error)
+	GetOAuthConsent(ctx context.Context, id int64) (OauthConsent, error)
+	GetOutboundPayment(ctx context.Context, id int64) (OutboundPayment, error)
+	GetOutboundPaymentByEndToEndIDForUpdate(ctx context.Context, endToEndID string) (OutboundPayment, error)
+	GetOutboundPaymentForUpdate(ctx context.Context, id int64) (OutboundPayment, error)
+	GetPasswordResetToken(ctx context.Context, hashedToken string) (PasswordResetToken, error)
+	GetPayee(ctx context.Context, arg GetPayeeParams) (Payee, error)
+	GetPaymentExport(ctx context.Context, id int64) (PaymentExport, error)
+	GetPaymentExportForUpdate(ctx context.Context, id int64) (PaymentExport, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	GetReconciliationRun(ctx context.Context, id int64) (ReconciliationRun, error)
+	GetStatementImport(ctx context.Context, id int64) (StatementImport, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (SystemAccount, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferBatch(ctx context.Context, id int64) (TransferBatch, error)
+	GetTransferBatchForUpdate(ctx context.Context, id int64) (TransferBatch, error)
+	GetTransferBatchItemForUpdate(ctx context.Context, id int64) (TransferBatchItem, error)
+	GetTransferReview(ctx context.Context, id int64) (TransferReview, error)
+	GetTransferReviewForUpdate(ctx context.Context, id int64) (TransferReview, error)
+	GetUnpostedInterest(ctx context.Context, arg GetUnpostedInterestParams) (GetUnpostedInterestRow, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserTransferTotals(ctx context.Context, arg GetUserTransferTotalsParams) (GetUserTransferTotalsRow, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error)
+	HoldTransferBatchItem(ctx context.Context, arg HoldTransferBatchItemParams) (TransferBatchItem, error)
+	InvalidatePasswordResetTokens(ctx context.Context, username string) error
+	LiftAccountFreeze(ctx context.Context, arg LiftAccountFreezeParams) (AccountFreeze, error)
+	ListAPIKeys(ctx context.Context, owner string) ([]ApiKey, error)
+	ListAccountDrift(ctx context.Context) ([]ListAccountDriftRow, error)
+	ListAccountFreezes(ctx context.Context, accountID int64) ([]AccountFreeze, error)
+	ListAccountProducts(ctx context.Context) ([]AccountProduct, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
-	ListEntryFromAccountId(ctx context.Context, arg ListEntryFromAccountIdParams) ([]Entry, error)
+	ListAccountsWithUnpostedInterest(ctx context.Context, accrualDate time.Time) ([]int64, error)
+	ListAccrualCandidates(ctx context.Context, arg ListAccrualCandidatesParams) ([]ListAccrualCandidatesRow, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListAuditEventsAfter(ctx context.Context, arg ListAuditEventsAfterParams) ([]AuditEvent, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	ListEntryFromAccountId(ctx context.Context, arg ListEntryFromAccountIdParams) ([]ListEntryFromAccountIdRow, error)
+	ListExportedEndToEndIDs(ctx context.Context, messageID string) ([]string, error)
+	ListExternalBeneficiaries(ctx context.Context, arg ListExternalBeneficiariesParams) ([]ExternalBeneficiary, error)
+	ListFeeSchedules(ctx context.Context) ([]FeeSchedule, error)
+	ListFeesByTransfer(ctx context.Context, transferID sql.NullInt64) ([]Fee, error)
+	ListIncomingPaymentRequests(ctx context.Context, arg ListIncomingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListInitiatedOutboundPaymentsForUpdate(ctx context.Context, limit int32) ([]OutboundPayment, error)
+	ListInterestRates(ctx context.Context, product string) ([]InterestRate, error)
+	ListLedgerTotals(ctx context.Context) ([]ListLedgerTotalsRow, error)
+	ListMaintenanceFeeAccounts(ctx context.Context, arg ListMaintenanceFeeAccountsParams) ([]int64, error)
+	ListOAuthConsents(ctx context.Context, username string) ([]OauthConsent, error)
+	ListOpenAccountsForUpdate(ctx context.Context, owner string) ([]Account, error)
+	ListOutboundPayments(ctx context.Context, arg ListOutboundPaymentsParams) ([]OutboundPayment, error)
+	ListOutgoingPaymentRequests(ctx context.Context, arg ListOutgoingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListPasswordHistory(ctx context.Context, arg ListPasswordHistoryParams) ([]PasswordHistory, error)
+	ListPayees(ctx context.Context, arg ListPayeesParams) ([]Payee, error)
+	ListPaymentExports(ctx context.Context, arg ListPaymentExportsParams) ([]ListPaymentExportsRow, error)
+	ListReconciliationDiscrepancies(ctx context.Context, arg ListReconciliationDiscrepanciesParams) ([]ReconciliationDiscrepancy, error)
+	ListReconciliationRuns(ctx context.Context, arg ListReconciliationRunsParams) ([]ReconciliationRun, error)
+	ListReconciliationTotals(ctx context.Context, runID int64) ([]ReconciliationTotal, error)
+	ListStatementImports(ctx context.Context, arg ListStatementImportsParams) ([]StatementImport, error)
+	ListTransferBatchItems(ctx context.Context, batchID int64) ([]TransferBatchItem, error)
+	ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error)
 	ListTransfersFromAccountId(ctx context.Context, arg ListTransfersFromAccountIdParams) ([]ListTransfersFromAccountIdRow, error)
-	SeachEntriesByAccountOwner(ctx context.Context, arg SeachEntriesByAccountOwnerParams) ([]Entry, error)
+	ListUnmatchedStatementLines(ctx context.Context, importID int64) ([]StatementLine, error)
+	LockAuditChain(ctx context.Context) error
+	LockUserAccounts(ctx context.Context, owner string) error
+	LockUserTransfers(ctx context.Context, owner string) error
+	MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) error
+	MarkOutboundPaymentsSent(ctx context.Context, arg MarkOutboundPaymentsSentParams) error
+	PayPaymentRequest(ctx context.Context, arg PayPaymentRequestParams) (PaymentRequest, error)
+	RejectTransferReview(ctx context.Context, arg RejectTransferReviewParams) (TransferReview, error)
+	ReturnOutboundPayment(ctx context.Context, arg ReturnOutboundPaymentParams) (OutboundPayment, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error)
+	RevokeAPIKeysByOwner(ctx context.Context, owner string) error
+	RevokeOAuthConsent(ctx context.Context, arg RevokeOAuthConsentParams) (OauthConsent, error)
+	RevokeOAuthConsentsByUser(ctx context.Context, username string) error
+	SeachEntriesByAccountOwner(ctx context.Context, arg SeachEntriesByAccountOwnerParams) ([]SeachEntriesByAccountOwnerRow, error)
 	SeachTransfersByAccountOwner(ctx context.Context, arg SeachTransfersByAccountOwnerParams) ([]SeachTransfersByAccountOwnerRow, error)
 	SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error)
 	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
+	SetAccountFreeze(ctx context.Context, arg SetAccountFreezeParams) (Account, error)
+	SettleOutboundPayment(ctx context.Context, id int64) (OutboundPayment, error)
+	UpdateAPIKeyLastUsed(ctx context.Context, id int64) error
+	UpdateAccountNickname(ctx context.Context, arg UpdateAccountNicknameParams) (Account, error)
+	UpdateCurrencyEnabled(ctx context.Context, arg UpdateCurrencyEnabledParams) (Currency, error)
 	UpdatePassword(ctx context.Context, arg UpdatePasswordParams) error
+	UpdatePasswordHash(ctx context.Context, arg UpdatePasswordHashParams) error
+	UpdatePayee(ctx context.Context, arg UpdatePayeeParams) (Payee, error)
+	UpdatePaymentExportStatus(ctx context.Context, arg UpdatePaymentExportStatusParams) (PaymentExport, error)
+	UpsertFeeSchedule(ctx context.Context, arg UpsertFeeScheduleParams) (FeeSchedule, error)
 }
 
 var _ Querier = (*Queries)(nil)
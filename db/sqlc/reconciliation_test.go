@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	// an account created with a balance and no entries has drifted
+	drifted := createRandomAccount(t)
+
+	sound := createTestBatchAccount(t, createRandomUser(t).Username, 0)
+	_, err := testQueries.CreateEntry(ctx, CreateEntryParams{
+		AccountID: sound.ID,
+		Amount:    500,
+		EntryType: EntryTypeAdjustment,
+	})
+	require.NoError(t, err)
+	_, err = testQueries.AddAccountBalance(ctx, AddAccountBalanceParams{ID: sound.ID, Amount: 500})
+	require.NoError(t, err)
+
+	result, err := store.ReconcileTx(ctx)
+	require.NoError(t, err)
+	require.True(t, result.Run.FinishedAt.Valid)
+	require.Equal(t, int64(len(result.Discrepancies)), result.Run.Discrepancies)
+	require.NotEmpty(t, result.Totals)
+
+	var accounts int64
+	for _, total := range result.Totals {
+		accounts += total.Accounts
+	}
+	require.Equal(t, accounts, result.Run.AccountsChecked)
+
+	var found bool
+	for _, d := range result.Discrepancies {
+		if d.Kind != DiscrepancyAccountDrift {
+			continue
+		}
+
+		require.NotEqual(t, sound.ID, d.AccountID.Int64)
+		if d.AccountID.Int64 == drifted.ID {
+			found = true
+			require.Equal(t, drifted.Balance, d.Actual)
+			require.Equal(t, int64(0), d.Expected)
+			require.Equal(t, drifted.Currency, d.Currency)
+		}
+	}
+	require.True(t, found)
+
+	run, err := testQueries.GetReconciliationRun(ctx, result.Run.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Run, run)
+
+	totals, err := testQueries.ListReconciliationTotals(ctx, run.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Totals, totals)
+}
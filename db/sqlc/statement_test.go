@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportStatementTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	settlement := createTestBatchAccount(t, createRandomUser(t).Username, 0)
+	payer := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+
+	// two credits of the same amount, told apart by their reference
+	first, err := store.TransferTx(ctx, TransferTxParams{FromAccID: payer.ID, ToAccID: settlement.ID, Amount: 100})
+	require.NoError(t, err)
+	second, err := store.TransferTx(ctx, TransferTxParams{FromAccID: payer.ID, ToAccID: settlement.ID, Amount: 100, Reference: "INV-2"})
+	require.NoError(t, err)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	lines := []StatementLineParams{
+		{Position: 1, BookedOn: today, Amount: 100, Currency: settlement.Currency, Reference: "INV-2"},
+		{Position: 2, BookedOn: today, Amount: 100, Currency: settlement.Currency},
+		{Position: 3, BookedOn: today, Amount: 100, Currency: settlement.Currency},
+		{Position: 4, BookedOn: today.AddDate(0, 0, -30), Amount: -5, Currency: settlement.Currency},
+	}
+
+	result, err := store.ImportStatementTx(ctx, ImportStatementTxParams{
+		AccountID:   settlement.ID,
+		Format:      "csv",
+		Filename:    "march.csv",
+		ImportedBy:  "_system",
+		Lines:       lines,
+		MatchWindow: 72 * time.Hour,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), result.Import.Lines)
+	require.Equal(t, int64(2), result.Import.Matched)
+	require.Len(t, result.Unmatched, 2)
+	require.Equal(t, int32(3), result.Unmatched[0].Position)
+	require.Equal(t, int32(4), result.Unmatched[1].Position)
+
+	unmatched, err := testQueries.ListUnmatchedStatementLines(ctx, result.Import.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Unmatched, unmatched)
+
+	// the entries are taken, so importing the statement again matches nothing
+	again, err := store.ImportStatementTx(ctx, ImportStatementTxParams{
+		AccountID:   settlement.ID,
+		Format:      "csv",
+		ImportedBy:  "_system",
+		Lines:       lines[:1],
+		MatchWindow: 72 * time.Hour,
+	})
+	require.NoError(t, err)
+	require.Zero(t, again.Import.Matched)
+
+	// the referenced transfer went to the line with its reference
+	matched, err := testDB.QueryContext(ctx, "SELECT position, transfer_id FROM statement_lines WHERE import_id = $1 AND entry_id IS NOT NULL ORDER BY position", result.Import.ID)
+	require.NoError(t, err)
+	defer matched.Close()
+
+	transfers := map[int32]int64{}
+	for matched.Next() {
+		var position int32
+		var transferID int64
+		require.NoError(t, matched.Scan(&position, &transferID))
+		transfers[position] = transferID
+	}
+	require.NoError(t, matched.Err())
+	require.Equal(t, map[int32]int64{1: second.Transfer.ID, 2: first.Transfer.ID}, transfers)
+}
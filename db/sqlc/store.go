@@ -3,14 +3,35 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 // Store provides all functions to execute db queries and transactions
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
-	DeleteUserWithAccountsTx(ctx context.Context, username string) error
+	CloseAccountTx(ctx context.Context, arg CloseAccountTxParams) (CloseAccountTxResult, error)
+	FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (FreezeAccountTxResult, error)
+	UnfreezeAccountTx(ctx context.Context, arg UnfreezeAccountTxParams) (FreezeAccountTxResult, error)
+	ApproveTransferReviewTx(ctx context.Context, arg ApproveTransferReviewTxParams) (ApproveTransferReviewTxResult, error)
+	DeactivateUserTx(ctx context.Context, arg DeactivateUserTxParams) error
+	OAuthAuthorizeTx(ctx context.Context, arg OAuthAuthorizeTxParams) (OAuthAuthorizeTxResult, error)
+	AuditTx(ctx context.Context, audit AuditParams, fn func(q Querier, audit *AuditParams) error) error
+	UpdatePasswordTx(ctx context.Context, arg UpdatePasswordTxParams) error
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) error
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error)
+	ChargeMaintenanceFeeTx(ctx context.Context, arg ChargeMaintenanceFeeTxParams) (ChargeMaintenanceFeeTxResult, error)
+	ExecuteTransferBatchTx(ctx context.Context, arg ExecuteTransferBatchTxParams) (ExecuteTransferBatchTxResult, error)
+	ExecuteTransferBatchItemTx(ctx context.Context, arg ExecuteTransferBatchItemTxParams) (TransferBatchItem, error)
+	PayPaymentRequestTx(ctx context.Context, arg PayPaymentRequestTxParams) (PayPaymentRequestTxResult, error)
+	ReconcileTx(ctx context.Context) (ReconcileTxResult, error)
+	ImportStatementTx(ctx context.Context, arg ImportStatementTxParams) (ImportStatementTxResult, error)
+	ExportOutboundPaymentsTx(ctx context.Context, arg ExportOutboundPaymentsTxParams) (ExportOutboundPaymentsTxResult, error)
+	SettleOutboundPaymentTx(ctx context.Context, arg SettleOutboundPaymentTxParams) (OutboundPayment, error)
+	ReturnOutboundPaymentTx(ctx context.Context, arg ReturnOutboundPaymentTxParams) (ReturnOutboundPaymentTxResult, error)
+	ApplyPaymentStatusReportTx(ctx context.Context, arg ApplyPaymentStatusReportTxParams) (ApplyPaymentStatusReportTxResult, error)
 }
 
 type SQLStore struct {
@@ -28,7 +49,13 @@ func NewStore(db *sql.DB) Store {
 
 // ExecTx executes a function within a database transaction
 func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+	return store.execTxOptions(ctx, nil, fn)
+}
+
+// execTxOptions executes a function within a database transaction started
+// with opts
+func (store *SQLStore) execTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
@@ -47,12 +74,32 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	return tx.Commit()
 }
 
+// Entry types
+const (
+	EntryTypeTransferDebit  = "transfer_debit"
+	EntryTypeTransferCredit = "transfer_credit"
+	EntryTypeAdjustment     = "adjustment"
+	EntryTypeFee            = "fee"
+)
+
 // TransferTxParams contains the input parameters of the transfer transaction
 
 type TransferTxParams struct {
-	FromAccID int64 `json:"from_account_id"`
-	ToAccID   int64 `json:"to_account_id"`
-	Amount    int64 `json:"amount"`
+	FromAccID int64           `json:"from_account_id"`
+	ToAccID   int64           `json:"to_account_id"`
+	Amount    int64           `json:"amount"`
+	Memo      string          `json:"memo"`
+	Reference string          `json:"reference"`
+	Metadata  json.RawMessage `json:"metadata"`
+	Limits    TransferLimits  `json:"-"`
+	// MaxFee fails the transfer with ErrFeeAboveMax if its fee is higher,
+	// nil accepts any fee
+	MaxFee *int64 `json:"-"`
+	// BeneficiaryID pays one of the sender's external beneficiaries: the
+	// money goes to the external settlement account of the currency instead
+	// of ToAccID and an outbound payment is initiated
+	BeneficiaryID int64       `json:"beneficiary_id,omitempty"`
+	Audit         AuditParams `json:"-"`
 }
 
 // TransferTxResult is the result of the transfer transaction
@@ -62,6 +109,9 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	Fees        []Fee    `json:"fees"`
+	// OutboundPayment is set for transfers to external beneficiaries
+	OutboundPayment *OutboundPayment `json:"outbound_payment,omitempty"`
 }
 
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
@@ -69,54 +119,142 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = makeTransfer(ctx, q, arg)
+		return err
+	})
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccID,
-			ToAccountID:   arg.ToAccID,
-			Amount:        arg.Amount,
-		})
+	return result, err
+}
 
+// makeTransfer is a customer transfer within the transaction of q: the
+// transfer itself, the rules of the product it leaves, its fee, the outbound
+// payment of a transfer to an external beneficiary and its audit event
+func makeTransfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var beneficiary ExternalBeneficiary
+	if arg.BeneficiaryID != 0 {
+		var err error
+		beneficiary, arg.ToAccID, err = externalSettlement(ctx, q, arg.FromAccID, arg.BeneficiaryID)
 		if err != nil {
-			return err
+			return TransferTxResult{}, err
 		}
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccID,
-			Amount:    -arg.Amount,
-		})
+	}
 
-		if err != nil {
-			return err
-		}
+	result, err := transfer(ctx, q, arg)
+	if err != nil {
+		return result, err
+	}
+
+	if err := checkProductRules(ctx, q, result.FromAccount); err != nil {
+		return result, err
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccID,
-			Amount:    arg.Amount,
-		})
+	if err := chargeTransferFee(ctx, q, &result, arg.MaxFee); err != nil {
+		return result, err
+	}
 
+	if arg.BeneficiaryID != 0 {
+		payment, err := initiateOutboundPayment(ctx, q, result, beneficiary)
 		if err != nil {
-			return err
+			return result, err
 		}
+		result.OutboundPayment = &payment
+	}
 
-		if arg.FromAccID < arg.ToAccID {
-			result.FromAccount, result.ToAccount, err = AddMoney(ctx, q, arg.FromAccID, -arg.Amount, arg.ToAccID, arg.Amount)
-			if err != nil {
-				return err
-			}
+	audit := arg.Audit
+	audit.TargetID = strconv.FormatInt(result.Transfer.ID, 10)
+	audit.After = result.Transfer
 
-		} else {
-			result.ToAccount, result.FromAccount, err = AddMoney(ctx, q, arg.ToAccID, arg.Amount, arg.FromAccID, -arg.Amount)
-			if err != nil {
-				return err
-			}
+	return result, recordAuditEvent(ctx, q, audit)
+}
 
-		}
+// transfer moves money between two accounts within the transaction of q
+func transfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	result, err := bookTransfer(ctx, q, arg)
+	if err != nil {
+		return result, err
+	}
+
+	// the accounts are locked by now, so their status can't change before this commits
+	if err := checkTransferAccounts(result.FromAccount, result.ToAccount); err != nil {
+		return result, err
+	}
+
+	if err := checkTransferLimits(ctx, q, result.FromAccount, arg.Amount, arg.Limits); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// bookTransfer records a transfer and its entries and moves the money within
+// the transaction of q, whatever the status of the accounts
+func bookTransfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
 
-		return nil
+	metadata := arg.Metadata
+	if len(metadata) == 0 {
+		metadata = json.RawMessage("{}")
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccID,
+		ToAccountID:   arg.ToAccID,
+		Amount:        arg.Amount,
+		Memo:          arg.Memo,
+		Reference:     arg.Reference,
+		Metadata:      metadata,
 	})
 
+	if err != nil {
+		return result, err
+	}
+	transferID := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.FromAccID,
+		Amount:     -arg.Amount,
+		TransferID: transferID,
+		EntryType:  EntryTypeTransferDebit,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.ToAccID,
+		Amount:     arg.Amount,
+		TransferID: transferID,
+		EntryType:  EntryTypeTransferCredit,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	if arg.FromAccID < arg.ToAccID {
+		result.FromAccount, result.ToAccount, err = AddMoney(ctx, q, arg.FromAccID, -arg.Amount, arg.ToAccID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = AddMoney(ctx, q, arg.ToAccID, arg.Amount, arg.FromAccID, -arg.Amount)
+	}
+
 	return result, err
 }
 
+// checkTransferAccounts makes sure money may leave from and enter to
+func checkTransferAccounts(from Account, to Account) error {
+	if from.Status == AccountStatusClosed || to.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+
+	if !from.CanDebit() || !to.CanCredit() {
+		return ErrAccountFrozen
+	}
+
+	return nil
+}
+
 func AddMoney(ctx context.Context, q *Queries, accountID1 int64, amount1 int64, accountID2 int64, amount2 int64) (account1 Account, account2 Account, err error) {
 	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
 		ID:     accountID1,
@@ -138,44 +276,3 @@ func AddMoney(ctx context.Context, q *Queries, accountID1 int64, amount1 int64,
 
 	return
 }
-
-func (store *SQLStore) DeleteUserWithAccountsTx(ctx context.Context, username string) error {
-
-	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-
-		_, err = q.GetUser(ctx, username)
-
-		if err != nil {
-			return err
-		}
-
-		accounts, err := q.ListAccounts(ctx, ListAccountsParams{Owner: username})
-
-		fmt.Println(accounts)
-
-		if err != nil {
-			return err
-		}
-
-		for _, account := range accounts {
-			err = q.DeleteAccount(ctx, account.ID)
-			fmt.Println(account.ID)
-
-			if err != nil {
-				return err
-			}
-		}
-
-		fmt.Println(username)
-		err = q.DeleteUser(ctx, username)
-
-		if err != nil {
-			return err
-		}
-
-		return nil
-	})
-
-	return err
-}
@@ -176,15 +176,51 @@ func TestTransferTxDeadlock(t *testing.T) {
 
 }
 
-func TestDeleteUserTx(t *testing.T) {
+func TestDeactivateUserTx(t *testing.T) {
 	store := NewStore(testDB)
 	ctx := context.Background()
-	user := createRandomUser(t)
+	account := createRandomAccount(t)
 
-	err := store.DeleteUserWithAccountsTx(ctx, user.Username)
+	err := store.DeactivateUserTx(ctx, DeactivateUserTxParams{Username: account.Owner})
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	_, err = testQueries.AddAccountBalance(ctx, AddAccountBalanceParams{ID: account.ID, Amount: -account.Balance})
+	require.NoError(t, err)
+
+	err = store.DeactivateUserTx(ctx, DeactivateUserTxParams{Username: account.Owner})
+	require.NoError(t, err)
+
+	user, err := testQueries.GetUser(ctx, account.Owner)
 	require.NoError(t, err)
-	user, err = testQueries.GetUser(context.Background(), user.Username)
-	require.Error(t, err)
+	require.True(t, user.DeactivatedAt.Valid)
+
+	account, err = testQueries.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, account.Status)
+	require.True(t, account.ClosedAt.Valid)
+
+	err = store.DeactivateUserTx(ctx, DeactivateUserTxParams{Username: account.Owner})
 	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, user)
+}
+
+func TestTransferTxClosedAccount(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	_, err := testQueries.CloseAccount(ctx, account2.ID)
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{
+		FromAccID: account1.ID,
+		ToAccID:   account2.ID,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	account1After, err := testQueries.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account1After.Balance)
 }
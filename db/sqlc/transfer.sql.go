@@ -10,26 +10,75 @@ import (
 	"database/sql"
 	"encoding/json"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+const countTransfersBetween = `-- name: CountTransfersBetween :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = $1 AND to_account_id = $2
+`
+
+type CountTransfersBetweenParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+}
+
+func (q *Queries) CountTransfersBetween(ctx context.Context, arg CountTransfersBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTransfersBetween, arg.FromAccountID, arg.ToAccountID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTransfersSince = `-- name: CountTransfersSince :one
+SELECT COUNT(*) FROM transfers
+WHERE from_account_id = $1 AND created_at >= $2
+`
+
+type CountTransfersSinceParams struct {
+	FromAccountID int64     `json:"from_account_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (q *Queries) CountTransfersSince(ctx context.Context, arg CountTransfersSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTransfersSince, arg.FromAccountID, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    memo,
+    reference,
+    metadata
     ) VALUES (  
-    $1,$2,$3
-    ) RETURNING id, from_account_id, to_account_id, amount, created_at
+    $1,$2,$3,$4,$5,$6
+    ) RETURNING id, from_account_id, to_account_id, amount, created_at, memo, reference, metadata
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64           `json:"from_account_id"`
+	ToAccountID   int64           `json:"to_account_id"`
+	Amount        int64           `json:"amount"`
+	Memo          string          `json:"memo"`
+	Reference     string          `json:"reference"`
+	Metadata      json.RawMessage `json:"metadata"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Memo,
+		arg.Reference,
+		arg.Metadata,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -37,12 +86,15 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers WHERE id = $1 LIMIT 1
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, reference, metadata FROM transfers WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
@@ -54,12 +106,15 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.Reference,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const listTransfersFromAccountId = `-- name: ListTransfersFromAccountId :many
-SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, 
+SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.memo, t.reference, t.metadata, 
 json_build_object('owner', a1.owner, 'balance', a1.balance) AS from_account,
 json_build_object('owner', a2.owner, 'balance', a2.balance) AS to_account
 FROM transfers t
@@ -84,6 +139,9 @@ type ListTransfersFromAccountIdRow struct {
 	ToAccountID   int64           `json:"to_account_id"`
 	Amount        int64           `json:"amount"`
 	CreatedAt     time.Time       `json:"created_at"`
+	Memo          string          `json:"memo"`
+	Reference     string          `json:"reference"`
+	Metadata      json.RawMessage `json:"metadata"`
 	FromAccount   json.RawMessage `json:"from_account"`
 	ToAccount     json.RawMessage `json:"to_account"`
 }
@@ -103,6 +161,9 @@ func (q *Queries) ListTransfersFromAccountId(ctx context.Context, arg ListTransf
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
 			&i.FromAccount,
 			&i.ToAccount,
 		); err != nil {
@@ -120,22 +181,33 @@ func (q *Queries) ListTransfersFromAccountId(ctx context.Context, arg ListTransf
 }
 
 const seachTransfersByAccountOwner = `-- name: SeachTransfersByAccountOwner :many
-SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at , 
+SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.memo, t.reference, t.metadata , 
 json_build_object('owner', a1.owner, 'balance', a1.balance) AS from_account,
-json_build_object('owner', a2.owner, 'balance', a2.balance) AS to_account
+json_build_object('owner', a2.owner, 'balance', a2.balance) AS to_account,
+a1.currency
 FROM transfers t
 INNER JOIN accounts a1 ON t.from_account_id = a1.id
 INNER JOIN accounts a2 ON t.to_account_id = a2.id
-WHERE a1.owner ILIKE '%' || $3 || '%'
+WHERE (a1.owner ILIKE '%' || $3 || '%'
 OR a2.owner ILIKE '%' || $3 || '%'
+OR t.memo ILIKE '%' || $3 || '%'
+OR t.reference ILIKE '%' || $3 || '%')
+AND t.metadata @> $4::jsonb
+AND (
+    (a1.owner = $5 AND ($6::bigint[] IS NULL OR t.from_account_id = ANY($6::bigint[])))
+    OR (a2.owner = $5 AND ($6::bigint[] IS NULL OR t.to_account_id = ANY($6::bigint[])))
+)
 LIMIT $1
 OFFSET $2
 `
 
 type SeachTransfersByAccountOwnerParams struct {
-	Limit       int32          `json:"limit"`
-	Offset      int32          `json:"offset"`
-	SearchQuery sql.NullString `json:"search_query"`
+	Limit       int32           `json:"limit"`
+	Offset      int32           `json:"offset"`
+	SearchQuery sql.NullString  `json:"search_query"`
+	Metadata    json.RawMessage `json:"metadata"`
+	Owner       string          `json:"owner"`
+	AccountIds  []int64         `json:"account_ids"`
 }
 
 type SeachTransfersByAccountOwnerRow struct {
@@ -144,12 +216,23 @@ type SeachTransfersByAccountOwnerRow struct {
 	ToAccountID   int64           `json:"to_account_id"`
 	Amount        int64           `json:"amount"`
 	CreatedAt     time.Time       `json:"created_at"`
+	Memo          string          `json:"memo"`
+	Reference     string          `json:"reference"`
+	Metadata      json.RawMessage `json:"metadata"`
 	FromAccount   json.RawMessage `json:"from_account"`
 	ToAccount     json.RawMessage `json:"to_account"`
+	Currency      string          `json:"currency"`
 }
 
 func (q *Queries) SeachTransfersByAccountOwner(ctx context.Context, arg SeachTransfersByAccountOwnerParams) ([]SeachTransfersByAccountOwnerRow, error) {
-	rows, err := q.db.QueryContext(ctx, seachTransfersByAccountOwner, arg.Limit, arg.Offset, arg.SearchQuery)
+	rows, err := q.db.QueryContext(ctx, seachTransfersByAccountOwner,
+		arg.Limit,
+		arg.Offset,
+		arg.SearchQuery,
+		arg.Metadata,
+		arg.Owner,
+		pq.Array(arg.AccountIds),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -163,8 +246,12 @@ func (q *Queries) SeachTransfersByAccountOwner(ctx context.Context, arg SeachTra
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.Reference,
+			&i.Metadata,
 			&i.FromAccount,
 			&i.ToAccount,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
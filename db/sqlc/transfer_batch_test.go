@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestBatchAccount(t *testing.T, owner string, balance int64) Account {
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    owner,
+		Balance:  balance,
+		Currency: util.USD,
+		Product:  ProductChecking,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
+// createTestTransferBatch creates a batch paying amounts from one account to
+// new accounts
+func createTestTransferBatch(t *testing.T, mode string, from Account, amounts ...int64) (TransferBatch, []TransferBatchItem) {
+	ctx := context.Background()
+
+	batch, err := testQueries.CreateTransferBatch(ctx, CreateTransferBatchParams{
+		Owner: from.Owner,
+		Mode:  mode,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchProcessing, batch.Status)
+	require.False(t, batch.CompletedAt.Valid)
+
+	items := make([]TransferBatchItem, len(amounts))
+	for i, amount := range amounts {
+		to := createTestBatchAccount(t, createRandomUser(t).Username, 0)
+
+		items[i], err = testQueries.CreateTransferBatchItem(ctx, CreateTransferBatchItemParams{
+			BatchID:       batch.ID,
+			Position:      int32(i + 1),
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+			Currency:      util.USD,
+			Memo:          "payroll",
+			Metadata:      json.RawMessage("{}"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, TransferBatchItemPending, items[i].Status)
+	}
+
+	return batch, items
+}
+
+func TestExecuteTransferBatchTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	from := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+	batch, items := createTestTransferBatch(t, TransferBatchAtomic, from, 100, 200)
+
+	result, err := store.ExecuteTransferBatchTx(ctx, ExecuteTransferBatchTxParams{BatchID: batch.ID})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchCompleted, result.Batch.Status)
+	require.True(t, result.Batch.CompletedAt.Valid)
+	require.Len(t, result.Items, len(items))
+
+	for _, item := range result.Items {
+		require.Equal(t, TransferBatchItemCompleted, item.Status)
+		require.True(t, item.TransferID.Valid)
+
+		transfer, err := testQueries.GetTransfer(ctx, item.TransferID.Int64)
+		require.NoError(t, err)
+		require.Equal(t, item.Amount, transfer.Amount)
+		require.Equal(t, item.ToAccountID, transfer.ToAccountID)
+	}
+
+	after, err := testQueries.GetAccount(ctx, from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-300, after.Balance)
+
+	// a batch is only made once
+	_, err = store.ExecuteTransferBatchTx(ctx, ExecuteTransferBatchTxParams{BatchID: batch.ID})
+	require.ErrorIs(t, err, ErrBatchNotProcessing)
+}
+
+func TestExecuteTransferBatchTxRollsBack(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	from := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+	batch, items := createTestTransferBatch(t, TransferBatchAtomic, from, 100, 200)
+
+	// the second transfer is above the limit, so neither is made
+	_, err := store.ExecuteTransferBatchTx(ctx, ExecuteTransferBatchTxParams{
+		BatchID: batch.ID,
+		Limits:  map[string]TransferLimits{util.USD: {MaxPerTransfer: 150}},
+	})
+
+	var itemErr *BatchItemError
+	require.ErrorAs(t, err, &itemErr)
+	require.Equal(t, items[1].ID, itemErr.Item.ID)
+
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+
+	after, err := testQueries.GetAccount(ctx, from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, after.Balance)
+
+	stored, err := testQueries.ListTransferBatchItems(ctx, batch.ID)
+	require.NoError(t, err)
+	for _, item := range stored {
+		require.Equal(t, TransferBatchItemPending, item.Status)
+	}
+}
+
+func TestExecuteTransferBatchItemTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	from := createTestBatchAccount(t, createRandomUser(t).Username, 1_000)
+	batch, items := createTestTransferBatch(t, TransferBatchBestEffort, from, 100, 2_000)
+
+	item, err := store.ExecuteTransferBatchItemTx(ctx, ExecuteTransferBatchItemTxParams{ItemID: items[0].ID})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchItemCompleted, item.Status)
+	require.True(t, item.TransferID.Valid)
+
+	// a completed item is not made twice
+	_, err = store.ExecuteTransferBatchItemTx(ctx, ExecuteTransferBatchItemTxParams{ItemID: items[0].ID})
+	require.ErrorIs(t, err, ErrBatchItemNotPending)
+
+	_, err = store.ExecuteTransferBatchItemTx(ctx, ExecuteTransferBatchItemTxParams{
+		ItemID: items[1].ID,
+		Limits: TransferLimits{MaxPerTransfer: 1_500},
+	})
+	var limitErr *LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+
+	failed, err := testQueries.FailTransferBatchItem(ctx, FailTransferBatchItemParams{ID: items[1].ID, Error: err.Error()})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchItemFailed, failed.Status)
+
+	finished, err := testQueries.FinishTransferBatch(ctx, FinishTransferBatchParams{ID: batch.ID, Status: TransferBatchPartiallyCompleted})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchPartiallyCompleted, finished.Status)
+	require.True(t, finished.CompletedAt.Valid)
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransferReview(t *testing.T, from Account, to Account, amount int64) TransferReview {
+	arg := CreateTransferReviewParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        amount,
+		Currency:      from.Currency,
+		RequestedBy:   from.Owner,
+		Rules:         []string{"new_payee"},
+		Memo:          "rent",
+		Metadata:      json.RawMessage("{}"),
+	}
+
+	review, err := testQueries.CreateTransferReview(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.FromAccountID, review.FromAccountID)
+	require.Equal(t, arg.Amount, review.Amount)
+	require.Equal(t, arg.Rules, review.Rules)
+	require.Equal(t, TransferReviewPending, review.Status)
+	require.False(t, review.TransferID.Valid)
+
+	return review
+}
+
+func TestApproveTransferReviewTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	banker := createRandomUser(t)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	amount := int64(10)
+
+	review := createRandomTransferReview(t, account1, account2, amount)
+
+	result, err := store.ApproveTransferReviewTx(ctx, ApproveTransferReviewTxParams{
+		ReviewID:   review.ID,
+		ReviewedBy: banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferReviewApproved, result.Review.Status)
+	require.Equal(t, banker.Username, result.Review.ReviewedBy.String)
+	require.True(t, result.Review.ReviewedAt.Valid)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Review.TransferID.Int64)
+	require.Equal(t, account1.Balance-amount, result.Transfer.FromAccount.Balance)
+	require.Equal(t, account2.Balance+amount, result.Transfer.ToAccount.Balance)
+	require.Equal(t, review.Memo, result.Transfer.Transfer.Memo)
+
+	_, err = store.ApproveTransferReviewTx(ctx, ApproveTransferReviewTxParams{
+		ReviewID:   review.ID,
+		ReviewedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrReviewNotPending)
+}
+
+func TestApproveTransferReviewTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	banker := createRandomUser(t)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	review := createRandomTransferReview(t, account1, account2, 10)
+
+	_, err := store.FreezeAccountTx(ctx, FreezeAccountTxParams{
+		AccountID:  account1.ID,
+		Mode:       FreezeModeDebit,
+		ReasonCode: "fraud",
+		FrozenBy:   banker.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = store.ApproveTransferReviewTx(ctx, ApproveTransferReviewTxParams{
+		ReviewID:   review.ID,
+		ReviewedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// the failed approval is rolled back, so the review can still be rejected
+	rejected, err := testQueries.RejectTransferReview(ctx, RejectTransferReviewParams{
+		ReviewedBy: banker.Username,
+		ID:         review.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferReviewRejected, rejected.Status)
+	require.False(t, rejected.TransferID.Valid)
+}
+
+func TestCountTransfers(t *testing.T) {
+	transfer := createRandomTransfer(t)
+	ctx := context.Background()
+
+	count, err := testQueries.CountTransfersBetween(ctx, CountTransfersBetweenParams{
+		FromAccountID: transfer.FromAccountID,
+		ToAccountID:   transfer.ToAccountID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	count, err = testQueries.CountTransfersSince(ctx, CountTransfersSinceParams{
+		FromAccountID: transfer.FromAccountID,
+		CreatedAt:     transfer.CreatedAt.Add(1),
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
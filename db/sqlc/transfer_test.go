@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ func createRandomTransfer(t *testing.T) Transfer {
 		FromAccountID: account1.ID,
 		ToAccountID:   account2.ID,
 		Amount:        util.RandomMoney(),
+		Metadata:      json.RawMessage("{}"),
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), args)
@@ -62,6 +64,7 @@ func TestListTransfersFromAccount(t *testing.T) {
 			FromAccountID: account.ID,
 			ToAccountID:   account2.ID,
 			Amount:        util.RandomMoney(),
+			Metadata:      json.RawMessage("{}"),
 		}
 		_, err := testQueries.CreateTransfer(context.Background(), arg)
 		require.NoError(t, err)
@@ -120,6 +123,7 @@ func TestSeachTransfersByAccountOwner(t *testing.T) {
 			FromAccountID: account.ID,
 			ToAccountID:   account2.ID,
 			Amount:        util.RandomMoney(),
+			Metadata:      json.RawMessage("{}"),
 		}
 		_, err := testQueries.CreateTransfer(context.Background(), arg)
 		require.NoError(t, err)
@@ -128,6 +132,7 @@ func TestSeachTransfersByAccountOwner(t *testing.T) {
 	// List transfers from account
 	arg := SeachTransfersByAccountOwnerParams{
 		SearchQuery: sql.NullString{String: account.Owner, Valid: true},
+		Metadata:    json.RawMessage("{}"),
 		Limit:       5,
 		Offset:      0}
 
@@ -144,3 +149,50 @@ func TestSeachTransfersByAccountOwner(t *testing.T) {
 	}
 
 }
+
+func TestSearchTransfersByMemoAndMetadata(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	reference := util.RandomString(12)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccID: account1.ID,
+		ToAccID:   account2.ID,
+		Amount:    10,
+		Memo:      "quarterly invoice",
+		Reference: reference,
+		Metadata:  json.RawMessage(`{"invoice": "42", "project": "apollo"}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "quarterly invoice", result.Transfer.Memo)
+	require.Equal(t, reference, result.Transfer.Reference)
+	require.JSONEq(t, `{"invoice": "42", "project": "apollo"}`, string(result.Transfer.Metadata))
+
+	// both entries link back to the transfer
+	for _, entryID := range []int64{result.FromEntry.ID, result.ToEntry.ID} {
+		entry, err := testQueries.GetEntry(context.Background(), entryID)
+		require.NoError(t, err)
+		require.Equal(t, result.Transfer.ID, entry.TransferID.Int64)
+		require.Equal(t, reference, entry.Reference.String)
+	}
+
+	transfers, err := testQueries.SeachTransfersByAccountOwner(context.Background(), SeachTransfersByAccountOwnerParams{
+		SearchQuery: sql.NullString{String: reference, Valid: true},
+		Metadata:    json.RawMessage(`{"project": "apollo"}`),
+		Limit:       5,
+		Offset:      0,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, result.Transfer.ID, transfers[0].ID)
+
+	transfers, err = testQueries.SeachTransfersByAccountOwner(context.Background(), SeachTransfersByAccountOwnerParams{
+		SearchQuery: sql.NullString{String: reference, Valid: true},
+		Metadata:    json.RawMessage(`{"project": "gemini"}`),
+		Limit:       5,
+		Offset:      0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, transfers)
+}
@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Account statuses
+const (
+	AccountStatusActive = "active"
+	AccountStatusFrozen = "frozen"
+	AccountStatusClosed = "closed"
+)
+
+var (
+	ErrAccountClosed       = errors.New("account is closed")
+	ErrAccountNotEmpty     = errors.New("account balance must be zero or swept to another account")
+	ErrInvalidSweepAccount = errors.New("sweep account must be another open account in the same currency")
+)
+
+// CloseAccountTxParams contains the input parameters of the close account transaction
+type CloseAccountTxParams struct {
+	AccountID int64
+	// SweepAccountID receives the remaining balance, leave it 0 to require a zero balance
+	SweepAccountID int64
+	Audit          AuditParams
+}
+
+// CloseAccountTxResult is the result of the close account transaction
+type CloseAccountTxResult struct {
+	Account Account `json:"account"`
+	// Sweep is the transfer of the remaining balance, if there was one
+	Sweep *TransferTxResult `json:"sweep,omitempty"`
+}
+
+// CloseAccountTx closes an account, first moving its remaining balance to
+// the sweep account. The account keeps its entries and transfers. The sweep
+// isn't held to transfer limits or the withdrawal rules of the product, and
+// is charged no fee.
+func (store *SQLStore) CloseAccountTx(ctx context.Context, arg CloseAccountTxParams) (CloseAccountTxResult, error) {
+	var result CloseAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, sweepAccount, err := lockAccountsForClose(ctx, q, arg.AccountID, arg.SweepAccountID)
+		if err != nil {
+			return err
+		}
+
+		switch account.Status {
+		case AccountStatusClosed:
+			return ErrAccountClosed
+		case AccountStatusFrozen:
+			return ErrAccountFrozen
+		}
+
+		arg.Audit.Before = account
+
+		if account.Balance != 0 {
+			if arg.SweepAccountID == 0 || account.Balance < 0 {
+				return ErrAccountNotEmpty
+			}
+
+			if sweepAccount.ID == account.ID || sweepAccount.Status == AccountStatusClosed || sweepAccount.Currency != account.Currency {
+				return ErrInvalidSweepAccount
+			}
+
+			sweep, err := transfer(ctx, q, TransferTxParams{
+				FromAccID: account.ID,
+				ToAccID:   sweepAccount.ID,
+				Amount:    account.Balance,
+			})
+			if err != nil {
+				return err
+			}
+			result.Sweep = &sweep
+		}
+
+		result.Account, err = q.CloseAccount(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.After = result
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
+
+// lockAccountsForClose locks the account being closed and the sweep account in
+// id order, the same order TransferTx updates them in, to avoid deadlocks
+func lockAccountsForClose(ctx context.Context, q *Queries, accountID int64, sweepAccountID int64) (account Account, sweepAccount Account, err error) {
+	if sweepAccountID == 0 || sweepAccountID == accountID {
+		account, err = q.GetAccountForUpdate(ctx, accountID)
+		return account, account, err
+	}
+
+	if accountID < sweepAccountID {
+		account, err = q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return
+		}
+		sweepAccount, err = q.GetAccountForUpdate(ctx, sweepAccountID)
+		return
+	}
+
+	sweepAccount, err = q.GetAccountForUpdate(ctx, sweepAccountID)
+	if err != nil {
+		return
+	}
+	account, err = q.GetAccountForUpdate(ctx, accountID)
+	return
+}
+
+// DeactivateUserTxParams contains the input parameters of the deactivate user transaction
+type DeactivateUserTxParams struct {
+	Username string
+	Audit    AuditParams
+}
+
+// DeactivateUserTx closes all of the user's accounts, which must be empty and
+// not frozen, revokes their API keys and OAuth consents and marks the user deactivated.
+// Entries, transfers and the user row itself are kept for the history.
+func (store *SQLStore) DeactivateUserTx(ctx context.Context, arg DeactivateUserTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		accounts, err := q.ListOpenAccountsForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			if account.Status == AccountStatusFrozen {
+				return ErrAccountFrozen
+			}
+
+			if account.Balance != 0 {
+				return ErrAccountNotEmpty
+			}
+
+			_, err = q.CloseAccount(ctx, account.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = q.RevokeAPIKeysByOwner(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.RevokeOAuthConsentsByUser(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.InvalidatePasswordResetTokens(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		// returns sql.ErrNoRows if the user doesn't exist or is already deactivated
+		_, err = q.DeactivateUser(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditParams describes a state change to record in the audit log. Before and
+// After are encoded as JSON, so they must not contain secrets such as password
+// hashes; pass API response types rather than rows.
+type AuditParams struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	IP         string
+	Before     interface{}
+	After      interface{}
+}
+
+// AuditTx runs fn in a transaction and appends an audit event for the change
+// in the same transaction, so the change and its audit record commit or roll
+// back together. fn can fill in the target id and the after state once the
+// change is made.
+func (store *SQLStore) AuditTx(ctx context.Context, audit AuditParams, fn func(q Querier, audit *AuditParams) error) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := fn(q, &audit); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, q, audit)
+	})
+}
+
+// recordAuditEvent appends an event to the hash chain. Transactions without an
+// action, e.g. the ones run directly by tests, are not audited.
+func recordAuditEvent(ctx context.Context, q *Queries, audit AuditParams) error {
+	if audit.Action == "" {
+		return nil
+	}
+
+	before, err := json.Marshal(audit.Before)
+	if err != nil {
+		return fmt.Errorf("cannot encode audit state: %w", err)
+	}
+
+	after, err := json.Marshal(audit.After)
+	if err != nil {
+		return fmt.Errorf("cannot encode audit state: %w", err)
+	}
+
+	// serialize writers so every event links to the one committed before it
+	if err := q.LockAuditChain(ctx); err != nil {
+		return err
+	}
+
+	var prevHash string
+
+	last, err := q.GetLastAuditEvent(ctx)
+	switch {
+	case err == nil:
+		prevHash = last.Hash
+	case err != sql.ErrNoRows:
+		return err
+	}
+
+	arg := CreateAuditEventParams{
+		Actor:      audit.Actor,
+		Action:     audit.Action,
+		TargetType: audit.TargetType,
+		TargetID:   audit.TargetID,
+		RequestID:  audit.RequestID,
+		Ip:         audit.IP,
+		Before:     before,
+		After:      after,
+		PrevHash:   prevHash,
+		// postgres keeps microseconds, the hash must match what is stored
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	arg.Hash = AuditEventHash(arg)
+
+	_, err = q.CreateAuditEvent(ctx, arg)
+	return err
+}
+
+// AuditEventHash computes the chained hash of an audit event
+func AuditEventHash(event CreateAuditEventParams) string {
+	// encoding the fields as a JSON array keeps the boundaries between them unambiguous
+	data, _ := json.Marshal([]interface{}{
+		event.PrevHash,
+		event.Actor,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.RequestID,
+		event.Ip,
+		event.Before,
+		event.After,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// ChargeMaintenanceFeeTxParams contains the input parameters of the charge maintenance fee transaction
+type ChargeMaintenanceFeeTxParams struct {
+	AccountID int64
+	// PeriodStart is the first day of the month the fee is for
+	PeriodStart time.Time
+	Audit       AuditParams
+}
+
+// ChargeMaintenanceFeeTxResult is the result of the charge maintenance fee transaction
+type ChargeMaintenanceFeeTxResult struct {
+	// Fee is nil when the account owed nothing or is no longer active
+	Fee     *Fee    `json:"fee,omitempty"`
+	Account Account `json:"account"`
+}
+
+// ChargeMaintenanceFeeTx charges the monthly maintenance fee of an account,
+// worked out on its current balance. An account is charged at most once for
+// a month, so the job can be rerun after a failure.
+func (store *SQLStore) ChargeMaintenanceFeeTx(ctx context.Context, arg ChargeMaintenanceFeeTxParams) (ChargeMaintenanceFeeTxResult, error) {
+	var result ChargeMaintenanceFeeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// serializes charges for the account, so the balance can't change under the quote
+		result.Account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if result.Account.Status != AccountStatusActive {
+			return nil
+		}
+
+		quote, err := QuoteFee(ctx, q, FeeTypeMaintenance, result.Account, result.Account.Balance)
+		if err != nil || quote.Amount == 0 {
+			return err
+		}
+
+		fee, account, err := chargeFee(ctx, q, result.Account, quote, sql.NullInt64{}, sql.NullTime{Time: arg.PeriodStart, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		result.Fee = &fee
+		result.Account = account
+
+		audit := arg.Audit
+		audit.TargetID = strconv.FormatInt(fee.ID, 10)
+		audit.After = fee
+
+		return recordAuditEvent(ctx, q, audit)
+	})
+
+	return result, err
+}
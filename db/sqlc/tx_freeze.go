@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Freeze modes
+const (
+	// FreezeModeDebit blocks money leaving the account but still lets it receive
+	FreezeModeDebit = "debit"
+	// FreezeModeFull blocks money moving in either direction
+	FreezeModeFull = "full"
+)
+
+var (
+	ErrAccountFrozen        = errors.New("account is frozen")
+	ErrAccountAlreadyFrozen = errors.New("account is already frozen")
+	ErrAccountNotFrozen     = errors.New("account is not frozen")
+)
+
+// FreezeAccountTxParams contains the input parameters of the freeze account transaction
+type FreezeAccountTxParams struct {
+	AccountID  int64
+	Mode       string
+	ReasonCode string
+	Note       string
+	FrozenBy   string
+	Audit      AuditParams
+}
+
+// FreezeAccountTxResult is the result of the freeze and unfreeze account transactions
+type FreezeAccountTxResult struct {
+	Account Account       `json:"account"`
+	Freeze  AccountFreeze `json:"freeze"`
+}
+
+// FreezeAccountTx freezes an open account and records who froze it and why
+func (store *SQLStore) FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (FreezeAccountTxResult, error) {
+	var result FreezeAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		switch account.Status {
+		case AccountStatusClosed:
+			return ErrAccountClosed
+		case AccountStatusFrozen:
+			return ErrAccountAlreadyFrozen
+		}
+
+		result.Freeze, err = q.CreateAccountFreeze(ctx, CreateAccountFreezeParams{
+			AccountID:  arg.AccountID,
+			Mode:       arg.Mode,
+			ReasonCode: arg.ReasonCode,
+			Note:       arg.Note,
+			FrozenBy:   arg.FrozenBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.SetAccountFreeze(ctx, SetAccountFreezeParams{
+			ID:         arg.AccountID,
+			Status:     AccountStatusFrozen,
+			FreezeMode: arg.Mode,
+		})
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.Before = account
+		arg.Audit.After = result
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
+
+// UnfreezeAccountTxParams contains the input parameters of the unfreeze account transaction
+type UnfreezeAccountTxParams struct {
+	AccountID int64
+	LiftedBy  string
+	Audit     AuditParams
+}
+
+// UnfreezeAccountTx lifts the freeze in force on an account and makes it active again
+func (store *SQLStore) UnfreezeAccountTx(ctx context.Context, arg UnfreezeAccountTxParams) (FreezeAccountTxResult, error) {
+	var result FreezeAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.Status != AccountStatusFrozen {
+			return ErrAccountNotFrozen
+		}
+
+		result.Freeze, err = q.LiftAccountFreeze(ctx, LiftAccountFreezeParams{
+			LiftedBy:  arg.LiftedBy,
+			AccountID: arg.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.SetAccountFreeze(ctx, SetAccountFreezeParams{
+			ID:     arg.AccountID,
+			Status: AccountStatusActive,
+		})
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.Before = account
+		arg.Audit.After = result
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
+
+// CanDebit reports whether money may leave the account
+func (account Account) CanDebit() bool {
+	return account.Status == AccountStatusActive
+}
+
+// CanCredit reports whether money may enter the account
+func (account Account) CanCredit() bool {
+	switch account.Status {
+	case AccountStatusActive:
+		return true
+	case AccountStatusFrozen:
+		return account.FreezeMode == FreezeModeDebit
+	}
+	return false
+}
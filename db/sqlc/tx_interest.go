@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// PostInterestTxParams contains the input parameters of the post interest transaction
+type PostInterestTxParams struct {
+	AccountID int64
+	// PeriodEnd is the first day not covered, e.g. October 1st to post September
+	PeriodEnd time.Time
+	Audit     AuditParams
+}
+
+// PostInterestTxResult is the result of the post interest transaction
+type PostInterestTxResult struct {
+	Posting InterestPosting `json:"posting"`
+	// Transfer is the credit from the interest expense account, nil when
+	// less than a minor unit was due
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// PostInterestTx pays the interest an account accrued before the end of the
+// period. Whole minor units are credited from the interest expense account
+// in the account's currency; the fraction left over is carried to the next
+// posting, so no interest is lost to rounding.
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// serializes postings for the account, so accruals are only paid once
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		unposted, err := q.GetUnpostedInterest(ctx, GetUnpostedInterestParams{
+			AccountID:   account.ID,
+			AccrualDate: arg.PeriodEnd,
+		})
+		if err != nil {
+			return err
+		}
+
+		accrued := unposted.AccruedMicros
+
+		last, err := q.GetLastInterestPosting(ctx, account.ID)
+		switch {
+		case err == nil:
+			accrued += last.AccruedMicros - last.Amount*MicrosPerMinorUnit
+		case err != sql.ErrNoRows:
+			return err
+		}
+
+		posting := CreateInterestPostingParams{
+			AccountID:     account.ID,
+			PeriodEnd:     arg.PeriodEnd,
+			AccruedMicros: accrued,
+			Amount:        accrued / MicrosPerMinorUnit,
+		}
+
+		if posting.Amount > 0 {
+			expense, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+				Purpose:  SystemAccountInterestExpense,
+				Currency: account.Currency,
+			})
+			if err != nil {
+				return fmt.Errorf("interest expense account for %s: %w", account.Currency, err)
+			}
+
+			transfer, err := transfer(ctx, q, TransferTxParams{
+				FromAccID: expense.AccountID,
+				ToAccID:   account.ID,
+				Amount:    posting.Amount,
+				Memo:      "Interest to " + arg.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+				Reference: "interest",
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Transfer = &transfer
+			posting.TransferID = sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}
+		}
+
+		result.Posting, err = q.CreateInterestPosting(ctx, posting)
+		if err != nil {
+			return err
+		}
+
+		err = q.MarkInterestAccrualsPosted(ctx, MarkInterestAccrualsPostedParams{
+			PostingID:   sql.NullInt64{Int64: result.Posting.ID, Valid: true},
+			AccountID:   account.ID,
+			AccrualDate: arg.PeriodEnd,
+		})
+		if err != nil {
+			return err
+		}
+
+		audit := arg.Audit
+		audit.TargetID = strconv.FormatInt(result.Posting.ID, 10)
+		audit.After = result.Posting
+
+		return recordAuditEvent(ctx, q, audit)
+	})
+
+	return result, err
+}
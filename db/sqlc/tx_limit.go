@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Names of the transfer limits
+const (
+	LimitPerTransfer    = "per_transfer"
+	LimitAccountDaily   = "account_daily"
+	LimitAccountMonthly = "account_monthly"
+	LimitUserDaily      = "user_daily"
+	LimitUserMonthly    = "user_monthly"
+)
+
+// TransferLimits caps how much can leave an account, in the account's
+// currency. A zero limit means there is no limit.
+type TransferLimits struct {
+	MaxPerTransfer int64
+	AccountDaily   int64
+	AccountMonthly int64
+	// the user limits cover all of the user's accounts in the currency
+	UserDaily   int64
+	UserMonthly int64
+}
+
+// LimitExceededError reports the limit a transfer would exceed and how much
+// can still be sent under it
+type LimitExceededError struct {
+	Limit     string `json:"limit"`
+	Max       int64  `json:"max"`
+	Remaining int64  `json:"remaining"`
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("transfer exceeds the %s limit of %d, %d remaining", e.Limit, e.Max, e.Remaining)
+}
+
+// LimitUsage is how much of a limit has been used in the current period
+type LimitUsage struct {
+	Limit     int64 `json:"limit"`
+	Used      int64 `json:"used"`
+	Remaining int64 `json:"remaining"`
+}
+
+// TransferLimitUsage is the usage of every limit of an account. Limits of 0
+// are unlimited and report no remaining amount.
+type TransferLimitUsage struct {
+	Currency       string     `json:"currency"`
+	MaxPerTransfer int64      `json:"max_per_transfer"`
+	AccountDaily   LimitUsage `json:"account_daily"`
+	AccountMonthly LimitUsage `json:"account_monthly"`
+	UserDaily      LimitUsage `json:"user_daily"`
+	UserMonthly    LimitUsage `json:"user_monthly"`
+}
+
+func newLimitUsage(limit int64, used int64) LimitUsage {
+	usage := LimitUsage{Limit: limit, Used: used}
+	if limit > 0 && used < limit {
+		usage.Remaining = limit - used
+	}
+	return usage
+}
+
+// GetTransferLimitUsage adds up what left the account and its owner's
+// accounts in the same currency since the start of the current UTC day and
+// month
+func GetTransferLimitUsage(ctx context.Context, q Querier, account Account, limits TransferLimits, now time.Time) (TransferLimitUsage, error) {
+	now = now.UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	usage := TransferLimitUsage{
+		Currency:       account.Currency,
+		MaxPerTransfer: limits.MaxPerTransfer,
+	}
+
+	accountTotals, err := q.GetAccountTransferTotals(ctx, GetAccountTransferTotalsParams{
+		DayStart:   dayStart,
+		AccountID:  account.ID,
+		MonthStart: monthStart,
+	})
+	if err != nil {
+		return usage, err
+	}
+
+	userTotals, err := q.GetUserTransferTotals(ctx, GetUserTransferTotalsParams{
+		DayStart:   dayStart,
+		Owner:      account.Owner,
+		Currency:   account.Currency,
+		MonthStart: monthStart,
+	})
+	if err != nil {
+		return usage, err
+	}
+
+	usage.AccountDaily = newLimitUsage(limits.AccountDaily, accountTotals.Daily)
+	usage.AccountMonthly = newLimitUsage(limits.AccountMonthly, accountTotals.Monthly)
+	usage.UserDaily = newLimitUsage(limits.UserDaily, userTotals.Daily)
+	usage.UserMonthly = newLimitUsage(limits.UserMonthly, userTotals.Monthly)
+
+	return usage, nil
+}
+
+// checkTransferLimits runs after the transfer is written and its accounts are
+// locked, so the totals include it and concurrent transfers from the same
+// account wait for this one to finish. Transfers from the user's other
+// accounts are serialized by a per-user lock.
+func checkTransferLimits(ctx context.Context, q *Queries, from Account, amount int64, limits TransferLimits) error {
+	if limits.MaxPerTransfer > 0 && amount > limits.MaxPerTransfer {
+		return &LimitExceededError{Limit: LimitPerTransfer, Max: limits.MaxPerTransfer, Remaining: limits.MaxPerTransfer}
+	}
+
+	if limits.AccountDaily == 0 && limits.AccountMonthly == 0 && limits.UserDaily == 0 && limits.UserMonthly == 0 {
+		return nil
+	}
+
+	if limits.UserDaily > 0 || limits.UserMonthly > 0 {
+		if err := q.LockUserTransfers(ctx, from.Owner); err != nil {
+			return err
+		}
+	}
+
+	usage, err := GetTransferLimitUsage(ctx, q, from, limits, time.Now())
+	if err != nil {
+		return err
+	}
+
+	checks := []struct {
+		name  string
+		usage LimitUsage
+	}{
+		{LimitAccountDaily, usage.AccountDaily},
+		{LimitAccountMonthly, usage.AccountMonthly},
+		{LimitUserDaily, usage.UserDaily},
+		{LimitUserMonthly, usage.UserMonthly},
+	}
+
+	for _, check := range checks {
+		if check.usage.Limit > 0 && check.usage.Used > check.usage.Limit {
+			// the totals already include this transfer
+			remaining := check.usage.Limit - (check.usage.Used - amount)
+			if remaining < 0 {
+				remaining = 0
+			}
+			return &LimitExceededError{Limit: check.name, Max: check.usage.Limit, Remaining: remaining}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// OAuthAuthorizeTxParams contains the input parameters of the OAuth authorize transaction
+type OAuthAuthorizeTxParams struct {
+	Username      string
+	ClientID      string
+	Scopes        []string
+	AccountIDs    []int64
+	HashedCode    string
+	RedirectUri   string
+	CodeChallenge string
+	ExpiresAt     time.Time
+	Audit         AuditParams
+}
+
+// OAuthAuthorizeTxResult is the result of the OAuth authorize transaction
+type OAuthAuthorizeTxResult struct {
+	Consent           OauthConsent           `json:"consent"`
+	AuthorizationCode OauthAuthorizationCode `json:"authorization_code"`
+}
+
+// OAuthAuthorizeTx records the user's consent for a client and issues the
+// authorization code the client exchanges for an access token.
+func (store *SQLStore) OAuthAuthorizeTx(ctx context.Context, arg OAuthAuthorizeTxParams) (OAuthAuthorizeTxResult, error) {
+	var result OAuthAuthorizeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Consent, err = q.CreateOAuthConsent(ctx, CreateOAuthConsentParams{
+			Username:   arg.Username,
+			ClientID:   arg.ClientID,
+			Scopes:     arg.Scopes,
+			AccountIds: arg.AccountIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.AuthorizationCode, err = q.CreateOAuthAuthorizationCode(ctx, CreateOAuthAuthorizationCodeParams{
+			HashedCode:    arg.HashedCode,
+			ConsentID:     result.Consent.ID,
+			RedirectUri:   arg.RedirectUri,
+			CodeChallenge: arg.CodeChallenge,
+			ExpiresAt:     arg.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		audit := arg.Audit
+		audit.TargetID = strconv.FormatInt(result.Consent.ID, 10)
+		audit.After = result.Consent
+
+		return recordAuditEvent(ctx, q, audit)
+	})
+
+	return result, err
+}
@@ -0,0 +1,432 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Outbound payment statuses
+const (
+	OutboundPaymentInitiated = "initiated"
+	OutboundPaymentSent      = "sent"
+	OutboundPaymentSettled   = "settled"
+	OutboundPaymentReturned  = "returned"
+)
+
+// Payment export statuses
+const (
+	PaymentExportSent             = "sent"
+	PaymentExportSettled          = "settled"
+	PaymentExportReturned         = "returned"
+	PaymentExportPartiallySettled = "partially_settled"
+)
+
+var (
+	ErrNoOutboundPayments        = errors.New("there are no initiated outbound payments to export")
+	ErrOutboundPaymentNotSent    = errors.New("outbound payment has not been sent")
+	ErrOutboundPaymentReturned   = errors.New("outbound payment was already returned")
+	ErrNoExternalSettlement      = errors.New("external payments are not available in this currency")
+	ErrOutboundPaymentNotPending = errors.New("outbound payment was already settled or returned")
+)
+
+// OutboundEndToEndID is the end-to-end id an outbound payment is sent with,
+// which the correspondent bank quotes back in its status reports
+func OutboundEndToEndID(transferID int64) string {
+	return fmt.Sprintf("SIMPLEBANK-%d", transferID)
+}
+
+// externalSettlement looks up the beneficiary of a transfer to an external
+// account and the settlement account that receives it in the currency of the
+// account the money leaves. The beneficiary must belong to the holder of
+// that account.
+func externalSettlement(ctx context.Context, q *Queries, fromAccountID int64, beneficiaryID int64) (ExternalBeneficiary, int64, error) {
+	account, err := q.GetAccount(ctx, fromAccountID)
+	if err != nil {
+		return ExternalBeneficiary{}, 0, err
+	}
+
+	beneficiary, err := q.GetExternalBeneficiary(ctx, GetExternalBeneficiaryParams{
+		ID:    beneficiaryID,
+		Owner: account.Owner,
+	})
+	if err != nil {
+		return beneficiary, 0, err
+	}
+
+	settlement, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountExternalSettlement,
+		Currency: account.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return beneficiary, 0, ErrNoExternalSettlement
+	}
+
+	return beneficiary, settlement.AccountID, err
+}
+
+// initiateOutboundPayment records the payment out to beneficiary that the
+// transfer in result funded, with a copy of the beneficiary's details so
+// later changes to it don't change where the money goes
+func initiateOutboundPayment(ctx context.Context, q *Queries, result TransferTxResult, beneficiary ExternalBeneficiary) (OutboundPayment, error) {
+	return q.CreateOutboundPayment(ctx, CreateOutboundPaymentParams{
+		TransferID:            result.Transfer.ID,
+		AccountID:             result.FromAccount.ID,
+		BeneficiaryID:         sql.NullInt64{Int64: beneficiary.ID, Valid: true},
+		Amount:                result.Transfer.Amount,
+		Currency:              result.FromAccount.Currency,
+		CreditorName:          beneficiary.Name,
+		CreditorIban:          beneficiary.Iban,
+		CreditorAccountNumber: beneficiary.AccountNumber,
+		CreditorBic:           beneficiary.Bic,
+		CreditorAbaRouting:    beneficiary.AbaRouting,
+		RemittanceInfo:        result.Transfer.Memo,
+		EndToEndID:            OutboundEndToEndID(result.Transfer.ID),
+	})
+}
+
+// ExportOutboundPaymentsTxParams contains the input parameters of the export outbound payments transaction
+type ExportOutboundPaymentsTxParams struct {
+	MaxPayments int32
+	CreatedBy   string
+	// Document writes the message sent to the correspondent bank for the
+	// payments. Its error rolls the export back.
+	Document func(messageID string, payments []OutboundPayment) ([]byte, error)
+	Audit    AuditParams
+}
+
+// ExportOutboundPaymentsTxResult is the result of the export outbound payments transaction
+type ExportOutboundPaymentsTxResult struct {
+	Export   PaymentExport     `json:"export"`
+	Payments []OutboundPayment `json:"payments"`
+}
+
+// ExportOutboundPaymentsTx takes the oldest initiated outbound payments, up
+// to MaxPayments, into a new export and marks them sent. The payments stay
+// locked until the export commits, so each is exported once. It fails with
+// ErrNoOutboundPayments when there is nothing to export.
+func (store *SQLStore) ExportOutboundPaymentsTx(ctx context.Context, arg ExportOutboundPaymentsTxParams) (ExportOutboundPaymentsTxResult, error) {
+	var result ExportOutboundPaymentsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		payments, err := q.ListInitiatedOutboundPaymentsForUpdate(ctx, arg.MaxPayments)
+		if err != nil {
+			return err
+		}
+
+		if len(payments) == 0 {
+			return ErrNoOutboundPayments
+		}
+
+		// payments are exported once, so the first of them makes the
+		// message id unique
+		now := time.Now().UTC()
+		messageID := fmt.Sprintf("SIMPLEBANK-%s-%d", now.Format("20060102150405"), payments[0].ID)
+
+		document, err := arg.Document(messageID, payments)
+		if err != nil {
+			return err
+		}
+
+		result.Export, err = q.CreatePaymentExport(ctx, CreatePaymentExportParams{
+			MessageID: messageID,
+			Payments:  int32(len(payments)),
+			Document:  document,
+			CreatedBy: arg.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, len(payments))
+		for i, payment := range payments {
+			ids[i] = payment.ID
+			payments[i].Status = OutboundPaymentSent
+			payments[i].ExportID = sql.NullInt64{Int64: result.Export.ID, Valid: true}
+			payments[i].SentAt = sql.NullTime{Time: now, Valid: true}
+		}
+
+		err = q.MarkOutboundPaymentsSent(ctx, MarkOutboundPaymentsSentParams{
+			ExportID: sql.NullInt64{Int64: result.Export.ID, Valid: true},
+			Ids:      ids,
+		})
+		if err != nil {
+			return err
+		}
+		result.Payments = payments
+
+		audit := arg.Audit
+		audit.TargetID = strconv.FormatInt(result.Export.ID, 10)
+		audit.After = map[string]interface{}{
+			"message_id": result.Export.MessageID,
+			"payments":   ids,
+		}
+		return recordAuditEvent(ctx, q, audit)
+	})
+
+	return result, err
+}
+
+// PaymentExportStatus is the status of an export from how many payments it
+// has and how many of them were settled and returned. It stays sent until
+// each payment has a final status.
+func PaymentExportStatus(payments int32, settled int32, returned int32) string {
+	switch {
+	case settled+returned < payments:
+		return PaymentExportSent
+	case returned == 0:
+		return PaymentExportSettled
+	case settled == 0:
+		return PaymentExportReturned
+	default:
+		return PaymentExportPartiallySettled
+	}
+}
+
+// updateExportStatus recounts the settled and returned payments of the
+// export payment was sent in and updates its status. Payments returned
+// before they were exported have no export to update.
+func updateExportStatus(ctx context.Context, q *Queries, payment OutboundPayment) error {
+	if !payment.ExportID.Valid {
+		return nil
+	}
+
+	// the export is locked before its payments are counted, so reports
+	// applied at the same time count each other's payments
+	export, err := q.GetPaymentExportForUpdate(ctx, payment.ExportID.Int64)
+	if err != nil {
+		return err
+	}
+
+	counts, err := q.CountExportPaymentsByStatus(ctx, payment.ExportID)
+	if err != nil {
+		return err
+	}
+
+	arg := UpdatePaymentExportStatusParams{
+		ID:          export.ID,
+		Settled:     int32(counts.Settled),
+		Returned:    int32(counts.Returned),
+		CompletedAt: export.CompletedAt,
+	}
+	arg.Status = PaymentExportStatus(export.Payments, arg.Settled, arg.Returned)
+
+	if arg.Status == PaymentExportSent {
+		arg.CompletedAt = sql.NullTime{}
+	} else if !arg.CompletedAt.Valid {
+		arg.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	_, err = q.UpdatePaymentExportStatus(ctx, arg)
+	return err
+}
+
+// settleOutbound marks a sent payment as settled by the correspondent bank
+func settleOutbound(ctx context.Context, q *Queries, payment OutboundPayment) (OutboundPayment, error) {
+	switch payment.Status {
+	case OutboundPaymentInitiated:
+		return payment, ErrOutboundPaymentNotSent
+	case OutboundPaymentSettled, OutboundPaymentReturned:
+		return payment, ErrOutboundPaymentNotPending
+	}
+
+	payment, err := q.SettleOutboundPayment(ctx, payment.ID)
+	if err != nil {
+		return payment, err
+	}
+
+	return payment, updateExportStatus(ctx, q, payment)
+}
+
+// returnOutbound credits a payment that didn't reach its beneficiary back
+// to the customer's account from the settlement account, and marks it
+// returned. Initiated payments are returned before they are sent, and
+// settled ones when the receiving bank sends the money back. The money is
+// the customer's whatever the state of their account, so it is credited to
+// closed and frozen accounts too, for a banker to pay out or release. The
+// bank didn't complete the payment, so the transfer fee charged on it is
+// refunded with it.
+func returnOutbound(ctx context.Context, q *Queries, payment OutboundPayment, reason string) (OutboundPayment, TransferTxResult, error) {
+	if payment.Status == OutboundPaymentReturned {
+		return payment, TransferTxResult{}, ErrOutboundPaymentReturned
+	}
+
+	settlement, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountExternalSettlement,
+		Currency: payment.Currency,
+	})
+	if err != nil {
+		return payment, TransferTxResult{}, fmt.Errorf("external settlement account for %s: %w", payment.Currency, err)
+	}
+
+	metadata, err := json.Marshal(map[string]int64{"outbound_payment_id": payment.ID})
+	if err != nil {
+		return payment, TransferTxResult{}, err
+	}
+
+	refund, err := bookTransfer(ctx, q, TransferTxParams{
+		FromAccID: settlement.AccountID,
+		ToAccID:   payment.AccountID,
+		Amount:    payment.Amount,
+		Memo:      "Returned: " + reason,
+		Reference: payment.EndToEndID,
+		Metadata:  metadata,
+	})
+	if err != nil {
+		return payment, refund, err
+	}
+
+	refund.Fees, refund.ToAccount, err = refundTransferFees(ctx, q, refund.ToAccount, payment.TransferID)
+	if err != nil {
+		return payment, refund, err
+	}
+
+	payment, err = q.ReturnOutboundPayment(ctx, ReturnOutboundPaymentParams{
+		ID:               payment.ID,
+		ReturnReason:     reason,
+		ReturnTransferID: sql.NullInt64{Int64: refund.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return payment, refund, err
+	}
+
+	return payment, refund, updateExportStatus(ctx, q, payment)
+}
+
+// SettleOutboundPaymentTxParams contains the input parameters of the settle outbound payment transaction
+type SettleOutboundPaymentTxParams struct {
+	ID    int64
+	Audit AuditParams
+}
+
+// SettleOutboundPaymentTx marks a sent outbound payment as settled
+func (store *SQLStore) SettleOutboundPaymentTx(ctx context.Context, arg SettleOutboundPaymentTxParams) (OutboundPayment, error) {
+	var payment OutboundPayment
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetOutboundPaymentForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		payment, err = settleOutbound(ctx, q, before)
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.Before = before
+		arg.Audit.After = payment
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return payment, err
+}
+
+// ReturnOutboundPaymentTxParams contains the input parameters of the return outbound payment transaction
+type ReturnOutboundPaymentTxParams struct {
+	ID     int64
+	Reason string
+	Audit  AuditParams
+}
+
+// ReturnOutboundPaymentTxResult is the result of the return outbound payment transaction
+type ReturnOutboundPaymentTxResult struct {
+	Payment  OutboundPayment  `json:"payment"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// ReturnOutboundPaymentTx returns an outbound payment and credits its amount
+// and its transfer fee back to the customer
+func (store *SQLStore) ReturnOutboundPaymentTx(ctx context.Context, arg ReturnOutboundPaymentTxParams) (ReturnOutboundPaymentTxResult, error) {
+	var result ReturnOutboundPaymentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetOutboundPaymentForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Payment, result.Transfer, err = returnOutbound(ctx, q, before, arg.Reason)
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.Before = before
+		arg.Audit.After = result.Payment
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
+
+// OutboundPaymentStatus is what the correspondent bank reported for the
+// payment with EndToEndID: OutboundPaymentSettled or OutboundPaymentReturned
+type OutboundPaymentStatus struct {
+	EndToEndID string
+	Status     string
+	Reason     string
+}
+
+// ApplyPaymentStatusReportTxParams contains the input parameters of the apply payment status report transaction
+type ApplyPaymentStatusReportTxParams struct {
+	Statuses []OutboundPaymentStatus
+	Audit    AuditParams
+}
+
+// ApplyPaymentStatusReportTxResult is the result of the apply payment status report transaction
+type ApplyPaymentStatusReportTxResult struct {
+	Settled  []OutboundPayment `json:"settled"`
+	Returned []OutboundPayment `json:"returned"`
+	// Ignored are the end-to-end ids of statuses that matched no payment or
+	// that the payment already had
+	Ignored []string `json:"ignored"`
+}
+
+// ApplyPaymentStatusReportTx settles and returns the outbound payments a
+// status report from the correspondent bank gives a final status for, and
+// updates the status of the exports they were sent in. A report may be
+// applied more than once; statuses already applied are ignored.
+func (store *SQLStore) ApplyPaymentStatusReportTx(ctx context.Context, arg ApplyPaymentStatusReportTxParams) (ApplyPaymentStatusReportTxResult, error) {
+	result := ApplyPaymentStatusReportTxResult{
+		Settled:  []OutboundPayment{},
+		Returned: []OutboundPayment{},
+		Ignored:  []string{},
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, status := range arg.Statuses {
+			payment, err := q.GetOutboundPaymentByEndToEndIDForUpdate(ctx, status.EndToEndID)
+			if err == sql.ErrNoRows {
+				result.Ignored = append(result.Ignored, status.EndToEndID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case status.Status == OutboundPaymentSettled && payment.Status == OutboundPaymentSent:
+				payment, err = settleOutbound(ctx, q, payment)
+				result.Settled = append(result.Settled, payment)
+			case status.Status == OutboundPaymentReturned && payment.Status != OutboundPaymentReturned:
+				payment, _, err = returnOutbound(ctx, q, payment, status.Reason)
+				result.Returned = append(result.Returned, payment)
+			default:
+				result.Ignored = append(result.Ignored, status.EndToEndID)
+			}
+
+			if err != nil {
+				return fmt.Errorf("outbound payment %s: %w", status.EndToEndID, err)
+			}
+		}
+
+		arg.Audit.After = result
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// UpdatePasswordTxParams contains the input parameters of the update password transaction
+type UpdatePasswordTxParams struct {
+	Username          string
+	OldHashedPassword string
+	HashedPassword    string
+	Audit             AuditParams
+}
+
+// ResetPasswordTxParams contains the input parameters of the reset password transaction
+type ResetPasswordTxParams struct {
+	HashedToken string
+	UpdatePasswordTxParams
+}
+
+// UpdatePasswordTx replaces the user's password, keeps the replaced hash in
+// the password history and invalidates any outstanding reset tokens.
+func (store *SQLStore) UpdatePasswordTx(ctx context.Context, arg UpdatePasswordTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		return changePassword(ctx, q, arg)
+	})
+}
+
+// ResetPasswordTx consumes a single-use reset token and sets the new password.
+// It returns sql.ErrNoRows if the token was already used, has expired or
+// belongs to another user.
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		resetToken, err := q.ConsumePasswordResetToken(ctx, arg.HashedToken)
+		if err != nil {
+			return err
+		}
+
+		if resetToken.Username != arg.Username {
+			return sql.ErrNoRows
+		}
+
+		return changePassword(ctx, q, arg.UpdatePasswordTxParams)
+	})
+}
+
+func changePassword(ctx context.Context, q *Queries, arg UpdatePasswordTxParams) error {
+	_, err := q.CreatePasswordHistory(ctx, CreatePasswordHistoryParams{
+		Username:       arg.Username,
+		HashedPassword: arg.OldHashedPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.UpdatePassword(ctx, UpdatePasswordParams{
+		Username:       arg.Username,
+		HashedPassword: arg.HashedPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.InvalidatePasswordResetTokens(ctx, arg.Username)
+	if err != nil {
+		return err
+	}
+
+	return recordAuditEvent(ctx, q, arg.Audit)
+}
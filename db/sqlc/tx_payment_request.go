@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Payment request statuses
+const (
+	PaymentRequestPending  = "pending"
+	PaymentRequestPaid     = "paid"
+	PaymentRequestDeclined = "declined"
+	// PaymentRequestExpired is reported for pending requests past their
+	// expiry; it is never stored
+	PaymentRequestExpired = "expired"
+)
+
+var (
+	ErrPaymentRequestNotPending = errors.New("payment request is not pending")
+	ErrPaymentRequestExpired    = errors.New("payment request has expired")
+)
+
+// Expired reports whether the request was still pending when it expired
+func (r PaymentRequest) Expired(now time.Time) bool {
+	return r.Status == PaymentRequestPending && !now.Before(r.ExpiresAt)
+}
+
+// CheckPending returns why the request can no longer be paid or declined
+func (r PaymentRequest) CheckPending(now time.Time) error {
+	if r.Status != PaymentRequestPending {
+		return ErrPaymentRequestNotPending
+	}
+
+	if r.Expired(now) {
+		return ErrPaymentRequestExpired
+	}
+
+	return nil
+}
+
+// PayPaymentRequestTxParams contains the input parameters of the pay payment request transaction
+type PayPaymentRequestTxParams struct {
+	RequestID     int64
+	FromAccountID int64
+	Limits        TransferLimits
+	Audit         AuditParams
+}
+
+// PayPaymentRequestTxResult is the result of the pay payment request transaction
+type PayPaymentRequestTxResult struct {
+	Request  PaymentRequest   `json:"request"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// PayPaymentRequestTx pays a pending request with a transfer from the payer's
+// account to the requester's. The request is locked while the transfer is
+// made, so it is paid at most once and can't be declined meanwhile. The
+// payment is audited as the request being paid.
+func (store *SQLStore) PayPaymentRequestTx(ctx context.Context, arg PayPaymentRequestTxParams) (PayPaymentRequestTxResult, error) {
+	var result PayPaymentRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := q.GetPaymentRequestForUpdate(ctx, arg.RequestID)
+		if err != nil {
+			return err
+		}
+
+		if err := request.CheckPending(time.Now()); err != nil {
+			return err
+		}
+
+		metadata, err := json.Marshal(map[string]int64{"payment_request_id": request.ID})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = makeTransfer(ctx, q, TransferTxParams{
+			FromAccID: arg.FromAccountID,
+			ToAccID:   request.RequesterAccountID,
+			Amount:    request.Amount,
+			Memo:      request.Memo,
+			Metadata:  metadata,
+			Limits:    arg.Limits,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Request, err = q.PayPaymentRequest(ctx, PayPaymentRequestParams{
+			ID:         request.ID,
+			TransferID: sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.Before = request
+		arg.Audit.After = result.Request
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Account products
+const (
+	ProductChecking = "checking"
+	ProductSavings  = "savings"
+)
+
+var ErrWithdrawalLimit = errors.New("the account's product allows no more withdrawals this month")
+
+// checkProductRules enforces the rules of the product of the account money
+// is leaving. Like checkTransferLimits it runs after the transfer is written,
+// so the count includes it and the locked account serializes the check.
+func checkProductRules(ctx context.Context, q *Queries, from Account) error {
+	product, err := q.GetAccountProduct(ctx, from.Product)
+	if err != nil {
+		return err
+	}
+
+	if product.MaxMonthlyWithdrawals == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	count, err := q.CountTransfersSince(ctx, CountTransfersSinceParams{
+		FromAccountID: from.ID,
+		CreatedAt:     time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return err
+	}
+
+	if count > int64(product.MaxMonthlyWithdrawals) {
+		return ErrWithdrawalLimit
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Reconciliation discrepancy kinds
+const (
+	DiscrepancyAccountDrift    = "account_drift"
+	DiscrepancyLedgerImbalance = "ledger_imbalance"
+)
+
+// ReconcileTxResult is the result of the reconcile transaction
+type ReconcileTxResult struct {
+	Run           ReconciliationRun           `json:"run"`
+	Totals        []ReconciliationTotal       `json:"totals"`
+	Discrepancies []ReconciliationDiscrepancy `json:"discrepancies"`
+}
+
+// ReconcileTx checks every account balance against the sum of its entries,
+// and the transfer and fee entries of every currency against 0, and records
+// the totals and any discrepancy as a reconciliation run. It reads a single
+// snapshot of the ledger, so transfers committing meanwhile can't show up as
+// drift.
+func (store *SQLStore) ReconcileTx(ctx context.Context) (ReconcileTxResult, error) {
+	var result ReconcileTxResult
+
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+	err := store.execTxOptions(ctx, opts, func(q *Queries) error {
+		var err error
+		result.Run, err = q.CreateReconciliationRun(ctx)
+		if err != nil {
+			return err
+		}
+
+		drift, err := q.ListAccountDrift(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, account := range drift {
+			discrepancy, err := q.CreateReconciliationDiscrepancy(ctx, CreateReconciliationDiscrepancyParams{
+				RunID:     result.Run.ID,
+				Kind:      DiscrepancyAccountDrift,
+				AccountID: sql.NullInt64{Int64: account.AccountID, Valid: true},
+				Currency:  account.Currency,
+				Expected:  account.EntryTotal,
+				Actual:    account.Balance,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Discrepancies = append(result.Discrepancies, discrepancy)
+		}
+
+		totals, err := q.ListLedgerTotals(ctx)
+		if err != nil {
+			return err
+		}
+
+		var accounts int64
+		for _, total := range totals {
+			accounts += total.Accounts
+
+			row, err := q.CreateReconciliationTotal(ctx, CreateReconciliationTotalParams{
+				RunID:         result.Run.ID,
+				Currency:      total.Currency,
+				Accounts:      total.Accounts,
+				BalanceTotal:  total.BalanceTotal,
+				EntryTotal:    total.EntryTotal,
+				InternalTotal: total.InternalTotal,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Totals = append(result.Totals, row)
+
+			// every transfer and fee credits what it debits
+			if total.InternalTotal == 0 {
+				continue
+			}
+
+			discrepancy, err := q.CreateReconciliationDiscrepancy(ctx, CreateReconciliationDiscrepancyParams{
+				RunID:    result.Run.ID,
+				Kind:     DiscrepancyLedgerImbalance,
+				Currency: total.Currency,
+				Expected: 0,
+				Actual:   total.InternalTotal,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Discrepancies = append(result.Discrepancies, discrepancy)
+		}
+
+		result.Run, err = q.FinishReconciliationRun(ctx, FinishReconciliationRunParams{
+			ID:              result.Run.ID,
+			AccountsChecked: accounts,
+			Discrepancies:   int64(len(result.Discrepancies)),
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// StatementLineParams is a line of a bank statement to import
+type StatementLineParams struct {
+	Position    int32
+	BookedOn    time.Time
+	Amount      int64
+	Currency    string
+	Reference   string
+	Description string
+}
+
+// ImportStatementTxParams contains the input parameters of the import statement transaction
+type ImportStatementTxParams struct {
+	AccountID  int64
+	Format     string
+	Filename   string
+	ImportedBy string
+	Lines      []StatementLineParams
+	// MatchWindow is how long before or after the day a line was booked on
+	// the entry it matches may have been made
+	MatchWindow time.Duration
+	Audit       AuditParams
+}
+
+// ImportStatementTxResult is the result of the import statement transaction
+type ImportStatementTxResult struct {
+	Import    StatementImport `json:"import"`
+	Unmatched []StatementLine `json:"unmatched"`
+}
+
+// ImportStatementTx stages the lines of a bank statement for an account and
+// matches each to an entry of the account with the same amount made around
+// the day the line was booked, preferring the entry of a transfer with the
+// line's reference, then the oldest. An entry is matched to one line at
+// most, across imports.
+func (store *SQLStore) ImportStatementTx(ctx context.Context, arg ImportStatementTxParams) (ImportStatementTxResult, error) {
+	result := ImportStatementTxResult{Unmatched: []StatementLine{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Import, err = q.CreateStatementImport(ctx, CreateStatementImportParams{
+			AccountID:  arg.AccountID,
+			Format:     arg.Format,
+			Filename:   arg.Filename,
+			ImportedBy: arg.ImportedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		var matched int64
+		for _, line := range arg.Lines {
+			entry, err := q.FindStatementMatch(ctx, FindStatementMatchParams{
+				AccountID:   arg.AccountID,
+				Amount:      line.Amount,
+				CreatedFrom: line.BookedOn.Add(-arg.MatchWindow),
+				CreatedTo:   line.BookedOn.AddDate(0, 0, 1).Add(arg.MatchWindow),
+				Reference:   line.Reference,
+			})
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+
+			params := CreateStatementLineParams{
+				ImportID:    result.Import.ID,
+				Position:    line.Position,
+				BookedOn:    line.BookedOn,
+				Amount:      line.Amount,
+				Currency:    line.Currency,
+				Reference:   line.Reference,
+				Description: line.Description,
+			}
+			if err == nil {
+				params.EntryID = sql.NullInt64{Int64: entry.ID, Valid: true}
+				params.TransferID = entry.TransferID
+			}
+
+			staged, err := q.CreateStatementLine(ctx, params)
+			if err != nil {
+				return err
+			}
+
+			if staged.EntryID.Valid {
+				matched++
+			} else {
+				result.Unmatched = append(result.Unmatched, staged)
+			}
+		}
+
+		result.Import, err = q.FinishStatementImport(ctx, FinishStatementImportParams{
+			ID:      result.Import.ID,
+			Lines:   int64(len(arg.Lines)),
+			Matched: matched,
+		})
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.TargetID = strconv.FormatInt(result.Import.ID, 10)
+		arg.Audit.After = result.Import
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
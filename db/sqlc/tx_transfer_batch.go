@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Transfer batch modes
+const (
+	TransferBatchAtomic     = "atomic"
+	TransferBatchBestEffort = "best_effort"
+)
+
+// Transfer batch statuses
+const (
+	TransferBatchProcessing         = "processing"
+	TransferBatchCompleted          = "completed"
+	TransferBatchPartiallyCompleted = "partially_completed"
+	TransferBatchFailed             = "failed"
+)
+
+// Transfer batch item statuses
+const (
+	TransferBatchItemPending   = "pending"
+	TransferBatchItemCompleted = "completed"
+	TransferBatchItemFailed    = "failed"
+	TransferBatchItemHeld      = "held"
+)
+
+var (
+	ErrBatchNotProcessing  = errors.New("transfer batch is not processing")
+	ErrBatchItemNotPending = errors.New("transfer batch item is not pending")
+)
+
+// BatchItemError is the transfer that failed an atomic batch
+type BatchItemError struct {
+	Item TransferBatchItem
+	Err  error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("transfer %d of the batch: %v", e.Item.Position, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// ExecuteTransferBatchTxParams contains the input parameters of the execute transfer batch transaction
+type ExecuteTransferBatchTxParams struct {
+	BatchID int64
+	// Limits holds the transfer limits of each currency in the batch
+	Limits map[string]TransferLimits
+	// Audit is recorded for each transfer
+	Audit AuditParams
+}
+
+// ExecuteTransferBatchTxResult is the result of the execute transfer batch transaction
+type ExecuteTransferBatchTxResult struct {
+	Batch TransferBatch       `json:"batch"`
+	Items []TransferBatchItem `json:"items"`
+}
+
+// ExecuteTransferBatchTx makes every transfer of an atomic batch in one
+// transaction, so either all of them are made or none. A transfer that fails
+// is returned as a *BatchItemError and rolls back the others; the batch is
+// left processing for the caller to fail.
+func (store *SQLStore) ExecuteTransferBatchTx(ctx context.Context, arg ExecuteTransferBatchTxParams) (ExecuteTransferBatchTxResult, error) {
+	var result ExecuteTransferBatchTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		batch, err := q.GetTransferBatchForUpdate(ctx, arg.BatchID)
+		if err != nil {
+			return err
+		}
+
+		if batch.Status != TransferBatchProcessing {
+			return ErrBatchNotProcessing
+		}
+
+		items, err := q.ListTransferBatchItems(ctx, batch.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Items = make([]TransferBatchItem, len(items))
+		for i, item := range items {
+			result.Items[i], err = executeBatchItem(ctx, q, item, arg.Limits[item.Currency], arg.Audit)
+			if err != nil {
+				return &BatchItemError{Item: item, Err: err}
+			}
+		}
+
+		result.Batch, err = q.FinishTransferBatch(ctx, FinishTransferBatchParams{
+			ID:     batch.ID,
+			Status: TransferBatchCompleted,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ExecuteTransferBatchItemTxParams contains the input parameters of the execute transfer batch item transaction
+type ExecuteTransferBatchItemTxParams struct {
+	ItemID int64
+	Limits TransferLimits
+	Audit  AuditParams
+}
+
+// ExecuteTransferBatchItemTx makes one transfer of a best-effort batch. The
+// item is marked completed in the same transaction, so it is never made
+// twice; a failed item is left pending for the caller to fail.
+func (store *SQLStore) ExecuteTransferBatchItemTx(ctx context.Context, arg ExecuteTransferBatchItemTxParams) (TransferBatchItem, error) {
+	var result TransferBatchItem
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		item, err := q.GetTransferBatchItemForUpdate(ctx, arg.ItemID)
+		if err != nil {
+			return err
+		}
+
+		if item.Status != TransferBatchItemPending {
+			return ErrBatchItemNotPending
+		}
+
+		result, err = executeBatchItem(ctx, q, item, arg.Limits, arg.Audit)
+		return err
+	})
+
+	return result, err
+}
+
+// executeBatchItem makes the transfer of a batch item and marks it completed
+func executeBatchItem(ctx context.Context, q *Queries, item TransferBatchItem, limits TransferLimits, audit AuditParams) (TransferBatchItem, error) {
+	result, err := makeTransfer(ctx, q, TransferTxParams{
+		FromAccID: item.FromAccountID,
+		ToAccID:   item.ToAccountID,
+		Amount:    item.Amount,
+		Memo:      item.Memo,
+		Reference: item.Reference,
+		Metadata:  item.Metadata,
+		Limits:    limits,
+		Audit:     audit,
+	})
+	if err != nil {
+		return item, err
+	}
+
+	return q.CompleteTransferBatchItem(ctx, CompleteTransferBatchItemParams{
+		ID:         item.ID,
+		TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+	})
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Transfer review statuses
+const (
+	TransferReviewPending  = "pending"
+	TransferReviewApproved = "approved"
+	TransferReviewRejected = "rejected"
+)
+
+var ErrReviewNotPending = errors.New("transfer review is not pending")
+
+// ApproveTransferReviewTxParams contains the input parameters of the approve transfer review transaction
+type ApproveTransferReviewTxParams struct {
+	ReviewID   int64
+	ReviewedBy string
+	Limits     TransferLimits
+	Audit      AuditParams
+}
+
+// ApproveTransferReviewTxResult is the result of the approve transfer review transaction
+type ApproveTransferReviewTxResult struct {
+	Review   TransferReview   `json:"review"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// ApproveTransferReviewTx releases a held transfer. The transfer is made with
+// the same checks as any other, so it fails if an account was closed or
+// frozen, or a limit was used up, while it waited for review.
+func (store *SQLStore) ApproveTransferReviewTx(ctx context.Context, arg ApproveTransferReviewTxParams) (ApproveTransferReviewTxResult, error) {
+	var result ApproveTransferReviewTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		review, err := q.GetTransferReviewForUpdate(ctx, arg.ReviewID)
+		if err != nil {
+			return err
+		}
+
+		if review.Status != TransferReviewPending {
+			return ErrReviewNotPending
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccID: review.FromAccountID,
+			ToAccID:   review.ToAccountID,
+			Amount:    review.Amount,
+			Memo:      review.Memo,
+			Reference: review.Reference,
+			Metadata:  review.Metadata,
+			Limits:    arg.Limits,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := checkProductRules(ctx, q, result.Transfer.FromAccount); err != nil {
+			return err
+		}
+
+		if err := chargeTransferFee(ctx, q, &result.Transfer, nil); err != nil {
+			return err
+		}
+
+		result.Review, err = q.ApproveTransferReview(ctx, ApproveTransferReviewParams{
+			ReviewedBy: arg.ReviewedBy,
+			TransferID: result.Transfer.Transfer.ID,
+			ID:         arg.ReviewID,
+		})
+		if err != nil {
+			return err
+		}
+
+		arg.Audit.Before = review
+		arg.Audit.After = result.Review
+		return recordAuditEvent(ctx, q, arg.Audit)
+	})
+
+	return result, err
+}
@@ -23,7 +23,7 @@ INSERT INTO users (
     $2,
     $3,
     $4
-    ) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+    ) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, deactivated_at
 `
 
 type CreateUserParams struct {
@@ -48,6 +48,31 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.DeactivatedAt,
+	)
+	return i, err
+}
+
+const deactivateUser = `-- name: DeactivateUser :one
+UPDATE users
+SET deactivated_at = now()
+WHERE username = $1 AND deactivated_at IS NULL
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, deactivated_at
+`
+
+func (q *Queries) DeactivateUser(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, deactivateUser, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DeactivatedAt,
 	)
 	return i, err
 }
@@ -62,7 +87,7 @@ func (q *Queries) DeleteUser(ctx context.Context, username string) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users WHERE username = $1 LIMIT 1
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, deactivated_at FROM users WHERE username = $1 LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
@@ -75,12 +100,34 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.DeactivatedAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, deactivated_at FROM users WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.DeactivatedAt,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, deactivated_at FROM users
 WHERE username = ANY($3::text[])
 ORDER BY username
 LIMIT $1
@@ -109,6 +156,8 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 			&i.Email,
 			&i.PasswordChangedAt,
 			&i.CreatedAt,
+			&i.Role,
+			&i.DeactivatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -124,7 +173,7 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 }
 
 const searchUsers = `-- name: SearchUsers :many
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, deactivated_at FROM users
 WHERE username ILIKE '%' || $1 || '%'
 ORDER BY username
 LIMIT $2
@@ -153,6 +202,8 @@ func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]Use
 			&i.Email,
 			&i.PasswordChangedAt,
 			&i.CreatedAt,
+			&i.Role,
+			&i.DeactivatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -169,7 +220,7 @@ func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]Use
 
 const updatePassword = `-- name: UpdatePassword :exec
 UPDATE users
-SET hashed_password = $2
+SET hashed_password = $2, password_changed_at = now()
 WHERE username = $1
 `
 
@@ -182,3 +233,20 @@ func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams)
 	_, err := q.db.ExecContext(ctx, updatePassword, arg.Username, arg.HashedPassword)
 	return err
 }
+
+const updatePasswordHash = `-- name: UpdatePasswordHash :exec
+UPDATE users
+SET hashed_password = $1
+WHERE username = $2 AND hashed_password = $3
+`
+
+type UpdatePasswordHashParams struct {
+	HashedPassword    string `json:"hashed_password"`
+	Username          string `json:"username"`
+	OldHashedPassword string `json:"old_hashed_password"`
+}
+
+func (q *Queries) UpdatePasswordHash(ctx context.Context, arg UpdatePasswordHashParams) error {
+	_, err := q.db.ExecContext(ctx, updatePasswordHash, arg.HashedPassword, arg.Username, arg.OldHashedPassword)
+	return err
+}
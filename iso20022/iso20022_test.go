@@ -0,0 +1,307 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validInitiation() CreditTransferInitiation {
+	return CreditTransferInitiation{
+		MessageID:       "SB-20240301-1",
+		CreatedAt:       time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		InitiatingParty: "Simple Bank",
+		PaymentInfoID:   "SB-20240301-1",
+		ExecutionDate:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Debtor: Party{
+			Name: "Simple Bank Settlement",
+			IBAN: "DE89370400440532013000",
+			BIC:  "COBADEFFXXX",
+		},
+		Transfers: []CreditTransfer{
+			{
+				EndToEndID:     "SB-1",
+				Amount:         12550,
+				Currency:       "EUR",
+				Creditor:       Party{Name: "Acme GmbH", IBAN: "FR1420041010050500013M02606", BIC: "PSSTFRPPXXX"},
+				RemittanceInfo: "Invoice 1",
+			},
+			{
+				EndToEndID: "SB-2",
+				Amount:     1000,
+				Currency:   "USD",
+				Creditor:   Party{Name: "Jane Doe", AccountNumber: "000123456789", ABARouting: "021000021"},
+			},
+		},
+	}
+}
+
+func TestMarshalPain001(t *testing.T) {
+	initiation := validInitiation()
+	require.Equal(t, "135.50", initiation.ControlSum())
+
+	data, err := MarshalPain001(initiation)
+	require.NoError(t, err)
+
+	document := string(data)
+	require.True(t, strings.HasPrefix(document, xml.Header))
+	require.Contains(t, document, `<Document xmlns="`+Pain001Namespace+`">`)
+	require.Contains(t, document, "<NbOfTxs>2</NbOfTxs>")
+	require.Contains(t, document, "<CtrlSum>135.50</CtrlSum>")
+	require.Contains(t, document, "<CreDtTm>2024-03-01T09:30:00</CreDtTm>")
+	require.Contains(t, document, "<ReqdExctnDt>2024-03-01</ReqdExctnDt>")
+	require.Contains(t, document, `<InstdAmt Ccy="EUR">125.50</InstdAmt>`)
+	require.Contains(t, document, "<IBAN>FR1420041010050500013M02606</IBAN>")
+	require.Contains(t, document, "<Ustrd>Invoice 1</Ustrd>")
+	require.Contains(t, document, "<Id>000123456789</Id>")
+	require.NotContains(t, document, "<Othr></Othr>")
+	require.Contains(t, document, "<Cd>USABA</Cd>")
+	require.Contains(t, document, "<MmbId>021000021</MmbId>")
+
+	// what is written reads back
+	var parsed pain001Document
+	require.NoError(t, xml.Unmarshal(data, &parsed))
+	require.Len(t, parsed.Initiation.PaymentInfo.Transactions, 2)
+	require.Equal(t, "SB-2", parsed.Initiation.PaymentInfo.Transactions[1].EndToEndID)
+}
+
+func TestValidatePain001(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(m *CreditTransferInitiation)
+		err    string
+	}{
+		{
+			name:   "NoTransfers",
+			modify: func(m *CreditTransferInitiation) { m.Transfers = nil },
+			err:    "there are no transfers",
+		},
+		{
+			name:   "LongMessageID",
+			modify: func(m *CreditTransferInitiation) { m.MessageID = strings.Repeat("x", 36) },
+			err:    "message id must be 1 to 35 characters",
+		},
+		{
+			name:   "DuplicateEndToEndID",
+			modify: func(m *CreditTransferInitiation) { m.Transfers[1].EndToEndID = "SB-1" },
+			err:    `transfer 2: end to end id "SB-1" is used twice`,
+		},
+		{
+			name:   "NonPositiveAmount",
+			modify: func(m *CreditTransferInitiation) { m.Transfers[0].Amount = 0 },
+			err:    "transfer 1: amount must be positive",
+		},
+		{
+			name:   "UnknownCurrency",
+			modify: func(m *CreditTransferInitiation) { m.Transfers[0].Currency = "XYZ" },
+			err:    `transfer 1: unknown currency "XYZ"`,
+		},
+		{
+			name:   "MalformedIBAN",
+			modify: func(m *CreditTransferInitiation) { m.Transfers[0].Creditor.IBAN = "fr14 2004" },
+			err:    "transfer 1: creditor: IBAN is malformed",
+		},
+		{
+			name:   "MalformedBIC",
+			modify: func(m *CreditTransferInitiation) { m.Debtor.BIC = "COBA" },
+			err:    "debtor: BIC is malformed",
+		},
+		{
+			name:   "AccountNumberWithoutBank",
+			modify: func(m *CreditTransferInitiation) { m.Transfers[1].Creditor.ABARouting = "" },
+			err:    "transfer 2: creditor: an account number needs a BIC or ABA routing number",
+		},
+		{
+			name:   "NoAccount",
+			modify: func(m *CreditTransferInitiation) { m.Transfers[0].Creditor.IBAN = "" },
+			err:    "transfer 1: creditor: has no IBAN or account number",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initiation := validInitiation()
+			tc.modify(&initiation)
+
+			err := initiation.Validate()
+			require.ErrorContains(t, err, tc.err)
+
+			_, err = MarshalPain001(initiation)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParsePain002(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03">
+  <CstmrPmtStsRpt>
+    <GrpHdr><MsgId>BANK-77</MsgId></GrpHdr>
+    <OrgnlGrpInfAndSts>
+      <OrgnlMsgId>SB-20240301-1</OrgnlMsgId>
+      <OrgnlMsgNmId>pain.001.001.03</OrgnlMsgNmId>
+      <GrpSts>PART</GrpSts>
+    </OrgnlGrpInfAndSts>
+    <OrgnlPmtInfAndSts>
+      <OrgnlPmtInfId>SB-20240301-1</OrgnlPmtInfId>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>SB-1</OrgnlEndToEndId>
+        <TxSts>ACSC</TxSts>
+      </TxInfAndSts>
+      <TxInfAndSts>
+        <OrgnlEndToEndId>SB-2</OrgnlEndToEndId>
+        <TxSts>RJCT</TxSts>
+        <StsRsnInf>
+          <Rsn><Cd>AC04</Cd></Rsn>
+          <AddtlInf>Account closed</AddtlInf>
+        </StsRsnInf>
+      </TxInfAndSts>
+    </OrgnlPmtInfAndSts>
+  </CstmrPmtStsRpt>
+</Document>`
+
+	report, err := ParsePain002(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, "BANK-77", report.MessageID)
+	require.Equal(t, "SB-20240301-1", report.OriginalMessageID)
+	require.Equal(t, []TransactionStatus{
+		{EndToEndID: "SB-1", Status: "ACSC"},
+		{EndToEndID: "SB-2", Status: "RJCT", Reason: "AC04", AdditionalInfo: "Account closed"},
+	}, report.Transactions)
+
+	status, ok := report.Status("SB-1")
+	require.True(t, ok)
+	require.Equal(t, OutcomeAccepted, Outcome(status.Status))
+
+	status, ok = report.Status("SB-2")
+	require.True(t, ok)
+	require.Equal(t, OutcomeRejected, Outcome(status.Status))
+
+	// a partial acceptance says nothing of the payments it doesn't list
+	_, ok = report.Status("SB-3")
+	require.False(t, ok)
+}
+
+func TestParsePain002GroupStatus(t *testing.T) {
+	input := `<Document><CstmrPmtStsRpt>
+  <GrpHdr><MsgId>BANK-78</MsgId></GrpHdr>
+  <OrgnlGrpInfAndSts>
+    <OrgnlMsgId>SB-20240301-2</OrgnlMsgId>
+    <GrpSts>RJCT</GrpSts>
+    <StsRsnInf><Rsn><Cd>FF01</Cd></Rsn></StsRsnInf>
+  </OrgnlGrpInfAndSts>
+</CstmrPmtStsRpt></Document>`
+
+	report, err := ParsePain002(strings.NewReader(input))
+	require.NoError(t, err)
+
+	status, ok := report.Status("SB-9")
+	require.True(t, ok)
+	require.Equal(t, TransactionStatus{EndToEndID: "SB-9", Status: "RJCT", Reason: "FF01"}, status)
+
+	_, err = ParsePain002(strings.NewReader("<Document><CstmrPmtStsRpt/></Document>"))
+	require.Error(t, err)
+}
+
+func TestOutcome(t *testing.T) {
+	require.Equal(t, OutcomeAccepted, Outcome("ACSC"))
+	require.Equal(t, OutcomeAccepted, Outcome("ACTC"))
+	require.Equal(t, OutcomeRejected, Outcome("RJCT"))
+	require.Equal(t, OutcomePending, Outcome("PDNG"))
+	require.Equal(t, OutcomePending, Outcome(""))
+}
+
+func validCustomerCreditTransfer() CustomerCreditTransfer {
+	initiation := validInitiation()
+
+	return CustomerCreditTransfer{
+		MessageID:           initiation.MessageID,
+		CreatedAt:           initiation.CreatedAt,
+		SettlementDate:      initiation.ExecutionDate,
+		SettlementMethod:    SettlementInstructedAgent,
+		InstructingAgentBIC: "SIMPDEFFXXX",
+		InstructedAgentBIC:  initiation.Debtor.BIC,
+		Debtor:              initiation.Debtor,
+		Transfers:           initiation.Transfers,
+	}
+}
+
+func TestMarshalPacs008(t *testing.T) {
+	transfer := validCustomerCreditTransfer()
+	require.Equal(t, "135.50", transfer.ControlSum())
+
+	data, err := MarshalPacs008(transfer)
+	require.NoError(t, err)
+
+	document := string(data)
+	require.True(t, strings.HasPrefix(document, xml.Header))
+	require.Contains(t, document, `<Document xmlns="`+Pacs008Namespace+`">`)
+	require.Contains(t, document, "<NbOfTxs>2</NbOfTxs>")
+	require.Contains(t, document, "<CtrlSum>135.50</CtrlSum>")
+	require.Contains(t, document, "<IntrBkSttlmDt>2024-03-01</IntrBkSttlmDt>")
+	require.Contains(t, document, "<SttlmMtd>INDA</SttlmMtd>")
+	require.Contains(t, document, "<BIC>SIMPDEFFXXX</BIC>")
+	require.Contains(t, document, `<IntrBkSttlmAmt Ccy="EUR">125.50</IntrBkSttlmAmt>`)
+	require.Contains(t, document, "<TxId>SB-2</TxId>")
+	require.Contains(t, document, "<MmbId>021000021</MmbId>")
+	require.Contains(t, document, "<Ustrd>Invoice 1</Ustrd>")
+	require.NotContains(t, document, "<Othr></Othr>")
+
+	// what is written reads back
+	var parsed pacs008Document
+	require.NoError(t, xml.Unmarshal(data, &parsed))
+	require.Len(t, parsed.Transfer.Transactions, 2)
+	require.Equal(t, "DE89370400440532013000", parsed.Transfer.Transactions[1].DebtorAccount.IBAN)
+	require.Equal(t, "SB-2", parsed.Transfer.Transactions[1].EndToEndID)
+}
+
+func TestValidatePacs008(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(m *CustomerCreditTransfer)
+		err    string
+	}{
+		{
+			name:   "NoSettlementDate",
+			modify: func(m *CustomerCreditTransfer) { m.SettlementDate = time.Time{} },
+			err:    "settlement date is missing",
+		},
+		{
+			name:   "UnknownSettlementMethod",
+			modify: func(m *CustomerCreditTransfer) { m.SettlementMethod = "COVE" },
+			err:    `unknown settlement method "COVE"`,
+		},
+		{
+			name:   "NoInstructedAgent",
+			modify: func(m *CustomerCreditTransfer) { m.InstructedAgentBIC = "" },
+			err:    "instructed agent BIC is malformed",
+		},
+		{
+			name:   "NoTransfers",
+			modify: func(m *CustomerCreditTransfer) { m.Transfers = nil },
+			err:    "there are no transfers",
+		},
+		{
+			name:   "MalformedCreditor",
+			modify: func(m *CustomerCreditTransfer) { m.Transfers[0].Creditor.IBAN = "fr14 2004" },
+			err:    "transfer 1: creditor: IBAN is malformed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transfer := validCustomerCreditTransfer()
+			transfer.Transfers = append([]CreditTransfer(nil), transfer.Transfers...)
+			tc.modify(&transfer)
+
+			err := transfer.Validate()
+			require.ErrorContains(t, err, tc.err)
+
+			_, err = MarshalPacs008(transfer)
+			require.Error(t, err)
+		})
+	}
+}
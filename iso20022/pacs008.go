@@ -0,0 +1,166 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/money"
+)
+
+// Pacs008Namespace is the namespace of the pacs.008 version written
+const Pacs008Namespace = "urn:iso:std:iso:20022:tech:xsd:pacs.008.001.02"
+
+// Settlement methods of a pacs.008 message
+const (
+	// SettlementInstructedAgent settles through our account with the bank
+	// the message is sent to
+	SettlementInstructedAgent = "INDA"
+	// SettlementInstructingAgent settles through the account the bank the
+	// message is sent to keeps with us
+	SettlementInstructingAgent = "INGA"
+	// SettlementClearing settles through a clearing system
+	SettlementClearing = "CLRG"
+)
+
+// CustomerCreditTransfer asks another bank to credit its customers, or to
+// pass the payments on to the creditors' banks, on behalf of the debtor
+type CustomerCreditTransfer struct {
+	MessageID        string
+	CreatedAt        time.Time
+	SettlementDate   time.Time
+	SettlementMethod string
+	// InstructingAgentBIC is our bank and InstructedAgentBIC the bank the
+	// message is sent to
+	InstructingAgentBIC string
+	InstructedAgentBIC  string
+	Debtor              Party
+	Transfers           []CreditTransfer
+}
+
+// Validate checks the transfer against the rules of the pacs.008 schema that
+// the types don't enforce, returning every problem found
+func (m CustomerCreditTransfer) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validText(m.MessageID, 35), "message id must be 1 to 35 characters")
+	check(!m.CreatedAt.IsZero(), "creation time is missing")
+	check(!m.SettlementDate.IsZero(), "settlement date is missing")
+
+	switch m.SettlementMethod {
+	case SettlementInstructedAgent, SettlementInstructingAgent, SettlementClearing:
+	default:
+		check(false, "unknown settlement method %q", m.SettlementMethod)
+	}
+
+	check(bicPattern.MatchString(m.InstructingAgentBIC), "instructing agent BIC is malformed")
+	check(bicPattern.MatchString(m.InstructedAgentBIC), "instructed agent BIC is malformed")
+	errs = append(errs, validateParty("debtor", m.Debtor)...)
+	errs = append(errs, validateTransfers(m.Transfers)...)
+
+	return errors.Join(errs...)
+}
+
+// ControlSum is the sum of the amounts of the transfers, whatever their
+// currency, as the decimal the pacs.008 header carries
+func (m CustomerCreditTransfer) ControlSum() string {
+	return controlSum(m.Transfers)
+}
+
+// MarshalPacs008 writes a validated transfer as a pacs.008.001.02 document.
+// Every transaction carries the debtor and is settled on the settlement date
+// of the message.
+func MarshalPacs008(m CustomerCreditTransfer) ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	transfer := pacs008Transfer{
+		Header: pacs008Header{
+			MessageID:            m.MessageID,
+			CreatedAt:            m.CreatedAt.UTC().Format("2006-01-02T15:04:05"),
+			NumberOfTransactions: fmt.Sprint(len(m.Transfers)),
+			ControlSum:           m.ControlSum(),
+			SettlementDate:       m.SettlementDate.Format(time.DateOnly),
+			SettlementMethod:     m.SettlementMethod,
+			InstructingAgent:     pain001Agent{BIC: m.InstructingAgentBIC},
+			InstructedAgent:      pain001Agent{BIC: m.InstructedAgentBIC},
+		},
+	}
+
+	for _, credit := range m.Transfers {
+		tx := pacs008Transaction{
+			EndToEndID:    credit.EndToEndID,
+			TransactionID: credit.EndToEndID,
+			Amount: pain001Amount{
+				Currency: credit.Currency,
+				Value:    money.New(credit.Amount, credit.Currency).String(),
+			},
+			ChargeBearer:    "SHAR",
+			Debtor:          pain001PartyName{Name: m.Debtor.Name},
+			DebtorAccount:   newPain001Account(m.Debtor),
+			DebtorAgent:     newPain001Agent(m.Debtor),
+			CreditorAgent:   newPain001Agent(credit.Creditor),
+			Creditor:        pain001PartyName{Name: credit.Creditor.Name},
+			CreditorAccount: newPain001Account(credit.Creditor),
+		}
+
+		if credit.RemittanceInfo != "" {
+			tx.RemittanceInfo = &pain001Remittance{Unstructured: credit.RemittanceInfo}
+		}
+
+		transfer.Transactions = append(transfer.Transactions, tx)
+	}
+
+	data, err := xml.MarshalIndent(pacs008Document{Namespace: Pacs008Namespace, Transfer: transfer}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// The parties, accounts, agents and amounts of a pacs.008 message are the
+// same components as in pain.001, so their pain001 types are used
+
+type pacs008Document struct {
+	XMLName   xml.Name        `xml:"Document"`
+	Namespace string          `xml:"xmlns,attr"`
+	Transfer  pacs008Transfer `xml:"FIToFICstmrCdtTrf"`
+}
+
+type pacs008Transfer struct {
+	Header       pacs008Header        `xml:"GrpHdr"`
+	Transactions []pacs008Transaction `xml:"CdtTrfTxInf"`
+}
+
+type pacs008Header struct {
+	MessageID            string       `xml:"MsgId"`
+	CreatedAt            string       `xml:"CreDtTm"`
+	NumberOfTransactions string       `xml:"NbOfTxs"`
+	ControlSum           string       `xml:"CtrlSum"`
+	SettlementDate       string       `xml:"IntrBkSttlmDt"`
+	SettlementMethod     string       `xml:"SttlmInf>SttlmMtd"`
+	InstructingAgent     pain001Agent `xml:"InstgAgt"`
+	InstructedAgent      pain001Agent `xml:"InstdAgt"`
+}
+
+type pacs008Transaction struct {
+	EndToEndID      string             `xml:"PmtId>EndToEndId"`
+	TransactionID   string             `xml:"PmtId>TxId"`
+	Amount          pain001Amount      `xml:"IntrBkSttlmAmt"`
+	ChargeBearer    string             `xml:"ChrgBr"`
+	Debtor          pain001PartyName   `xml:"Dbtr"`
+	DebtorAccount   pain001Account     `xml:"DbtrAcct"`
+	DebtorAgent     pain001Agent       `xml:"DbtrAgt"`
+	CreditorAgent   pain001Agent       `xml:"CdtrAgt"`
+	Creditor        pain001PartyName   `xml:"Cdtr"`
+	CreditorAccount pain001Account     `xml:"CdtrAcct"`
+	RemittanceInfo  *pain001Remittance `xml:"RmtInf"`
+}
@@ -0,0 +1,334 @@
+// Package iso20022 writes the ISO 20022 messages we exchange with our
+// correspondent bank: pain.001 customer credit transfer initiations or, where
+// we take part in the clearing ourselves, pacs.008 interbank customer credit
+// transfers for external payouts, and reads the pain.002 payment status
+// reports it sends back.
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Srinath-exe/simplebank/money"
+)
+
+// Pain001Namespace is the namespace of the pain.001 version written
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+var (
+	ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[a-zA-Z0-9]{1,30}$`)
+	bicPattern  = regexp.MustCompile(`^[A-Z]{6}[A-Z2-9][A-NP-Z0-9]([A-Z0-9]{3})?$`)
+	abaPattern  = regexp.MustCompile(`^[0-9]{9}$`)
+)
+
+// Party is the holder of an account and the bank that keeps it. The account
+// is an IBAN or, where IBANs aren't used, an account number at a bank named
+// by its BIC or its US ABA routing number.
+type Party struct {
+	Name          string
+	IBAN          string
+	AccountNumber string
+	BIC           string
+	ABARouting    string
+}
+
+// CreditTransfer is a payment to a creditor
+type CreditTransfer struct {
+	// EndToEndID identifies the payment to the creditor and in the status
+	// reports of the bank
+	EndToEndID string
+	// Amount is in minor units of Currency
+	Amount   int64
+	Currency string
+	Creditor Party
+	// RemittanceInfo tells the creditor what the payment is for
+	RemittanceInfo string
+}
+
+// CreditTransferInitiation asks the bank to make credit transfers from the
+// debtor's account
+type CreditTransferInitiation struct {
+	MessageID       string
+	CreatedAt       time.Time
+	InitiatingParty string
+	// PaymentInfoID identifies the payments as a whole
+	PaymentInfoID string
+	ExecutionDate time.Time
+	Debtor        Party
+	Transfers     []CreditTransfer
+}
+
+// Validate checks the initiation against the rules of the pain.001 schema
+// that the types don't enforce, returning every problem found
+func (m CreditTransferInitiation) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validText(m.MessageID, 35), "message id must be 1 to 35 characters")
+	check(validText(m.PaymentInfoID, 35), "payment info id must be 1 to 35 characters")
+	check(validText(m.InitiatingParty, 140), "initiating party must be 1 to 140 characters")
+	check(!m.CreatedAt.IsZero(), "creation time is missing")
+	check(!m.ExecutionDate.IsZero(), "execution date is missing")
+	errs = append(errs, validateParty("debtor", m.Debtor)...)
+	errs = append(errs, validateTransfers(m.Transfers)...)
+
+	return errors.Join(errs...)
+}
+
+// validateTransfers checks the transfers of a message, which must have at
+// least one
+func validateTransfers(transfers []CreditTransfer) []error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(len(transfers) > 0, "there are no transfers")
+
+	endToEndIDs := make(map[string]bool, len(transfers))
+	for i, transfer := range transfers {
+		name := fmt.Sprintf("transfer %d", i+1)
+
+		check(validText(transfer.EndToEndID, 35), "%s: end to end id must be 1 to 35 characters", name)
+		check(!endToEndIDs[transfer.EndToEndID], "%s: end to end id %q is used twice", name, transfer.EndToEndID)
+		endToEndIDs[transfer.EndToEndID] = true
+
+		_, known := money.Exponent(transfer.Currency)
+		check(known, "%s: unknown currency %q", name, transfer.Currency)
+		check(transfer.Amount > 0, "%s: amount must be positive", name)
+		check(utf8.RuneCountInString(transfer.RemittanceInfo) <= 140, "%s: remittance info must be at most 140 characters", name)
+		errs = append(errs, validateParty(name+": creditor", transfer.Creditor)...)
+	}
+
+	return errs
+}
+
+func validateParty(name string, party Party) []error {
+	var errs []error
+	check := func(ok bool, format string) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", name, format))
+		}
+	}
+
+	check(validText(party.Name, 140), "name must be 1 to 140 characters")
+	check(party.BIC == "" || bicPattern.MatchString(party.BIC), "BIC is malformed")
+	check(party.ABARouting == "" || abaPattern.MatchString(party.ABARouting), "ABA routing number is malformed")
+
+	switch {
+	case party.IBAN != "":
+		check(ibanPattern.MatchString(party.IBAN), "IBAN is malformed")
+		check(party.AccountNumber == "", "has both an IBAN and an account number")
+	case party.AccountNumber != "":
+		check(validText(party.AccountNumber, 34), "account number must be 1 to 34 characters")
+		check(party.BIC != "" || party.ABARouting != "", "an account number needs a BIC or ABA routing number")
+	default:
+		check(false, "has no IBAN or account number")
+	}
+
+	return errs
+}
+
+func validText(s string, max int) bool {
+	n := utf8.RuneCountInString(s)
+	return n > 0 && n <= max
+}
+
+// ControlSum is the sum of the amounts of the transfers, whatever their
+// currency, as the decimal the pain.001 header carries
+func (m CreditTransferInitiation) ControlSum() string {
+	return controlSum(m.Transfers)
+}
+
+func controlSum(transfers []CreditTransfer) string {
+	sum := new(big.Rat)
+	decimals := 0
+
+	for _, transfer := range transfers {
+		exponent, _ := money.Exponent(transfer.Currency)
+		decimals = max(decimals, exponent)
+
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+		sum.Add(sum, new(big.Rat).SetFrac(big.NewInt(transfer.Amount), scale))
+	}
+
+	return sum.FloatString(decimals)
+}
+
+// MarshalPain001 writes a validated initiation as a pain.001.001.03 document
+func MarshalPain001(m CreditTransferInitiation) ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	numberOfTransactions := fmt.Sprint(len(m.Transfers))
+	controlSum := m.ControlSum()
+
+	info := pain001PaymentInfo{
+		PaymentInfoID:        m.PaymentInfoID,
+		PaymentMethod:        "TRF",
+		NumberOfTransactions: numberOfTransactions,
+		ControlSum:           controlSum,
+		ExecutionDate:        m.ExecutionDate.Format(time.DateOnly),
+		Debtor:               pain001PartyName{Name: m.Debtor.Name},
+		DebtorAccount:        newPain001Account(m.Debtor),
+		DebtorAgent:          newPain001Agent(m.Debtor),
+		ChargeBearer:         "SHAR",
+	}
+
+	for _, transfer := range m.Transfers {
+		tx := pain001Transaction{
+			EndToEndID: transfer.EndToEndID,
+			Amount: pain001Amount{
+				Currency: transfer.Currency,
+				Value:    money.New(transfer.Amount, transfer.Currency).String(),
+			},
+			Creditor:        pain001PartyName{Name: transfer.Creditor.Name},
+			CreditorAccount: newPain001Account(transfer.Creditor),
+		}
+
+		if transfer.Creditor.BIC != "" || transfer.Creditor.ABARouting != "" {
+			agent := newPain001Agent(transfer.Creditor)
+			tx.CreditorAgent = &agent
+		}
+
+		if transfer.RemittanceInfo != "" {
+			tx.RemittanceInfo = &pain001Remittance{Unstructured: transfer.RemittanceInfo}
+		}
+
+		info.Transactions = append(info.Transactions, tx)
+	}
+
+	document := pain001Document{
+		Namespace: Pain001Namespace,
+		Initiation: pain001Initiation{
+			Header: pain001Header{
+				MessageID:            m.MessageID,
+				CreatedAt:            m.CreatedAt.UTC().Format("2006-01-02T15:04:05"),
+				NumberOfTransactions: numberOfTransactions,
+				ControlSum:           controlSum,
+				InitiatingParty:      pain001PartyName{Name: m.InitiatingParty},
+			},
+			PaymentInfo: info,
+		},
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+type pain001Document struct {
+	XMLName    xml.Name          `xml:"Document"`
+	Namespace  string            `xml:"xmlns,attr"`
+	Initiation pain001Initiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiation struct {
+	Header      pain001Header      `xml:"GrpHdr"`
+	PaymentInfo pain001PaymentInfo `xml:"PmtInf"`
+}
+
+type pain001Header struct {
+	MessageID            string           `xml:"MsgId"`
+	CreatedAt            string           `xml:"CreDtTm"`
+	NumberOfTransactions string           `xml:"NbOfTxs"`
+	ControlSum           string           `xml:"CtrlSum"`
+	InitiatingParty      pain001PartyName `xml:"InitgPty"`
+}
+
+type pain001PaymentInfo struct {
+	PaymentInfoID        string               `xml:"PmtInfId"`
+	PaymentMethod        string               `xml:"PmtMtd"`
+	NumberOfTransactions string               `xml:"NbOfTxs"`
+	ControlSum           string               `xml:"CtrlSum"`
+	ExecutionDate        string               `xml:"ReqdExctnDt"`
+	Debtor               pain001PartyName     `xml:"Dbtr"`
+	DebtorAccount        pain001Account       `xml:"DbtrAcct"`
+	DebtorAgent          pain001Agent         `xml:"DbtrAgt"`
+	ChargeBearer         string               `xml:"ChrgBr"`
+	Transactions         []pain001Transaction `xml:"CdtTrfTxInf"`
+}
+
+type pain001Transaction struct {
+	EndToEndID      string             `xml:"PmtId>EndToEndId"`
+	Amount          pain001Amount      `xml:"Amt>InstdAmt"`
+	CreditorAgent   *pain001Agent      `xml:"CdtrAgt"`
+	Creditor        pain001PartyName   `xml:"Cdtr"`
+	CreditorAccount pain001Account     `xml:"CdtrAcct"`
+	RemittanceInfo  *pain001Remittance `xml:"RmtInf"`
+}
+
+type pain001PartyName struct {
+	Name string `xml:"Nm"`
+}
+
+type pain001Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// pain001Other identifies an account or bank by other means than an IBAN or
+// BIC. encoding/xml writes the parents of empty omitted elements, so optional
+// elements that have children are pointers.
+type pain001Other struct {
+	ID string `xml:"Id"`
+}
+
+type pain001Account struct {
+	IBAN  string        `xml:"Id>IBAN,omitempty"`
+	Other *pain001Other `xml:"Id>Othr"`
+}
+
+func newPain001Account(party Party) pain001Account {
+	if party.IBAN != "" {
+		return pain001Account{IBAN: party.IBAN}
+	}
+
+	return pain001Account{Other: &pain001Other{ID: party.AccountNumber}}
+}
+
+type pain001Agent struct {
+	BIC      string                 `xml:"FinInstnId>BIC,omitempty"`
+	Clearing *pain001ClearingMember `xml:"FinInstnId>ClrSysMmbId"`
+	Other    *pain001Other          `xml:"FinInstnId>Othr"`
+}
+
+type pain001ClearingMember struct {
+	System   string `xml:"ClrSysId>Cd"`
+	MemberID string `xml:"MmbId"`
+}
+
+// newPain001Agent names the bank of party by its BIC, and by its routing
+// number in the US ABA clearing system if it has one. A bank with neither is
+// left to the bank receiving the message to tell from the account.
+func newPain001Agent(party Party) pain001Agent {
+	agent := pain001Agent{BIC: party.BIC}
+	if party.ABARouting != "" {
+		agent.Clearing = &pain001ClearingMember{System: "USABA", MemberID: party.ABARouting}
+	}
+
+	if agent.BIC == "" && agent.Clearing == nil {
+		agent.Other = &pain001Other{ID: "NOTPROVIDED"}
+	}
+
+	return agent
+}
+
+type pain001Remittance struct {
+	Unstructured string `xml:"Ustrd"`
+}
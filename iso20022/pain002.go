@@ -0,0 +1,153 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Outcomes of a payment status
+const (
+	OutcomeAccepted = "accepted"
+	OutcomeRejected = "rejected"
+	OutcomePending  = "pending"
+)
+
+// Outcome tells whether a status code of a pain.002 report accepts or
+// rejects a payment, or leaves it pending
+func Outcome(status string) string {
+	switch status {
+	case "ACCP", "ACSC", "ACSP", "ACTC", "ACWC", "ACFC", "ACCC":
+		return OutcomeAccepted
+	case "RJCT":
+		return OutcomeRejected
+	}
+
+	return OutcomePending
+}
+
+// TransactionStatus is the status of a payment in a status report
+type TransactionStatus struct {
+	EndToEndID string
+	Status     string
+	// Reason is the ISO code of why the payment has its status, e.g. AC04
+	// for a closed account
+	Reason         string
+	AdditionalInfo string
+}
+
+// PaymentStatusReport is a pain.002 report on the payments of a message we
+// sent
+type PaymentStatusReport struct {
+	MessageID         string
+	OriginalMessageID string
+	// GroupStatus applies to the payments of the message the report doesn't
+	// list
+	GroupStatus  string
+	GroupReason  string
+	Transactions []TransactionStatus
+}
+
+// Status returns the status of a payment, from the report's transactions or
+// else from the status of the whole message, or false if the report doesn't
+// say
+func (r PaymentStatusReport) Status(endToEndID string) (TransactionStatus, bool) {
+	for _, transaction := range r.Transactions {
+		if transaction.EndToEndID == endToEndID {
+			return transaction, true
+		}
+	}
+
+	// a partially accepted message leaves each payment to its own status
+	if r.GroupStatus == "" || r.GroupStatus == "PART" {
+		return TransactionStatus{}, false
+	}
+
+	return TransactionStatus{EndToEndID: endToEndID, Status: r.GroupStatus, Reason: r.GroupReason}, true
+}
+
+type pain002Document struct {
+	Report struct {
+		MessageID   string `xml:"GrpHdr>MsgId"`
+		GroupStatus struct {
+			OriginalMessageID string          `xml:"OrgnlMsgId"`
+			Status            string          `xml:"GrpSts"`
+			Reasons           []pain002Reason `xml:"StsRsnInf"`
+		} `xml:"OrgnlGrpInfAndSts"`
+		PaymentInfos []struct {
+			Status       string          `xml:"PmtInfSts"`
+			Reasons      []pain002Reason `xml:"StsRsnInf"`
+			Transactions []struct {
+				EndToEndID string          `xml:"OrgnlEndToEndId"`
+				Status     string          `xml:"TxSts"`
+				Reasons    []pain002Reason `xml:"StsRsnInf"`
+			} `xml:"TxInfAndSts"`
+		} `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002Reason struct {
+	Code           string   `xml:"Rsn>Cd"`
+	AdditionalInfo []string `xml:"AddtlInf"`
+}
+
+// reason returns the code and additional information of the first reason
+func reason(reasons []pain002Reason) (string, string) {
+	if len(reasons) == 0 {
+		return "", ""
+	}
+
+	return strings.TrimSpace(reasons[0].Code), strings.TrimSpace(strings.Join(reasons[0].AdditionalInfo, " "))
+}
+
+// ParsePain002 reads a pain.002 customer payment status report. Payments
+// without a status of their own take the status of their payment
+// information block. Elements are matched whatever their namespace, so the
+// versions of the message read alike.
+func ParsePain002(r io.Reader) (PaymentStatusReport, error) {
+	var document pain002Document
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return PaymentStatusReport{}, fmt.Errorf("cannot read the pain.002 report: %w", err)
+	}
+
+	report := document.Report
+	if report.GroupStatus.OriginalMessageID == "" {
+		return PaymentStatusReport{}, errors.New("pain.002 report doesn't name the original message")
+	}
+
+	result := PaymentStatusReport{
+		MessageID:         strings.TrimSpace(report.MessageID),
+		OriginalMessageID: strings.TrimSpace(report.GroupStatus.OriginalMessageID),
+		GroupStatus:       strings.TrimSpace(report.GroupStatus.Status),
+		Transactions:      []TransactionStatus{},
+	}
+	result.GroupReason, _ = reason(report.GroupStatus.Reasons)
+
+	for _, info := range report.PaymentInfos {
+		// the messages we send have a single payment information block, so
+		// the status of a block listing no payments is that of the message
+		if len(info.Transactions) == 0 && result.GroupStatus == "" {
+			result.GroupStatus = strings.TrimSpace(info.Status)
+			result.GroupReason, _ = reason(info.Reasons)
+		}
+
+		for _, transaction := range info.Transactions {
+			status := TransactionStatus{
+				EndToEndID: strings.TrimSpace(transaction.EndToEndID),
+				Status:     strings.TrimSpace(transaction.Status),
+			}
+			status.Reason, status.AdditionalInfo = reason(transaction.Reasons)
+
+			if status.Status == "" {
+				status.Status = strings.TrimSpace(info.Status)
+				status.Reason, status.AdditionalInfo = reason(info.Reasons)
+			}
+
+			result.Transactions = append(result.Transactions, status)
+		}
+	}
+
+	return result, nil
+}
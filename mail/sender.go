@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// EmailSender sends plain text emails
+type EmailSender interface {
+	SendEmail(subject string, content string, to []string) error
+}
+
+// SMTPSender sends emails through an SMTP server
+type SMTPSender struct {
+	from     mail.Address
+	address  string
+	username string
+	password string
+}
+
+// NewSMTPSender creates a new SMTPSender. The server address is in host:port
+// form; username may be empty for servers that don't require authentication.
+func NewSMTPSender(name string, fromAddress string, address string, username string, password string) EmailSender {
+	return &SMTPSender{
+		from:     mail.Address{Name: name, Address: fromAddress},
+		address:  address,
+		username: username,
+		password: password,
+	}
+}
+
+// SendEmail sends a plain text email to the given recipients
+func (sender *SMTPSender) SendEmail(subject string, content string, to []string) error {
+	var auth smtp.Auth
+	if sender.username != "" {
+		host, _, err := net.SplitHostPort(sender.address)
+		if err != nil {
+			return fmt.Errorf("invalid smtp address: %w", err)
+		}
+		auth = smtp.PlainAuth("", sender.username, sender.password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", sender.from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(content)
+
+	err := smtp.SendMail(sender.address, auth, sender.from.Address, to, []byte(msg.String()))
+	if err != nil {
+		return fmt.Errorf("cannot send email: %w", err)
+	}
+
+	return nil
+}
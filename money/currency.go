@@ -0,0 +1,79 @@
+package money
+
+import "sync"
+
+// exponents holds the number of minor units of ISO 4217 currencies, e.g. 2
+// for USD where 1 dollar is 100 cents. They are the defaults until the
+// currencies of the bank are registered.
+var exponents = map[string]int{
+	"AED": 2,
+	"ARS": 2,
+	"AUD": 2,
+	"BHD": 3,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CLP": 0,
+	"CNY": 2,
+	"CZK": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"HUF": 2,
+	"IDR": 2,
+	"ILS": 2,
+	"INR": 2,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"MYR": 2,
+	"NOK": 2,
+	"NZD": 2,
+	"OMR": 3,
+	"PHP": 2,
+	"PLN": 2,
+	"SAR": 2,
+	"SEK": 2,
+	"SGD": 2,
+	"THB": 2,
+	"TND": 3,
+	"TRY": 2,
+	"TWD": 2,
+	"UGX": 0,
+	"USD": 2,
+	"VND": 0,
+	"ZAR": 2,
+}
+
+var (
+	registeredMu sync.RWMutex
+	registered   = map[string]int{}
+)
+
+// Register sets the number of minor units of a currency, taking precedence
+// over the ISO 4217 defaults. Currencies unknown to both are not accepted by
+// Parse.
+func Register(currency string, exponent int) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+
+	registered[currency] = exponent
+}
+
+// Exponent returns the number of minor units of a currency
+func Exponent(currency string) (int, bool) {
+	registeredMu.RLock()
+	exponent, ok := registered[currency]
+	registeredMu.RUnlock()
+
+	if ok {
+		return exponent, true
+	}
+
+	exponent, ok = exponents[currency]
+	return exponent, ok
+}
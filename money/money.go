@@ -0,0 +1,176 @@
+// Package money represents amounts of money as a whole number of minor units
+// of a currency, e.g. cents for USD, and converts them to and from the
+// decimal strings used in the API.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrTooPrecise       = errors.New("amount has more decimal places than the currency allows")
+	ErrOverflow         = errors.New("amount is out of range")
+	ErrCurrencyMismatch = errors.New("currencies don't match")
+)
+
+// Money is an amount in the minor units of a currency
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// New returns amount minor units of currency
+func New(amount int64, currency string) Money {
+	return Money{amount: amount, currency: currency}
+}
+
+// Parse parses a decimal amount such as "12.34" in the major units of
+// currency. It fails if the amount has more decimal places than the currency
+// has minor units, trailing zeros aside.
+func Parse(s string, currency string) (Money, error) {
+	exponent, ok := Exponent(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+
+	digits, negative := strings.CutPrefix(s, "-")
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+
+	if !isDigits(whole) || (hasPoint && !isDigits(fraction)) {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > exponent {
+		return Money{}, fmt.Errorf("%w: %q in %s", ErrTooPrecise, s, currency)
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrOverflow, s)
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return New(amount, currency), nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Amount returns the amount in minor units
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the ISO 4217 code of the currency
+func (m Money) Currency() string {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Add returns m + other. Both must be in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+
+	if (other.amount > 0 && m.amount > math.MaxInt64-other.amount) ||
+		(other.amount < 0 && m.amount < math.MinInt64-other.amount) {
+		return Money{}, ErrOverflow
+	}
+
+	return New(m.amount+other.amount, m.currency), nil
+}
+
+// Sub returns m - other. Both must be in the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	negated, err := other.Neg()
+	if err != nil {
+		return Money{}, err
+	}
+	return m.Add(negated)
+}
+
+// Mul returns m multiplied by n
+func (m Money) Mul(n int64) (Money, error) {
+	if m.amount == 0 || n == 0 {
+		return New(0, m.currency), nil
+	}
+
+	product := m.amount * n
+	if product/n != m.amount || (m.amount == -1 && n == math.MinInt64) || (n == -1 && m.amount == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+
+	return New(product, m.currency), nil
+}
+
+// Neg returns -m
+func (m Money) Neg() (Money, error) {
+	if m.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return New(-m.amount, m.currency), nil
+}
+
+// String formats the amount as a decimal in the major units of the currency,
+// e.g. "12.34" for 1234 cents. Amounts in currencies without a known exponent
+// are written in minor units.
+func (m Money) String() string {
+	exponent, _ := Exponent(m.currency)
+
+	sign := ""
+	abs := uint64(m.amount)
+	if m.amount < 0 {
+		sign = "-"
+		abs = -abs
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	point := len(digits) - exponent
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalJSON writes the amount as a decimal string so clients don't lose
+// precision or have to know the exponent of the currency
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
@@ -0,0 +1,126 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		currency string
+		amount   int64
+		err      error
+	}{
+		{input: "12.34", currency: "USD", amount: 1234},
+		{input: "12.3", currency: "USD", amount: 1230},
+		{input: "12", currency: "USD", amount: 1200},
+		{input: "0.01", currency: "USD", amount: 1},
+		{input: "12.340", currency: "USD", amount: 1234},
+		{input: "-5.5", currency: "EUR", amount: -550},
+		{input: "1500", currency: "JPY", amount: 1500},
+		{input: "1.234", currency: "KWD", amount: 1234},
+		{input: "12.345", currency: "USD", err: ErrTooPrecise},
+		{input: "1.5", currency: "JPY", err: ErrTooPrecise},
+		{input: "12.34", currency: "XYZ", err: ErrUnknownCurrency},
+		{input: "", currency: "USD", err: ErrInvalidAmount},
+		{input: ".5", currency: "USD", err: ErrInvalidAmount},
+		{input: "5.", currency: "USD", err: ErrInvalidAmount},
+		{input: "1e3", currency: "USD", err: ErrInvalidAmount},
+		{input: "+5", currency: "USD", err: ErrInvalidAmount},
+		{input: "1,000.00", currency: "USD", err: ErrInvalidAmount},
+		{input: "92233720368547758.08", currency: "USD", err: ErrOverflow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input+" "+tc.currency, func(t *testing.T) {
+			m, err := Parse(tc.input, tc.currency)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.amount, m.Amount())
+			require.Equal(t, tc.currency, m.Currency())
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	_, err := Parse("1.5", "XBT")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+
+	Register("XBT", 4)
+	m, err := Parse("1.5", "XBT")
+	require.NoError(t, err)
+	require.Equal(t, int64(15000), m.Amount())
+	require.Equal(t, "1.5000", m.String())
+
+	// the registered minor units take precedence over the ISO 4217 default
+	Register("ISK", 2)
+	defer Register("ISK", 0)
+	require.Equal(t, "12.34", New(1234, "ISK").String())
+}
+
+func TestString(t *testing.T) {
+	require.Equal(t, "12.34", New(1234, "USD").String())
+	require.Equal(t, "0.05", New(5, "USD").String())
+	require.Equal(t, "0.00", New(0, "EUR").String())
+	require.Equal(t, "-0.50", New(-50, "CAD").String())
+	require.Equal(t, "1500", New(1500, "JPY").String())
+	require.Equal(t, "1.234", New(1234, "BHD").String())
+	require.Equal(t, "-92233720368547758.08", New(math.MinInt64, "USD").String())
+	require.Equal(t, "1234", New(1234, "XYZ").String())
+}
+
+func TestMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Balance Money `json:"balance"`
+	}{Balance: New(1234, "USD")})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"balance":"12.34"}`, string(data))
+}
+
+func TestArithmetic(t *testing.T) {
+	a := New(1000, "USD")
+	b := New(250, "USD")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, New(1250, "USD"), sum)
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	require.Equal(t, New(-750, "USD"), diff)
+	require.True(t, diff.IsNegative())
+
+	product, err := b.Mul(4)
+	require.NoError(t, err)
+	require.Equal(t, a, product)
+
+	_, err = a.Add(New(1, "EUR"))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = New(math.MaxInt64, "USD").Add(New(1, "USD"))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = New(math.MinInt64, "USD").Sub(New(1, "USD"))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = New(0, "USD").Sub(New(math.MinInt64, "USD"))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = New(math.MaxInt64/2+1, "USD").Mul(2)
+	require.ErrorIs(t, err, ErrOverflow)
+
+	_, err = New(math.MinInt64, "USD").Mul(-1)
+	require.ErrorIs(t, err, ErrOverflow)
+
+	zero, err := New(math.MaxInt64, "USD").Mul(0)
+	require.NoError(t, err)
+	require.True(t, zero.IsZero())
+}
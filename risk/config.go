@@ -0,0 +1,133 @@
+package risk
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Types of rules in the rules file
+const (
+	RuleTypeNewPayeeAmount     = "new_payee_amount"
+	RuleTypeVelocity           = "velocity"
+	RuleTypeRoundAmountAtNight = "round_amount_at_night"
+)
+
+type rulesFile struct {
+	Rules []ruleConfig `yaml:"rules"`
+}
+
+type ruleConfig struct {
+	Name      string           `yaml:"name"`
+	Type      string           `yaml:"type"`
+	Outcome   string           `yaml:"outcome"`
+	MinAmount map[string]int64 `yaml:"min_amount"`
+	Window    time.Duration    `yaml:"window"`
+	MaxCount  int64            `yaml:"max_count"`
+	Multiple  int64            `yaml:"multiple"`
+	StartHour int              `yaml:"start_hour"`
+	EndHour   int              `yaml:"end_hour"`
+	Timezone  string           `yaml:"timezone"`
+}
+
+// LoadEngine creates an engine from the rules in a YAML file. An empty path
+// gives an engine without rules.
+func LoadEngine(path string) (*Engine, error) {
+	if path == "" {
+		return NewEngine(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read risk rules: %w", err)
+	}
+
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEngine(rules...), nil
+}
+
+// ParseRules parses the rules of a YAML rules file
+func ParseRules(data []byte) ([]Rule, error) {
+	var file rulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse risk rules: %w", err)
+	}
+
+	rules := make([]Rule, 0, len(file.Rules))
+	for i, config := range file.Rules {
+		rule, err := config.rule()
+		if err != nil {
+			return nil, fmt.Errorf("risk rule %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (config ruleConfig) rule() (Rule, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	outcome, err := ParseDecision(config.Outcome)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.Type {
+	case RuleTypeNewPayeeAmount:
+		if len(config.MinAmount) == 0 {
+			return nil, fmt.Errorf("%s needs min_amount", config.Type)
+		}
+		return &NewPayeeAmountRule{
+			RuleName:   config.Name,
+			Outcome:    outcome,
+			MinAmounts: config.MinAmount,
+		}, nil
+
+	case RuleTypeVelocity:
+		if config.Window <= 0 || config.MaxCount <= 0 {
+			return nil, fmt.Errorf("%s needs a positive window and max_count", config.Type)
+		}
+		return &VelocityRule{
+			RuleName: config.Name,
+			Outcome:  outcome,
+			Window:   config.Window,
+			MaxCount: config.MaxCount,
+		}, nil
+
+	case RuleTypeRoundAmountAtNight:
+		if config.Multiple <= 0 {
+			return nil, fmt.Errorf("%s needs a positive multiple", config.Type)
+		}
+		if config.StartHour < 0 || config.StartHour > 23 || config.EndHour < 0 || config.EndHour > 23 {
+			return nil, fmt.Errorf("%s hours must be between 0 and 23", config.Type)
+		}
+
+		location := time.UTC
+		if config.Timezone != "" {
+			location, err = time.LoadLocation(config.Timezone)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &RoundAmountAtNightRule{
+			RuleName:  config.Name,
+			Outcome:   outcome,
+			Multiple:  config.Multiple,
+			StartHour: config.StartHour,
+			EndHour:   config.EndHour,
+			Location:  location,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown rule type %q", config.Type)
+}
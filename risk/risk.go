@@ -0,0 +1,105 @@
+// Package risk scores transfers against a set of rules before they are
+// executed. Each rule can allow a transfer, hold it for review by a banker or
+// deny it; the strictest outcome wins.
+package risk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Decision is the outcome of evaluating a transfer
+type Decision string
+
+// Decisions ordered from the most to the least permissive
+const (
+	Allow  Decision = "allow"
+	Review Decision = "review"
+	Deny   Decision = "deny"
+)
+
+func (d Decision) severity() int {
+	switch d {
+	case Review:
+		return 1
+	case Deny:
+		return 2
+	}
+	return 0
+}
+
+// ParseDecision parses the outcome of a rule in the rules file
+func ParseDecision(s string) (Decision, error) {
+	switch d := Decision(s); d {
+	case Allow, Review, Deny:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown risk decision %q", s)
+}
+
+// Transfer is the transfer being evaluated
+type Transfer struct {
+	FromAccountID int64
+	ToAccountID   int64
+	Owner         string
+	Amount        int64
+	Currency      string
+	Time          time.Time
+}
+
+// History answers questions rules ask about past transfers
+type History interface {
+	// CountTransfersBetween counts the transfers ever sent from one account to another
+	CountTransfersBetween(ctx context.Context, fromAccountID int64, toAccountID int64) (int64, error)
+	// CountTransfersSince counts the transfers sent from an account since a time
+	CountTransfersSince(ctx context.Context, fromAccountID int64, since time.Time) (int64, error)
+}
+
+// Rule is a single check on a transfer. Evaluate returns Allow when the rule
+// doesn't apply to the transfer.
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, transfer Transfer, history History) (Decision, error)
+}
+
+// Result is the combined decision of all rules and the names of the rules
+// that didn't allow the transfer
+type Result struct {
+	Decision Decision `json:"decision"`
+	Rules    []string `json:"rules"`
+}
+
+// Engine evaluates transfers against its rules
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine creates an engine with the given rules. An engine without rules
+// allows every transfer.
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Evaluate runs every rule and returns the strictest decision
+func (engine *Engine) Evaluate(ctx context.Context, transfer Transfer, history History) (Result, error) {
+	result := Result{Decision: Allow, Rules: []string{}}
+
+	for _, rule := range engine.rules {
+		decision, err := rule.Evaluate(ctx, transfer, history)
+		if err != nil {
+			return result, fmt.Errorf("risk rule %s: %w", rule.Name(), err)
+		}
+
+		if decision == Allow {
+			continue
+		}
+
+		result.Rules = append(result.Rules, rule.Name())
+		if decision.severity() > result.Decision.severity() {
+			result.Decision = decision
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHistory struct {
+	between int64
+	since   int64
+	err     error
+}
+
+func (history fakeHistory) CountTransfersBetween(ctx context.Context, fromAccountID int64, toAccountID int64) (int64, error) {
+	return history.between, history.err
+}
+
+func (history fakeHistory) CountTransfersSince(ctx context.Context, fromAccountID int64, since time.Time) (int64, error) {
+	return history.since, history.err
+}
+
+func newTransfer(amount int64, at time.Time) Transfer {
+	return Transfer{
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Owner:         "alice",
+		Amount:        amount,
+		Currency:      "USD",
+		Time:          at,
+	}
+}
+
+func TestNewPayeeAmountRule(t *testing.T) {
+	rule := &NewPayeeAmountRule{RuleName: "new_payee", Outcome: Review, MinAmounts: map[string]int64{"USD": 1000}}
+	ctx := context.Background()
+	now := time.Now()
+
+	decision, err := rule.Evaluate(ctx, newTransfer(1000, now), fakeHistory{})
+	require.NoError(t, err)
+	require.Equal(t, Review, decision)
+
+	decision, err = rule.Evaluate(ctx, newTransfer(1000, now), fakeHistory{between: 1})
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+
+	decision, err = rule.Evaluate(ctx, newTransfer(999, now), fakeHistory{})
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+
+	transfer := newTransfer(5000, now)
+	transfer.Currency = "EUR"
+	decision, err = rule.Evaluate(ctx, transfer, fakeHistory{})
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+}
+
+func TestVelocityRule(t *testing.T) {
+	rule := &VelocityRule{RuleName: "velocity", Outcome: Deny, Window: time.Minute, MaxCount: 3}
+	ctx := context.Background()
+
+	decision, err := rule.Evaluate(ctx, newTransfer(10, time.Now()), fakeHistory{since: 2})
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+
+	decision, err = rule.Evaluate(ctx, newTransfer(10, time.Now()), fakeHistory{since: 3})
+	require.NoError(t, err)
+	require.Equal(t, Deny, decision)
+}
+
+func TestRoundAmountAtNightRule(t *testing.T) {
+	rule := &RoundAmountAtNightRule{RuleName: "night", Outcome: Review, Multiple: 100, StartHour: 0, EndHour: 5, Location: time.UTC}
+	ctx := context.Background()
+	night := time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC)
+	day := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+
+	decision, err := rule.Evaluate(ctx, newTransfer(500, night), fakeHistory{})
+	require.NoError(t, err)
+	require.Equal(t, Review, decision)
+
+	decision, err = rule.Evaluate(ctx, newTransfer(550, night), fakeHistory{})
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+
+	decision, err = rule.Evaluate(ctx, newTransfer(500, day), fakeHistory{})
+	require.NoError(t, err)
+	require.Equal(t, Allow, decision)
+}
+
+func TestEngineStrictestDecisionWins(t *testing.T) {
+	engine := NewEngine(
+		&VelocityRule{RuleName: "velocity", Outcome: Deny, Window: time.Minute, MaxCount: 1},
+		&NewPayeeAmountRule{RuleName: "new_payee", Outcome: Review, MinAmounts: map[string]int64{"USD": 1}},
+	)
+	ctx := context.Background()
+
+	result, err := engine.Evaluate(ctx, newTransfer(10, time.Now()), fakeHistory{since: 1})
+	require.NoError(t, err)
+	require.Equal(t, Deny, result.Decision)
+	require.Equal(t, []string{"velocity", "new_payee"}, result.Rules)
+
+	result, err = engine.Evaluate(ctx, newTransfer(10, time.Now()), fakeHistory{between: 1})
+	require.NoError(t, err)
+	require.Equal(t, Allow, result.Decision)
+	require.Empty(t, result.Rules)
+
+	_, err = engine.Evaluate(ctx, newTransfer(10, time.Now()), fakeHistory{err: errors.New("db down")})
+	require.Error(t, err)
+}
+
+func TestEngineWithoutRules(t *testing.T) {
+	engine, err := LoadEngine("")
+	require.NoError(t, err)
+
+	result, err := engine.Evaluate(context.Background(), newTransfer(10, time.Now()), fakeHistory{})
+	require.NoError(t, err)
+	require.Equal(t, Allow, result.Decision)
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`
+rules:
+  - name: big_new_payee
+    type: new_payee_amount
+    outcome: review
+    min_amount:
+      USD: 100000
+  - name: burst
+    type: velocity
+    outcome: deny
+    window: 10m
+    max_count: 5
+  - name: night
+    type: round_amount_at_night
+    outcome: review
+    multiple: 10000
+    start_hour: 0
+    end_hour: 5
+`))
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	velocity, ok := rules[1].(*VelocityRule)
+	require.True(t, ok)
+	require.Equal(t, "burst", velocity.Name())
+	require.Equal(t, Deny, velocity.Outcome)
+	require.Equal(t, 10*time.Minute, velocity.Window)
+	require.Equal(t, int64(5), velocity.MaxCount)
+
+	_, err = ParseRules([]byte("rules:\n  - name: x\n    type: unknown\n    outcome: review\n"))
+	require.Error(t, err)
+
+	_, err = ParseRules([]byte("rules:\n  - name: x\n    type: velocity\n    outcome: block\n    window: 1m\n    max_count: 1\n"))
+	require.Error(t, err)
+
+	_, err = ParseRules([]byte("rules:\n  - name: x\n    type: velocity\n    outcome: deny\n"))
+	require.Error(t, err)
+}
+
+func TestLoadEngineFromSampleFile(t *testing.T) {
+	_, err := LoadEngine("../risk_rules.yaml")
+	require.NoError(t, err)
+}
@@ -0,0 +1,103 @@
+package risk
+
+import (
+	"context"
+	"time"
+)
+
+// NewPayeeAmountRule flags large transfers to an account the sender has
+// never sent money to
+type NewPayeeAmountRule struct {
+	RuleName string
+	Outcome  Decision
+	// MinAmounts is the smallest amount flagged, per currency. Currencies
+	// without an amount are never flagged.
+	MinAmounts map[string]int64
+}
+
+func (rule *NewPayeeAmountRule) Name() string {
+	return rule.RuleName
+}
+
+func (rule *NewPayeeAmountRule) Evaluate(ctx context.Context, transfer Transfer, history History) (Decision, error) {
+	minAmount, ok := rule.MinAmounts[transfer.Currency]
+	if !ok || transfer.Amount < minAmount {
+		return Allow, nil
+	}
+
+	count, err := history.CountTransfersBetween(ctx, transfer.FromAccountID, transfer.ToAccountID)
+	if err != nil {
+		return Allow, err
+	}
+
+	if count > 0 {
+		return Allow, nil
+	}
+	return rule.Outcome, nil
+}
+
+// VelocityRule flags transfers from an account that already sent MaxCount
+// transfers within Window
+type VelocityRule struct {
+	RuleName string
+	Outcome  Decision
+	Window   time.Duration
+	MaxCount int64
+}
+
+func (rule *VelocityRule) Name() string {
+	return rule.RuleName
+}
+
+func (rule *VelocityRule) Evaluate(ctx context.Context, transfer Transfer, history History) (Decision, error) {
+	count, err := history.CountTransfersSince(ctx, transfer.FromAccountID, transfer.Time.Add(-rule.Window))
+	if err != nil {
+		return Allow, err
+	}
+
+	if count < rule.MaxCount {
+		return Allow, nil
+	}
+	return rule.Outcome, nil
+}
+
+// RoundAmountAtNightRule flags transfers of round amounts made at night, a
+// common pattern when a taken over account is being emptied
+type RoundAmountAtNightRule struct {
+	RuleName string
+	Outcome  Decision
+	// Multiple makes an amount round when it divides it
+	Multiple int64
+	// StartHour and EndHour bound the night, which can wrap around midnight
+	StartHour int
+	EndHour   int
+	Location  *time.Location
+}
+
+func (rule *RoundAmountAtNightRule) Name() string {
+	return rule.RuleName
+}
+
+func (rule *RoundAmountAtNightRule) Evaluate(ctx context.Context, transfer Transfer, history History) (Decision, error) {
+	if rule.Multiple <= 0 || transfer.Amount%rule.Multiple != 0 {
+		return Allow, nil
+	}
+
+	if !rule.isNight(transfer.Time) {
+		return Allow, nil
+	}
+	return rule.Outcome, nil
+}
+
+func (rule *RoundAmountAtNightRule) isNight(t time.Time) bool {
+	location := rule.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	hour := t.In(location).Hour()
+	if rule.StartHour <= rule.EndHour {
+		return hour >= rule.StartHour && hour < rule.EndHour
+	}
+	return hour >= rule.StartHour || hour < rule.EndHour
+}
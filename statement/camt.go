@@ -0,0 +1,166 @@
+package statement
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// camtDocument is the part of an ISO 20022 camt.053 bank to customer
+// statement that is read. Elements are matched whatever their namespace, so
+// the versions of the message read alike.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Currency string      `xml:"Acct>Ccy"`
+	Entries  []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Amount struct {
+		Value    string `xml:",chardata"`
+		Currency string `xml:"Ccy,attr"`
+	} `xml:"Amt"`
+	CreditDebit string `xml:"CdtDbtInd"`
+	// Status is BOOK for booked entries; it is a code of its own element
+	// since version 8 of the message
+	Status struct {
+		Value string `xml:",chardata"`
+		Code  string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate        camtDate `xml:"BookgDt"`
+	ValueDate          camtDate `xml:"ValDt"`
+	AdditionalInfo     string   `xml:"AddtlNtryInf"`
+	TransactionDetails []struct {
+		EndToEndID        string   `xml:"Refs>EndToEndId"`
+		Unstructured      []string `xml:"RmtInf>Ustrd"`
+		CreditorReference string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+// time returns the day of the date, or false if it has none
+func (d camtDate) time() (time.Time, bool, error) {
+	if d.Date != "" {
+		t, err := time.Parse("2006-01-02", strings.TrimSpace(d.Date))
+		return t, true, err
+	}
+
+	if d.DateTime != "" {
+		value := strings.TrimSpace(d.DateTime)
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse("2006-01-02T15:04:05", value)
+		}
+		return t, true, err
+	}
+
+	return time.Time{}, false, nil
+}
+
+// parseCAMT053 reads the booked entries of the statements of a camt.053
+// message. Pending and informational entries aren't booked yet, so they are
+// left out.
+func parseCAMT053(r io.Reader, options Options) ([]Line, error) {
+	var document camtDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("cannot read the camt.053 statement: %w", err)
+	}
+
+	lines := []Line{}
+	entries := 0
+
+	for _, statement := range document.Statements {
+		currency := statement.Currency
+		if currency == "" {
+			currency = options.Currency
+		}
+
+		for _, entry := range statement.Entries {
+			entries++
+
+			status := strings.TrimSpace(entry.Status.Value)
+			if entry.Status.Code != "" {
+				status = strings.TrimSpace(entry.Status.Code)
+			}
+			if status != "" && status != "BOOK" {
+				continue
+			}
+
+			line, err := camtLine(entry, currency)
+			if err != nil {
+				return nil, &LineError{Line: entries, Err: err}
+			}
+
+			line.Position = len(lines) + 1
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, nil
+}
+
+// camtLine reads a line from a statement entry
+func camtLine(entry camtEntry, currency string) (Line, error) {
+	line := Line{Currency: strings.ToUpper(currency)}
+	if entry.Amount.Currency != "" {
+		line.Currency = strings.ToUpper(entry.Amount.Currency)
+	}
+
+	date, ok, err := entry.BookingDate.time()
+	if !ok {
+		date, ok, err = entry.ValueDate.time()
+	}
+	if err != nil {
+		return line, fmt.Errorf("invalid date: %w", err)
+	}
+	if !ok {
+		return line, errors.New("entry has no booking date")
+	}
+	line.BookedOn = day(date)
+
+	amount, err := parseAmount(entry.Amount.Value, line.Currency)
+	if err != nil {
+		return line, err
+	}
+
+	switch strings.TrimSpace(entry.CreditDebit) {
+	case "CRDT":
+		line.Amount = amount
+	case "DBIT":
+		line.Amount = -amount
+	default:
+		return line, fmt.Errorf("invalid CdtDbtInd %q", entry.CreditDebit)
+	}
+
+	line.Description = strings.TrimSpace(entry.AdditionalInfo)
+
+	// entries batching several transactions are matched as a whole, by what
+	// the first transaction says
+	if len(entry.TransactionDetails) > 0 {
+		details := entry.TransactionDetails[0]
+
+		line.Reference = strings.TrimSpace(details.EndToEndID)
+		if line.Reference == "NOTPROVIDED" {
+			line.Reference = ""
+		}
+		if line.Reference == "" {
+			line.Reference = strings.TrimSpace(details.CreditorReference)
+		}
+
+		if len(details.Unstructured) > 0 {
+			line.Description = strings.TrimSpace(strings.Join(details.Unstructured, " "))
+		}
+	}
+
+	return line, nil
+}
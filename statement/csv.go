@@ -0,0 +1,186 @@
+package statement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CSVMapping names the columns of a CSV statement. Every statement has a
+// header row naming its columns; names are matched case-insensitively.
+// Either Amount or at least one of Credit and Debit must be mapped.
+type CSVMapping struct {
+	Date string
+	// Amount is a signed amount, positive for credits
+	Amount string
+	// Credit and Debit are unsigned amounts in separate columns, for
+	// statements that don't sign them
+	Credit      string
+	Debit       string
+	Currency    string
+	Reference   string
+	Description string
+	// DateLayout is the layout of the dates, as for time.Parse
+	DateLayout string
+	// Delimiter separates the fields, a comma unless set
+	Delimiter string
+}
+
+// DefaultCSVMapping is the mapping of the columns not otherwise mapped
+var DefaultCSVMapping = CSVMapping{
+	Date:        "date",
+	Amount:      "amount",
+	Currency:    "currency",
+	Reference:   "reference",
+	Description: "description",
+	DateLayout:  time.DateOnly,
+	Delimiter:   ",",
+}
+
+func (m CSVMapping) withDefaults() CSVMapping {
+	or := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+
+	// split credit and debit columns replace the signed amount
+	amount := DefaultCSVMapping.Amount
+	if m.Credit != "" || m.Debit != "" {
+		amount = ""
+	}
+
+	return CSVMapping{
+		Date:        or(m.Date, DefaultCSVMapping.Date),
+		Amount:      or(m.Amount, amount),
+		Credit:      m.Credit,
+		Debit:       m.Debit,
+		Currency:    or(m.Currency, DefaultCSVMapping.Currency),
+		Reference:   or(m.Reference, DefaultCSVMapping.Reference),
+		Description: or(m.Description, DefaultCSVMapping.Description),
+		DateLayout:  or(m.DateLayout, DefaultCSVMapping.DateLayout),
+		Delimiter:   or(m.Delimiter, DefaultCSVMapping.Delimiter),
+	}
+}
+
+func parseCSV(r io.Reader, options Options) ([]Line, error) {
+	mapping := options.CSV.withDefaults()
+
+	delimiter, size := utf8.DecodeRuneInString(mapping.Delimiter)
+	if size != len(mapping.Delimiter) {
+		return nil, fmt.Errorf("CSV delimiter %q must be a single character", mapping.Delimiter)
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	column := func(name string) (int, bool) {
+		i, ok := columns[strings.ToLower(name)]
+		return i, ok && name != ""
+	}
+
+	required := []string{mapping.Date}
+	if mapping.Amount != "" {
+		required = append(required, mapping.Amount)
+	} else {
+		for _, name := range []string{mapping.Credit, mapping.Debit} {
+			if name != "" {
+				required = append(required, name)
+			}
+		}
+	}
+	for _, name := range required {
+		if _, ok := column(name); !ok {
+			return nil, fmt.Errorf("CSV has no %q column", name)
+		}
+	}
+
+	lines := []Line{}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := column(name); ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		line, err := csvLine(field, mapping, options.Currency)
+		if err != nil {
+			return nil, &LineError{Line: row, Err: err}
+		}
+
+		line.Position = len(lines) + 1
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// csvLine reads a line from the fields of a CSV record
+func csvLine(field func(name string) string, mapping CSVMapping, currency string) (Line, error) {
+	line := Line{
+		Currency:    strings.ToUpper(field(mapping.Currency)),
+		Reference:   field(mapping.Reference),
+		Description: field(mapping.Description),
+	}
+	if line.Currency == "" {
+		line.Currency = currency
+	}
+
+	date, err := time.Parse(mapping.DateLayout, field(mapping.Date))
+	if err != nil {
+		return line, fmt.Errorf("invalid date: %w", err)
+	}
+	line.BookedOn = day(date)
+
+	if mapping.Amount != "" {
+		line.Amount, err = parseAmount(field(mapping.Amount), line.Currency)
+		return line, err
+	}
+
+	for _, column := range []struct {
+		name string
+		sign int64
+	}{{mapping.Credit, 1}, {mapping.Debit, -1}} {
+		value := strings.TrimPrefix(field(column.name), "-")
+		if column.name == "" || value == "" {
+			continue
+		}
+
+		amount, err := parseAmount(value, line.Currency)
+		if err != nil {
+			return line, err
+		}
+		line.Amount += column.sign * amount
+	}
+
+	if line.Amount == 0 && field(mapping.Credit) == "" && field(mapping.Debit) == "" {
+		return line, errors.New("line has no amount")
+	}
+
+	return line, nil
+}
@@ -0,0 +1,124 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+var errNotOFX = errors.New("not an OFX statement")
+
+// parseOFX reads the transactions of the bank statements of an OFX file. Both
+// the SGML of OFX 1.x, whose elements needn't be closed, and the XML of OFX
+// 2.x are read the same way: each element is taken as a tag and the text up
+// to the next one.
+func parseOFX(r io.Reader, options Options) ([]Line, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body := string(data)
+
+	// the headers before the root element are of no use
+	start := strings.Index(strings.ToUpper(body), "<OFX>")
+	if start < 0 {
+		return nil, errNotOFX
+	}
+
+	currency := options.Currency
+	lines := []Line{}
+
+	// the elements of the transaction being read, and the aggregate within it
+	var transaction map[string]string
+	var aggregate string
+
+	for _, token := range strings.Split(body[start+1:], "<") {
+		tag, value, _ := strings.Cut(token, ">")
+		tag = strings.ToUpper(strings.TrimSpace(tag))
+		value = html.UnescapeString(strings.TrimSpace(value))
+
+		switch {
+		case tag == "CURDEF":
+			currency = value
+		case tag == "STMTTRN":
+			transaction = map[string]string{}
+			aggregate = ""
+		case tag == "/STMTTRN":
+			if transaction == nil {
+				return nil, fmt.Errorf("%w: unexpected </STMTTRN>", errNotOFX)
+			}
+
+			line, err := ofxLine(transaction, currency)
+			if err != nil {
+				return nil, &LineError{Line: len(lines) + 1, Err: err}
+			}
+
+			line.Position = len(lines) + 1
+			lines = append(lines, line)
+			transaction = nil
+		case transaction == nil || strings.HasPrefix(tag, "/"):
+		case value == "":
+			aggregate = tag
+		case tag == "CURSYM":
+			// the amount is in the currency of a CURRENCY aggregate, but not of
+			// an ORIGCURRENCY one
+			if aggregate == "CURRENCY" {
+				transaction[tag] = value
+			}
+		default:
+			transaction[tag] = value
+		}
+	}
+
+	if transaction != nil {
+		return nil, &LineError{Line: len(lines) + 1, Err: errors.New("unterminated transaction")}
+	}
+
+	return lines, nil
+}
+
+// ofxLine reads a line from the elements of a STMTTRN aggregate
+func ofxLine(transaction map[string]string, currency string) (Line, error) {
+	line := Line{Currency: strings.ToUpper(currency)}
+	if cursym := transaction["CURSYM"]; cursym != "" {
+		line.Currency = strings.ToUpper(cursym)
+	}
+
+	// dates are YYYYMMDD, optionally followed by a time and a time zone that
+	// don't change the day the bank booked the transaction on
+	posted := transaction["DTPOSTED"]
+	if len(posted) < 8 {
+		return line, fmt.Errorf("invalid DTPOSTED %q", posted)
+	}
+
+	date, err := time.Parse("20060102", posted[:8])
+	if err != nil {
+		return line, fmt.Errorf("invalid DTPOSTED %q", posted)
+	}
+	line.BookedOn = day(date)
+
+	// OFX allows a comma as the decimal separator
+	line.Amount, err = parseAmount(strings.Replace(transaction["TRNAMT"], ",", ".", 1), line.Currency)
+	if err != nil {
+		return line, err
+	}
+
+	line.Reference = transaction["REFNUM"]
+	if line.Reference == "" {
+		line.Reference = transaction["CHECKNUM"]
+	}
+
+	description := []string{}
+	for _, tag := range []string{"NAME", "MEMO"} {
+		if transaction[tag] != "" {
+			description = append(description, transaction[tag])
+		}
+	}
+	line.Description = strings.Join(description, " ")
+
+	return line, nil
+}
@@ -0,0 +1,115 @@
+// Package statement parses the account statements partner banks send us, as
+// CSV, OFX or ISO 20022 camt.053 files, into the booked lines to match
+// against our ledger.
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/money"
+)
+
+// Statement formats
+const (
+	FormatCSV     = "csv"
+	FormatOFX     = "ofx"
+	FormatCAMT053 = "camt053"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown statement format")
+	ErrNoCurrency    = errors.New("statement line has no currency")
+)
+
+// Line is a movement booked on a statement
+type Line struct {
+	// Position is the number of the line in the statement, from 1
+	Position int
+	// BookedOn is the day the bank booked the line, at midnight UTC
+	BookedOn time.Time
+	// Amount is in minor units, positive for credits and negative for debits
+	Amount   int64
+	Currency string
+	// Reference identifies the payment end to end, e.g. the reference given
+	// with a transfer
+	Reference   string
+	Description string
+}
+
+// Options tunes how a statement is parsed
+type Options struct {
+	// Currency is used for the lines that don't state theirs
+	Currency string
+	// CSV maps the columns of CSV statements
+	CSV CSVMapping
+}
+
+// LineError is why a line of a statement can't be parsed
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Parse reads the lines of a statement in format
+func Parse(r io.Reader, format string, options Options) ([]Line, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r, options)
+	case FormatOFX:
+		return parseOFX(r, options)
+	case FormatCAMT053:
+		return parseCAMT053(r, options)
+	}
+
+	return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+// DetectFormat guesses the format of a statement from its file name, or
+// returns "" if it can't
+func DetectFormat(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".ofx", ".qfx":
+		return FormatOFX
+	case ".xml", ".053":
+		return FormatCAMT053
+	}
+
+	return ""
+}
+
+// parseAmount parses a decimal amount as banks write it, with an optional
+// sign and surrounding spaces
+func parseAmount(s string, currency string) (int64, error) {
+	if currency == "" {
+		return 0, ErrNoCurrency
+	}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "+")
+
+	m, err := money.Parse(s, strings.ToUpper(currency))
+	if err != nil {
+		return 0, err
+	}
+
+	return m.Amount(), nil
+}
+
+// day truncates t to its day, at midnight UTC
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
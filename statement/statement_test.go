@@ -0,0 +1,211 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseCSV(t *testing.T) {
+	input := `Date,Amount,Currency,Reference,Description
+2024-03-01,125.50,USD,INV-1,Payment from Acme
+2024-03-02,-20,,,Bank charge
+`
+
+	lines, err := Parse(strings.NewReader(input), FormatCSV, Options{Currency: "USD"})
+	require.NoError(t, err)
+	require.Equal(t, []Line{
+		{Position: 1, BookedOn: date("2024-03-01"), Amount: 12550, Currency: "USD", Reference: "INV-1", Description: "Payment from Acme"},
+		{Position: 2, BookedOn: date("2024-03-02"), Amount: -2000, Currency: "USD", Description: "Bank charge"},
+	}, lines)
+}
+
+func TestParseCSVMapping(t *testing.T) {
+	input := `Booked;Credit;Debit;Ref
+01/03/2024;125.50;;INV-1
+02/03/2024;;20.00;
+`
+
+	lines, err := Parse(strings.NewReader(input), FormatCSV, Options{
+		Currency: "EUR",
+		CSV: CSVMapping{
+			Date:       "booked",
+			Credit:     "credit",
+			Debit:      "debit",
+			Reference:  "ref",
+			DateLayout: "02/01/2006",
+			Delimiter:  ";",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	require.Equal(t, date("2024-03-01"), lines[0].BookedOn)
+	require.Equal(t, int64(12550), lines[0].Amount)
+	require.Equal(t, "INV-1", lines[0].Reference)
+	require.Equal(t, date("2024-03-02"), lines[1].BookedOn)
+	require.Equal(t, int64(-2000), lines[1].Amount)
+	require.Equal(t, "EUR", lines[1].Currency)
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		check func(t *testing.T, err error)
+	}{
+		{
+			name:  "MissingColumn",
+			input: "date,reference\n2024-03-01,x\n",
+			check: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, `no "amount" column`)
+			},
+		},
+		{
+			name:  "InvalidAmount",
+			input: "date,amount\n2024-03-01,10.00\n2024-03-02,1.234\n",
+			check: func(t *testing.T, err error) {
+				var lineErr *LineError
+				require.ErrorAs(t, err, &lineErr)
+				require.Equal(t, 3, lineErr.Line)
+				require.ErrorIs(t, err, money.ErrTooPrecise)
+			},
+		},
+		{
+			name:  "InvalidDate",
+			input: "date,amount\n03/01/2024,10.00\n",
+			check: func(t *testing.T, err error) {
+				require.ErrorContains(t, err, "line 2: invalid date")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.input), FormatCSV, Options{Currency: "USD"})
+			tc.check(t, err)
+		})
+	}
+}
+
+func TestParseOFX(t *testing.T) {
+	input := `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240301120000.000[-5:EST]
+<TRNAMT>125.50
+<FITID>1001
+<REFNUM>INV-1
+<NAME>Acme &amp; Co
+<MEMO>March invoice
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240302
+<TRNAMT>-20,00
+<FITID>1002
+<CURRENCY><CURRATE>1.0<CURSYM>EUR</CURRENCY>
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+	lines, err := Parse(strings.NewReader(input), FormatOFX, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []Line{
+		{Position: 1, BookedOn: date("2024-03-01"), Amount: 12550, Currency: "USD", Reference: "INV-1", Description: "Acme & Co March invoice"},
+		{Position: 2, BookedOn: date("2024-03-02"), Amount: -2000, Currency: "EUR"},
+	}, lines)
+
+	_, err = Parse(strings.NewReader("DATA:OFXSGML\n"), FormatOFX, Options{})
+	require.ErrorIs(t, err, errNotOFX)
+}
+
+func TestParseOFXXML(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="211"?>
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>CAD</CURDEF><BANKTRANLIST>
+<STMTTRN><TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20240305</DTPOSTED><TRNAMT>+7.25</TRNAMT><FITID>1</FITID><CHECKNUM>42</CHECKNUM></STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+	lines, err := Parse(strings.NewReader(input), FormatOFX, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []Line{
+		{Position: 1, BookedOn: date("2024-03-05"), Amount: 725, Currency: "CAD", Reference: "42"},
+	}, lines)
+}
+
+func TestParseCAMT053(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Ccy>EUR</Ccy></Acct>
+      <Ntry>
+        <Amt Ccy="EUR">125.50</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-03-01</Dt></BookgDt>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>INV-1</EndToEndId></Refs>
+          <RmtInf><Ustrd>March invoice</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">5.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <BookgDt><Dt>2024-03-02</Dt></BookgDt>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">20</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><DtTm>2024-03-02T23:30:00+01:00</DtTm></BookgDt>
+        <AddtlNtryInf>Bank charge</AddtlNtryInf>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+          <RmtInf><Strd><CdtrRefInf><Ref>RF18539007547034</Ref></CdtrRefInf></Strd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+	lines, err := Parse(strings.NewReader(input), FormatCAMT053, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []Line{
+		{Position: 1, BookedOn: date("2024-03-01"), Amount: 12550, Currency: "EUR", Reference: "INV-1", Description: "March invoice"},
+		{Position: 2, BookedOn: date("2024-03-02"), Amount: -2000, Currency: "EUR", Reference: "RF18539007547034", Description: "Bank charge"},
+	}, lines)
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	_, err := Parse(strings.NewReader(""), "mt940", Options{})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestDetectFormat(t *testing.T) {
+	require.Equal(t, FormatCSV, DetectFormat("march.CSV"))
+	require.Equal(t, FormatOFX, DetectFormat("march.qfx"))
+	require.Equal(t, FormatCAMT053, DetectFormat("march.xml"))
+	require.Equal(t, "", DetectFormat("march.txt"))
+}
@@ -0,0 +1,76 @@
+package token
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// JWTAsymmetricMaker is a JSON Web Token maker signing with RS256 or EdDSA
+// keys, so that tokens can be verified with the public keys only.
+type JWTAsymmetricMaker struct {
+	keySet *KeySet
+}
+
+// NewJWTAsymmetricMaker creates a new JWTAsymmetricMaker
+func NewJWTAsymmetricMaker(keySet *KeySet) (Maker, error) {
+	return &JWTAsymmetricMaker{keySet}, nil
+}
+
+// CreateToken creates a new token for a specific username and duration
+func (maker *JWTAsymmetricMaker) CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error) {
+	payload, err := NewPayload(username, duration, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	key := maker.keySet.SigningKey()
+
+	jwtToken := jwt.NewWithClaims(jwtSigningMethod(key.Algorithm), payload)
+	jwtToken.Header["kid"] = key.ID
+
+	return jwtToken.SignedString(key.PrivateKey)
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *JWTAsymmetricMaker) VerifyToken(token string) (*Payload, error) {
+	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		key, err := maker.keySet.VerificationKey(kid)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+
+		if token.Method != jwtSigningMethod(key.Algorithm) {
+			return nil, ErrInvalidToken
+		}
+
+		return key.PublicKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payload, ok := jwtToken.Claims.(*Payload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	return payload, nil
+}
+
+// KeySet returns the keys used to sign and verify tokens
+func (maker *JWTAsymmetricMaker) KeySet() *KeySet {
+	return maker.keySet
+}
+
+func jwtSigningMethod(algorithm string) jwt.SigningMethod {
+	if algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
@@ -0,0 +1,82 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAsymmetricMaker(t *testing.T) {
+	for _, key := range []*Key{randomEd25519Key(t), randomRSAKey(t)} {
+		t.Run(key.Algorithm, func(t *testing.T) {
+			keySet, err := NewKeySet(key.ID, key)
+			require.NoError(t, err)
+
+			maker, err := NewJWTAsymmetricMaker(keySet)
+			require.NoError(t, err)
+
+			username := util.RandomOwner()
+			duration := time.Minute
+
+			issuedAt := time.Now()
+			expiredAt := issuedAt.Add(duration)
+
+			token, err := maker.CreateToken(username, duration)
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+
+			payload, err := maker.VerifyToken(token)
+			require.NoError(t, err)
+			require.NotEmpty(t, payload)
+
+			require.NotZero(t, payload.ID)
+			require.Equal(t, username, payload.Username)
+			require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+			require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+		})
+	}
+}
+
+func TestExpiredJWTAsymmetricToken(t *testing.T) {
+	key := randomEd25519Key(t)
+	keySet, err := NewKeySet(key.ID, key)
+	require.NoError(t, err)
+
+	maker, err := NewJWTAsymmetricMaker(keySet)
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err := maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidJWTAsymmetricToken(t *testing.T) {
+	key := randomEd25519Key(t)
+	keySet, err := NewKeySet(key.ID, key)
+	require.NoError(t, err)
+
+	maker, err := NewJWTAsymmetricMaker(keySet)
+	require.NoError(t, err)
+
+	payload, err := NewPayload(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	// an HS256 token must not be accepted even with a known kid
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	jwtToken.Header["kid"] = key.ID
+	token, err := jwtToken.SignedString([]byte(util.RandomString(32)))
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
@@ -19,8 +19,8 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, error) {
-	payload, err := NewPayload(username, duration)
+func (maker *JWTMaker) CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error) {
+	payload, err := NewPayload(username, duration, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,206 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Signing algorithms supported by asymmetric keys
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+)
+
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+// Key is an asymmetric key identified by its kid. Keys without a private
+// part can only be used to verify tokens, which is how retired keys are
+// kept around during a rotation.
+type Key struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// NewKey creates a key from either a private or a public key
+func NewKey(id string, key interface{}) (*Key, error) {
+	if len(id) == 0 {
+		return nil, errors.New("empty key id")
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return &Key{ID: id, Algorithm: AlgorithmEdDSA, PrivateKey: k, PublicKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, Algorithm: AlgorithmEdDSA, PublicKey: k}, nil
+	case *rsa.PrivateKey:
+		return &Key{ID: id, Algorithm: AlgorithmRS256, PrivateKey: k, PublicKey: k.Public()}, nil
+	case *rsa.PublicKey:
+		return &Key{ID: id, Algorithm: AlgorithmRS256, PublicKey: k}, nil
+	}
+
+	return nil, fmt.Errorf("key %s: unsupported key type %T", id, key)
+}
+
+// KeySet holds the key used to sign new tokens and every key that is
+// still accepted when verifying tokens.
+type KeySet struct {
+	activeKeyID string
+	keys        map[string]*Key
+}
+
+// NewKeySet creates a KeySet signing with the key identified by activeKeyID
+func NewKeySet(activeKeyID string, keys ...*Key) (*KeySet, error) {
+	keySet := &KeySet{
+		activeKeyID: activeKeyID,
+		keys:        make(map[string]*Key, len(keys)),
+	}
+
+	for _, key := range keys {
+		if _, ok := keySet.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %s", key.ID)
+		}
+		keySet.keys[key.ID] = key
+	}
+
+	active, ok := keySet.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active key %s: %w", activeKeyID, ErrUnknownKeyID)
+	}
+
+	if active.PrivateKey == nil {
+		return nil, fmt.Errorf("active key %s has no private key", activeKeyID)
+	}
+
+	return keySet, nil
+}
+
+// LoadKeySet reads every PEM file in dir as a key whose kid is the file
+// name without the .pem extension. Private keys (PKCS#8, or PKCS#1 for RSA)
+// can sign and verify, public keys (PKIX) can only verify.
+func LoadKeySet(dir string, activeKeyID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := parsePEMKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+
+		key, err := NewKey(strings.TrimSuffix(filepath.Base(path), ".pem"), parsed)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(activeKeyID, keys...)
+}
+
+func parsePEMKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+}
+
+// SigningKey returns the active key used to sign new tokens
+func (keySet *KeySet) SigningKey() *Key {
+	return keySet.keys[keySet.activeKeyID]
+}
+
+// VerificationKey returns the key identified by kid
+func (keySet *KeySet) VerificationKey(kid string) (*Key, error) {
+	key, ok := keySet.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return key, nil
+}
+
+// JWK is the public part of a key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set so other services can verify tokens
+func (keySet *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+
+	for _, key := range keySet.keys {
+		jwk := JWK{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+		}
+
+		switch publicKey := key.PublicKey.(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].KeyID < jwks.Keys[j].KeyID
+	})
+
+	return jwks
+}
+
+// KeySetProvider is implemented by makers that sign with asymmetric keys
+type KeySetProvider interface {
+	KeySet() *KeySet
+}
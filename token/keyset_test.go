@@ -0,0 +1,109 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEd25519Key(t *testing.T) *Key {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	key, err := NewKey(util.RandomString(8), privateKey)
+	require.NoError(t, err)
+
+	return key
+}
+
+func randomRSAKey(t *testing.T) *Key {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key, err := NewKey(util.RandomString(8), privateKey)
+	require.NoError(t, err)
+
+	return key
+}
+
+func publicOnly(t *testing.T, key *Key) *Key {
+	publicKey, err := NewKey(key.ID, key.PublicKey)
+	require.NoError(t, err)
+
+	return publicKey
+}
+
+func TestNewKeySet(t *testing.T) {
+	active := randomEd25519Key(t)
+	retired := randomRSAKey(t)
+
+	keySet, err := NewKeySet(active.ID, active, publicOnly(t, retired))
+	require.NoError(t, err)
+	require.Equal(t, active, keySet.SigningKey())
+
+	key, err := keySet.VerificationKey(retired.ID)
+	require.NoError(t, err)
+	require.Nil(t, key.PrivateKey)
+
+	_, err = keySet.VerificationKey("unknown")
+	require.ErrorIs(t, err, ErrUnknownKeyID)
+
+	jwks := keySet.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	for _, jwk := range jwks.Keys {
+		switch jwk.KeyID {
+		case active.ID:
+			require.Equal(t, "OKP", jwk.KeyType)
+			require.Equal(t, AlgorithmEdDSA, jwk.Algorithm)
+			require.NotEmpty(t, jwk.X)
+		case retired.ID:
+			require.Equal(t, "RSA", jwk.KeyType)
+			require.Equal(t, AlgorithmRS256, jwk.Algorithm)
+			require.NotEmpty(t, jwk.N)
+			require.Equal(t, "AQAB", jwk.E)
+		}
+	}
+
+	_, err = NewKeySet(retired.ID, active, publicOnly(t, retired))
+	require.Error(t, err)
+
+	_, err = NewKeySet("unknown", active)
+	require.ErrorIs(t, err, ErrUnknownKeyID)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+
+	_, activeKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(activeKey)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "active.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	require.NoError(t, err)
+
+	retiredKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(retiredKey)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "retired.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600)
+	require.NoError(t, err)
+
+	keySet, err := LoadKeySet(dir, "active")
+	require.NoError(t, err)
+	require.Equal(t, "active", keySet.SigningKey().ID)
+
+	key, err := keySet.VerificationKey("retired")
+	require.NoError(t, err)
+	require.Equal(t, retiredKey, key.PublicKey)
+
+	_, err = LoadKeySet(dir, "retired")
+	require.Error(t, err)
+}
@@ -2,9 +2,17 @@ package token
 
 import "time"
 
+// Token maker types selectable with TOKEN_MAKER_TYPE
+const (
+	MakerTypePasetoLocal  = "paseto_v2_local"
+	MakerTypePasetoPublic = "paseto_v4_public"
+	MakerTypeJWTHS256     = "jwt_hs256"
+	MakerTypeJWTPublic    = "jwt_public"
+)
+
 type Maker interface {
 	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, error)
+	CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error)
 
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
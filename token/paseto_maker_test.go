@@ -48,3 +48,32 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestPasetoTokenAuthClaims(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	authTime := time.Now().Add(-time.Hour)
+
+	token, err := maker.CreateToken(util.RandomOwner(), time.Minute, WithAuthTime(authTime), WithAMR(AMRPassword))
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.WithinDuration(t, authTime, payload.AuthTime, time.Second)
+	require.Equal(t, []string{AMRPassword}, payload.AMR)
+	require.True(t, payload.AuthAge() >= time.Hour)
+}
+
+func TestPasetoTokenRole(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(util.RandomOwner(), time.Minute, WithRole(util.BankerRole))
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, util.BankerRole, payload.Role)
+}
@@ -0,0 +1,139 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const pasetoV4PublicHeader = "v4.public."
+
+// PasetoPublicMaker is a PASETO v4.public token maker. Tokens are signed
+// with Ed25519 and carry the key id in the footer.
+type PasetoPublicMaker struct {
+	keySet *KeySet
+}
+
+type pasetoFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// NewPasetoPublicMaker creates a new PasetoPublicMaker
+func NewPasetoPublicMaker(keySet *KeySet) (Maker, error) {
+	if key := keySet.SigningKey(); key.Algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("key %s: v4.public requires an Ed25519 key", key.ID)
+	}
+
+	return &PasetoPublicMaker{keySet}, nil
+}
+
+// CreateToken creates a new token for a specific username and duration
+func (maker *PasetoPublicMaker) CreateToken(username string, duration time.Duration, opts ...PayloadOption) (string, error) {
+	payload, err := NewPayload(username, duration, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	key := maker.keySet.SigningKey()
+
+	footer, err := json.Marshal(pasetoFooter{KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+
+	signature := ed25519.Sign(key.PrivateKey.(ed25519.PrivateKey), pae([]byte(pasetoV4PublicHeader), message, footer, nil))
+
+	return pasetoV4PublicHeader +
+		base64.RawURLEncoding.EncodeToString(append(message, signature...)) + "." +
+		base64.RawURLEncoding.EncodeToString(footer), nil
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *PasetoPublicMaker) VerifyToken(token string) (*Payload, error) {
+	if !strings.HasPrefix(token, pasetoV4PublicHeader) {
+		return nil, ErrInvalidToken
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, pasetoV4PublicHeader), ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(body) < ed25519.SignatureSize {
+		return nil, ErrInvalidToken
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var f pasetoFooter
+	if err := json.Unmarshal(footer, &f); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := maker.keySet.VerificationKey(f.KeyID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	publicKey, ok := key.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	message := body[:len(body)-ed25519.SignatureSize]
+	signature := body[len(body)-ed25519.SignatureSize:]
+
+	if !ed25519.Verify(publicKey, pae([]byte(pasetoV4PublicHeader), message, footer, nil), signature) {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	if err := json.Unmarshal(message, payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// KeySet returns the keys used to sign and verify tokens
+func (maker *PasetoPublicMaker) KeySet() *KeySet {
+	return maker.keySet
+}
+
+// pae is the PASETO pre-authentication encoding of the given pieces
+func pae(pieces ...[]byte) []byte {
+	var buf bytes.Buffer
+
+	writeLength := func(n int) {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(n)&^(1<<63))
+		buf.Write(b[:])
+	}
+
+	writeLength(len(pieces))
+	for _, piece := range pieces {
+		writeLength(len(piece))
+		buf.Write(piece)
+	}
+
+	return buf.Bytes()
+}
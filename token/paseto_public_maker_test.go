@@ -0,0 +1,101 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Srinath-exe/simplebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasetoPublicMaker(t *testing.T) {
+	key := randomEd25519Key(t)
+	keySet, err := NewKeySet(key.ID, key)
+	require.NoError(t, err)
+
+	maker, err := NewPasetoPublicMaker(keySet)
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, err := maker.CreateToken(username, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	key := randomEd25519Key(t)
+	keySet, err := NewKeySet(key.ID, key)
+	require.NoError(t, err)
+
+	maker, err := NewPasetoPublicMaker(keySet)
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err := maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicKeyRotation(t *testing.T) {
+	oldKey := randomEd25519Key(t)
+	newKey := randomEd25519Key(t)
+
+	oldKeySet, err := NewKeySet(oldKey.ID, oldKey)
+	require.NoError(t, err)
+
+	oldMaker, err := NewPasetoPublicMaker(oldKeySet)
+	require.NoError(t, err)
+
+	token, err := oldMaker.CreateToken(util.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	// the old key is still accepted for verification after rotating
+	rotatedKeySet, err := NewKeySet(newKey.ID, newKey, publicOnly(t, oldKey))
+	require.NoError(t, err)
+
+	rotatedMaker, err := NewPasetoPublicMaker(rotatedKeySet)
+	require.NoError(t, err)
+
+	payload, err := rotatedMaker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	// and rejected once it is removed from the key set
+	newKeySet, err := NewKeySet(newKey.ID, newKey)
+	require.NoError(t, err)
+
+	newMaker, err := NewPasetoPublicMaker(newKeySet)
+	require.NoError(t, err)
+
+	payload, err = newMaker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicMakerRequiresEd25519(t *testing.T) {
+	key := randomRSAKey(t)
+	keySet, err := NewKeySet(key.ID, key)
+	require.NoError(t, err)
+
+	maker, err := NewPasetoPublicMaker(keySet)
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
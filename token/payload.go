@@ -2,17 +2,31 @@ package token
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	uuid "github.com/google/uuid"
 )
 
+// Authentication method references stored in the amr claim
+const (
+	AMRPassword = "pwd"
+)
+
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	Role      string    `json:"role,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
+	AuthTime  time.Time `json:"auth_time"`
+	AMR       []string  `json:"amr,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty"`
+	// set on tokens issued to OAuth clients
+	ClientID   string  `json:"client_id,omitempty"`
+	ConsentID  int64   `json:"consent_id,omitempty"`
+	AccountIDs []int64 `json:"account_ids,omitempty"`
+	// set on payloads of requests authenticated with an API key
+	APIKeyID int64 `json:"api_key_id,omitempty"`
 }
 
 var (
@@ -20,20 +34,71 @@ var (
 	ErrInvalidToken = errors.New("signature is invalid")
 )
 
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+// PayloadOption customizes a payload before it is signed
+type PayloadOption func(payload *Payload)
+
+// WithAuthTime sets the time the user last actively authenticated
+func WithAuthTime(authTime time.Time) PayloadOption {
+	return func(payload *Payload) {
+		payload.AuthTime = authTime
+	}
+}
+
+// WithAMR sets the authentication methods used to obtain the token
+func WithAMR(methods ...string) PayloadOption {
+	return func(payload *Payload) {
+		payload.AMR = methods
+	}
+}
+
+// WithRole sets the role of the user, e.g. util.BankerRole
+func WithRole(role string) PayloadOption {
+	return func(payload *Payload) {
+		payload.Role = role
+	}
+}
+
+// WithScopes restricts the token to the given scopes
+func WithScopes(scopes ...string) PayloadOption {
+	return func(payload *Payload) {
+		payload.Scopes = scopes
+	}
+}
+
+// WithClientID records the OAuth client the token was issued to
+func WithClientID(clientID string) PayloadOption {
+	return func(payload *Payload) {
+		payload.ClientID = clientID
+	}
+}
+
+// WithConsent restricts the token to the accounts of an OAuth consent
+func WithConsent(consentID int64, accountIDs ...int64) PayloadOption {
+	return func(payload *Payload) {
+		payload.ConsentID = consentID
+		payload.AccountIDs = accountIDs
+	}
+}
+
+func NewPayload(username string, duration time.Duration, opts ...PayloadOption) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
-	now := time.Now()
 
+	now := time.Now()
 	expiry := now.Add(duration)
-	fmt.Println("Expiry time: ", expiry)
+
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
 		IssuedAt:  now,
 		ExpiredAt: expiry,
+		AuthTime:  now,
+	}
+
+	for _, opt := range opts {
+		opt(payload)
 	}
 
 	return payload, nil
@@ -45,3 +110,46 @@ func (payload *Payload) Valid() error {
 	}
 	return nil
 }
+
+// AuthAge returns how long ago the user last actively authenticated
+func (payload *Payload) AuthAge() time.Duration {
+	return time.Since(payload.AuthTime)
+}
+
+// HasScope reports whether the payload grants scope. Tokens issued by an
+// interactive login act with the user's full access; delegated credentials
+// only have the scopes they were granted.
+func (payload *Payload) HasScope(scope string) bool {
+	if !payload.IsDelegated() {
+		return true
+	}
+
+	for _, s := range payload.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDelegated reports whether the payload comes from a credential the user
+// handed to someone else, an OAuth client or an API key, rather than from the
+// user logging in
+func (payload *Payload) IsDelegated() bool {
+	return payload.ClientID != "" || payload.ConsentID != 0 || payload.APIKeyID != 0
+}
+
+// CanAccessAccount reports whether the payload grants access to an account.
+// Only tokens issued for an OAuth consent are restricted to some accounts.
+func (payload *Payload) CanAccessAccount(accountID int64) bool {
+	if len(payload.AccountIDs) == 0 {
+		return true
+	}
+
+	for _, id := range payload.AccountIDs {
+		if id == accountID {
+			return true
+		}
+	}
+	return false
+}
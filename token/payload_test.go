@@ -0,0 +1,31 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadScopes(t *testing.T) {
+	session, err := NewPayload("alice", time.Minute)
+	require.NoError(t, err)
+	require.False(t, session.IsDelegated())
+	require.True(t, session.HasScope("transfers:write"))
+
+	delegated, err := NewPayload("alice", time.Minute, WithScopes("accounts:read"), WithClientID("client"))
+	require.NoError(t, err)
+	require.True(t, delegated.IsDelegated())
+	require.True(t, delegated.HasScope("accounts:read"))
+	require.False(t, delegated.HasScope("transfers:write"))
+
+	// a delegated credential without scopes grants nothing
+	empty, err := NewPayload("alice", time.Minute, WithClientID("client"), WithConsent(1, 2))
+	require.NoError(t, err)
+	require.True(t, empty.IsDelegated())
+	require.False(t, empty.HasScope("accounts:read"))
+
+	apiKey := &Payload{Username: "alice", APIKeyID: 1}
+	require.True(t, apiKey.IsDelegated())
+	require.False(t, apiKey.HasScope("accounts:read"))
+}
@@ -0,0 +1,92 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ibanLengths is the length of the IBANs of each country in the IBAN
+// registry
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24,
+	"DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18,
+	"FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27,
+	"GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28,
+	"PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31,
+	"SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+var (
+	ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+	bicPattern  = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	abaPattern  = regexp.MustCompile(`^[0-9]{9}$`)
+)
+
+// NormalizeIBAN writes an IBAN in its electronic format, without the spaces
+// it is usually printed with and in upper case
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+// IsValidIBAN reports whether an IBAN in electronic format has the length of
+// its country and correct mod-97 check digits
+func IsValidIBAN(iban string) bool {
+	if !ibanPattern.MatchString(iban) || ibanLengths[iban[:2]] != len(iban) {
+		return false
+	}
+
+	// the country code and check digits move to the end and letters count
+	// as 10 to 35, which leaves a number whose remainder by 97 must be 1
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' {
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+
+	return remainder == 1
+}
+
+// NormalizeBIC writes a BIC in upper case, as it is registered
+func NormalizeBIC(bic string) string {
+	return strings.ToUpper(bic)
+}
+
+// IsValidBIC reports whether a BIC has the 8 or 11 characters of ISO 9362:
+// bank and country codes, a location code and an optional branch code
+func IsValidBIC(bic string) bool {
+	return bicPattern.MatchString(bic)
+}
+
+// IsValidABARouting reports whether a US routing transit number has a prefix
+// in use and a correct check digit. The digits are weighted 3, 7 and 1 in
+// turn and must add up to a multiple of 10.
+func IsValidABARouting(routing string) bool {
+	if !abaPattern.MatchString(routing) {
+		return false
+	}
+
+	// 00 is the US government, 01 to 12 the Federal Reserve districts, 21 to
+	// 32 thrifts, 61 to 72 electronic transactions and 80 traveler's checks
+	prefix := int(routing[0]-'0')*10 + int(routing[1]-'0')
+	switch {
+	case prefix <= 12, prefix >= 21 && prefix <= 32, prefix >= 61 && prefix <= 72, prefix == 80:
+	default:
+		return false
+	}
+
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, c := range routing {
+		sum += int(c-'0') * weights[i%3]
+	}
+
+	return sum%10 == 0
+}
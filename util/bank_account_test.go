@@ -0,0 +1,50 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidIBAN(t *testing.T) {
+	for _, iban := range []string{
+		"GB82WEST12345698765432",
+		"DE89370400440532013000",
+		"NL91ABNA0417164300",
+		"NO9386011117947",
+		NormalizeIBAN("fr14 2004 1010 0505 0001 3m02 606"),
+	} {
+		require.True(t, IsValidIBAN(iban), iban)
+	}
+
+	for _, iban := range []string{
+		"",
+		"GB82WEST12345698765431", // check digits
+		"GB82WEST1234569876543",  // length
+		"XX82WEST12345698765432", // country
+		"GB82 WEST 1234 5698 7654 32",
+		"gb82west12345698765432",
+	} {
+		require.False(t, IsValidIBAN(iban), iban)
+	}
+}
+
+func TestIsValidBIC(t *testing.T) {
+	require.True(t, IsValidBIC("DEUTDEFF"))
+	require.True(t, IsValidBIC("DEUTDEFF500"))
+	require.False(t, IsValidBIC("DEUTDEFF5"))
+	require.False(t, IsValidBIC("DEU1DEFF"))
+	require.False(t, IsValidBIC("deutdeff"))
+	require.True(t, IsValidBIC(NormalizeBIC("deutdeff")))
+}
+
+func TestIsValidABARouting(t *testing.T) {
+	require.True(t, IsValidABARouting("021000021"))
+	require.True(t, IsValidABARouting("011000015"))
+	require.True(t, IsValidABARouting("322271627"))
+
+	require.False(t, IsValidABARouting("021000022")) // check digit
+	require.False(t, IsValidABARouting("02100002"))  // length
+	require.False(t, IsValidABARouting("02100002a"))
+	require.False(t, IsValidABARouting("500000005")) // prefix
+}
@@ -3,15 +3,59 @@ package util
 import (
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	DBDriver            string        `mapstructure:"DB_DRIVER"`
-	DBSource            string        `mapstructure:"DB_SOURCE"`
-	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
-	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	DBDriver                 string          `mapstructure:"DB_DRIVER"`
+	DBSource                 string          `mapstructure:"DB_SOURCE"`
+	ServerAddress            string          `mapstructure:"SERVER_ADDRESS"`
+	TokenMakerType           string          `mapstructure:"TOKEN_MAKER_TYPE"`
+	TokenSymmetricKey        string          `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenKeySetDir           string          `mapstructure:"TOKEN_KEYSET_DIR"`
+	TokenActiveKeyID         string          `mapstructure:"TOKEN_ACTIVE_KEY_ID"`
+	AccessTokenDuration      time.Duration   `mapstructure:"ACCESS_TOKEN_DURATION"`
+	ElevatedTokenDuration    time.Duration   `mapstructure:"ELEVATED_TOKEN_DURATION"`
+	StepUpThresholds         CurrencyAmounts `mapstructure:"STEP_UP_THRESHOLDS"`
+	StepUpMaxAuthAge         time.Duration   `mapstructure:"STEP_UP_MAX_AUTH_AGE"`
+	TransferMaxAmounts       CurrencyAmounts `mapstructure:"TRANSFER_MAX_AMOUNTS"`
+	AccountDailyLimits       CurrencyAmounts `mapstructure:"ACCOUNT_DAILY_LIMITS"`
+	AccountMonthlyLimits     CurrencyAmounts `mapstructure:"ACCOUNT_MONTHLY_LIMITS"`
+	UserDailyLimits          CurrencyAmounts `mapstructure:"USER_DAILY_LIMITS"`
+	UserMonthlyLimits        CurrencyAmounts `mapstructure:"USER_MONTHLY_LIMITS"`
+	TransferBatchMaxItems    int             `mapstructure:"TRANSFER_BATCH_MAX_ITEMS"`
+	PayeeCoolingOff          time.Duration   `mapstructure:"PAYEE_COOLING_OFF"`
+	PayeeCoolingOffAmounts   CurrencyAmounts `mapstructure:"PAYEE_COOLING_OFF_AMOUNTS"`
+	PaymentRequestDuration   time.Duration   `mapstructure:"PAYMENT_REQUEST_DURATION"`
+	PaymentRequestMaxExpiry  time.Duration   `mapstructure:"PAYMENT_REQUEST_MAX_EXPIRY"`
+	StatementMatchWindow     time.Duration   `mapstructure:"STATEMENT_MATCH_WINDOW"`
+	PaymentExportMaxItems    int             `mapstructure:"PAYMENT_EXPORT_MAX_ITEMS"`
+	PaymentExportFormat      string          `mapstructure:"PAYMENT_EXPORT_FORMAT"`
+	CorrespondentBIC         string          `mapstructure:"CORRESPONDENT_BIC"`
+	NostroAccountName        string          `mapstructure:"NOSTRO_ACCOUNT_NAME"`
+	NostroIBAN               string          `mapstructure:"NOSTRO_IBAN"`
+	NostroBIC                string          `mapstructure:"NOSTRO_BIC"`
+	RiskRulesFile            string          `mapstructure:"RISK_RULES_FILE"`
+	CurrencyCacheTTL         time.Duration   `mapstructure:"CURRENCY_CACHE_TTL"`
+	OAuthAccessTokenDuration time.Duration   `mapstructure:"OAUTH_ACCESS_TOKEN_DURATION"`
+	OAuthCodeDuration        time.Duration   `mapstructure:"OAUTH_CODE_DURATION"`
+	PasswordMinLength        int             `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMinCharClasses   int             `mapstructure:"PASSWORD_MIN_CHAR_CLASSES"`
+	PasswordBreachedListFile string          `mapstructure:"PASSWORD_BREACHED_LIST_FILE"`
+	PasswordHistorySize      int             `mapstructure:"PASSWORD_HISTORY_SIZE"`
+	PasswordHashAlgorithm    string          `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	BcryptCost               int             `mapstructure:"BCRYPT_COST"`
+	Argon2Memory             uint32          `mapstructure:"ARGON2_MEMORY"`
+	Argon2Iterations         uint32          `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism        uint8           `mapstructure:"ARGON2_PARALLELISM"`
+	PasswordResetDuration    time.Duration   `mapstructure:"PASSWORD_RESET_DURATION"`
+	PasswordResetURL         string          `mapstructure:"PASSWORD_RESET_URL"`
+	EmailSenderName          string          `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress       string          `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	SMTPAddress              string          `mapstructure:"SMTP_ADDRESS"`
+	SMTPUsername             string          `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword             string          `mapstructure:"SMTP_PASSWORD"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -26,6 +70,10 @@ func LoadConfig(path string) (config Config, err error) {
 		return
 	}
 
-	err = viper.Unmarshal(&config)
+	err = viper.Unmarshal(&config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToCurrencyAmountsHookFunc(),
+	)))
 	return
 }
@@ -1,15 +1,9 @@
 package util
 
+// Currencies enabled when the database is created. Others are enabled by a
+// banker through the currencies endpoints.
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
-
-func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
-	}
-	return false
-}
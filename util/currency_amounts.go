@@ -0,0 +1,58 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Srinath-exe/simplebank/money"
+	"github.com/mitchellh/mapstructure"
+)
+
+// CurrencyAmounts maps a currency code to an amount in the minor units of
+// that currency, e.g. cents for USD.
+// In the env config it is written as a comma separated list of
+// CURRENCY:AMOUNT pairs, e.g. "USD:100000,EUR:90000".
+type CurrencyAmounts map[string]int64
+
+// ParseCurrencyAmounts parses a CURRENCY:AMOUNT list into CurrencyAmounts
+func ParseCurrencyAmounts(s string) (CurrencyAmounts, error) {
+	amounts := CurrencyAmounts{}
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		currency, value, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid currency amount %q: expected CURRENCY:AMOUNT", pair)
+		}
+
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if _, ok := money.Exponent(currency); !ok {
+			return nil, fmt.Errorf("invalid currency amount %q: unknown currency", pair)
+		}
+
+		amount, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency amount %q: %w", pair, err)
+		}
+
+		amounts[currency] = amount
+	}
+
+	return amounts, nil
+}
+
+func stringToCurrencyAmountsHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(CurrencyAmounts{}) {
+			return data, nil
+		}
+
+		return ParseCurrencyAmounts(data.(string))
+	}
+}
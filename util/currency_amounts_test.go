@@ -0,0 +1,22 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurrencyAmounts(t *testing.T) {
+	amounts, err := ParseCurrencyAmounts("USD:100000, eur:90000,")
+	require.NoError(t, err)
+	require.Equal(t, CurrencyAmounts{USD: 100000, EUR: 90000}, amounts)
+
+	_, err = ParseCurrencyAmounts("USD=100")
+	require.Error(t, err)
+
+	_, err = ParseCurrencyAmounts("XYZ:100")
+	require.Error(t, err)
+
+	_, err = ParseCurrencyAmounts("USD:abc")
+	require.Error(t, err)
+}
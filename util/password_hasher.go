@@ -0,0 +1,200 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported password hashing algorithms
+const (
+	PasswordHashArgon2id = "argon2id"
+	PasswordHashBcrypt   = "bcrypt"
+)
+
+// Default argon2id parameters, used for any value left at zero in the config
+const (
+	defaultArgon2Memory      = 64 * 1024
+	defaultArgon2Iterations  = 3
+	defaultArgon2Parallelism = 2
+	argon2SaltLength         = 16
+	argon2KeyLength          = 32
+)
+
+var (
+	ErrMismatchedPassword  = errors.New("password does not match")
+	ErrUnknownPasswordHash = errors.New("unknown password hash format")
+)
+
+// PasswordHasher hashes passwords into self-describing PHC strings, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>, so hashes made with older
+// algorithms or parameters keep verifying and can be upgraded on login.
+type PasswordHasher interface {
+	// Hash hashes the password with the configured algorithm and parameters
+	Hash(password string) (string, error)
+	// Check verifies the password against a hash made with any supported algorithm
+	Check(password string, hash string) error
+	// NeedsRehash reports whether the hash was made with a different
+	// algorithm or weaker parameters than the configured ones
+	NeedsRehash(hash string) bool
+}
+
+// NewPasswordHasher creates the hasher selected by PASSWORD_HASH_ALGORITHM.
+// bcrypt is the default so existing deployments keep their current hashes.
+func NewPasswordHasher(config Config) (PasswordHasher, error) {
+	switch config.PasswordHashAlgorithm {
+	case "", PasswordHashBcrypt:
+		cost := config.BcryptCost
+		if cost == 0 {
+			cost = bcrypt.DefaultCost
+		}
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost %d", cost)
+		}
+		return &passwordHasher{algorithm: PasswordHashBcrypt, bcryptCost: cost}, nil
+	case PasswordHashArgon2id:
+		params := argon2Params{
+			memory:      config.Argon2Memory,
+			iterations:  config.Argon2Iterations,
+			parallelism: config.Argon2Parallelism,
+		}
+		if params.memory == 0 {
+			params.memory = defaultArgon2Memory
+		}
+		if params.iterations == 0 {
+			params.iterations = defaultArgon2Iterations
+		}
+		if params.parallelism == 0 {
+			params.parallelism = defaultArgon2Parallelism
+		}
+		return &passwordHasher{algorithm: PasswordHashArgon2id, argon2: params}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported password hash algorithm %q", config.PasswordHashAlgorithm)
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+type passwordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     argon2Params
+}
+
+func (hasher *passwordHasher) Hash(password string) (string, error) {
+	if hasher.algorithm == PasswordHashBcrypt {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), hasher.bcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(hashedPassword), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	params := hasher.argon2
+	key := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.memory, params.iterations, params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (hasher *passwordHasher) Check(password string, hash string) error {
+	if isBcryptHash(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatchedPassword
+		}
+		return err
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+
+	return nil
+}
+
+func (hasher *passwordHasher) NeedsRehash(hash string) bool {
+	if hasher.algorithm == PasswordHashBcrypt {
+		if !isBcryptHash(hash) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost < hasher.bcryptCost
+	}
+
+	params, _, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return true
+	}
+
+	want := hasher.argon2
+	return params.memory < want.memory ||
+		params.iterations < want.iterations ||
+		params.parallelism < want.parallelism ||
+		len(key) < argon2KeyLength
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// decodeArgon2Hash parses a $argon2id$v=19$m=..,t=..,p=..$salt$key PHC string
+func decodeArgon2Hash(hash string) (params argon2Params, salt []byte, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != PasswordHashArgon2id {
+		err = ErrUnknownPasswordHash
+		return
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		err = fmt.Errorf("unsupported argon2 version %q", parts[2])
+		return
+	}
+
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		err = fmt.Errorf("invalid argon2 parameters %q: %w", parts[3], err)
+		return
+	}
+
+	if params.iterations == 0 || params.parallelism == 0 {
+		err = fmt.Errorf("invalid argon2 parameters %q", parts[3])
+		return
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		err = fmt.Errorf("invalid argon2 salt: %w", err)
+		return
+	}
+
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		err = fmt.Errorf("invalid argon2 hash: %w", err)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2idPasswordHasher(t *testing.T) {
+	hasher, err := NewPasswordHasher(Config{
+		PasswordHashAlgorithm: PasswordHashArgon2id,
+		Argon2Memory:          1024,
+		Argon2Iterations:      2,
+		Argon2Parallelism:     1,
+	})
+	require.NoError(t, err)
+
+	password := RandomString(8)
+	hashedPassword, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hashedPassword, "$argon2id$v=19$m=1024,t=2,p=1$"))
+
+	require.NoError(t, hasher.Check(password, hashedPassword))
+	require.ErrorIs(t, hasher.Check(RandomString(8), hashedPassword), ErrMismatchedPassword)
+	require.False(t, hasher.NeedsRehash(hashedPassword))
+
+	// the same password hashes differently every time
+	hashedPassword2, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.NotEqual(t, hashedPassword, hashedPassword2)
+
+	// bcrypt hashes keep verifying but are upgraded
+	bcryptHash, err := HashPassword(password)
+	require.NoError(t, err)
+	require.NoError(t, hasher.Check(password, bcryptHash))
+	require.True(t, hasher.NeedsRehash(bcryptHash))
+
+	// so are argon2id hashes made with weaker parameters
+	stronger, err := NewPasswordHasher(Config{
+		PasswordHashAlgorithm: PasswordHashArgon2id,
+		Argon2Memory:          2048,
+		Argon2Iterations:      2,
+		Argon2Parallelism:     1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, stronger.Check(password, hashedPassword))
+	require.True(t, stronger.NeedsRehash(hashedPassword))
+
+	require.ErrorIs(t, hasher.Check(password, "$argon2i$v=19$m=1024,t=2,p=1$c2FsdA$a2V5"), ErrUnknownPasswordHash)
+	require.Error(t, hasher.Check(password, "$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5"))
+}
+
+func TestBcryptPasswordHasher(t *testing.T) {
+	hasher, err := NewPasswordHasher(Config{BcryptCost: bcrypt.MinCost + 1})
+	require.NoError(t, err)
+
+	password := RandomString(8)
+	hashedPassword, err := hasher.Hash(password)
+	require.NoError(t, err)
+
+	require.NoError(t, hasher.Check(password, hashedPassword))
+	require.ErrorIs(t, hasher.Check(RandomString(8), hashedPassword), ErrMismatchedPassword)
+	require.False(t, hasher.NeedsRehash(hashedPassword))
+
+	weak, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	require.True(t, hasher.NeedsRehash(string(weak)))
+
+	_, err = NewPasswordHasher(Config{PasswordHashAlgorithm: "md5"})
+	require.Error(t, err)
+}
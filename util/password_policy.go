@@ -0,0 +1,111 @@
+package util
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// maxPasswordLength is the number of bytes bcrypt actually uses
+const maxPasswordLength = 72
+
+// ErrPasswordPolicy is wrapped by every error returned from PasswordPolicy.Validate
+var ErrPasswordPolicy = errors.New("password does not meet policy")
+
+// PasswordPolicy describes the strength rules a new password must satisfy
+type PasswordPolicy struct {
+	MinLength      int
+	MinCharClasses int
+	breached       map[string]struct{}
+}
+
+// NewPasswordPolicy creates the policy described by the config, loading the
+// breached password list from PASSWORD_BREACHED_LIST_FILE if one is set.
+func NewPasswordPolicy(config Config) (PasswordPolicy, error) {
+	policy := PasswordPolicy{
+		MinLength:      config.PasswordMinLength,
+		MinCharClasses: config.PasswordMinCharClasses,
+	}
+
+	if config.PasswordBreachedListFile == "" {
+		return policy, nil
+	}
+
+	breached, err := LoadBreachedPasswords(config.PasswordBreachedListFile)
+	if err != nil {
+		return policy, err
+	}
+	policy.breached = breached
+
+	return policy, nil
+}
+
+// LoadBreachedPasswords reads a list of known breached passwords, one per line.
+// Blank lines and lines starting with # are ignored.
+func LoadBreachedPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	breached := map[string]struct{}{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		breached[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read breached password list: %w", err)
+	}
+
+	return breached, nil
+}
+
+// Validate checks the password against the policy
+func (policy PasswordPolicy) Validate(password string) error {
+	if len(password) < policy.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters long", ErrPasswordPolicy, policy.MinLength)
+	}
+
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: must be at most %d bytes long", ErrPasswordPolicy, maxPasswordLength)
+	}
+
+	if classes := charClasses(password); classes < policy.MinCharClasses {
+		return fmt.Errorf("%w: must contain at least %d of lowercase letters, uppercase letters, digits and symbols", ErrPasswordPolicy, policy.MinCharClasses)
+	}
+
+	if _, ok := policy.breached[strings.ToLower(password)]; ok {
+		return fmt.Errorf("%w: appears in a list of breached passwords", ErrPasswordPolicy)
+	}
+
+	return nil
+}
+
+func charClasses(password string) int {
+	var lower, upper, digit, symbol int
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "breached.txt")
+	err := os.WriteFile(list, []byte("# common passwords\nPassword123!\n\nqwerty\n"), 0o600)
+	require.NoError(t, err)
+
+	policy, err := NewPasswordPolicy(Config{
+		PasswordMinLength:        8,
+		PasswordMinCharClasses:   3,
+		PasswordBreachedListFile: list,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{"OK", "correct-Horse7", true},
+		{"TooShort", "aB3!", false},
+		{"TooLong", "aB3!" + RandomString(maxPasswordLength), false},
+		{"TooFewClasses", "correcthorse", false},
+		{"Breached", "PASSWORD123!", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Validate(tc.password)
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrPasswordPolicy)
+		})
+	}
+
+	_, err = NewPasswordPolicy(Config{PasswordBreachedListFile: filepath.Join(t.TempDir(), "missing.txt")})
+	require.Error(t, err)
+}
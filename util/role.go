@@ -0,0 +1,7 @@
+package util
+
+// User roles
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+)